@@ -0,0 +1,472 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package copyconstraint
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/iop"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrPermutationSize     = errors.New("the permutation size must be nbColumns times a power of 2")
+	ErrPermutationRange    = errors.New("the permutation contains an out of range index")
+	ErrNbColumns           = errors.New("wrong number of columns")
+	ErrColumnSize          = errors.New("the columns should be of the size of the permutation domain")
+	ErrCopyConstraint      = errors.New("the columns do not satisfy the copy constraints")
+	ErrNbClaimedValues     = errors.New("wrong number of claimed values")
+	ErrCopyConstraintProof = errors.New("copy constraint proof verification failed")
+)
+
+// VerifyingKey contains the data needed to verify a copy constraint proof.
+type VerifyingKey struct {
+
+	// Size of the columns
+	Size uint64
+
+	// Generator of the domain of size Size
+	Generator fr.Element
+
+	// CosetShift u, the j-th column is identified with the coset uʲ<ω>
+	CosetShift fr.Element
+
+	// Commitments to the permutation polynomials Sσⱼ
+	S []kzg.Digest
+}
+
+// ProvingKey contains the data needed to build a copy constraint proof.
+type ProvingKey struct {
+	Vk VerifyingKey
+
+	// Domain[0] is the domain of size Size, Domain[1] the domain on which
+	// the quotient is computed
+	Domain [2]*fft.Domain
+
+	// permutation on [0, nbColumns*Size)
+	permutation []int64
+
+	// permutation polynomials Sσⱼ in canonical form, regular layout
+	s []*iop.Polynomial
+}
+
+// Proof proof that committed columns satisfy copy constraints.
+type Proof struct {
+
+	// Commitments to the columns
+	Columns []kzg.Digest
+
+	// Commitments to the grand product Z and to the quotient H
+	Z, H kzg.Digest
+
+	// Batch opening proof of the columns, Sσⱼ, Z, H (in that order) at ζ
+	BatchedProof kzg.BatchOpeningProof
+
+	// Opening proof of Z at ωζ
+	ZShiftedProof kzg.OpeningProof
+}
+
+// Setup computes the proving and verifying keys for the permutation acting on
+// nbColumns columns. The entry j*n+i of the permutation corresponds to the i-th entry
+// of the j-th column, where n = len(permutation)/nbColumns must be a power of 2.
+//
+// The quotient has degree less than nbColumns*n, so srs must contain at least
+// nbColumns*n points.
+func Setup(srs *kzg.SRS, nbColumns int, permutation []int64) (ProvingKey, VerifyingKey, error) {
+
+	var pk ProvingKey
+	if nbColumns < 1 || len(permutation)%nbColumns != 0 {
+		return pk, pk.Vk, ErrPermutationSize
+	}
+	n := len(permutation) / nbColumns
+	if n < 2 || n&(n-1) != 0 {
+		return pk, pk.Vk, ErrPermutationSize
+	}
+	for _, p := range permutation {
+		if p < 0 || p >= int64(len(permutation)) {
+			return pk, pk.Vk, ErrPermutationRange
+		}
+	}
+
+	pk.Domain[0] = fft.NewDomain(uint64(n))
+	pk.Domain[1] = fft.NewDomain(ecc.NextPowerOfTwo(uint64((nbColumns + 1) * n)))
+	pk.permutation = permutation
+
+	pk.Vk.Size = uint64(n)
+	pk.Vk.Generator.Set(&pk.Domain[0].Generator)
+	pk.Vk.CosetShift.Set(&pk.Domain[0].FrMultiplicativeGen)
+
+	// Sσⱼ(ωⁱ) = uᵏωˡ where σ(j*n+i) = k*n+l
+	support := identitySupport(nbColumns, pk.Domain[0])
+	pk.s = make([]*iop.Polynomial, nbColumns)
+	pk.Vk.S = make([]kzg.Digest, nbColumns)
+	for j := 0; j < nbColumns; j++ {
+		s := make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			s[i].Set(&support[permutation[j*n+i]])
+		}
+		pk.s[j] = iop.NewPolynomial(&s, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
+		pk.s[j].ToCanonical(pk.Domain[0]).ToRegular()
+
+		var err error
+		pk.Vk.S[j], err = kzg.Commit(pk.s[j].Coefficients(), srs)
+		if err != nil {
+			return pk, pk.Vk, err
+		}
+	}
+
+	return pk, pk.Vk, nil
+}
+
+// identitySupport returns [1,ω,..,ωⁿ⁻¹,u,uω,..,uωⁿ⁻¹,..,uᵐ⁻¹ωⁿ⁻¹], the
+// points identified with the entries of the columns.
+func identitySupport(nbColumns int, domain *fft.Domain) []fr.Element {
+	n := int(domain.Cardinality)
+	res := make([]fr.Element, nbColumns*n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &domain.Generator)
+	}
+	for j := 1; j < nbColumns; j++ {
+		for i := 0; i < n; i++ {
+			res[j*n+i].Mul(&res[(j-1)*n+i], &domain.FrMultiplicativeGen)
+		}
+	}
+	return res
+}
+
+// checkCopyConstraints checks that the entries of each cycle of the permutation are equal
+func checkCopyConstraints(columns []fr.Vector, permutation []int64) bool {
+	n := len(columns[0])
+	for k, p := range permutation {
+		if !columns[k/n][k%n].Equal(&columns[p/int64(n)][p%int64(n)]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Prove generates a proof that the columns, given in Lagrange form, satisfy the
+// copy constraints described by pk.
+func Prove(srs *kzg.SRS, pk *ProvingKey, columns ...fr.Vector) (Proof, error) {
+
+	var proof Proof
+	var err error
+
+	nbColumns := len(pk.s)
+	n := int(pk.Vk.Size)
+	if len(columns) != nbColumns {
+		return proof, ErrNbColumns
+	}
+	for i := range columns {
+		if len(columns[i]) != n {
+			return proof, ErrColumnSize
+		}
+	}
+	if !checkCopyConstraints(columns, pk.permutation) {
+		return proof, ErrCopyConstraint
+	}
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewTranscript(hFunc, "beta", "gamma", "alpha", "zeta")
+
+	// commit to the columns
+	lagrange := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	f := make([]*iop.Polynomial, nbColumns)
+	proof.Columns = make([]kzg.Digest, nbColumns)
+	for j := range columns {
+		c := make([]fr.Element, n, pk.Domain[1].Cardinality)
+		copy(c, columns[j])
+		f[j] = iop.NewPolynomial(&c, lagrange)
+		proof.Columns[j], err = kzg.Commit(f[j].Clone().ToCanonical(pk.Domain[0]).ToRegular().Coefficients(), srs)
+		if err != nil {
+			return proof, err
+		}
+	}
+
+	// derive β, γ
+	beta, err := deriveRandomness(&fs, "beta", append(append([]kzg.Digest{}, pk.Vk.S...), proof.Columns...)...)
+	if err != nil {
+		return proof, err
+	}
+	gamma, err := deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return proof, err
+	}
+
+	// compute and commit to the grand product
+	z, err := iop.BuildRatioCopyConstraint(
+		f,
+		pk.permutation,
+		beta,
+		gamma,
+		iop.Form{Basis: iop.Canonical, Layout: iop.Regular},
+		pk.Domain[0],
+	)
+	if err != nil {
+		return proof, err
+	}
+	proof.Z, err = kzg.Commit(z.Coefficients(), srs)
+	if err != nil {
+		return proof, err
+	}
+
+	// derive α
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Z)
+	if err != nil {
+		return proof, err
+	}
+
+	// compute the quotient
+	h, err := computeQuotient(pk, f, z, beta, gamma, alpha)
+	if err != nil {
+		return proof, err
+	}
+	proof.H, err = kzg.Commit(h, srs)
+	if err != nil {
+		return proof, err
+	}
+
+	// derive ζ
+	zeta, err := deriveRandomness(&fs, "zeta", proof.H)
+	if err != nil {
+		return proof, err
+	}
+
+	// open the columns, Sσⱼ, Z and H at ζ
+	polynomials := make([][]fr.Element, 0, 2*nbColumns+2)
+	digests := make([]kzg.Digest, 0, 2*nbColumns+2)
+	for j := range f {
+		polynomials = append(polynomials, f[j].Coefficients())
+	}
+	for j := range pk.s {
+		polynomials = append(polynomials, pk.s[j].Coefficients())
+	}
+	polynomials = append(polynomials, z.Coefficients(), h)
+	digests = append(digests, proof.Columns...)
+	digests = append(digests, pk.Vk.S...)
+	digests = append(digests, proof.Z, proof.H)
+	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(polynomials, digests, zeta, hFunc, srs)
+	if err != nil {
+		return proof, err
+	}
+
+	// open Z at ωζ
+	var shiftedZeta fr.Element
+	shiftedZeta.Mul(&zeta, &pk.Vk.Generator)
+	proof.ZShiftedProof, err = kzg.Open(z.Coefficients(), shiftedZeta, srs)
+	if err != nil {
+		return proof, err
+	}
+
+	return proof, nil
+}
+
+// computeQuotient returns the coefficients of
+// H = (Z(ωX)Πⱼ(fⱼ+β*Sσⱼ+γ) - Z(X)Πⱼ(fⱼ+β*uʲX+γ) + α*L₀(X)(Z(X)-1)) / (Xⁿ-1).
+// The polynomials in f are put in canonical form, regular layout.
+func computeQuotient(pk *ProvingKey, f []*iop.Polynomial, z *iop.Polynomial, beta, gamma, alpha fr.Element) ([]fr.Element, error) {
+
+	nbColumns := len(f)
+	n := int(pk.Vk.Size)
+	capacity := int(pk.Domain[1].Cardinality)
+
+	// X and L₀ in canonical form
+	x := make([]fr.Element, n, capacity)
+	x[1].SetOne()
+	l0 := make([]fr.Element, n, capacity)
+	l0[0].SetOne()
+	lagrange := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	canonical := iop.Form{Basis: iop.Canonical, Layout: iop.Regular}
+
+	// evaluate everything on the coset of the big domain
+	entries := make([]*iop.Polynomial, 0, 2*nbColumns+4)
+	for j := range f {
+		f[j].ToCanonical(pk.Domain[0]).ToRegular()
+		entries = append(entries, f[j].Clone(capacity).ToLagrangeCoset(pk.Domain[1]))
+	}
+	for j := range pk.s {
+		entries = append(entries, pk.s[j].Clone(capacity).ToLagrangeCoset(pk.Domain[1]))
+	}
+	zCoset := z.Clone(capacity).ToLagrangeCoset(pk.Domain[1])
+	entries = append(entries,
+		zCoset,
+		zCoset.ShallowClone().Shift(1),
+		iop.NewPolynomial(&x, canonical).ToLagrangeCoset(pk.Domain[1]),
+		iop.NewPolynomial(&l0, lagrange).ToCanonical(pk.Domain[0]).ToRegular().ToLagrangeCoset(pk.Domain[1]),
+	)
+
+	// uʲ
+	shifts := make([]fr.Element, nbColumns)
+	shifts[0].SetOne()
+	for j := 1; j < nbColumns; j++ {
+		shifts[j].Mul(&shifts[j-1], &pk.Vk.CosetShift)
+	}
+
+	constraints := func(e ...fr.Element) fr.Element {
+		return evaluateConstraints(e[:nbColumns], e[nbColumns:2*nbColumns], e[2*nbColumns], e[2*nbColumns+1], e[2*nbColumns+2], e[2*nbColumns+3], shifts, beta, gamma, alpha)
+	}
+	num, err := iop.Evaluate(constraints, iop.Form{Basis: iop.LagrangeCoset, Layout: iop.Regular}, entries...)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := iop.DivideByXMinusOne(num, pk.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// deg H < nbColumns*n
+	return h.Coefficients()[:nbColumns*n], nil
+}
+
+// evaluateConstraints returns
+// z(ωx)Πⱼ(fⱼ+β*sⱼ+γ) - z(x)Πⱼ(fⱼ+β*uʲx+γ) + α*l₀(z(x)-1)
+func evaluateConstraints(f, s []fr.Element, z, zShifted, x, l0 fr.Element, shifts []fr.Element, beta, gamma, alpha fr.Element) fr.Element {
+	var num, den, t fr.Element
+	num.Set(&zShifted)
+	den.Set(&z)
+	for j := range f {
+		t.Mul(&beta, &s[j]).Add(&t, &gamma).Add(&t, &f[j])
+		num.Mul(&num, &t)
+		t.Mul(&beta, &shifts[j]).Mul(&t, &x).Add(&t, &gamma).Add(&t, &f[j])
+		den.Mul(&den, &t)
+	}
+	num.Sub(&num, &den)
+	t.SetOne()
+	t.Sub(&z, &t).Mul(&t, &l0).Mul(&t, &alpha)
+	num.Add(&num, &t)
+	return num
+}
+
+// Verify verifies a copy constraint proof.
+func Verify(srs *kzg.SRS, vk *VerifyingKey, proof Proof) error {
+
+	nbColumns := len(vk.S)
+	if len(proof.Columns) != nbColumns {
+		return ErrNbColumns
+	}
+	if len(proof.BatchedProof.ClaimedValues) != 2*nbColumns+2 {
+		return ErrNbClaimedValues
+	}
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewTranscript(hFunc, "beta", "gamma", "alpha", "zeta")
+
+	// derive the challenges
+	beta, err := deriveRandomness(&fs, "beta", append(append([]kzg.Digest{}, vk.S...), proof.Columns...)...)
+	if err != nil {
+		return err
+	}
+	gamma, err := deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return err
+	}
+	alpha, err := deriveRandomness(&fs, "alpha", proof.Z)
+	if err != nil {
+		return err
+	}
+	zeta, err := deriveRandomness(&fs, "zeta", proof.H)
+	if err != nil {
+		return err
+	}
+
+	// check the opening proofs
+	digests := make([]kzg.Digest, 0, 2*nbColumns+2)
+	digests = append(digests, proof.Columns...)
+	digests = append(digests, vk.S...)
+	digests = append(digests, proof.Z, proof.H)
+	err = kzg.BatchVerifySinglePoint(digests, &proof.BatchedProof, zeta, hFunc, srs)
+	if err != nil {
+		return err
+	}
+	var shiftedZeta fr.Element
+	shiftedZeta.Mul(&zeta, &vk.Generator)
+	err = kzg.Verify(&proof.Z, &proof.ZShiftedProof, shiftedZeta, srs)
+	if err != nil {
+		return err
+	}
+
+	// L₀(ζ) = (ζⁿ-1)/(n(ζ-1))
+	var zetaNMinusOne, l0, n, one fr.Element
+	one.SetOne()
+	zetaNMinusOne.Exp(zeta, big.NewInt(int64(vk.Size))).Sub(&zetaNMinusOne, &one)
+	n.SetUint64(vk.Size)
+	l0.Sub(&zeta, &one).Mul(&l0, &n)
+	l0.Div(&zetaNMinusOne, &l0)
+
+	// check the constraints at ζ
+	shifts := make([]fr.Element, nbColumns)
+	shifts[0].SetOne()
+	for j := 1; j < nbColumns; j++ {
+		shifts[j].Mul(&shifts[j-1], &vk.CosetShift)
+	}
+	claimedValues := proof.BatchedProof.ClaimedValues
+	lhs := evaluateConstraints(
+		claimedValues[:nbColumns],
+		claimedValues[nbColumns:2*nbColumns],
+		claimedValues[2*nbColumns],
+		proof.ZShiftedProof.ClaimedValue,
+		zeta,
+		l0,
+		shifts,
+		beta,
+		gamma,
+		alpha,
+	)
+	var rhs fr.Element
+	rhs.Mul(&claimedValues[2*nbColumns+1], &zetaNMinusOne)
+	if !lhs.Equal(&rhs) {
+		return ErrCopyConstraintProof
+	}
+
+	return nil
+}
+
+// deriveRandomness derives a challenge from the transcript, after binding the points.
+func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...bls12377.G1Affine) (fr.Element, error) {
+
+	var buf [bls12377.SizeOfG1AffineUncompressed]byte
+	var r fr.Element
+
+	for _, p := range points {
+		buf = p.RawBytes()
+		if err := fs.Bind(challenge, buf[:]); err != nil {
+			return r, err
+		}
+	}
+
+	b, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return r, err
+	}
+	r.SetBytes(b)
+	return r, nil
+}
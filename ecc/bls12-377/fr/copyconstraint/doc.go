@@ -0,0 +1,28 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package copyconstraint provides an API to build PLONK-style copy constraint proofs.
+//
+// Given m columns f₀, .., fₘ₋₁ of size n and a permutation σ of [0, m*n), a proof
+// shows that the committed columns satisfy fⱼ[i] = fₖ[l] whenever σ maps j*n+i to k*n+l,
+// that is that the entries in each cycle of σ are equal.
+//
+// The argument follows https://eprint.iacr.org/2019/953.pdf: the grand product
+// Z(ωX) = Z(X)Πⱼ(fⱼ(X)+β*uʲX+γ)/Πⱼ(fⱼ(X)+β*Sσⱼ(X)+γ), Z(1) = 1 is committed, and
+// the quotient by Xⁿ-1 of the constraints is committed and opened together with the
+// columns, the permutation polynomials Sσⱼ and Z at a random point.
+// The proofs are not hiding.
+package copyconstraint
@@ -0,0 +1,439 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// GateFunction is a polynomial function of the gate's inputs.
+// It must not modify its inputs.
+type GateFunction func(...fr.Element) fr.Element
+
+// RegisteredGate is a Gate defined by a GateFunction, whose degree has been checked at registration.
+// It is referred to by its name in circuit descriptions.
+type RegisteredGate struct {
+	name   string
+	f      GateFunction
+	nbIn   int
+	degree int
+}
+
+// Evaluate implements Gate
+func (g *RegisteredGate) Evaluate(in ...fr.Element) fr.Element {
+	return g.f(in...)
+}
+
+// Degree implements Gate
+func (g *RegisteredGate) Degree() int {
+	return g.degree
+}
+
+// Name returns the name under which the gate is registered
+func (g *RegisteredGate) Name() string {
+	return g.name
+}
+
+// NbIn returns the number of inputs of the gate
+func (g *RegisteredGate) NbIn() int {
+	return g.nbIn
+}
+
+// maxAutoDegree is the largest degree that RegisterGate detects automatically.
+// Gates of higher degree must be registered using WithDegree.
+const maxAutoDegree = 32
+
+var (
+	gateRegistry     = make(map[string]*RegisteredGate)
+	gateRegistryLock sync.Mutex
+
+	errGateDegreeTooHigh = errors.New("the gate degree is too high to be detected automatically, use WithDegree")
+)
+
+type registerGateSettings struct {
+	degree         int
+	verifyDegree   bool
+	overwriteEntry bool
+}
+
+// RegisterGateOption configures RegisterGate
+type RegisterGateOption func(*registerGateSettings)
+
+// WithDegree declares the total degree of the gate.
+// It is checked at registration, instead of being detected.
+func WithDegree(degree int) RegisterGateOption {
+	return func(s *registerGateSettings) {
+		s.degree = degree
+	}
+}
+
+// WithUnverifiedDegree declares the total degree of the gate, without checking it.
+// A wrong degree breaks the soundness of the protocol, use with caution.
+func WithUnverifiedDegree(degree int) RegisterGateOption {
+	return func(s *registerGateSettings) {
+		s.degree = degree
+		s.verifyDegree = false
+	}
+}
+
+// WithOverwrite allows replacing a gate already registered under the same name.
+func WithOverwrite() RegisterGateOption {
+	return func(s *registerGateSettings) {
+		s.overwriteEntry = true
+	}
+}
+
+// RegisterGate adds a gate to the registry, so that it can be referred to by name in circuit descriptions.
+// Unless specified with WithDegree or WithUnverifiedDegree, the degree of the gate is found by restricting
+// f on a random line; a declared degree is checked in the same way.
+func RegisterGate(name string, f GateFunction, nbIn int, options ...RegisterGateOption) error {
+	s := registerGateSettings{degree: -1, verifyDegree: true}
+	for _, option := range options {
+		option(&s)
+	}
+
+	if nbIn < 1 {
+		return fmt.Errorf("gate \"%s\": a gate must have at least one input", name)
+	}
+
+	if s.degree < 0 {
+		var err error
+		if s.degree, err = findGateDegree(f, nbIn); err != nil {
+			return fmt.Errorf("gate \"%s\": %w", name, err)
+		}
+	} else if s.verifyDegree {
+		if err := checkGateDegree(f, nbIn, s.degree); err != nil {
+			return fmt.Errorf("gate \"%s\": %w", name, err)
+		}
+	}
+
+	gateRegistryLock.Lock()
+	defer gateRegistryLock.Unlock()
+
+	if _, ok := gateRegistry[name]; ok && !s.overwriteEntry {
+		return fmt.Errorf("gate \"%s\" already registered", name)
+	}
+	gateRegistry[name] = &RegisteredGate{
+		name:   name,
+		f:      f,
+		nbIn:   nbIn,
+		degree: s.degree,
+	}
+	return nil
+}
+
+// GetGate returns the gate registered under the given name, or nil if there is none.
+func GetGate(name string) *RegisteredGate {
+	gateRegistryLock.Lock()
+	defer gateRegistryLock.Unlock()
+	return gateRegistry[name]
+}
+
+// gateOnRandomLine returns the values of t ↦ f(x + ty) for t = 0, ..., nbPoints-1
+// where x and y are random vectors
+func gateOnRandomLine(f GateFunction, nbIn, nbPoints int) ([]fr.Element, error) {
+	x := make([]fr.Element, nbIn)
+	y := make([]fr.Element, nbIn)
+	for i := range x {
+		if _, err := x[i].SetRandom(); err != nil {
+			return nil, err
+		}
+		if _, err := y[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]fr.Element, nbPoints)
+	in := make([]fr.Element, nbIn)
+	for t := range res {
+		copy(in, x)
+		res[t] = f(in...)
+		for i := range x {
+			x[i].Add(&x[i], &y[i])
+		}
+	}
+	return res, nil
+}
+
+// finiteDifferences replaces v with its successive finite differences Δᵏv(0), for k = 0, ..., len(v)-1.
+// A polynomial of degree d is the smallest d such that Δᵈ⁺¹v = 0.
+func finiteDifferences(v []fr.Element) {
+	for k := 1; k < len(v); k++ {
+		for i := len(v) - 1; i >= k; i-- {
+			v[i].Sub(&v[i], &v[i-1])
+		}
+	}
+}
+
+// findGateDegree returns the total degree of f, by computing the degree of its restriction to a random line.
+// The result is correct with overwhelming probability.
+func findGateDegree(f GateFunction, nbIn int) (int, error) {
+	v, err := gateOnRandomLine(f, nbIn, maxAutoDegree+2)
+	if err != nil {
+		return -1, err
+	}
+	finiteDifferences(v)
+	if !v[maxAutoDegree+1].IsZero() {
+		return -1, errGateDegreeTooHigh
+	}
+	for d := maxAutoDegree; d > 0; d-- {
+		if !v[d].IsZero() {
+			return d, nil
+		}
+	}
+	// f is constant, which makes it of degree 0. Degree 1 keeps the sumcheck well-formed.
+	return 1, nil
+}
+
+// checkGateDegree verifies that the restriction of f to a random line is of degree at most degree.
+func checkGateDegree(f GateFunction, nbIn, degree int) error {
+	if degree < 1 {
+		return fmt.Errorf("invalid degree %d", degree)
+	}
+	v, err := gateOnRandomLine(f, nbIn, degree+2)
+	if err != nil {
+		return err
+	}
+	finiteDifferences(v)
+	if !v[degree+1].IsZero() {
+		return fmt.Errorf("the gate is not of degree at most %d", degree)
+	}
+	return nil
+}
+
+// Sum is the gate function (x₁, ..., xₙ) ↦ x₁ + ... + xₙ
+func Sum(in ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Set(&in[0])
+	for i := 1; i < len(in); i++ {
+		res.Add(&res, &in[i])
+	}
+	return res
+}
+
+// Product is the gate function (x₁, ..., xₙ) ↦ x₁ × ... × xₙ
+func Product(in ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Set(&in[0])
+	for i := 1; i < len(in); i++ {
+		res.Mul(&res, &in[i])
+	}
+	return res
+}
+
+// Sub is the gate function (x₁, x₂) ↦ x₁ - x₂
+func Sub(in ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Sub(&in[0], &in[1])
+	return res
+}
+
+// Neg is the gate function x ↦ -x
+func Neg(in ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Neg(&in[0])
+	return res
+}
+
+// LinearCombination returns the gate function (x₁, ..., xₙ) ↦ c₁x₁ + ... + cₙxₙ
+func LinearCombination(coeffs ...fr.Element) GateFunction {
+	coeffs = append([]fr.Element(nil), coeffs...)
+	return func(in ...fr.Element) fr.Element {
+		var res, t fr.Element
+		for i := range coeffs {
+			t.Mul(&coeffs[i], &in[i])
+			res.Add(&res, &t)
+		}
+		return res
+	}
+}
+
+// Select returns the gate function (x₀, ..., xₙ₋₁) ↦ xᵢ
+func Select(i int) GateFunction {
+	return func(in ...fr.Element) fr.Element {
+		return in[i]
+	}
+}
+
+// Power returns the gate function x ↦ xᵉ. It panics if e is negative.
+func Power(e int) GateFunction {
+	checkExponent(e)
+	return func(in ...fr.Element) fr.Element {
+		return pow(in[0], e)
+	}
+}
+
+// MiMCRound returns the gate function of a MiMC round with round constant c and exponent e:
+// (x, k) ↦ (x + k + c)ᵉ. It panics if e is negative.
+func MiMCRound(c fr.Element, e int) GateFunction {
+	checkExponent(e)
+	return func(in ...fr.Element) fr.Element {
+		var sum fr.Element
+		sum.Add(&in[0], &in[1]).
+			Add(&sum, &c)
+		return pow(sum, e)
+	}
+}
+
+// checkExponent panics if the exponent of a power gate is negative
+func checkExponent(e int) {
+	if e < 0 {
+		panic(fmt.Sprintf("negative exponent %d", e))
+	}
+}
+
+// pow computes xᵉ by square and multiply
+func pow(x fr.Element, e int) fr.Element {
+	var res fr.Element
+	res.SetOne()
+	for i := 63; i >= 0; i-- {
+		res.Square(&res)
+		if (e>>i)&1 == 1 {
+			res.Mul(&res, &x)
+		}
+	}
+	return res
+}
+
+func init() {
+	for _, g := range []struct {
+		name string
+		f    GateFunction
+		nbIn int
+	}{
+		{"identity", Select(0), 1},
+		{"add2", Sum, 2},
+		{"sub2", Sub, 2},
+		{"neg", Neg, 1},
+		{"mul2", Product, 2},
+		{"square", Power(2), 1},
+	} {
+		if err := RegisterGate(g.name, g.f, g.nbIn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// WireDescription is a serializable description of a wire: the name of its gate in the registry,
+// and the indexes of its inputs in the circuit. Input wires have no gate and no inputs.
+type WireDescription struct {
+	Gate   string `json:"gate"`
+	Inputs []int  `json:"inputs"`
+}
+
+// CircuitDescription is a serializable description of a circuit, shared by the prover and the verifier.
+type CircuitDescription []WireDescription
+
+// ToCircuit builds the circuit, looking up the gates in the registry.
+func (d CircuitDescription) ToCircuit() (Circuit, error) {
+	circuit := make(Circuit, len(d))
+	for i := range d {
+		if len(d[i].Inputs) == 0 {
+			if d[i].Gate != "" {
+				return nil, fmt.Errorf("wire %d: input wires must not have a gate", i)
+			}
+			continue
+		}
+		g := GetGate(d[i].Gate)
+		if g == nil {
+			return nil, fmt.Errorf("wire %d: gate \"%s\" not registered", i, d[i].Gate)
+		}
+		if g.nbIn != len(d[i].Inputs) {
+			return nil, fmt.Errorf("wire %d: gate \"%s\" has %d inputs, %d given", i, d[i].Gate, g.nbIn, len(d[i].Inputs))
+		}
+		circuit[i].Gate = g
+		circuit[i].Inputs = make([]*Wire, len(d[i].Inputs))
+		for k, inI := range d[i].Inputs {
+			if inI < 0 || inI >= len(d) || inI == i {
+				return nil, fmt.Errorf("wire %d: invalid input %d", i, inI)
+			}
+			circuit[i].Inputs[k] = &circuit[inI]
+		}
+	}
+	if err := d.checkAcyclic(); err != nil {
+		return nil, err
+	}
+	return circuit, nil
+}
+
+// checkAcyclic returns an error if a wire depends on itself through its inputs.
+// The inputs indexes must be valid.
+func (d CircuitDescription) checkAcyclic() error {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make([]int, len(d))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case inProgress:
+			return fmt.Errorf("wire %d: cycle in the circuit", i)
+		case done:
+			return nil
+		}
+		state[i] = inProgress
+		for _, inI := range d[i].Inputs {
+			if err := visit(inI); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		return nil
+	}
+	for i := range d {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Description returns the serializable description of the circuit.
+// All gates must have been obtained from the registry.
+func (c Circuit) Description() (CircuitDescription, error) {
+	index := make(map[*Wire]int, len(c))
+	for i := range c {
+		index[&c[i]] = i
+	}
+
+	res := make(CircuitDescription, len(c))
+	for i := range c {
+		res[i].Inputs = make([]int, len(c[i].Inputs))
+		if c[i].IsInput() {
+			continue
+		}
+		g, ok := c[i].Gate.(*RegisteredGate)
+		if !ok {
+			return nil, fmt.Errorf("wire %d: gate is not registered", i)
+		}
+		res[i].Gate = g.name
+		for k, in := range c[i].Inputs {
+			inI, ok := index[in]
+			if !ok {
+				return nil, fmt.Errorf("wire %d: input outside of the circuit", i)
+			}
+			res[i].Inputs[k] = inI
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,391 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrOpeningProof        = errors.New("opening proof rejected")
+	ErrEqualityProof       = errors.New("equality proof rejected")
+	ErrLinearRelationProof = errors.New("linear relation proof rejected")
+	ErrNbRelations         = errors.New("unexpected number of linear relations")
+)
+
+// OpeningProof is a proof of knowledge of the opening (values, r) of a
+// hiding commitment C = Σᵢ valuesᵢBasisᵢ + rH.
+type OpeningProof struct {
+	// A = Σᵢ aᵢBasisᵢ + bH for random aᵢ, b
+	A bls12377.G1Affine
+
+	// Z = a + c*values, ZR = b + c*r, where c is the challenge
+	Z  []fr.Element
+	ZR fr.Element
+}
+
+// EqualityProof is a proof that two hiding commitments, possibly with
+// different keys, commit to the same values.
+type EqualityProof struct {
+	// A1, A2 are the first messages for each commitment, using the same aᵢ
+	A1, A2 bls12377.G1Affine
+
+	// Z = a + c*values, ZR1 = b₁ + c*r₁, ZR2 = b₂ + c*r₂
+	Z        []fr.Element
+	ZR1, ZR2 fr.Element
+}
+
+// LinearRelation states that Σᵢ Coefficientsᵢ*valuesᵢ = Constant.
+type LinearRelation struct {
+	Coefficients []fr.Element
+	Constant     fr.Element
+}
+
+// LinearRelationProof is a proof that the values of a hiding commitment
+// satisfy linear relations.
+type LinearRelationProof struct {
+	OpeningProof
+
+	// T[k] = Σᵢ relations[k].Coefficientsᵢ*aᵢ
+	T []fr.Element
+}
+
+// BlindingGenerator returns the generator H used for blinding. It is derived
+// with HashToG1 from the basis, so nobody knows its discrete logarithm in the basis.
+func (pk *ProvingKey) BlindingGenerator() (bls12377.G1Affine, error) {
+	msg := make([]byte, 0, len(pk.Basis)*bls12377.SizeOfG1AffineCompressed)
+	for i := range pk.Basis {
+		b := pk.Basis[i].Bytes()
+		msg = append(msg, b[:]...)
+	}
+	return bls12377.HashToG1(msg, []byte("pedersen blinding generator"))
+}
+
+// CommitHiding returns the commitment Σᵢ valuesᵢBasisᵢ + rH, where H is the
+// blinding generator. r must be uniformly random and kept for opening.
+func (pk *ProvingKey) CommitHiding(values []fr.Element, r fr.Element) (commitment bls12377.G1Affine, err error) {
+
+	if len(values) != len(pk.Basis) {
+		err = ErrNbValues
+		return
+	}
+
+	h, err := pk.BlindingGenerator()
+	if err != nil {
+		return
+	}
+	_, err = commitment.MultiExp(append(pk.Basis[:len(pk.Basis):len(pk.Basis)], h), append(values[:len(values):len(values)], r), ecc.MultiExpConfig{NbTasks: 1})
+
+	return
+}
+
+// ProveOpening proves the knowledge of values and r such that commitment = CommitHiding(values, r).
+func (pk *ProvingKey) ProveOpening(commitment bls12377.G1Affine, values []fr.Element, r fr.Element) (OpeningProof, error) {
+	proof, _, err := pk.proveOpening("opening", commitment, values, r, nil)
+	return proof, err
+}
+
+// VerifyOpening verifies a proof of opening of the commitment.
+func (pk *ProvingKey) VerifyOpening(commitment bls12377.G1Affine, proof OpeningProof) error {
+	if len(proof.Z) != len(pk.Basis) {
+		return ErrOpeningProof
+	}
+	h, err := pk.BlindingGenerator()
+	if err != nil {
+		return err
+	}
+	c, err := sigmaChallenge("opening", [][]bls12377.G1Affine{pk.Basis, {h, commitment, proof.A}})
+	if err != nil {
+		return err
+	}
+	if ok, err := checkSigma(pk.Basis, h, commitment, proof.A, proof.Z, proof.ZR, c); err != nil || !ok {
+		return ErrOpeningProof
+	}
+	return nil
+}
+
+// ProveLinearRelations proves the knowledge of an opening (values, r) of the commitment
+// such that the values satisfy the relations.
+func (pk *ProvingKey) ProveLinearRelations(commitment bls12377.G1Affine, values []fr.Element, r fr.Element, relations []LinearRelation) (LinearRelationProof, error) {
+	var proof LinearRelationProof
+	for k := range relations {
+		if len(relations[k].Coefficients) != len(values) {
+			return proof, ErrNbValues
+		}
+		if l := innerProduct(relations[k].Coefficients, values); !l.Equal(&relations[k].Constant) {
+			return proof, ErrLinearRelationProof
+		}
+	}
+
+	var err error
+	proof.OpeningProof, proof.T, err = pk.proveOpening("linear relations", commitment, values, r, relations)
+	return proof, err
+}
+
+// VerifyLinearRelations verifies that the values committed to satisfy the relations.
+func (pk *ProvingKey) VerifyLinearRelations(commitment bls12377.G1Affine, relations []LinearRelation, proof LinearRelationProof) error {
+	if len(proof.Z) != len(pk.Basis) {
+		return ErrLinearRelationProof
+	}
+	if len(proof.T) != len(relations) {
+		return ErrNbRelations
+	}
+	h, err := pk.BlindingGenerator()
+	if err != nil {
+		return err
+	}
+	c, err := sigmaChallenge("linear relations", [][]bls12377.G1Affine{pk.Basis, {h, commitment, proof.A}}, relationsScalars(relations, proof.T)...)
+	if err != nil {
+		return err
+	}
+	if ok, err := checkSigma(pk.Basis, h, commitment, proof.A, proof.Z, proof.ZR, c); err != nil || !ok {
+		return ErrLinearRelationProof
+	}
+
+	// Σᵢ λᵢzᵢ = T + c*μ
+	for k := range relations {
+		if len(relations[k].Coefficients) != len(proof.Z) {
+			return ErrLinearRelationProof
+		}
+		lhs := innerProduct(relations[k].Coefficients, proof.Z)
+		var rhs fr.Element
+		rhs.Mul(&c, &relations[k].Constant).Add(&rhs, &proof.T[k])
+		if !lhs.Equal(&rhs) {
+			return ErrLinearRelationProof
+		}
+	}
+	return nil
+}
+
+// proveOpening builds an opening proof for the protocol. The returned
+// T[k] = Σᵢ relations[k].Coefficientsᵢ*aᵢ are bound to the transcript.
+func (pk *ProvingKey) proveOpening(protocol string, commitment bls12377.G1Affine, values []fr.Element, r fr.Element, relations []LinearRelation) (proof OpeningProof, t []fr.Element, err error) {
+
+	if len(values) != len(pk.Basis) {
+		err = ErrNbValues
+		return
+	}
+	h, err := pk.BlindingGenerator()
+	if err != nil {
+		return
+	}
+
+	// first message
+	a, err := randomVector(len(values))
+	if err != nil {
+		return
+	}
+	var b fr.Element
+	if _, err = b.SetRandom(); err != nil {
+		return
+	}
+	if _, err = proof.A.MultiExp(append(pk.Basis[:len(pk.Basis):len(pk.Basis)], h), append(a[:len(a):len(a)], b), ecc.MultiExpConfig{NbTasks: 1}); err != nil {
+		return
+	}
+
+	// challenge
+	t = make([]fr.Element, len(relations))
+	for k := range relations {
+		t[k] = innerProduct(relations[k].Coefficients, a)
+	}
+	c, err := sigmaChallenge(protocol, [][]bls12377.G1Affine{pk.Basis, {h, commitment, proof.A}}, relationsScalars(relations, t)...)
+	if err != nil {
+		return
+	}
+
+	// responses
+	proof.Z = make([]fr.Element, len(values))
+	for i := range values {
+		proof.Z[i].Mul(&c, &values[i]).Add(&proof.Z[i], &a[i])
+	}
+	proof.ZR.Mul(&c, &r).Add(&proof.ZR, &b)
+
+	return
+}
+
+// ProveEquality proves that commitment1 = pk1.CommitHiding(values, r1) and
+// commitment2 = pk2.CommitHiding(values, r2) commit to the same values.
+func ProveEquality(pk1, pk2 *ProvingKey, commitment1, commitment2 bls12377.G1Affine, values []fr.Element, r1, r2 fr.Element) (EqualityProof, error) {
+
+	var proof EqualityProof
+	if len(values) != len(pk1.Basis) || len(values) != len(pk2.Basis) {
+		return proof, ErrNbValues
+	}
+	h1, err := pk1.BlindingGenerator()
+	if err != nil {
+		return proof, err
+	}
+	h2, err := pk2.BlindingGenerator()
+	if err != nil {
+		return proof, err
+	}
+
+	// first messages, sharing the aᵢ
+	a, err := randomVector(len(values))
+	if err != nil {
+		return proof, err
+	}
+	var b1, b2 fr.Element
+	if _, err = b1.SetRandom(); err != nil {
+		return proof, err
+	}
+	if _, err = b2.SetRandom(); err != nil {
+		return proof, err
+	}
+	config := ecc.MultiExpConfig{NbTasks: 1}
+	if _, err = proof.A1.MultiExp(append(pk1.Basis[:len(pk1.Basis):len(pk1.Basis)], h1), append(a[:len(a):len(a)], b1), config); err != nil {
+		return proof, err
+	}
+	if _, err = proof.A2.MultiExp(append(pk2.Basis[:len(pk2.Basis):len(pk2.Basis)], h2), append(a[:len(a):len(a)], b2), config); err != nil {
+		return proof, err
+	}
+
+	c, err := sigmaChallenge("equality", [][]bls12377.G1Affine{pk1.Basis, pk2.Basis, {h1, h2, commitment1, commitment2, proof.A1, proof.A2}})
+	if err != nil {
+		return proof, err
+	}
+
+	proof.Z = make([]fr.Element, len(values))
+	for i := range values {
+		proof.Z[i].Mul(&c, &values[i]).Add(&proof.Z[i], &a[i])
+	}
+	proof.ZR1.Mul(&c, &r1).Add(&proof.ZR1, &b1)
+	proof.ZR2.Mul(&c, &r2).Add(&proof.ZR2, &b2)
+
+	return proof, nil
+}
+
+// VerifyEquality verifies that commitment1 (with pk1) and commitment2 (with pk2)
+// commit to the same values.
+func VerifyEquality(pk1, pk2 *ProvingKey, commitment1, commitment2 bls12377.G1Affine, proof EqualityProof) error {
+
+	if len(proof.Z) != len(pk1.Basis) || len(proof.Z) != len(pk2.Basis) {
+		return ErrEqualityProof
+	}
+	h1, err := pk1.BlindingGenerator()
+	if err != nil {
+		return err
+	}
+	h2, err := pk2.BlindingGenerator()
+	if err != nil {
+		return err
+	}
+
+	c, err := sigmaChallenge("equality", [][]bls12377.G1Affine{pk1.Basis, pk2.Basis, {h1, h2, commitment1, commitment2, proof.A1, proof.A2}})
+	if err != nil {
+		return err
+	}
+	if ok, err := checkSigma(pk1.Basis, h1, commitment1, proof.A1, proof.Z, proof.ZR1, c); err != nil || !ok {
+		return ErrEqualityProof
+	}
+	if ok, err := checkSigma(pk2.Basis, h2, commitment2, proof.A2, proof.Z, proof.ZR2, c); err != nil || !ok {
+		return ErrEqualityProof
+	}
+	return nil
+}
+
+// checkSigma checks that Σᵢ zᵢBasisᵢ + zr*H = A + c*C
+func checkSigma(basis []bls12377.G1Affine, h, commitment, a bls12377.G1Affine, z []fr.Element, zr, c fr.Element) (bool, error) {
+
+	if !commitment.IsInSubGroup() || !a.IsInSubGroup() {
+		return false, ErrSubgroupCheck
+	}
+
+	points := make([]bls12377.G1Affine, 0, len(basis)+3)
+	points = append(points, basis...)
+	points = append(points, h, commitment, a)
+	scalars := make([]fr.Element, 0, len(basis)+3)
+	scalars = append(scalars, z...)
+	var minusC, minusOne fr.Element
+	minusC.Neg(&c)
+	minusOne.SetOne()
+	minusOne.Neg(&minusOne)
+	scalars = append(scalars, zr, minusC, minusOne)
+
+	var res bls12377.G1Affine
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: 1}); err != nil {
+		return false, err
+	}
+	return res.IsInfinity(), nil
+}
+
+// sigmaChallenge derives the challenge of a Sigma protocol from the points and scalars
+func sigmaChallenge(protocol string, points [][]bls12377.G1Affine, scalars ...fr.Element) (c fr.Element, err error) {
+
+	fs := fiatshamir.NewTranscript(sha256.New(), "c")
+	if err = fs.Bind("c", []byte("pedersen "+protocol)); err != nil {
+		return
+	}
+	for i := range points {
+		for j := range points[i] {
+			b := points[i][j].RawBytes()
+			if err = fs.Bind("c", b[:]); err != nil {
+				return
+			}
+		}
+	}
+	for i := range scalars {
+		b := scalars[i].Bytes()
+		if err = fs.Bind("c", b[:]); err != nil {
+			return
+		}
+	}
+
+	b, err := fs.ComputeChallenge("c")
+	if err != nil {
+		return
+	}
+	c.SetBytes(b)
+	return
+}
+
+// relationsScalars returns the coefficients and constants of the relations, followed by t
+func relationsScalars(relations []LinearRelation, t []fr.Element) []fr.Element {
+	res := make([]fr.Element, 0)
+	for k := range relations {
+		res = append(res, relations[k].Coefficients...)
+		res = append(res, relations[k].Constant)
+	}
+	return append(res, t...)
+}
+
+// innerProduct returns Σᵢ aᵢbᵢ
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// randomVector returns a vector of n random elements
+func randomVector(n int) ([]fr.Element, error) {
+	res := make([]fr.Element, n)
+	for i := range res {
+		if _, err := res[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+)
+
+func TestLogUpVector(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookupVector := make(fr.Vector, 8)
+	fvector := make(fr.Vector, 13)
+	for i := 0; i < 8; i++ {
+		lookupVector[i].SetUint64(uint64(2 * i))
+	}
+	for i := 0; i < 13; i++ {
+		fvector[i].Set(&lookupVector[(4*i+1)%8])
+	}
+
+	// correct proof
+	proof, err := ProveLogUp(srs, []fr.Vector{fvector}, []fr.Vector{lookupVector})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyLogUp(srs, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	// tampered proofs
+	{
+		_proof := proof
+		_proof.BatchedProof.ClaimedValues = append([]fr.Element(nil), proof.BatchedProof.ClaimedValues...)
+		_proof.BatchedProof.ClaimedValues[2].SetOne() // wrong multiplicity
+		if err = VerifyLogUp(srs, _proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		_proof := proof
+		_proof.Ts = []kzg.Digest{proof.Fs[0]} // another table
+		if err = VerifyLogUp(srs, _proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		_proof := proof
+		_proof.Size *= 2 // another domain
+		if err = VerifyLogUp(srs, _proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+
+	// value not in the table
+	fvector[0].SetUint64(1)
+	if _, err = ProveLogUp(srs, []fr.Vector{fvector}, []fr.Vector{lookupVector}); err != ErrNotInTable {
+		t.Fatal("expected ErrNotInTable")
+	}
+}
+
+func TestLogUpTable(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// table of the XOR function on 2 bits, with a repeated row
+	lookupTable := make([]fr.Vector, 3)
+	fTable := make([]fr.Vector, 3)
+	for i := range lookupTable {
+		lookupTable[i] = make(fr.Vector, 17)
+		fTable[i] = make(fr.Vector, 5)
+	}
+	for j := 0; j < 16; j++ {
+		lookupTable[0][j].SetUint64(uint64(j >> 2))
+		lookupTable[1][j].SetUint64(uint64(j & 3))
+		lookupTable[2][j].SetUint64(uint64((j >> 2) ^ (j & 3)))
+	}
+	for i := range lookupTable {
+		lookupTable[i][16] = lookupTable[i][5]
+	}
+	for j := 0; j < 5; j++ {
+		for i := range fTable {
+			fTable[i][j] = lookupTable[i][(7*j+3)%17]
+		}
+	}
+
+	proof, err := ProveLogUp(srs, fTable, lookupTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyLogUp(srs, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	// the rows must be looked up as a whole
+	fTable[2][0].SetUint64(2)
+	fTable[2][0].Add(&fTable[2][0], &fTable[2][1])
+	if _, err = ProveLogUp(srs, fTable, lookupTable); err != ErrNotInTable {
+		t.Fatal("expected ErrNotInTable")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+var (
+	ErrInvalidThreshold = errors.New("the threshold must be between 1 and the number of shares")
+	ErrInvalidPoints    = errors.New("the evaluation points must be distinct and non zero")
+	ErrNbShares         = errors.New("unexpected number of shares")
+	ErrNbCommitments    = errors.New("unexpected number of commitments")
+)
+
+// Share is the share (X, Y = f(X)) of a secret f(0)
+type Share struct {
+	X, Y fr.Element
+}
+
+// Commitment is the Feldman commitment aᵢ⋅G to the coefficients aᵢ of a polynomial, where G is
+// the generator of G1
+type Commitment []bls12377.G1Affine
+
+// Split splits secret into n shares at the points 1, ..., n, any threshold of which
+// reconstruct it. It returns the shares and the random polynomial f of degree threshold-1
+// with f(0) = secret.
+func Split(secret *fr.Element, threshold, n int) ([]Share, polynomial.Polynomial, error) {
+	if n < 1 {
+		return nil, nil, ErrInvalidThreshold
+	}
+	xs := make([]fr.Element, n)
+	for i := range xs {
+		xs[i].SetUint64(uint64(i + 1))
+	}
+	return SplitAt(secret, threshold, xs)
+}
+
+// SplitAt splits secret into the shares at the points xs, any threshold of which
+// reconstruct it. The points must be distinct and non zero.
+// It returns the shares and the random polynomial f of degree threshold-1 with f(0) = secret.
+func SplitAt(secret *fr.Element, threshold int, xs []fr.Element) ([]Share, polynomial.Polynomial, error) {
+	if threshold < 1 || threshold > len(xs) {
+		return nil, nil, ErrInvalidThreshold
+	}
+	if err := checkPoints(xs); err != nil {
+		return nil, nil, err
+	}
+
+	f := make(polynomial.Polynomial, threshold)
+	f[0].Set(secret)
+	for i := 1; i < threshold; i++ {
+		if _, err := f[i].SetRandom(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	shares := make([]Share, len(xs))
+	for i := range xs {
+		shares[i].X.Set(&xs[i])
+		shares[i].Y = f.Eval(&xs[i])
+	}
+	return shares, f, nil
+}
+
+// Commit returns the Feldman commitment aᵢ⋅G to the coefficients of f
+func Commit(f polynomial.Polynomial) Commitment {
+	_, _, g1, _ := bls12377.Generators()
+	return bls12377.BatchScalarMultiplicationG1(&g1, f)
+}
+
+// Threshold returns the number of shares needed to reconstruct the committed secret
+func (c Commitment) Threshold() int {
+	return len(c)
+}
+
+// Eval returns f(x)⋅G = Σ xⁱ⋅aᵢ⋅G, the public share at x of the committed polynomial f
+func (c Commitment) Eval(x *fr.Element) (bls12377.G1Affine, error) {
+	var res bls12377.G1Affine
+	if len(c) == 0 {
+		return res, ErrNbCommitments
+	}
+	powers := make([]fr.Element, len(c))
+	powers[0].SetOne()
+	for i := 1; i < len(powers); i++ {
+		powers[i].Mul(&powers[i-1], x)
+	}
+	var resJac bls12377.G1Jac
+	if _, err := resJac.MultiExp(c, powers, ecc.MultiExpConfig{}); err != nil {
+		return res, err
+	}
+	res.FromJacobian(&resJac)
+	return res, nil
+}
+
+// Verify reports whether share is a share of the committed polynomial f, that is
+// Y⋅G = f(X)⋅G
+func (c Commitment) Verify(share *Share) (bool, error) {
+	expected, err := c.Eval(&share.X)
+	if err != nil {
+		return false, err
+	}
+	var y bls12377.G1Affine
+	y.ScalarMultiplicationBase(share.Y.BigInt(new(big.Int)))
+	return y.Equal(&expected), nil
+}
+
+// Reconstruct returns the secret f(0) interpolated from the shares. The result is
+// the secret only if the shares are at least the threshold.
+func Reconstruct(shares []Share) (fr.Element, error) {
+	var res fr.Element
+	if len(shares) == 0 {
+		return res, ErrNbShares
+	}
+	xs := make([]fr.Element, len(shares))
+	for i := range shares {
+		xs[i].Set(&shares[i].X)
+	}
+	lambdas, err := LagrangeCoefficients(xs)
+	if err != nil {
+		return res, err
+	}
+	var term fr.Element
+	for i := range shares {
+		term.Mul(&lambdas[i], &shares[i].Y)
+		res.Add(&res, &term)
+	}
+	return res, nil
+}
+
+// LagrangeCoefficients returns the coefficients λᵢ = Πⱼ≠ᵢ xⱼ/(xⱼ-xᵢ), such that
+// f(0) = Σ λᵢ⋅f(xᵢ) for any polynomial f of degree smaller than len(xs).
+// The points must be distinct and non zero.
+func LagrangeCoefficients(xs []fr.Element) ([]fr.Element, error) {
+	if err := checkPoints(xs); err != nil {
+		return nil, err
+	}
+
+	var product fr.Element
+	product.SetOne()
+	for i := range xs {
+		product.Mul(&product, &xs[i])
+	}
+
+	// λᵢ = Πⱼ xⱼ / (xᵢ⋅Πⱼ≠ᵢ (xⱼ-xᵢ))
+	denominators := make([]fr.Element, len(xs))
+	var diff fr.Element
+	for i := range xs {
+		denominators[i].Set(&xs[i])
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			diff.Sub(&xs[j], &xs[i])
+			denominators[i].Mul(&denominators[i], &diff)
+		}
+	}
+	res := fr.BatchInvert(denominators)
+	for i := range res {
+		res[i].Mul(&res[i], &product)
+	}
+	return res, nil
+}
+
+// Reshare splits share among the participants at the points xs with a new threshold, for a
+// proactive refresh (same points and threshold) or a redistribution of the secret. The
+// commitment to the returned polynomial must be published along with the sub-shares, and its
+// constant term checked against the public share of the old participant (VerifyReshare).
+func Reshare(share *Share, threshold int, xs []fr.Element) ([]Share, polynomial.Polynomial, error) {
+	return SplitAt(&share.Y, threshold, xs)
+}
+
+// VerifyReshare reports whether the commitment reshare of the polynomial resharing the share
+// at x is consistent with the commitment c of the old polynomial, that is f'(0)⋅G = f(x)⋅G
+func (c Commitment) VerifyReshare(x *fr.Element, reshare Commitment) (bool, error) {
+	if len(reshare) == 0 {
+		return false, ErrNbCommitments
+	}
+	expected, err := c.Eval(x)
+	if err != nil {
+		return false, err
+	}
+	return reshare[0].Equal(&expected), nil
+}
+
+// CombineReshares returns the new share of a participant from the sub-shares it received
+// from old participants, subShares[i] being the sub-share of the share at oldXs[i]. There must
+// be at least the old threshold of them, all at the same point.
+//
+// The new share is Σ λᵢ⋅subShares[i].Y, with the Lagrange coefficients of oldXs.
+func CombineReshares(oldXs []fr.Element, subShares []Share) (Share, error) {
+	var res Share
+	if len(subShares) != len(oldXs) || len(subShares) == 0 {
+		return res, ErrNbShares
+	}
+	lambdas, err := LagrangeCoefficients(oldXs)
+	if err != nil {
+		return res, err
+	}
+	res.X.Set(&subShares[0].X)
+	var term fr.Element
+	for i := range subShares {
+		if !subShares[i].X.Equal(&res.X) {
+			return res, ErrInvalidPoints
+		}
+		term.Mul(&lambdas[i], &subShares[i].Y)
+		res.Y.Add(&res.Y, &term)
+	}
+	return res, nil
+}
+
+// CombineCommitments returns the commitment to the new polynomial of a resharing,
+// Σ λᵢ⋅commitments[i], commitments[i] being the commitment to the polynomial resharing the
+// share at oldXs[i]. See CombineReshares.
+func CombineCommitments(oldXs []fr.Element, commitments []Commitment) (Commitment, error) {
+	if len(commitments) != len(oldXs) || len(commitments) == 0 {
+		return nil, ErrNbCommitments
+	}
+	lambdas, err := LagrangeCoefficients(oldXs)
+	if err != nil {
+		return nil, err
+	}
+	threshold := len(commitments[0])
+	points := make([]bls12377.G1Affine, len(commitments))
+	res := make(Commitment, threshold)
+	for k := 0; k < threshold; k++ {
+		for i := range commitments {
+			if len(commitments[i]) != threshold {
+				return nil, ErrNbCommitments
+			}
+			points[i] = commitments[i][k]
+		}
+		if _, err = res[k].MultiExp(points, lambdas, ecc.MultiExpConfig{}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// checkPoints checks that the points are distinct and non zero
+func checkPoints(xs []fr.Element) error {
+	seen := make(map[fr.Element]struct{}, len(xs))
+	for i := range xs {
+		if xs[i].IsZero() {
+			return ErrInvalidPoints
+		}
+		if _, ok := seen[xs[i]]; ok {
+			return ErrInvalidPoints
+		}
+		seen[xs[i]] = struct{}{}
+	}
+	return nil
+}
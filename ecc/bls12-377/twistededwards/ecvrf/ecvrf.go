@@ -0,0 +1,476 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecvrf
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	sizeFr = fr.Bytes
+
+	// sizePoint is ptLen, the size of a compressed point
+	sizePoint = fr.Bytes
+
+	// sizeChallenge is cLen, the size of the challenge
+	sizeChallenge = 16
+
+	sizePublicKey  = sizePoint
+	sizePrivateKey = sizePublicKey + sizeFr + 32
+	sizeProof      = sizePoint + sizeChallenge + sizeFr
+)
+
+// suite strings, see the package documentation
+const (
+	suiteELL2      = 0x10
+	suiteTAI       = 0x11
+	suiteFieldHash = 0x12
+)
+
+// domain separators of the hashes (RFC 9381 Section 5.4)
+const (
+	domainEncodeToCurve = 0x01
+	domainChallenge     = 0x02
+	domainProofToHash   = 0x03
+	domainBack          = 0x00
+)
+
+// maskTAI clears the bits of the candidates of the try-and-increment encoding that are above
+// the size of the modulus, apart from the sign bit, so that most candidates encode a field element
+const maskTAI = 0x80 | (1<<(fr.Bits%8) - 1)
+
+// h2cSuiteID is the identifier of the RFC 9380 suite of EncodeToCurve, which is part
+// of the DST of the ELL2 suite
+const h2cSuiteID = "bls12-377-twistededwards_XMD:SHA-256_ELL2_NU_"
+
+var (
+	errNotOnCurve       = errors.New("point not on curve")
+	errInvalidKey       = errors.New("the public key is not in the prime order subgroup or is the identity")
+	errNilFieldHash     = errors.New("the field hash function must not be nil")
+	errTAIFieldHash     = errors.New("the try-and-increment encoding can't be used with a field hash")
+	errEncodeToCurve    = errors.New("no valid point found by try-and-increment")
+	errFieldHashInput   = errors.New("with a field hash, the input must be a sequence of field elements")
+	errInconsistentSize = errors.New("inconsistent number of public keys, inputs and proofs")
+)
+
+// PublicKey is a VRF public key Y = x⋅Base
+type PublicKey struct {
+	A twistededwards.PointAffine
+}
+
+// PrivateKey is a VRF private key
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar x, in big endian
+	randSrc   [32]byte     // source of the nonces
+}
+
+// Proof is a VRF proof π = (Γ, c, s)
+type Proof struct {
+	Gamma twistededwards.PointAffine
+	C     [sizeChallenge]byte
+	S     [sizeFr]byte
+}
+
+// Option selects the suite of the VRF, see the package documentation. The same options
+// must be given to PrivateKey.Prove, PublicKey.Verify and Proof.Output.
+type Option func(*config) error
+
+type config struct {
+	// tryAndIncrement selects the TAI encoding of the input
+	tryAndIncrement bool
+
+	// fieldHash, if not nil, replaces SHA-512 in the encoding of the input, the challenge and the output
+	fieldHash func() hash.Hash
+}
+
+// WithTryAndIncrement encodes the input to the curve with the try-and-increment method
+// (RFC 9381 Section 5.4.1.1) instead of Elligator 2. The encoding is not constant time.
+func WithTryAndIncrement() Option {
+	return func(cfg *config) error {
+		cfg.tryAndIncrement = true
+		return nil
+	}
+}
+
+// WithFieldHash selects the SNARK friendly suite, where the hashes are computed with the
+// hash function h on field elements, typically hash.MIMC_BLS12_377.New. The inputs of the hashes
+// are the domain separator suite_string⋅2⁸ + domain and the affine coordinates of the points,
+// as field elements in big endian:
+//
+//	H = MapToCurve(h(0x1201, Y, alpha))
+//	c = h(0x1202, Y, H, Γ, U, V) mod 2¹²⁸
+//	beta = h(0x1203, cofactor⋅Γ)
+//
+// alpha must then be a sequence of field elements in big endian.
+func WithFieldHash(h func() hash.Hash) Option {
+	return func(cfg *config) error {
+		if h == nil {
+			return errNilFieldHash
+		}
+		cfg.fieldHash = h
+		return nil
+	}
+}
+
+func options(opts ...Option) (config, error) {
+	var cfg config
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.tryAndIncrement && cfg.fieldHash != nil {
+		return cfg, errTAIFieldHash
+	}
+	return cfg, nil
+}
+
+func (cfg *config) suite() byte {
+	switch {
+	case cfg.fieldHash != nil:
+		return suiteFieldHash
+	case cfg.tryAndIncrement:
+		return suiteTAI
+	default:
+		return suiteELL2
+	}
+}
+
+// GenerateKey generates a public and private key pair: SHA-512 of 32 bytes read from r
+// gives the secret scalar x (the first half, modulo the order) and the source of the nonces.
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	seed := make([]byte, 32)
+	if _, err := io.ReadFull(r, seed); err != nil {
+		return nil, err
+	}
+	h := sha512.Sum512(seed)
+
+	var priv PrivateKey
+	var x big.Int
+	x.SetBytes(h[:32]).Mod(&x, &curveParams.Order)
+	x.FillBytes(priv.scalar[:])
+	copy(priv.randSrc[:], h[32:])
+	priv.PublicKey.A.ScalarMultiplication(&curveParams.Base, &x)
+
+	return &priv, nil
+}
+
+// Prove returns the proof that Proof.Output is the VRF output of privKey on alpha
+// (RFC 9381 Section 5.1):
+//
+//	H = encode_to_curve(Y, alpha), Γ = x⋅H
+//	k = SHA-512(randSrc || H) mod order
+//	c = challenge(Y, H, Γ, k⋅Base, k⋅H), s = k + c⋅x mod order
+func (privKey *PrivateKey) Prove(alpha []byte, opts ...Option) (*Proof, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return nil, err
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	Y := &privKey.PublicKey.A
+	H, err := cfg.encodeToCurve(Y, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	var x big.Int
+	x.SetBytes(privKey.scalar[:])
+	var res Proof
+	res.Gamma.ScalarMultiplication(&H, &x)
+
+	// nonce generation of RFC 9381 Section 5.4.2.2
+	hString := H.Bytes()
+	h := sha512.New()
+	h.Write(privKey.randSrc[:])
+	h.Write(hString[:])
+	var k big.Int
+	k.SetBytes(h.Sum(nil)).Mod(&k, &curveParams.Order)
+
+	var U, V twistededwards.PointAffine
+	U.ScalarMultiplication(&curveParams.Base, &k)
+	V.ScalarMultiplication(&H, &k)
+	if res.C, err = cfg.challenge(Y, &H, &res.Gamma, &U, &V); err != nil {
+		return nil, err
+	}
+
+	var s big.Int
+	s.SetBytes(res.C[:]).
+		Mul(&s, &x).
+		Add(&s, &k).
+		Mod(&s, &curveParams.Order)
+	s.FillBytes(res.S[:])
+
+	return &res, nil
+}
+
+// Verify reports whether proof is a valid proof of the VRF output of pub on alpha
+// (RFC 9381 Section 5.3):
+//
+//	U = s⋅Base - c⋅Y, V = s⋅H - c⋅Γ, c ?= challenge(Y, H, Γ, U, V)
+//
+// It returns an error if pub is not in the prime order subgroup or is the identity.
+// Γ must be in the prime order subgroup, which is the case of the honestly generated proofs.
+func (pub *PublicKey) Verify(alpha []byte, proof *Proof, opts ...Option) (bool, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return false, err
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	Y := &pub.A
+	if Y.IsZero() || !Y.IsInSubGroup() {
+		return false, errInvalidKey
+	}
+	if !proof.Gamma.IsInSubGroup() {
+		return false, nil
+	}
+	var c, s big.Int
+	c.SetBytes(proof.C[:])
+	s.SetBytes(proof.S[:])
+	if s.Cmp(&curveParams.Order) >= 0 {
+		return false, nil
+	}
+
+	H, err := cfg.encodeToCurve(Y, alpha)
+	if err != nil {
+		return false, err
+	}
+
+	// U = s⋅Base - c⋅Y
+	var U, cY twistededwards.PointAffine
+	U.ScalarMultiplication(&curveParams.Base, &s)
+	cY.ScalarMultiplication(Y, &c)
+	cY.Neg(&cY)
+	U.Add(&U, &cY)
+
+	// V = s⋅H - c⋅Γ
+	var V, cGamma twistededwards.PointAffine
+	V.ScalarMultiplication(&H, &s)
+	cGamma.ScalarMultiplication(&proof.Gamma, &c)
+	cGamma.Neg(&cGamma)
+	V.Add(&V, &cGamma)
+
+	expected, err := cfg.challenge(Y, &H, &proof.Gamma, &U, &V)
+	if err != nil {
+		return false, err
+	}
+	return expected == proof.C, nil
+}
+
+// BatchVerify reports whether all the proofs[i] are valid proofs of the VRF outputs of
+// publicKeys[i] on alphas[i], as in PublicKey.Verify. The proofs are verified in parallel.
+//
+// If a verification fails with an error, it returns an error wrapping the index of the
+// first failing proof.
+func BatchVerify(publicKeys []PublicKey, alphas [][]byte, proofs []Proof, opts ...Option) (bool, error) {
+	n := len(proofs)
+	if len(publicKeys) != n || len(alphas) != n {
+		return false, errInconsistentSize
+	}
+	if _, err := options(opts...); err != nil {
+		return false, err
+	}
+
+	valid := make([]bool, n)
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			valid[i], errs[i] = publicKeys[i].Verify(alphas[i], &proofs[i], opts...)
+		}
+	})
+	for i := range errs {
+		if errs[i] != nil {
+			return false, fmt.Errorf("proof %d: %w", i, errs[i])
+		}
+	}
+	for i := range valid {
+		if !valid[i] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Output returns the VRF output beta of the proof (RFC 9381 Section 5.2):
+//
+//	beta = SHA-512(suite_string || 0x03 || cofactor⋅Γ || 0x00)
+//
+// The output is only meaningful once the proof has been verified.
+func (proof *Proof) Output(opts ...Option) ([]byte, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return nil, err
+	}
+	gamma := mulByCofactor(&proof.Gamma)
+	if cfg.fieldHash != nil {
+		return cfg.fieldDigest(domainProofToHash, nil, &gamma)
+	}
+
+	h := sha512.New()
+	h.Write([]byte{cfg.suite(), domainProofToHash})
+	gammaString := gamma.Bytes()
+	h.Write(gammaString[:])
+	h.Write([]byte{domainBack})
+	return h.Sum(nil), nil
+}
+
+// encodeToCurve returns the point H of the input alpha of the public key Y, in the
+// prime order subgroup (RFC 9381 Section 5.4.1)
+func (cfg *config) encodeToCurve(Y *twistededwards.PointAffine, alpha []byte) (twistededwards.PointAffine, error) {
+	switch {
+	case cfg.fieldHash != nil:
+		if len(alpha)%fr.Bytes != 0 {
+			return twistededwards.PointAffine{}, errFieldHashInput
+		}
+		digest, err := cfg.fieldDigest(domainEncodeToCurve, alpha, Y)
+		if err != nil {
+			return twistededwards.PointAffine{}, err
+		}
+		var u fr.Element
+		u.SetBytes(digest)
+		return twistededwards.MapToCurve(u), nil
+
+	case cfg.tryAndIncrement:
+		// H = cofactor⋅string_to_point(SHA-512(suite_string || 0x01 || Y || alpha || ctr || 0x00)[:ptLen]),
+		// the most significant byte of the candidate being masked with maskTAI
+		yString := Y.Bytes()
+		h := sha512.New()
+		var H twistededwards.PointAffine
+		for ctr := 0; ctr < 256; ctr++ {
+			h.Reset()
+			h.Write([]byte{suiteTAI, domainEncodeToCurve})
+			h.Write(yString[:])
+			h.Write(alpha)
+			h.Write([]byte{byte(ctr), domainBack})
+			candidate := h.Sum(nil)[:sizePoint]
+			candidate[sizePoint-1] &= maskTAI
+			if !stringToPoint(&H, candidate) {
+				continue
+			}
+			H = mulByCofactor(&H)
+			if !H.IsZero() {
+				return H, nil
+			}
+		}
+		return H, errEncodeToCurve
+
+	default:
+		// encode_to_curve(Y || alpha) with the DST "ECVRF_" || h2c_suite_ID_string || suite_string
+		yString := Y.Bytes()
+		msg := make([]byte, 0, sizePoint+len(alpha))
+		msg = append(msg, yString[:]...)
+		msg = append(msg, alpha...)
+		dst := append([]byte("ECVRF_"+h2cSuiteID), suiteELL2)
+		return twistededwards.EncodeToCurve(msg, dst)
+	}
+}
+
+// challenge returns c = SHA-512(suite_string || 0x02 || points || 0x00)[:cLen]
+// (RFC 9381 Section 5.4.3), or the last cLen bytes of the field hash
+func (cfg *config) challenge(points ...*twistededwards.PointAffine) ([sizeChallenge]byte, error) {
+	var c [sizeChallenge]byte
+	if cfg.fieldHash != nil {
+		digest, err := cfg.fieldDigest(domainChallenge, nil, points...)
+		if err != nil {
+			return c, err
+		}
+		copy(c[:], digest[len(digest)-sizeChallenge:])
+		return c, nil
+	}
+
+	h := sha512.New()
+	h.Write([]byte{cfg.suite(), domainChallenge})
+	for _, p := range points {
+		pString := p.Bytes()
+		h.Write(pString[:])
+	}
+	h.Write([]byte{domainBack})
+	copy(c[:], h.Sum(nil))
+	return c, nil
+}
+
+// fieldDigest returns the field hash of the domain separator suite_string⋅2⁸ + domain, the
+// affine coordinates of the points and data
+func (cfg *config) fieldDigest(domain byte, data []byte, points ...*twistededwards.PointAffine) ([]byte, error) {
+	h := cfg.fieldHash()
+
+	var prefix fr.Element
+	prefix.SetUint64(uint64(cfg.suite())<<8 | uint64(domain))
+	prefixBytes := prefix.Bytes()
+	if _, err := h.Write(prefixBytes[:]); err != nil {
+		return nil, err
+	}
+	for _, p := range points {
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		if _, err := h.Write(x[:]); err != nil {
+			return nil, err
+		}
+		if _, err := h.Write(y[:]); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) > 0 {
+		if _, err := h.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// stringToPoint sets p to the point of compressed encoding buf, and reports whether buf
+// is the canonical encoding of a point on the curve
+func stringToPoint(p *twistededwards.PointAffine, buf []byte) bool {
+	if _, err := p.SetBytes(buf); err != nil || !p.IsOnCurve() {
+		return false
+	}
+	pString := p.Bytes()
+	return string(pString[:]) == string(buf)
+}
+
+// mulByCofactor returns cofactor⋅p. It uses the double-and-add algorithm on projective
+// coordinates, as the faster scalar multiplications assume their input is in the subgroup.
+func mulByCofactor(p *twistededwards.PointAffine) twistededwards.PointAffine {
+	curveParams := twistededwards.GetEdwardsCurve()
+	var cofactor big.Int
+	curveParams.Cofactor.BigInt(&cofactor)
+
+	var q, res twistededwards.PointProj
+	q.FromAffine(p)
+	res.Set(&q)
+	for i := cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(&res)
+		if cofactor.Bit(i) == 1 {
+			res.Add(&res, &q)
+		}
+	}
+	var r twistededwards.PointAffine
+	r.FromProj(&res)
+	return r
+}
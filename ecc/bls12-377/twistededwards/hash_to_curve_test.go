@@ -0,0 +1,204 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// hasPrimeOrder returns true if [order]p is the identity. It uses the
+// double-and-add algorithm, as the faster scalar multiplications assume their
+// input is in the prime order subgroup.
+func hasPrimeOrder(p *PointAffine) bool {
+	params := GetEdwardsCurve()
+	var q, res PointProj
+	q.FromAffine(p)
+	res.setInfinity()
+	for i := params.Order.BitLen() - 1; i >= 0; i-- {
+		res.Double(&res)
+		if params.Order.Bit(i) == 1 {
+			res.Add(&res, &q)
+		}
+	}
+	return res.IsZero()
+}
+
+func TestHasPrimeOrder(t *testing.T) {
+	params := GetEdwardsCurve()
+	if !hasPrimeOrder(&params.Base) {
+		t.Fatal("the base point should be in the prime order subgroup")
+	}
+
+	// (0, -1) is of order 2
+	var t2, p PointAffine
+	t2.X.SetZero()
+	t2.Y.SetOne().Neg(&t2.Y)
+	if hasPrimeOrder(&t2) {
+		t.Fatal("(0, -1) should not be in the prime order subgroup")
+	}
+	p.Add(&params.Base, &t2)
+	if !p.IsOnCurve() || hasPrimeOrder(&p) {
+		t.Fatal("base + (0, -1) should not be in the prime order subgroup")
+	}
+}
+
+func TestMapToCurve(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[Elligator 2] the output should be on the Montgomery curve", prop.ForAll(
+		func(b big.Int) bool {
+			elligatorOnce.Do(initElligatorParams)
+			var u fr.Element
+			u.SetBigInt(&b)
+			s, t := mapToMontgomery(&u)
+
+			// K*t^2 = s^3 + J*s^2 + s
+			var j, lhs, rhs fr.Element
+			j.Mul(&elligatorParams.jOverK, &elligatorParams.k)
+			lhs.Square(&t).Mul(&lhs, &elligatorParams.k)
+			rhs.Add(&s, &j).Mul(&rhs, &s).Add(&rhs, new(fr.Element).SetOne()).Mul(&rhs, &s)
+			return lhs.Equal(&rhs)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("[Elligator 2] the output should be on the twisted Edwards curve", prop.ForAll(
+		func(b big.Int) bool {
+			elligatorOnce.Do(initElligatorParams)
+			var u fr.Element
+			u.SetBigInt(&b)
+			q := mapToCurve(&u)
+			var p PointAffine
+			p.FromProj(&q)
+			return p.IsOnCurve()
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("[MapToCurve] the output should be in the prime order subgroup", prop.ForAll(
+		func(b big.Int) bool {
+			var u fr.Element
+			u.SetBigInt(&b)
+			p := MapToCurve(u)
+			return p.IsOnCurve() && !p.IsZero() && hasPrimeOrder(&p)
+		},
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestMapToCurveExceptionalCases(t *testing.T) {
+	// (0, 0), of order 2, is mapped to the identity
+	var zero fr.Element
+	q := montgomeryToEdwards(&zero, &zero)
+	if !q.IsZero() {
+		t.Fatal("t = 0 should map to the identity")
+	}
+
+	// s = -1 is mapped to the identity
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	q = montgomeryToEdwards(&minusOne, new(fr.Element).SetOne())
+	if !q.IsZero() {
+		t.Fatal("s = -1 should map to the identity")
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+	t.Parallel()
+	dst := []byte("QUUX-V01-CS02-with-bls12-377_XMD:SHA-256_ELL2_RO_")
+
+	msgs := [][]byte{[]byte(""), []byte("abc"), []byte("abcdef0123456789")}
+	for _, msg := range msgs {
+		p, err := HashToCurve(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.IsOnCurve() || p.IsZero() || !hasPrimeOrder(&p) {
+			t.Fatal("HashToCurve: the output should be a non-zero point of the prime order subgroup")
+		}
+
+		// deterministic
+		p2, err := HashToCurve(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.Equal(&p2) {
+			t.Fatal("HashToCurve should be deterministic")
+		}
+
+		// domain separation
+		p2, err = HashToCurve(msg, []byte("another DST"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Equal(&p2) {
+			t.Fatal("HashToCurve should depend on the DST")
+		}
+
+		e, err := EncodeToCurve(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.IsOnCurve() || e.IsZero() || !hasPrimeOrder(&e) {
+			t.Fatal("EncodeToCurve: the output should be a non-zero point of the prime order subgroup")
+		}
+
+		// EncodeToCurve(msg) = MapToCurve(hash(msg))
+		u, err := fr.Hash(msg, dst, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2 = MapToCurve(u[0])
+		if !e.Equal(&p2) {
+			t.Fatal("EncodeToCurve should map the hash of the message")
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-bls12-377_XMD:SHA-256_ELL2_RO_")
+	msg := []byte("abcdef0123456789")
+	b.Run("hash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := HashToCurve(msg, dst); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := EncodeToCurve(msg, dst); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -17,8 +17,12 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/leanovate/gopter"
@@ -62,3 +66,98 @@ func TestSerialization(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+func TestSerializationSignature(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS12-378] ECDSA DER serialization: SetBytesDER(BytesDER()) should stay the same", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			sigBin, _ := privKey.Sign([]byte("testing ECDSA"), sha256.New())
+			var sig, end Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+
+			der := sig.BytesDER()
+			n, err := end.SetBytesDER(der)
+			if err != nil || n != len(der) {
+				return false
+			}
+
+			// trailing data is not read
+			n, err = end.SetBytesDER(append(der, 0x00))
+			if err != nil || n != len(der) {
+				return false
+			}
+
+			return bytes.Equal(end.Bytes(), sigBin)
+		},
+	))
+
+	properties.Property("[BLS12-378] ECDSA low-S normalization should give a valid signature", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			msg := []byte("testing ECDSA")
+			sigBin, _ := privKey.Sign(msg, sha256.New())
+			var sig Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+			wasLow := sig.IsLowS()
+			sig.NormalizeS()
+			if !sig.IsLowS() || wasLow != bytes.Equal(sig.Bytes(), sigBin) {
+				return false
+			}
+			flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, sha256.New())
+			return flag
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestSetBytesDERInvalid(t *testing.T) {
+	t.Parallel()
+
+	// r doesn't fit in sizeFr bytes
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 8*sizeFr)
+	tooLargeBin := append([]byte{0x02, byte(len(tooLarge.Bytes()))}, tooLarge.Bytes()...)
+	tooLargeDER := append([]byte{0x30, byte(len(tooLargeBin) + 3)}, tooLargeBin...)
+	tooLargeDER = append(tooLargeDER, 0x02, 0x01, 0x01)
+
+	for _, c := range []struct {
+		name, der string
+		valid     bool
+	}{
+		{"valid", "3006020101020101", true},
+		{"long form length", "308106020101020101", false},
+		{"indefinite length", "3080020101020101", false},
+		{"non-minimal integer", "300702020001020101", false},
+		{"negative integer", "30060201ff020101", false},
+		{"trailing data in sequence", "30080201010201010500", false},
+		{"missing s", "3003020101", false},
+		{"wrong tag", "3106020101020101", false},
+		{"truncated", "30060201010201", false},
+		{"empty", "", false},
+		{"too large", hex.EncodeToString(tooLargeDER), false},
+	} {
+		der, err := hex.DecodeString(c.der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig Signature
+		_, err = sig.SetBytesDER(der)
+		if (err == nil) != c.valid {
+			t.Fatalf("%s: expected valid=%v, got error %v", c.name, c.valid, err)
+		}
+	}
+}
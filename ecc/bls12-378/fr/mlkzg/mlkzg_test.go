@@ -0,0 +1,334 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mlkzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/polynomial"
+)
+
+const testNbVars = 6
+
+// testSRS re-used accross tests of the multilinear KZG scheme
+var testSRS *SRS
+
+func init() {
+	testSRS, _ = NewSRS(testNbVars, testTau(testNbVars))
+}
+
+func testTau(nbVars int) []*big.Int {
+	tau := make([]*big.Int, nbVars)
+	for i := range tau {
+		tau[i] = big.NewInt(int64(42 + i))
+	}
+	return tau
+}
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	f := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range f {
+		f[i].SetRandom()
+	}
+	return f
+}
+
+func randomPoint(nbVars int) []fr.Element {
+	point := make([]fr.Element, nbVars)
+	for i := range point {
+		point[i].SetRandom()
+	}
+	return point
+}
+
+func TestSerializationSRS(t *testing.T) {
+
+	// create a SRS
+	srs, err := NewSRS(3, testTau(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serialize it...
+	var buf bytes.Buffer
+	_, err = srs.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reconstruct the SRS
+	var _srs SRS
+	_, err = _srs.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compare
+	if !reflect.DeepEqual(srs, &_srs) {
+		t.Fatal("scheme serialization failed")
+	}
+
+}
+
+func TestCommit(t *testing.T) {
+
+	f := randomMultiLin(testNbVars)
+
+	digest, err := Commit(f, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check commitment using manual commit [f(τ)]G₁
+	tau := make([]fr.Element, testNbVars)
+	for i, b := range testTau(testNbVars) {
+		tau[i].SetBigInt(b)
+	}
+	fTau := f.Evaluate(tau, nil)
+	var fTauBigInt big.Int
+	fTau.BigInt(&fTauBigInt)
+	var manualCommit Digest
+	manualCommit.ScalarMultiplication(&testSRS.G1[testNbVars][0], &fTauBigInt)
+
+	if !digest.Equal(&manualCommit) {
+		t.Fatal("error multilinear KZG commitment")
+	}
+
+	if _, err := Commit(f[:len(f)/2], testSRS); err == nil {
+		t.Fatal("committing to a polynomial of the wrong size should fail")
+	}
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+
+	f := randomMultiLin(testNbVars)
+
+	digest, err := Commit(f, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	point := randomPoint(testNbVars)
+	proof, err := Open(f, point, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed valued
+	expected := f.Evaluate(point, nil)
+	if !proof.ClaimedValue.Equal(&expected) {
+		t.Fatal("inconsistant claimed value")
+	}
+
+	// verify correct proof
+	if err = Verify(&digest, &proof, point, testSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	// serialization round trip
+	var buf bytes.Buffer
+	if _, err = proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _proof OpeningProof
+	if _, err = _proof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("proof serialization failed")
+	}
+
+	{
+		// verify proof at another point
+		otherPoint := randomPoint(testNbVars)
+		if err = Verify(&digest, &proof, otherPoint, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		// verify wrong proof
+		proof.ClaimedValue.Double(&proof.ClaimedValue)
+		if err = Verify(&digest, &proof, point, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		// verify wrong proof with quotients set to zero
+		for i := range proof.Quotients {
+			proof.Quotients[i].X.SetZero()
+			proof.Quotients[i].Y.SetZero()
+		}
+		if err = Verify(&digest, &proof, point, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+
+	const nbPolynomials = 5
+
+	f := make([]polynomial.MultiLin, nbPolynomials)
+	digests := make([]Digest, nbPolynomials)
+	for i := range f {
+		f[i] = randomMultiLin(testNbVars)
+		digests[i], _ = Commit(f[i], testSRS)
+	}
+
+	// pick a hash function
+	hf := sha256.New()
+
+	point := randomPoint(testNbVars)
+	proof, err := BatchOpenSinglePoint(f, digests, point, hf, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := range f {
+		expectedClaim := f[i].Evaluate(point, nil)
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	if err = BatchVerifySinglePoint(digests, &proof, point, hf, testSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		// verify wrong proof
+		proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+		if err = BatchVerifySinglePoint(digests, &proof, point, hf, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+}
+
+func TestBatchVerifySinglePointForgedClaims(t *testing.T) {
+
+	const nbPolynomials = 2
+
+	f := make([]polynomial.MultiLin, nbPolynomials)
+	digests := make([]Digest, nbPolynomials)
+	for i := range f {
+		f[i] = randomMultiLin(testNbVars)
+		digests[i], _ = Commit(f[i], testSRS)
+	}
+	hf := sha256.New()
+	point := randomPoint(testNbVars)
+	proof, err := BatchOpenSinglePoint(f, digests, point, hf, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// shift the claimed values so that f₀(z) + γ⋅f₁(z) is unchanged for the γ of the
+	// honest proof: (f₀(z)+γ, f₁(z)-1)
+	gamma, err := deriveGamma(point, digests, proof.ClaimedValues, hf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var one fr.Element
+	one.SetOne()
+	proof.ClaimedValues[0].Add(&proof.ClaimedValues[0], &gamma)
+	proof.ClaimedValues[1].Sub(&proof.ClaimedValues[1], &one)
+	if err = BatchVerifySinglePoint(digests, &proof, point, hf, testSRS); err == nil {
+		t.Fatal("forged claimed values should be rejected")
+	}
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+
+	const nbPolynomials = 4
+
+	digests := make([]Digest, nbPolynomials)
+	proofs := make([]OpeningProof, nbPolynomials)
+	points := make([][]fr.Element, nbPolynomials)
+	for i := range digests {
+		f := randomMultiLin(testNbVars)
+		digests[i], _ = Commit(f, testSRS)
+		points[i] = randomPoint(testNbVars)
+		proofs[i], _ = Open(f, points[i], testSRS)
+	}
+
+	// batch verify correct proofs
+	if err := BatchVerifyMultiPoints(digests, proofs, points, testSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		// batch verify tampered proofs
+		points[1], points[2] = points[2], points[1]
+		if err := BatchVerifyMultiPoints(digests, proofs, points, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+		points[1], points[2] = points[2], points[1]
+	}
+	{
+		proofs[0].ClaimedValue.Double(&proofs[0].ClaimedValue)
+		if err := BatchVerifyMultiPoints(digests, proofs, points, testSRS); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+
+}
+
+const benchNbVars = 14
+
+func BenchmarkMLKZGOpen(b *testing.B) {
+	benchSRS, err := NewSRS(benchNbVars, testTau(benchNbVars))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	f := randomMultiLin(benchNbVars)
+	point := randomPoint(benchNbVars)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(f, point, benchSRS)
+	}
+}
+
+func BenchmarkMLKZGVerify(b *testing.B) {
+	benchSRS, err := NewSRS(benchNbVars, testTau(benchNbVars))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	f := randomMultiLin(benchNbVars)
+	point := randomPoint(benchNbVars)
+	digest, err := Commit(f, benchSRS)
+	if err != nil {
+		b.Fatal(err)
+	}
+	proof, err := Open(f, point, benchSRS)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&digest, &proof, point, benchSRS)
+	}
+}
@@ -17,6 +17,7 @@
 package pedersen
 
 import (
+	"bytes"
 	"github.com/consensys/gnark-crypto/ecc/bls12-378"
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
 	"github.com/stretchr/testify/assert"
@@ -52,29 +53,37 @@ func randomOnG1() (bls12378.G1Affine, error) { // TODO: Add to G1.go?
 	return bls12378.HashToG1(gBytes, []byte("random on g2"))
 }
 
-func testCommit(t *testing.T, values ...interface{}) {
-
-	basis := make([]bls12378.G1Affine, len(values))
+func randomBasis(t *testing.T, size int) []bls12378.G1Affine {
+	basis := make([]bls12378.G1Affine, size)
 	for i := range basis {
 		var err error
 		basis[i], err = randomOnG1()
 		assert.NoError(t, err)
 	}
+	return basis
+}
+
+func testCommit(t *testing.T, values ...interface{}) {
+
+	basis := randomBasis(t, len(values))
 
 	var (
-		key             Key
+		pk              []ProvingKey
+		vk              VerifyingKey
 		err             error
 		commitment, pok bls12378.G1Affine
 	)
 
-	key, err = Setup(basis)
+	pk, vk, err = Setup([][]bls12378.G1Affine{basis})
 	assert.NoError(t, err)
-	commitment, pok, err = key.Commit(interfaceSliceToFrSlice(t, values...))
+	commitment, err = pk[0].Commit(interfaceSliceToFrSlice(t, values...))
 	assert.NoError(t, err)
-	assert.NoError(t, key.VerifyKnowledgeProof(commitment, pok))
+	pok, err = pk[0].ProveKnowledge(interfaceSliceToFrSlice(t, values...))
+	assert.NoError(t, err)
+	assert.NoError(t, vk.Verify(commitment, pok))
 
 	pok.Neg(&pok)
-	assert.NotNil(t, key.VerifyKnowledgeProof(commitment, pok))
+	assert.NotNil(t, vk.Verify(commitment, pok))
 }
 
 func TestCommitToOne(t *testing.T) {
@@ -88,3 +97,144 @@ func TestCommitSingle(t *testing.T) {
 func TestCommitFiveElements(t *testing.T) {
 	testCommit(t, randomFrSlice(t, 5)...)
 }
+
+func TestDeprecatedKey(t *testing.T) {
+	values := interfaceSliceToFrSlice(t, randomFrSlice(t, 5)...)
+	key, err := SetupKey(randomBasis(t, len(values)))
+	assert.NoError(t, err)
+
+	commitment, pok, err := key.Commit(values)
+	assert.NoError(t, err)
+	assert.NoError(t, key.VerifyKnowledgeProof(commitment, pok))
+
+	pok.Neg(&pok)
+	assert.Equal(t, ErrProofRejected, key.VerifyKnowledgeProof(commitment, pok))
+
+	_, _, err = key.Commit(values[1:])
+	assert.Equal(t, ErrNbValues, err)
+}
+
+// setupMultiKey returns keys for bases of sizes 1..nbKeys, random values and the corresponding commitments
+func setupMultiKey(t *testing.T, nbKeys int, options ...SetupOption) ([]ProvingKey, VerifyingKey, [][]fr.Element, []bls12378.G1Affine) {
+	bases := make([][]bls12378.G1Affine, nbKeys)
+	values := make([][]fr.Element, nbKeys)
+	for i := range bases {
+		bases[i] = randomBasis(t, i+1)
+		values[i] = interfaceSliceToFrSlice(t, randomFrSlice(t, i+1)...)
+	}
+
+	pk, vk, err := Setup(bases, options...)
+	assert.NoError(t, err)
+	assert.Equal(t, nbKeys, len(pk))
+
+	commitments := make([]bls12378.G1Affine, nbKeys)
+	for i := range pk {
+		commitments[i], err = pk[i].Commit(values[i])
+		assert.NoError(t, err)
+	}
+	return pk, vk, values, commitments
+}
+
+func TestBatchVerify(t *testing.T) {
+	pk, vk, values, commitments := setupMultiKey(t, 4)
+
+	poks := make([]bls12378.G1Affine, len(pk))
+	for i := range pk {
+		var err error
+		poks[i], err = pk[i].ProveKnowledge(values[i])
+		assert.NoError(t, err)
+		assert.NoError(t, vk.Verify(commitments[i], poks[i]))
+	}
+	assert.NoError(t, vk.BatchVerify(commitments, poks))
+
+	vks := []VerifyingKey{vk, vk, vk, vk}
+	assert.NoError(t, BatchVerifyMultiVk(vks, commitments, poks))
+
+	// another setup
+	pk2, vk2, values2, commitments2 := setupMultiKey(t, 1)
+	pok2, err := pk2[0].ProveKnowledge(values2[0])
+	assert.NoError(t, err)
+	assert.NoError(t, BatchVerifyMultiVk(append(vks, vk2), append(commitments, commitments2[0]), append(poks, pok2)))
+
+	// swapped proofs
+	poks[0], poks[1] = poks[1], poks[0]
+	assert.NotNil(t, vk.BatchVerify(commitments, poks))
+	assert.NotNil(t, BatchVerifyMultiVk(vks, commitments, poks))
+}
+
+func TestFoldCommitments(t *testing.T) {
+	pk, vk, values, commitments := setupMultiKey(t, 5)
+	seed := []byte("transcript")
+
+	pok, err := BatchProve(pk, values, commitments, seed)
+	assert.NoError(t, err)
+	folded, err := FoldCommitments(commitments, seed)
+	assert.NoError(t, err)
+	assert.NoError(t, vk.Verify(folded, pok))
+
+	// the challenge depends on the seed
+	folded, err = FoldCommitments(commitments, []byte("another transcript"))
+	assert.NoError(t, err)
+	assert.NotNil(t, vk.Verify(folded, pok))
+
+	// wrong values
+	values[2][1].SetOne()
+	pok, err = BatchProve(pk, values, commitments, seed)
+	assert.NoError(t, err)
+	folded, err = FoldCommitments(commitments, seed)
+	assert.NoError(t, err)
+	assert.NotNil(t, vk.Verify(folded, pok))
+}
+
+func TestSeededSetup(t *testing.T) {
+	basis := randomBasis(t, 3)
+	seed := []byte("seed")
+
+	pk1, vk1, err := Setup([][]bls12378.G1Affine{basis}, WithSeed(seed))
+	assert.NoError(t, err)
+	pk2, vk2, err := Setup([][]bls12378.G1Affine{basis}, WithSeed(seed))
+	assert.NoError(t, err)
+	assert.Equal(t, vk1, vk2)
+	assert.Equal(t, pk1, pk2)
+
+	_, vk3, err := Setup([][]bls12378.G1Affine{basis}, WithSeed([]byte("another seed")))
+	assert.NoError(t, err)
+	assert.NotEqual(t, vk1, vk3)
+}
+
+func TestMarshal(t *testing.T) {
+	pk, vk, values, commitments := setupMultiKey(t, 2)
+	pok, err := pk[1].ProveKnowledge(values[1])
+	assert.NoError(t, err)
+
+	for _, raw := range []bool{false, true} {
+		var bufPk, bufVk bytes.Buffer
+		var written int64
+		if raw {
+			written, err = pk[1].WriteRawTo(&bufPk)
+			assert.NoError(t, err)
+			_, err = vk.WriteRawTo(&bufVk)
+		} else {
+			written, err = pk[1].WriteTo(&bufPk)
+			assert.NoError(t, err)
+			_, err = vk.WriteTo(&bufVk)
+		}
+		assert.NoError(t, err)
+
+		var _pk ProvingKey
+		var _vk VerifyingKey
+		read, err := _pk.ReadFrom(&bufPk)
+		assert.NoError(t, err)
+		assert.Equal(t, written, read)
+		_, err = _vk.ReadFrom(&bufVk)
+		assert.NoError(t, err)
+		assert.Equal(t, pk[1], _pk)
+		assert.Equal(t, vk, _vk)
+
+		// the decoded keys are usable
+		commitment, err := _pk.Commit(values[1])
+		assert.NoError(t, err)
+		assert.Equal(t, commitments[1], commitment)
+		assert.NoError(t, _vk.Verify(commitment, pok))
+	}
+}
@@ -0,0 +1,610 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12378 "github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrCqVerification = errors.New("cq verification failed")
+	ErrCqSRSSize      = errors.New("the size of the SRS must be a power of two, equal to the size of the table")
+)
+
+// CqSRS is the structured reference string of the cq lookup argument (https://eprint.iacr.org/2022/1763).
+// Its size N is the size of the lookup tables: the soundness of the argument relies on the G₁ powers
+// not going beyond τᴺ⁻¹.
+type CqSRS struct {
+	G1 []bls12378.G1Affine // [G₁, [τ]G₁, ..., [τᴺ⁻¹]G₁]
+	G2 []bls12378.G2Affine // [G₂, [τ]G₂, ..., [τᴺ]G₂]
+}
+
+// NewCqSRS returns a new SRS for tables of the given size, using tau as randomness source
+//
+// In production, a SRS generated through MPC should be used.
+func NewCqSRS(size uint64, bTau *big.Int) (*CqSRS, error) {
+
+	if size < 2 || size&(size-1) != 0 {
+		return nil, ErrCqSRSSize
+	}
+
+	var tau fr.Element
+	tau.SetBigInt(bTau)
+	taus := make([]fr.Element, size)
+	taus[0] = tau
+	for i := 1; i < len(taus); i++ {
+		taus[i].Mul(&taus[i-1], &tau)
+	}
+
+	_, _, gen1Aff, gen2Aff := bls12378.Generators()
+
+	var srs CqSRS
+	srs.G1 = make([]bls12378.G1Affine, size)
+	srs.G1[0] = gen1Aff
+	copy(srs.G1[1:], bls12378.BatchScalarMultiplicationG1(&gen1Aff, taus[:size-1]))
+
+	srs.G2 = make([]bls12378.G2Affine, size+1)
+	srs.G2[0] = gen2Aff
+	copy(srs.G2[1:], bls12378.BatchScalarMultiplicationG2(&gen2Aff, taus))
+
+	return &srs, nil
+}
+
+// kzgSRS returns the KZG SRS sharing the powers of τ in G₁
+func (srs *CqSRS) kzgSRS() *kzg.SRS {
+	return &kzg.SRS{
+		G1: srs.G1,
+		G2: [2]bls12378.G2Affine{srs.G2[0], srs.G2[1]},
+	}
+}
+
+// CqTable is a lookup table, preprocessed so that the cost of proving a lookup
+// does not depend on the size of the table.
+type CqTable struct {
+
+	// Columns of the table, padded to the size of the SRS
+	Columns []fr.Vector
+
+	// Digests are the commitments [Tₖ(τ)]₂ to the columns. The verifier only needs them.
+	Digests []bls12378.G2Affine
+
+	// index of the first occurrence of each row
+	index map[string]int
+
+	// quotients[k][i] = [(Tₖ(X) - Tₖ(ωⁱ))Lᵢ(X)/(Xᴺ - 1)]₁, the cached quotients
+	quotients [][]bls12378.G1Affine
+
+	// lagrange[i] = [Lᵢ(τ)]₁ and lagrangeZero[i] = [(Lᵢ(τ) - Lᵢ(0))/τ]₁
+	lagrange, lagrangeZero []bls12378.G1Affine
+}
+
+// g1FFT replaces a with its discrete Fourier transform aᵢ ← ∑ⱼ ωⁱʲaⱼ, where ω is of order len(a)
+func g1FFT(a []bls12378.G1Jac, omega fr.Element) {
+	n := len(a)
+	nn := uint64(64 - bits.TrailingZeros64(uint64(n)))
+	for i := 0; i < n; i++ {
+		if j := int(bits.Reverse64(uint64(i)) >> nn); i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	var t bls12378.G1Jac
+	var wBigInt big.Int
+	for m := 2; m <= n; m <<= 1 {
+		var wm, w fr.Element
+		wm.Exp(omega, big.NewInt(int64(n/m)))
+		w.SetOne()
+		for j := 0; j < m/2; j++ {
+			w.BigInt(&wBigInt)
+			for k := j; k < n; k += m {
+				t.ScalarMultiplication(&a[k+m/2], &wBigInt)
+				a[k+m/2].Set(&a[k]).SubAssign(&t)
+				a[k].AddAssign(&t)
+			}
+			w.Mul(&w, &wm)
+		}
+	}
+}
+
+// scaleG1 multiplies the points by the scalars, in place
+func scaleG1(points []bls12378.G1Jac, scalars []fr.Element) {
+	var s big.Int
+	for i := range points {
+		scalars[i].BigInt(&s)
+		points[i].ScalarMultiplication(&points[i], &s)
+	}
+}
+
+// g1Jac returns the points in Jacobian coordinates, padded with the point at infinity to size
+func g1Jac(points []bls12378.G1Affine, size int) []bls12378.G1Jac {
+	var infinity bls12378.G1Affine
+	res := make([]bls12378.G1Jac, size)
+	for i := range res {
+		if i < len(points) {
+			res[i].FromAffine(&points[i])
+		} else {
+			res[i].FromAffine(&infinity)
+		}
+	}
+	return res
+}
+
+// NewCqTable preprocesses the table given by columns. The size of the table must not exceed the size of the SRS;
+// it is padded by repeating its last row.
+//
+// The cached quotients are computed in O(N log N) operations in G₁, as the KZG opening proofs of
+// the columns at all points of the domain (https://eprint.iacr.org/2023/033).
+func NewCqTable(srs *CqSRS, columns ...fr.Vector) (*CqTable, error) {
+
+	N := len(srs.G1)
+	if N < 2 || N&(N-1) != 0 || len(srs.G2) != N+1 {
+		return nil, ErrCqSRSSize
+	}
+	if err := checkColumns(columns, columns); err != nil {
+		return nil, err
+	}
+	if len(columns[0]) > N {
+		return nil, ErrCqSRSSize
+	}
+
+	table := CqTable{
+		Columns: padColumns(columns, N, len(columns[0])-1),
+		Digests: make([]bls12378.G2Affine, len(columns)),
+	}
+	table.index = rowIndex(table.Columns)
+
+	domain := fft.NewDomain(uint64(N))
+	domainBig := fft.NewDomain(uint64(2 * N))
+	var omegaInv, omegaBigInv, nInv fr.Element
+	omegaInv.Inverse(&domain.Generator)
+	omegaBigInv.Inverse(&domainBig.Generator)
+	nInv.SetUint64(uint64(N)).Inverse(&nInv)
+
+	// [Lᵢ(τ)]₁ = 1/N ∑ⱼ ω⁻ⁱʲ[τʲ]₁
+	// [(Lᵢ(τ) - Lᵢ(0))/τ]₁ = ω⁻ⁱ/N ∑ⱼ ω⁻ⁱʲ[τʲ]₁, for j < N-1
+	lagrange := g1Jac(srs.G1, N)
+	lagrangeZero := g1Jac(srs.G1[:N-1], N)
+	g1FFT(lagrange, omegaInv)
+	g1FFT(lagrangeZero, omegaInv)
+	scalars := make([]fr.Element, N)
+	scalars[0].Set(&nInv)
+	for i := 1; i < N; i++ {
+		scalars[i].Mul(&scalars[i-1], &omegaInv)
+	}
+	scaleG1(lagrangeZero, scalars)
+	for i := range scalars {
+		scalars[i].Set(&nInv)
+	}
+	scaleG1(lagrange, scalars)
+	table.lagrange = bls12378.BatchJacobianToAffineG1(lagrange)
+	table.lagrangeZero = bls12378.BatchJacobianToAffineG1(lagrangeZero)
+
+	// The opening proof of T at ωⁱ is ∑ⱼ hⱼωⁱʲ where hⱼ = ∑_{k>j} cₖ[τᵏ⁻ʲ⁻¹]₁, cₖ being the coefficients of T.
+	// h is the second half of the cyclic convolution of c and the reversed powers of τ.
+	srsFFT := make([]bls12378.G1Jac, 2*N)
+	var infinity bls12378.G1Affine
+	srsFFT[0].FromAffine(&infinity)
+	for q := 1; q < 2*N; q++ {
+		if q < N {
+			srsFFT[q].FromAffine(&srs.G1[N-1-q])
+		} else {
+			srsFFT[q].FromAffine(&infinity)
+		}
+	}
+	g1FFT(srsFFT, domainBig.Generator)
+
+	// Qᵢ = ωⁱ/N × opening proof at ωⁱ, and 1/2N from the inverse FFT
+	scalars[0].Mul(&nInv, &domainBig.CardinalityInv)
+	for i := 1; i < N; i++ {
+		scalars[i].Mul(&scalars[i-1], &domain.Generator)
+	}
+
+	table.quotients = make([][]bls12378.G1Affine, len(columns))
+	for k := range table.Columns {
+		c := toCanonical(table.Columns[k], domain)
+
+		var err error
+		if _, err = table.Digests[k].MultiExp(srs.G2[:N], c, ecc.MultiExpConfig{}); err != nil {
+			return nil, err
+		}
+
+		cBig := make([]fr.Element, 2*N)
+		copy(cBig, c)
+		domainBig.FFT(cBig, fft.DIF)
+		fft.BitReverse(cBig)
+
+		h := make([]bls12378.G1Jac, 2*N)
+		copy(h, srsFFT)
+		scaleG1(h, cBig)
+		g1FFT(h, omegaBigInv)
+
+		proofs := h[N:]
+		g1FFT(proofs, domain.Generator)
+		scaleG1(proofs, scalars)
+		table.quotients[k] = bls12378.BatchJacobianToAffineG1(proofs)
+	}
+
+	return &table, nil
+}
+
+// ProofCq is a cq lookup proof (https://eprint.iacr.org/2022/1763) that the rows of f are rows of a preprocessed table.
+// With β a random challenge, a = m/(β + t) on the table domain V of size N and b = 1/(β + f) on the domain H of size n,
+// it shows that ∑_V a = N × a(0) = n × b(0) = ∑_H b.
+type ProofCq struct {
+
+	// Size of the domain H
+	Size uint64
+
+	// Commitments to the columns of f
+	Fs []kzg.Digest
+
+	// Commitments to the multiplicities m, a, the quotient of a(β + t) - m by Xᴺ - 1 and (a - a(0))/X
+	M, A, QA, A0 kzg.Digest
+
+	// Commitments to (b - b(0))/X, the quotient of b(β + f) - 1 by Xⁿ - 1, and b₀ shifted to degree N - 1
+	B0, QB, P kzg.Digest
+
+	// AZero is a(0)
+	AZero fr.Element
+
+	// Batch opening proof of b₀, f (folded), q_b at γ
+	BatchedProof kzg.BatchOpeningProof
+}
+
+// deriveCqLambda derives the challenge folding the columns, bound to the size of the domain H
+func deriveCqLambda(fs *fiatshamir.Sponge, size uint64, digests []bls12378.G2Affine, fsDigests []kzg.Digest) (fr.Element, error) {
+	bindSize(fs, "lambda", size)
+	for i := range digests {
+		fs.AbsorbMarshalers("lambda", &digests[i])
+	}
+	for k := range fsDigests {
+		fs.AbsorbMarshalers("lambda", &fsDigests[k])
+	}
+	return fiatshamir.SqueezeElement[fr.Element](fs, "lambda", fr.Modulus())
+}
+
+// ProveCq returns a proof that the rows of f are rows of the table. The cost of the prover
+// is independent of the size of the table.
+func ProveCq(srs *CqSRS, table *CqTable, f []fr.Vector) (ProofCq, error) {
+
+	var proof ProofCq
+	var err error
+
+	if err = checkColumns(f, table.Columns); err != nil {
+		return proof, err
+	}
+	N := len(table.Columns[0])
+	if len(srs.G1) != N {
+		return proof, ErrCqSRSSize
+	}
+	kzgSRS := srs.kzgSRS()
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.cq")
+
+	domain := fft.NewDomain(uint64(len(f[0])))
+	n := int(domain.Cardinality)
+	if n > N {
+		return proof, ErrIncompatibleSize
+	}
+	proof.Size = domain.Cardinality
+	f = padColumns(f, n, 0)
+
+	// commit to f and fold the columns
+	proof.Fs = make([]kzg.Digest, len(f))
+	for k := range f {
+		if proof.Fs[k], err = kzg.Commit(toCanonical(f[k], domain), kzgSRS); err != nil {
+			return proof, err
+		}
+	}
+	lambda, err := deriveCqLambda(fs, proof.Size, table.Digests, proof.Fs)
+	if err != nil {
+		return proof, err
+	}
+	lambdas := make([]fr.Element, len(f))
+	lambdas[0].SetOne()
+	for k := 1; k < len(lambdas); k++ {
+		lambdas[k].Mul(&lambdas[k-1], &lambda)
+	}
+
+	// multiplicities, only on the rows of the table that are looked up
+	m, err := computeMultiplicities(f, table.index, N)
+	if err != nil {
+		return proof, err
+	}
+	var support []int
+	for i := range m {
+		if !m[i].IsZero() {
+			support = append(support, i)
+		}
+	}
+	lagrange := make([]bls12378.G1Affine, len(support))
+	lagrangeZero := make([]bls12378.G1Affine, len(support))
+	mSupport := make([]fr.Element, len(support))
+	for j, i := range support {
+		lagrange[j] = table.lagrange[i]
+		lagrangeZero[j] = table.lagrangeZero[i]
+		mSupport[j] = m[i]
+	}
+	if _, err = proof.M.MultiExp(lagrange, mSupport, ecc.MultiExpConfig{}); err != nil {
+		return proof, err
+	}
+
+	fs.AbsorbMarshalers("beta", &proof.M)
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+
+	// a = m/(β + t) on the support, and a(0) = ∑ aᵢ/N
+	tSupport := make(fr.Vector, len(support))
+	for j, i := range support {
+		for k := len(table.Columns) - 1; k >= 0; k-- {
+			tSupport[j].Mul(&tSupport[j], &lambda).
+				Add(&tSupport[j], &table.Columns[k][i])
+		}
+	}
+	a, err := invertShifted(tSupport, beta)
+	if err != nil {
+		return proof, err
+	}
+	var nInv fr.Element
+	nInv.SetUint64(uint64(N)).Inverse(&nInv)
+	proof.AZero.SetZero()
+	for j := range a {
+		a[j].Mul(&a[j], &mSupport[j])
+		proof.AZero.Add(&proof.AZero, &a[j])
+	}
+	proof.AZero.Mul(&proof.AZero, &nInv)
+
+	if _, err = proof.A.MultiExp(lagrange, a, ecc.MultiExpConfig{}); err != nil {
+		return proof, err
+	}
+	if _, err = proof.A0.MultiExp(lagrangeZero, a, ecc.MultiExpConfig{}); err != nil {
+		return proof, err
+	}
+
+	// q_a = ∑ᵢ aᵢ ∑ₖ λᵏ Qₖᵢ using the cached quotients
+	quotients := make([]bls12378.G1Affine, 0, len(support)*len(f))
+	scalars := make([]fr.Element, 0, len(support)*len(f))
+	for j, i := range support {
+		for k := range table.quotients {
+			var s fr.Element
+			s.Mul(&a[j], &lambdas[k])
+			quotients = append(quotients, table.quotients[k][i])
+			scalars = append(scalars, s)
+		}
+	}
+	if _, err = proof.QA.MultiExp(quotients, scalars, ecc.MultiExpConfig{}); err != nil {
+		return proof, err
+	}
+
+	// b = 1/(β + f) on H, b₀ = (b - b(0))/X
+	lf := foldColumns(f, lambda)
+	cf := toCanonical(lf, domain)
+	lb, err := invertShifted(lf, beta)
+	if err != nil {
+		return proof, err
+	}
+	cb := toCanonical(lb, domain)
+	cb0 := cb[1:]
+	if proof.B0, err = kzg.Commit(cb0, kzgSRS); err != nil {
+		return proof, err
+	}
+
+	// q_b = (b(β + f) - 1)/(Xⁿ - 1)
+	domainBig := fft.NewDomain(uint64(2 * n))
+	_lf := toLagrangeCosetBitReversed(cf, domainBig)
+	_lb := toLagrangeCosetBitReversed(cb, domainBig)
+	var one fr.Element
+	one.SetOne()
+	for i := range _lb {
+		_lb[i].Mul(&_lb[i], _lf[i].Add(&_lf[i], &beta)).
+			Sub(&_lb[i], &one)
+	}
+	cqb := divideByXnMinusOne(_lb, domainBig)[:n]
+	if proof.QB, err = kzg.Commit(cqb, kzgSRS); err != nil {
+		return proof, err
+	}
+
+	// p = b₀ × Xᴺ⁻ⁿ⁺¹ shows that deg(b₀) ≤ n - 2
+	cp := make([]fr.Element, N)
+	copy(cp[N-n+1:], cb0)
+	if proof.P, err = kzg.Commit(cp, kzgSRS); err != nil {
+		return proof, err
+	}
+
+	// open at γ
+	aZeroBytes := proof.AZero.Bytes()
+	fs.Absorb("gamma", aZeroBytes[:])
+	fs.AbsorbMarshalers("gamma", &proof.A, &proof.QA, &proof.A0, &proof.B0, &proof.QB, &proof.P)
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+	foldedF, err := foldDigests(proof.Fs, lambda)
+	if err != nil {
+		return proof, err
+	}
+	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
+		[][]fr.Element{cb0, cf, cqb},
+		[]kzg.Digest{proof.B0, foldedF, proof.QB},
+		gamma,
+		hFunc,
+		kzgSRS,
+	)
+
+	return proof, err
+}
+
+// VerifyCq verifies a ProofCq proof against the commitments to the columns of the table, CqTable.Digests
+func VerifyCq(srs *CqSRS, tableDigests []bls12378.G2Affine, proof ProofCq) error {
+
+	N := uint64(len(srs.G1))
+	if N < 2 || N&(N-1) != 0 || uint64(len(srs.G2)) != N+1 {
+		return ErrCqSRSSize
+	}
+	if len(proof.Fs) != len(tableDigests) || len(proof.Fs) == 0 {
+		return ErrNumberDigests
+	}
+	if proof.Size < 2 || proof.Size&(proof.Size-1) != 0 || proof.Size > N {
+		return ErrDomainSize
+	}
+	if len(proof.BatchedProof.ClaimedValues) != 3 {
+		return ErrCqVerification
+	}
+
+	// hash function that is used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.cq")
+
+	lambda, err := deriveCqLambda(fs, proof.Size, tableDigests, proof.Fs)
+	if err != nil {
+		return err
+	}
+	fs.AbsorbMarshalers("beta", &proof.M)
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
+	if err != nil {
+		return err
+	}
+	aZeroBytes := proof.AZero.Bytes()
+	fs.Absorb("gamma", aZeroBytes[:])
+	fs.AbsorbMarshalers("gamma", &proof.A, &proof.QA, &proof.A0, &proof.B0, &proof.QB, &proof.P)
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
+	if err != nil {
+		return err
+	}
+
+	// a(β + t) - m = q_a(Xᴺ - 1):
+	// e([a], [t]₂ + β[1]₂) e(-[q_a], [τᴺ]₂ - [1]₂) e(-[m], [1]₂) = 1
+	lambdas := make([]fr.Element, len(tableDigests))
+	lambdas[0].SetOne()
+	for k := 1; k < len(lambdas); k++ {
+		lambdas[k].Mul(&lambdas[k-1], &lambda)
+	}
+	var foldedT, betaG2, zV bls12378.G2Affine
+	if _, err = foldedT.MultiExp(tableDigests, lambdas, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var betaBigInt big.Int
+	beta.BigInt(&betaBigInt)
+	betaG2.ScalarMultiplication(&srs.G2[0], &betaBigInt)
+	foldedT.Add(&foldedT, &betaG2)
+	zV.Sub(&srs.G2[N], &srs.G2[0])
+
+	var negQA, negM bls12378.G1Affine
+	negQA.Neg(&proof.QA)
+	negM.Neg(&proof.M)
+	ok, err := bls12378.PairingCheck(
+		[]bls12378.G1Affine{proof.A, negQA, negM},
+		[]bls12378.G2Affine{foldedT, zV, srs.G2[0]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCqVerification
+	}
+
+	// a - a(0) = a₀X: e([a] - a(0)[1]₁, [1]₂) e(-[a₀], [τ]₂) = 1
+	var aMinusAZero, negA0 bls12378.G1Affine
+	var aZeroBigInt big.Int
+	proof.AZero.BigInt(&aZeroBigInt)
+	aMinusAZero.ScalarMultiplication(&srs.G1[0], &aZeroBigInt)
+	aMinusAZero.Sub(&proof.A, &aMinusAZero)
+	negA0.Neg(&proof.A0)
+	ok, err = bls12378.PairingCheck(
+		[]bls12378.G1Affine{aMinusAZero, negA0},
+		[]bls12378.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCqVerification
+	}
+
+	// deg(b₀) ≤ n - 2: e([b₀], [τᴺ⁻ⁿ⁺¹]₂) e(-[p], [1]₂) = 1
+	var negP bls12378.G1Affine
+	negP.Neg(&proof.P)
+	ok, err = bls12378.PairingCheck(
+		[]bls12378.G1Affine{proof.B0, negP},
+		[]bls12378.G2Affine{srs.G2[N-proof.Size+1], srs.G2[0]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCqVerification
+	}
+
+	// check the opening proofs at γ
+	foldedF, err := foldDigests(proof.Fs, lambda)
+	if err != nil {
+		return err
+	}
+	err = kzg.BatchVerifySinglePoint(
+		[]kzg.Digest{proof.B0, foldedF, proof.QB},
+		&proof.BatchedProof,
+		gamma,
+		hFunc,
+		srs.kzgSRS(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// b(0) = N a(0) / n and b(γ) = γ b₀(γ) + b(0)
+	claimed := proof.BatchedProof.ClaimedValues
+	var bZero, bGamma, s fr.Element
+	s.SetUint64(proof.Size).Inverse(&s)
+	bZero.SetUint64(N).
+		Mul(&bZero, &proof.AZero).
+		Mul(&bZero, &s)
+	bGamma.Mul(&gamma, &claimed[0]).
+		Add(&bGamma, &bZero)
+
+	// b(γ)(β + f(γ)) - 1 = q_b(γ)(γⁿ - 1)
+	var lhs, rhs, one fr.Element
+	one.SetOne()
+	lhs.Add(&beta, &claimed[1]).
+		Mul(&lhs, &bGamma).
+		Sub(&lhs, &one)
+	rhs.Exp(gamma, new(big.Int).SetUint64(proof.Size)).
+		Sub(&rhs, &one).
+		Mul(&rhs, &claimed[2])
+	if !lhs.Equal(&rhs) {
+		return ErrCqVerification
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378"
+)
+
+// newEncoder returns an encoder on w, with uncompressed points if raw is set
+func newEncoder(w io.Writer, raw bool) *bls12378.Encoder {
+	if raw {
+		return bls12378.NewEncoder(w, bls12378.RawEncoding())
+	}
+	return bls12378.NewEncoder(w)
+}
+
+// WriteTo writes the binary encoding of the lookup proof, with compressed points
+func (proof *ProofLookupVector) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes the binary encoding of the lookup proof, with uncompressed points
+func (proof *ProofLookupVector) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *ProofLookupVector) writeTo(w io.Writer, raw bool) (int64, error) {
+	enc := newEncoder(w, raw)
+
+	toEncode := []interface{}{
+		proof.size,
+		&proof.g,
+		&proof.h1,
+		&proof.h2,
+		&proof.t,
+		&proof.z,
+		&proof.f,
+		&proof.h,
+		&proof.BatchedProof.H,
+		proof.BatchedProof.ClaimedValues,
+		&proof.BatchedProofShifted.H,
+		proof.BatchedProofShifted.ClaimedValues,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a lookup proof written with WriteTo or WriteRawTo
+func (proof *ProofLookupVector) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.size,
+		&proof.g,
+		&proof.h1,
+		&proof.h2,
+		&proof.t,
+		&proof.z,
+		&proof.f,
+		&proof.h,
+		&proof.BatchedProof.H,
+		&proof.BatchedProof.ClaimedValues,
+		&proof.BatchedProofShifted.H,
+		&proof.BatchedProofShifted.ClaimedValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the table lookup proof, with compressed points
+func (proof *ProofLookupTables) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes the binary encoding of the table lookup proof, with uncompressed points
+func (proof *ProofLookupTables) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *ProofLookupTables) writeTo(w io.Writer, raw bool) (int64, error) {
+	enc := newEncoder(w, raw)
+
+	toEncode := []interface{}{
+		proof.fs,
+		proof.ts,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	// the nested proofs are encoded with their own encoders
+	n := enc.BytesWritten()
+	m, err := proof.foldedProof.writeTo(w, raw)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	if raw {
+		m, err = proof.permutationProof.WriteRawTo(w)
+	} else {
+		m, err = proof.permutationProof.WriteTo(w)
+	}
+	n += m
+	return n, err
+}
+
+// ReadFrom decodes a table lookup proof written with WriteTo or WriteRawTo
+func (proof *ProofLookupTables) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.fs,
+		&proof.ts,
+		&proof.foldedProof,
+		&proof.permutationProof,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the LogUp proof, with compressed points
+func (proof *ProofLogUp) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes the binary encoding of the LogUp proof, with uncompressed points
+func (proof *ProofLogUp) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *ProofLogUp) writeTo(w io.Writer, raw bool) (int64, error) {
+	enc := newEncoder(w, raw)
+
+	toEncode := []interface{}{
+		proof.Size,
+		proof.Fs,
+		proof.Ts,
+		&proof.M,
+		&proof.A,
+		&proof.B,
+		&proof.Z,
+		&proof.H,
+		&proof.BatchedProof.H,
+		proof.BatchedProof.ClaimedValues,
+		&proof.ZShiftedProof.H,
+		&proof.ZShiftedProof.ClaimedValue,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a LogUp proof written with WriteTo or WriteRawTo
+func (proof *ProofLogUp) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.Size,
+		&proof.Fs,
+		&proof.Ts,
+		&proof.M,
+		&proof.A,
+		&proof.B,
+		&proof.Z,
+		&proof.H,
+		&proof.BatchedProof.H,
+		&proof.BatchedProof.ClaimedValues,
+		&proof.ZShiftedProof.H,
+		&proof.ZShiftedProof.ClaimedValue,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the cq proof, with compressed points
+func (proof *ProofCq) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes the binary encoding of the cq proof, with uncompressed points
+func (proof *ProofCq) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *ProofCq) writeTo(w io.Writer, raw bool) (int64, error) {
+	enc := newEncoder(w, raw)
+
+	toEncode := []interface{}{
+		proof.Size,
+		proof.Fs,
+		&proof.M,
+		&proof.A,
+		&proof.QA,
+		&proof.A0,
+		&proof.B0,
+		&proof.QB,
+		&proof.P,
+		&proof.AZero,
+		&proof.BatchedProof.H,
+		proof.BatchedProof.ClaimedValues,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a cq proof written with WriteTo or WriteRawTo
+func (proof *ProofCq) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.Size,
+		&proof.Fs,
+		&proof.M,
+		&proof.A,
+		&proof.QA,
+		&proof.A0,
+		&proof.B0,
+		&proof.QB,
+		&proof.P,
+		&proof.AZero,
+		&proof.BatchedProof.H,
+		&proof.BatchedProof.ClaimedValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
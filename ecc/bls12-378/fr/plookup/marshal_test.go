@@ -0,0 +1,273 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/kzg"
+)
+
+type serializable interface {
+	io.ReaderFrom
+	io.WriterTo
+	WriteRawTo(w io.Writer) (int64, error)
+}
+
+// roundTrip encodes from (compressed or raw) and decodes the result in to
+func roundTrip(t *testing.T, from, to serializable, raw bool) {
+	var buf bytes.Buffer
+	var written int64
+	var err error
+	if raw {
+		written, err = from.WriteRawTo(&buf)
+	} else {
+		written, err = from.WriteTo(&buf)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := to.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatalf("read %d bytes, wrote %d", read, written)
+	}
+	if buf.Len() != 0 {
+		t.Fatal("trailing bytes after decoding")
+	}
+}
+
+// lookupTestData returns a table of 3 columns and a valid lookup into it
+func lookupTestData() (f, lookupTable []fr.Vector) {
+	lookupTable = make([]fr.Vector, 3)
+	f = make([]fr.Vector, 3)
+	for i := 0; i < 3; i++ {
+		lookupTable[i] = make(fr.Vector, 8)
+		f[i] = make(fr.Vector, 7)
+		for j := 0; j < 8; j++ {
+			lookupTable[i][j].SetUint64(uint64(2*i + j))
+		}
+		for j := 0; j < 7; j++ {
+			f[i][j].Set(&lookupTable[i][(4*j+1)%8])
+		}
+	}
+	return
+}
+
+// writeProofs writes the srs and the proofs for the test data, compressed or raw
+func writeProofs(w io.Writer, raw bool) error {
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		return err
+	}
+	f, lookupTable := lookupTestData()
+	proofVector, err := ProveLookupVector(srs, f[0], lookupTable[0])
+	if err != nil {
+		return err
+	}
+	proofTables, err := ProveLookupTables(srs, f, lookupTable)
+	if err != nil {
+		return err
+	}
+	proofLogUp, err := ProveLogUp(srs, f, lookupTable)
+	if err != nil {
+		return err
+	}
+
+	if _, err = srs.WriteTo(w); err != nil {
+		return err
+	}
+	for _, p := range []serializable{&proofVector, &proofTables, &proofLogUp} {
+		if raw {
+			_, err = p.WriteRawTo(w)
+		} else {
+			_, err = p.WriteTo(w)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAndVerifyProofs reads what writeProofs wrote and verifies the proofs
+func readAndVerifyProofs(r io.Reader) error {
+	var srs kzg.SRS
+	var proofVector ProofLookupVector
+	var proofTables ProofLookupTables
+	var proofLogUp ProofLogUp
+	for _, v := range []io.ReaderFrom{&srs, &proofVector, &proofTables, &proofLogUp} {
+		if _, err := v.ReadFrom(r); err != nil {
+			return err
+		}
+	}
+	if err := VerifyLookupVector(&srs, proofVector); err != nil {
+		return err
+	}
+	if err := VerifyLookupTables(&srs, proofTables); err != nil {
+		return err
+	}
+	return VerifyLogUp(&srs, proofLogUp)
+}
+
+func TestSerializationLookup(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, lookupTable := lookupTestData()
+
+	proofVector, err := ProveLookupVector(srs, f[0], lookupTable[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofTables, err := ProveLookupTables(srs, f, lookupTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofLogUp, err := ProveLogUp(srs, f, lookupTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range []bool{false, true} {
+		var _proofVector ProofLookupVector
+		roundTrip(t, &proofVector, &_proofVector, raw)
+		if _proofVector.H1() != proofVector.H1() || _proofVector.F() != proofVector.F() || _proofVector.Size() != proofVector.Size() {
+			t.Fatal("decoded commitments differ")
+		}
+		if err = VerifyLookupVector(srs, _proofVector); err != nil {
+			t.Fatal(err)
+		}
+
+		var _proofTables ProofLookupTables
+		roundTrip(t, &proofTables, &_proofTables, raw)
+		if len(_proofTables.Fs()) != 3 || _proofTables.Ts()[2] != proofTables.Ts()[2] {
+			t.Fatal("decoded commitments differ")
+		}
+		permutationProof := _proofTables.PermutationProof()
+		expectedPermutationProof := proofTables.PermutationProof()
+		if permutationProof.Z() != expectedPermutationProof.Z() {
+			t.Fatal("decoded commitments differ")
+		}
+		if err = VerifyLookupTables(srs, _proofTables); err != nil {
+			t.Fatal(err)
+		}
+
+		var _proofLogUp ProofLogUp
+		roundTrip(t, &proofLogUp, &_proofLogUp, raw)
+		if err = VerifyLogUp(srs, _proofLogUp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a proof without digests must be rejected, not panic
+	proofTables.fs, proofTables.ts = nil, nil
+	var _proofTables ProofLookupTables
+	roundTrip(t, &proofTables, &_proofTables, false)
+	if err = VerifyLookupTables(srs, _proofTables); err != ErrNumberDigests {
+		t.Fatal("expected ErrNumberDigests")
+	}
+}
+
+func TestSerializationCq(t *testing.T) {
+
+	srs, err := NewCqSRS(8, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, lookupTable := lookupTestData()
+	table, err := NewCqTable(srs, lookupTable...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ProveCq(srs, table, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the verifier only knows the encoded table digests and proof
+	var buf bytes.Buffer
+	enc := bls12378.NewEncoder(&buf)
+	if err = enc.Encode(table.Digests); err != nil {
+		t.Fatal(err)
+	}
+	var digests []bls12378.G2Affine
+	if err = bls12378.NewDecoder(&buf).Decode(&digests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range []bool{false, true} {
+		var _proof ProofCq
+		roundTrip(t, &proof, &_proof, raw)
+		if _proof.AZero != proof.AZero || _proof.M != proof.M {
+			t.Fatal("decoded proof differs")
+		}
+		if err = VerifyCq(srs, digests, _proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestSerializationFreshProcess checks that proofs written by this process
+// verify in another process which only has access to the serialized data.
+func TestSerializationFreshProcess(t *testing.T) {
+
+	if path := os.Getenv("PLOOKUP_PROOFS_FILE"); path != "" {
+		// child process
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if err = readAndVerifyProofs(file); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := writeProofs(&buf, raw); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "proofs")
+		if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestSerializationFreshProcess$")
+		cmd.Env = append(os.Environ(), "PLOOKUP_PROOFS_FILE="+path)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("verification in a fresh process failed: %v\n%s", err, out)
+		}
+	}
+}
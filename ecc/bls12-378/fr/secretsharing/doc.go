@@ -0,0 +1,33 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package secretsharing provides Shamir secret sharing over fr.Element, with Feldman
+// verifiable commitments in G1.
+//
+// A secret s is split (Split, SplitAt) with a random polynomial f of degree threshold-1,
+// f(0) = s, into the shares (x, f(x)). Any threshold shares reconstruct s by Lagrange
+// interpolation at 0 (Reconstruct), fewer reveal nothing about it.
+//
+// The dealer publishes the Feldman commitment aᵢ⋅G to the coefficients of f (Commit),
+// against which anyone verifies a share (Commitment.Verify) or computes the public share
+// f(x)⋅G (Commitment.Eval).
+//
+// The shares can be proactively refreshed, or redistributed to a new set of participants with
+// a new threshold, without reconstructing the secret: each old participant reshares its share
+// (Reshare), and each new participant combines the sub-shares it receives (CombineReshares).
+// The commitment to the new polynomial is the combination of the commitments of the old
+// participants (CombineCommitments).
+package secretsharing
@@ -0,0 +1,303 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecvrf
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+// testSuites are the options of the suites, with an input of each suite
+func testSuites() map[string]struct {
+	opts  []Option
+	alpha []byte
+} {
+	var e fr.Element
+	e.SetUint64(42)
+	eBytes := e.Bytes()
+	return map[string]struct {
+		opts  []Option
+		alpha []byte
+	}{
+		"ELL2":      {nil, []byte("sample")},
+		"TAI":       {[]Option{WithTryAndIncrement()}, []byte("sample")},
+		"FieldHash": {[]Option{WithFieldHash(hash.MIMC_BLS12_378.New)}, eBytes[:]},
+	}
+}
+
+func TestProveVerify(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, suite := range testSuites() {
+		proof, err := privKey.Prove(suite.alpha, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		valid, err := privKey.PublicKey.Verify(suite.alpha, proof, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !valid {
+			t.Fatal(name, "the proof should be valid")
+		}
+
+		// the proofs are deterministic
+		proof2, err := privKey.Prove(suite.alpha, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !bytes.Equal(proof.Bytes(), proof2.Bytes()) {
+			t.Fatal(name, "the proofs should be deterministic")
+		}
+
+		// another input or another key
+		alpha := append([]byte{}, suite.alpha...)
+		alpha[len(alpha)-1] ^= 1
+		if valid, _ = privKey.PublicKey.Verify(alpha, proof, suite.opts...); valid {
+			t.Fatal(name, "the proof should not be valid for another input")
+		}
+		if valid, _ = other.PublicKey.Verify(suite.alpha, proof, suite.opts...); valid {
+			t.Fatal(name, "the proof should not be valid for another key")
+		}
+
+		// tampered proofs
+		tampered := *proof
+		tampered.C[0] ^= 1
+		if valid, _ = privKey.PublicKey.Verify(suite.alpha, &tampered, suite.opts...); valid {
+			t.Fatal(name, "the proof should not be valid with another challenge")
+		}
+		tampered = *proof
+		tampered.S[len(tampered.S)-1] ^= 1
+		if valid, _ = privKey.PublicKey.Verify(suite.alpha, &tampered, suite.opts...); valid {
+			t.Fatal(name, "the proof should not be valid with another s")
+		}
+		tampered = *proof
+		tampered.Gamma.Add(&tampered.Gamma, &privKey.PublicKey.A)
+		if valid, _ = privKey.PublicKey.Verify(suite.alpha, &tampered, suite.opts...); valid {
+			t.Fatal(name, "the proof should not be valid with another Γ")
+		}
+
+		// the proof of another suite is not valid
+		for otherName, otherSuite := range testSuites() {
+			if otherName == name {
+				continue
+			}
+			if valid, _ = privKey.PublicKey.Verify(suite.alpha, proof, otherSuite.opts...); valid {
+				t.Fatal(name, "the proof should not be valid in the suite", otherName)
+			}
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, suite := range testSuites() {
+		proof, err := privKey.Prove(suite.alpha, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		beta, err := proof.Output(suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		// the output is a function of the key and the input
+		alpha := append([]byte{}, suite.alpha...)
+		alpha[len(alpha)-1] ^= 1
+		proof2, err := privKey.Prove(alpha, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		beta2, err := proof2.Output(suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if bytes.Equal(beta, beta2) {
+			t.Fatal(name, "the outputs of different inputs should differ")
+		}
+
+		// small order components of Γ don't change the output
+		var small twistededwards.PointAffine
+		small.X.SetZero()
+		small.Y.SetOne()
+		small.Y.Neg(&small.Y) // (0, -1) has order 2
+		tweaked := *proof
+		tweaked.Gamma.Add(&tweaked.Gamma, &small)
+		beta3, err := tweaked.Output(suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !bytes.Equal(beta, beta3) {
+			t.Fatal(name, "the cofactor is not cleared")
+		}
+		if valid, _ := privKey.PublicKey.Verify(suite.alpha, &tweaked, suite.opts...); valid {
+			t.Fatal(name, "Γ should be in the prime order subgroup")
+		}
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := []byte("sample")
+	proof, err := privKey.Prove(alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var identity, small PublicKey
+	identity.A.Y.SetOne()
+	small.A.Y.SetOne()
+	small.A.Y.Neg(&small.A.Y)
+	for _, pub := range []PublicKey{identity, small} {
+		if _, err = pub.Verify(alpha, proof); err != errInvalidKey {
+			t.Fatal("expected errInvalidKey, got", err)
+		}
+	}
+
+	if _, err = privKey.Prove(alpha, WithTryAndIncrement(), WithFieldHash(hash.MIMC_BLS12_378.New)); err != errTAIFieldHash {
+		t.Fatal("expected errTAIFieldHash, got", err)
+	}
+	if _, err = privKey.Prove(alpha, WithFieldHash(nil)); err != errNilFieldHash {
+		t.Fatal("expected errNilFieldHash, got", err)
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	for name, suite := range testSuites() {
+		publicKeys := make([]PublicKey, n)
+		alphas := make([][]byte, n)
+		proofs := make([]Proof, n)
+		for i := 0; i < n; i++ {
+			privKey, err := GenerateKey(crand.Reader)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			publicKeys[i] = privKey.PublicKey
+			alphas[i] = suite.alpha
+			proof, err := privKey.Prove(alphas[i], suite.opts...)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			proofs[i] = *proof
+		}
+
+		valid, err := BatchVerify(publicKeys, alphas, proofs, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !valid {
+			t.Fatal(name, "the proofs should be valid")
+		}
+
+		proofs[2], proofs[3] = proofs[3], proofs[2]
+		if valid, _ = BatchVerify(publicKeys, alphas, proofs, suite.opts...); valid {
+			t.Fatal(name, "the swapped proofs should not be valid")
+		}
+
+		if _, err = BatchVerify(publicKeys[1:], alphas, proofs, suite.opts...); err != errInconsistentSize {
+			t.Fatal(name, "expected errInconsistentSize, got", err)
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- This is a false positive
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var privKey2 PrivateKey
+	if _, err = privKey2.SetBytes(privKey.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(privKey.Bytes(), privKey2.Bytes()) {
+		t.Fatal("Error serialize(deserialize(.)) of the private key")
+	}
+	if !privKey2.PublicKey.A.Equal(&privKey.PublicKey.A) {
+		t.Fatal("Error serialize(deserialize(.)) of the public key")
+	}
+
+	proof, err := privKey.Prove([]byte("sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var proof2 Proof
+	n, err := proof2.SetBytes(proof.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != sizeProof || proof2 != *proof {
+		t.Fatal("Error serialize(deserialize(.)) of the proof")
+	}
+	if _, err = proof2.SetBytes(proof.Bytes()[1:]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkProve(b *testing.B) {
+	privKey, _ := GenerateKey(crand.Reader)
+	alpha := []byte("benchmark")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Prove(alpha)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	privKey, _ := GenerateKey(crand.Reader)
+	alpha := []byte("benchmark")
+	proof, _ := privKey.Prove(alpha)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(alpha, proof)
+	}
+}
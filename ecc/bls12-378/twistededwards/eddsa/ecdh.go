@@ -0,0 +1,150 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	errSmallOrderPoint  = errors.New("the public key is in the small order subgroup")
+	errShortCiphertext  = errors.New("the ciphertext is too short")
+	errDecryptionFailed = errors.New("decryption failed")
+)
+
+const (
+	eciesKeySize   = 32 // AES-256
+	eciesNonceSize = 12
+	eciesTagSize   = 16
+)
+
+// SharedSecret returns the ECDH shared secret between privKey and pub: the
+// compressed encoding of [s]([cofactor]A), where s is the secret scalar of privKey
+// and A the point of pub.
+//
+// Clearing the cofactor maps A to the prime order subgroup. It returns an error
+// if A is not on the curve or if [cofactor]A is the identity, that is if A is
+// in the small order subgroup.
+//
+// The shared secret is not uniformly distributed, it should be fed into a key
+// derivation function, as in Encrypt.
+func (privKey *PrivateKey) SharedSecret(pub *PublicKey) ([]byte, error) {
+	if !pub.A.IsOnCurve() {
+		return nil, errNotOnCurve
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	var bCofactor, scalar big.Int
+	curveParams.Cofactor.BigInt(&bCofactor)
+	scalar.SetBytes(privKey.scalar[:])
+
+	// the projective formulas are complete, so they also handle the small order points
+	var q twistededwards.PointProj
+	q.FromAffine(&pub.A)
+	q.ScalarMultiplication(&q, &bCofactor)
+	if q.IsZero() {
+		return nil, errSmallOrderPoint
+	}
+
+	var p twistededwards.PointAffine
+	p.FromProj(&q)
+	p.ScalarMultiplication(&p, &scalar)
+
+	res := p.Bytes()
+	return res[:], nil
+}
+
+// Encrypt encrypts msg to pub with ECIES.
+//
+// An ephemeral key pair (r, R) is drawn from rand. The AES-256-GCM key and nonce are
+// derived with HKDF-SHA256 from the ECDH shared secret between r and pub, using
+// R||pub||sharedInfo as HKDF info. The output is R||AES-GCM(msg), where R is encoded
+// as PublicKey.Bytes and the authentication tag is appended to the ciphertext.
+//
+// sharedInfo is optional, and must be provided again to Decrypt.
+func Encrypt(rand io.Reader, pub *PublicKey, msg, sharedInfo []byte) ([]byte, error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralBin := ephemeral.PublicKey.Bytes()
+	aead, nonce, err := eciesAEAD(ephemeral, pub, ephemeralBin, pub.Bytes(), sharedInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, sizePublicKey, sizePublicKey+len(msg)+eciesTagSize)
+	copy(res, ephemeralBin)
+	return aead.Seal(res, nonce, msg, nil), nil
+}
+
+// Decrypt decrypts a ciphertext returned by Encrypt, see Encrypt.
+func (privKey *PrivateKey) Decrypt(ciphertext, sharedInfo []byte) ([]byte, error) {
+	if len(ciphertext) < sizePublicKey+eciesTagSize {
+		return nil, errShortCiphertext
+	}
+	var ephemeral PublicKey
+	if _, err := ephemeral.SetBytes(ciphertext[:sizePublicKey]); err != nil {
+		return nil, err
+	}
+	aead, nonce, err := eciesAEAD(privKey, &ephemeral, ciphertext[:sizePublicKey], privKey.PublicKey.Bytes(), sharedInfo)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := aead.Open(nil, nonce, ciphertext[sizePublicKey:], nil)
+	if err != nil {
+		return nil, errDecryptionFailed
+	}
+	return msg, nil
+}
+
+// eciesAEAD returns the AES-GCM instance and the nonce derived from the shared
+// secret between privKey and pub. The encodings of the ephemeral and recipient
+// public keys are bound to the derived key.
+func eciesAEAD(privKey *PrivateKey, pub *PublicKey, ephemeralBin, recipientBin, sharedInfo []byte) (cipher.AEAD, []byte, error) {
+	secret, err := privKey.SharedSecret(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := make([]byte, 0, 2*sizePublicKey+len(sharedInfo))
+	info = append(info, ephemeralBin...)
+	info = append(info, recipientBin...)
+	info = append(info, sharedInfo...)
+
+	var okm [eciesKeySize + eciesNonceSize]byte
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), okm[:]); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(okm[:eciesKeySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, okm[eciesKeySize:], nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards"
+)
+
+func TestSharedSecret(t *testing.T) {
+	t.Parallel()
+
+	alice, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1, err := alice.SharedSecret(&bob.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := bob.SharedSecret(&alice.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s1, s2) {
+		t.Fatal("shared secrets differ")
+	}
+
+	// adding a small order point doesn't change the shared secret
+	var small twistededwards.PointAffine
+	small.X.SetZero()
+	small.Y.SetOne()
+	small.Y.Neg(&small.Y) // (0, -1) has order 2
+	var tweaked PublicKey
+	tweaked.A.Add(&bob.PublicKey.A, &small)
+	s3, err := alice.SharedSecret(&tweaked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s1, s3) {
+		t.Fatal("the cofactor is not cleared")
+	}
+
+	// small order public keys are rejected
+	if _, err = alice.SharedSecret(&PublicKey{A: small}); err != errSmallOrderPoint {
+		t.Fatal("expected errSmallOrderPoint, got", err)
+	}
+
+	// points not on the curve are rejected
+	var invalid PublicKey
+	invalid.A.X.SetOne()
+	invalid.A.Y.SetOne()
+	if _, err = alice.SharedSecret(&invalid); err != errNotOnCurve {
+		t.Fatal("expected errNotOnCurve, got", err)
+	}
+}
+
+func TestECIES(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("testing ECIES")
+	sharedInfo := []byte("context")
+
+	ciphertext, err := Encrypt(crand.Reader, &privKey.PublicKey, msg, sharedInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := privKey.Decrypt(ciphertext, sharedInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, decrypted) {
+		t.Fatal("wrong decrypted message")
+	}
+
+	// wrong shared info
+	if _, err = privKey.Decrypt(ciphertext, nil); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	// wrong recipient
+	other, _ := GenerateKey(crand.Reader)
+	if _, err = other.Decrypt(ciphertext, sharedInfo); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	// tampered ciphertext
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err = privKey.Decrypt(ciphertext, sharedInfo); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	if _, err = privKey.Decrypt(ciphertext[:sizePublicKey], sharedInfo); err != errShortCiphertext {
+		t.Fatal("expected errShortCiphertext, got", err)
+	}
+}
+
+func BenchmarkSharedSecret(b *testing.B) {
+	alice, _ := GenerateKey(crand.Reader)
+	bob, _ := GenerateKey(crand.Reader)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		alice.SharedSecret(&bob.PublicKey)
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	// ErrInvalidParameters is returned when the threshold, the number of participants or
+	// an identifier is out of range
+	ErrInvalidParameters = errors.New("invalid threshold, number of participants or identifier")
+
+	// ErrInvalidProof is returned when the proof of knowledge of the secret of a participant
+	// of the DKG does not verify
+	ErrInvalidProof = errors.New("invalid proof of knowledge")
+
+	// ErrInvalidShare is returned when a secret share does not match the commitment of
+	// its dealer
+	ErrInvalidShare = errors.New("invalid secret share")
+
+	// ErrMissingMessage is returned when a message of a participant is missing or duplicated
+	ErrMissingMessage = errors.New("missing or duplicated message")
+)
+
+// GroupKey is the public information of a (threshold, n) sharing of a secret key s:
+// the group public key s⋅G and the public key shares sᵢ⋅G of the participants.
+type GroupKey struct {
+	// Threshold is the number of participants needed to sign
+	Threshold int
+
+	// PublicKey is the group public key s⋅G
+	PublicKey point
+
+	// PublicShares[i-1] is sᵢ⋅G, the public key share of the participant of identifier i
+	PublicShares []point
+}
+
+// KeyShare is the secret key share sᵢ = f(i) of the participant of identifier i, where f is a
+// polynomial of degree threshold-1 with f(0) = s.
+type KeyShare struct {
+	// ID is the identifier of the participant, in [1, n]
+	ID uint32
+
+	// Secret is the secret key share sᵢ
+	Secret big.Int
+
+	GroupKey
+}
+
+// SplitKey splits secret in n shares with a trusted dealer, any threshold of which can sign.
+// The participants have the identifiers 1, ..., n.
+func SplitKey(rand io.Reader, secret *big.Int, threshold, n int) ([]KeyShare, error) {
+	if err := checkParameters(threshold, n); err != nil {
+		return nil, err
+	}
+	f, err := randomPolynomial(rand, secret, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return splitKey(f, n), nil
+}
+
+// splitKey returns the shares f(1), ..., f(n) of the polynomial f
+func splitKey(f secretPolynomial, n int) []KeyShare {
+	commitment := commit(f)
+	groupKey := GroupKey{
+		Threshold:    len(f),
+		PublicKey:    commitment[0],
+		PublicShares: make([]point, n),
+	}
+	for i := range groupKey.PublicShares {
+		groupKey.PublicShares[i] = evalCommitment(commitment, uint32(i+1))
+	}
+
+	res := make([]KeyShare, n)
+	for i := range res {
+		res[i].ID = uint32(i + 1)
+		res[i].Secret.Set(evalPolynomial(f, res[i].ID))
+		res[i].GroupKey = groupKey
+	}
+	return res
+}
+
+// IsValid reports whether the secret key share matches its public key share
+func (ks *KeyShare) IsValid() bool {
+	if ks.ID == 0 || int(ks.ID) > len(ks.PublicShares) {
+		return false
+	}
+	pub := scalarBaseMult(&ks.Secret)
+	return pub.Equal(&ks.PublicShares[ks.ID-1])
+}
+
+// DKGParticipant is a participant of the distributed key generation of Pedersen, with the
+// Feldman verifiable secret sharing and proofs of knowledge of the secrets as in FROST
+// (Komlo and Goldberg, https://eprint.iacr.org/2020/852).
+//
+// Each participant i deals a secret aᵢ₀ with a random polynomial fᵢ of degree threshold-1, the
+// group secret key being s = Σ aᵢ₀. The participants exchange the messages of two rounds:
+//
+//  1. broadcast the DKGCommitment returned by Commitment,
+//  2. on receipt of the commitments of all the participants, send the DKGShare returned by
+//     Shares to each participant.
+//
+// Finalize then returns the key share of the participant from the shares it received.
+// No participant learns s.
+type DKGParticipant struct {
+	id        uint32
+	threshold int
+	n         int
+
+	coefficients secretPolynomial
+	commitment   DKGCommitment
+
+	// commitments[j-1] is the commitment of the participant j, set by Shares
+	commitments []DKGCommitment
+}
+
+// DKGCommitment is the round 1 message of a participant of the DKG: the Feldman commitment
+// aᵢⱼ⋅G, j < threshold, to the coefficients of its polynomial, and a Schnorr proof (R, z)
+// of knowledge of aᵢ₀.
+type DKGCommitment struct {
+	From       uint32
+	Commitment []point
+	ProofR     point
+	ProofZ     big.Int
+}
+
+// DKGShare is the round 2 message of a participant of the DKG, the secret share fᵢ(j) sent
+// by the participant i to the participant j. It must be sent on a confidential channel.
+type DKGShare struct {
+	From, To uint32
+	Share    big.Int
+}
+
+// NewDKGParticipant returns the participant of identifier id ∈ [1, n] of a DKG of a key
+// which any threshold participants can use.
+func NewDKGParticipant(id uint32, threshold, n int, rand io.Reader) (*DKGParticipant, error) {
+	if err := checkParameters(threshold, n); err != nil {
+		return nil, err
+	}
+	if id == 0 || int(id) > n {
+		return nil, ErrInvalidParameters
+	}
+
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	res := &DKGParticipant{
+		id:        id,
+		threshold: threshold,
+		n:         n,
+	}
+	if res.coefficients, err = randomPolynomial(rand, secret, threshold); err != nil {
+		return nil, err
+	}
+
+	// proof of knowledge of aᵢ₀: R = k⋅G, c = H(i, aᵢ₀⋅G, R), z = k + aᵢ₀⋅c
+	k, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	res.commitment.From = id
+	res.commitment.Commitment = commit(res.coefficients)
+	res.commitment.ProofR = scalarBaseMult(k)
+	c := dkgChallenge(id, &res.commitment.Commitment[0], &res.commitment.ProofR)
+	res.commitment.ProofZ.Mul(c, secret).
+		Add(&res.commitment.ProofZ, k).
+		Mod(&res.commitment.ProofZ, order)
+
+	return res, nil
+}
+
+// ID returns the identifier of the participant
+func (p *DKGParticipant) ID() uint32 {
+	return p.id
+}
+
+// Commitment returns the round 1 message of the participant, to broadcast to all the
+// participants.
+func (p *DKGParticipant) Commitment() *DKGCommitment {
+	return &p.commitment
+}
+
+// Shares verifies the round 1 messages of all the participants, its own included, and
+// returns the round 2 messages of the participant, one for each other participant.
+//
+// If a proof of knowledge does not verify, it returns an error wrapping ErrInvalidProof
+// and the identifier of the culprit.
+func (p *DKGParticipant) Shares(commitments []DKGCommitment) ([]DKGShare, error) {
+	sorted := make([]DKGCommitment, p.n)
+	for i := range commitments {
+		from := commitments[i].From
+		if from == 0 || int(from) > p.n || sorted[from-1].Commitment != nil {
+			return nil, fmt.Errorf("participant %d: %w", from, ErrMissingMessage)
+		}
+		if err := commitments[i].verify(p.threshold); err != nil {
+			return nil, fmt.Errorf("participant %d: %w", from, err)
+		}
+		sorted[from-1] = commitments[i]
+	}
+	for i := range sorted {
+		if sorted[i].Commitment == nil {
+			return nil, fmt.Errorf("participant %d: %w", i+1, ErrMissingMessage)
+		}
+	}
+	if !sorted[p.id-1].Commitment[0].Equal(&p.commitment.Commitment[0]) {
+		return nil, fmt.Errorf("participant %d: %w", p.id, ErrInvalidProof)
+	}
+	p.commitments = sorted
+
+	res := make([]DKGShare, 0, p.n-1)
+	for j := 1; j <= p.n; j++ {
+		if uint32(j) == p.id {
+			continue
+		}
+		share := DKGShare{From: p.id, To: uint32(j)}
+		share.Share.Set(evalPolynomial(p.coefficients, uint32(j)))
+		res = append(res, share)
+	}
+	return res, nil
+}
+
+// Finalize verifies the round 2 messages sent to the participant by all the other
+// participants, and returns its key share sᵢ = Σⱼ fⱼ(i).
+//
+// If a share does not match the commitment of its dealer, it returns an error wrapping
+// ErrInvalidShare and the identifier of the culprit.
+func (p *DKGParticipant) Finalize(shares []DKGShare) (*KeyShare, error) {
+	if p.commitments == nil {
+		return nil, ErrMissingMessage
+	}
+	received := make([]bool, p.n)
+	received[p.id-1] = true
+
+	res := &KeyShare{ID: p.id}
+	res.Secret.Set(evalPolynomial(p.coefficients, p.id))
+	for i := range shares {
+		from := shares[i].From
+		if shares[i].To != p.id || from == 0 || int(from) > p.n || received[from-1] {
+			return nil, fmt.Errorf("participant %d: %w", from, ErrMissingMessage)
+		}
+		received[from-1] = true
+
+		// fⱼ(i)⋅G = Σₖ iᵏ⋅aⱼₖ⋅G
+		expected := evalCommitment(p.commitments[from-1].Commitment, p.id)
+		pub := scalarBaseMult(&shares[i].Share)
+		if shares[i].Share.Cmp(order) >= 0 || !pub.Equal(&expected) {
+			return nil, fmt.Errorf("participant %d: %w", from, ErrInvalidShare)
+		}
+		res.Secret.Add(&res.Secret, &shares[i].Share)
+	}
+	for i := range received {
+		if !received[i] {
+			return nil, fmt.Errorf("participant %d: %w", i+1, ErrMissingMessage)
+		}
+	}
+	res.Secret.Mod(&res.Secret, order)
+
+	// the commitment to the polynomial Σⱼ fⱼ is the sum of the commitments
+	commitment := make([]point, p.threshold)
+	copy(commitment, p.commitments[0].Commitment)
+	for j := 1; j < p.n; j++ {
+		for k := range commitment {
+			commitment[k] = addPoints(&commitment[k], &p.commitments[j].Commitment[k])
+		}
+	}
+	res.Threshold = p.threshold
+	res.PublicKey = commitment[0]
+	res.PublicShares = make([]point, p.n)
+	for j := range res.PublicShares {
+		res.PublicShares[j] = evalCommitment(commitment, uint32(j+1))
+	}
+
+	return res, nil
+}
+
+// verify checks the size of the commitment and the proof of knowledge of its constant term
+func (c *DKGCommitment) verify(threshold int) error {
+	if len(c.Commitment) != threshold {
+		return ErrInvalidParameters
+	}
+	for i := range c.Commitment {
+		if isIdentity(&c.Commitment[i]) {
+			return ErrInvalidProof
+		}
+	}
+	// z⋅G = R + c⋅aᵢ₀⋅G
+	challenge := dkgChallenge(c.From, &c.Commitment[0], &c.ProofR)
+	lhs := scalarBaseMult(&c.ProofZ)
+	rhs := scalarMult(&c.Commitment[0], challenge)
+	rhs = addPoints(&rhs, &c.ProofR)
+	if !lhs.Equal(&rhs) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// dkgChallenge returns the challenge of the proof of knowledge H("dkg", id || φ || R)
+func dkgChallenge(id uint32, phi, R *point) *big.Int {
+	return hashToScalar("dkg", encodeID(id), encodePoint(phi), encodePoint(R))
+}
+
+// encodeID returns the encoding of the identifier as a scalar
+func encodeID(id uint32) []byte {
+	return encodeScalar(new(big.Int).SetUint64(uint64(id)))
+}
+
+// checkParameters checks that 1 ≤ threshold ≤ n
+func checkParameters(threshold, n int) error {
+	if threshold < 1 || threshold > n || int64(n) > int64(^uint32(0)) {
+		return ErrInvalidParameters
+	}
+	return nil
+}
+
+// secretPolynomial holds the coefficients aⱼ ∈ [0, order-1] of a polynomial f of degree
+// threshold-1, whose constant term f(0) is a secret
+type secretPolynomial = []*big.Int
+
+// randomPolynomial returns a polynomial f of degree threshold-1 with f(0) = secret, and
+// random coefficients read from rand
+func randomPolynomial(rand io.Reader, secret *big.Int, threshold int) (secretPolynomial, error) {
+	res := make(secretPolynomial, threshold)
+	res[0] = new(big.Int).Mod(secret, order)
+	for i := 1; i < threshold; i++ {
+		var err error
+		if res[i], err = randomScalar(rand); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// commit returns the Feldman commitment aⱼ⋅G of the coefficients aⱼ of f
+func commit(f secretPolynomial) []point {
+	res := make([]point, len(f))
+	for i := range f {
+		res[i] = scalarBaseMult(f[i])
+	}
+	return res
+}
+
+// evalPolynomial returns f(x) mod order, with Horner's method
+func evalPolynomial(f secretPolynomial, x uint32) *big.Int {
+	bx := new(big.Int).SetUint64(uint64(x))
+	res := new(big.Int)
+	for i := len(f) - 1; i >= 0; i-- {
+		res.Mul(res, bx).
+			Add(res, f[i]).
+			Mod(res, order)
+	}
+	return res
+}
+
+// evalCommitment returns f(x)⋅G from the commitment aⱼ⋅G to f, with Horner's method
+func evalCommitment(commitment []point, x uint32) point {
+	bx := new(big.Int).SetUint64(uint64(x))
+	res := commitment[len(commitment)-1]
+	for i := len(commitment) - 2; i >= 0; i-- {
+		res = scalarMult(&res, bx)
+		res = addPoints(&res, &commitment[i])
+	}
+	return res
+}
+
+// lagrangeCoefficient returns λᵢ = Πⱼ≠ᵢ j/(j-i) mod order, the Lagrange coefficient of
+// the identifier id at 0 in the set ids
+func lagrangeCoefficient(id uint32, ids []uint32) *big.Int {
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	bi := new(big.Int).SetUint64(uint64(id))
+	var bj, diff big.Int
+	for _, j := range ids {
+		if j == id {
+			continue
+		}
+		bj.SetUint64(uint64(j))
+		num.Mul(num, &bj).Mod(num, order)
+		diff.Sub(&bj, bi)
+		den.Mul(den, &diff).Mod(den, order)
+	}
+	den.ModInverse(den, order)
+	return num.Mul(num, den).Mod(num, order)
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package frost provides the FROST two-round threshold Schnorr signatures (RFC 9591)
+// on bls12-378's twistededwards curve.
+//
+// A (threshold, n) group key is shared among n participants, either by a trusted dealer
+// (SplitKey) or with a distributed key generation (DKGParticipant). A signature of the group
+// is then produced by any threshold participants and a coordinator:
+//
+//  1. each signer generates nonces and sends their commitment to the coordinator
+//     (KeyShare.Commit),
+//  2. the coordinator sends the message and the list of commitments to the signers, which
+//     send back their signature share (KeyShare.Sign),
+//  3. the coordinator aggregates the shares into a Schnorr signature (GroupKey.Aggregate),
+//     identifying the participants which sent invalid shares.
+//
+// RFC 9591 registers no ciphersuite on bls12-378's twistededwards curve. The one implemented
+// here is adapted from FROST(Ed25519, SHA-512), with the context string "FROST-bls12-378-twistededwards-SHA512-v1".
+// The challenge and the encoding of the signatures are those of the eddsa package with
+// SHA-512, so that the signatures also verify with eddsa.PublicKey.Verify.
+//
+// # See also
+//
+// https://www.rfc-editor.org/rfc/rfc9591.html
+// https://eprint.iacr.org/2020/852
+package frost
@@ -0,0 +1,249 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards/eddsa"
+)
+
+// sign runs FROST with the signers and returns their commitments and signature shares
+func sign(t *testing.T, signers []KeyShare, msg []byte) ([]SigningCommitment, []SignatureShare) {
+	t.Helper()
+	nonces := make([]*SigningNonces, len(signers))
+	commitments := make([]SigningCommitment, len(signers))
+	for i := range signers {
+		var err error
+		if nonces[i], err = signers[i].Commit(crand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		// the commitments travel serialized
+		if _, err = commitments[i].SetBytes(nonces[i].Commitment.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shares := make([]SignatureShare, len(signers))
+	for i := range signers {
+		share, err := signers[i].Sign(nonces[i], msg, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = shares[i].SetBytes(share.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return commitments, shares
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 3, 5
+	keyShares, err := runDKG(newNetwork(n), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing FROST")
+
+	for _, signers := range [][]KeyShare{keyShares[:threshold], keyShares[n-threshold:], keyShares} {
+		commitments, shares := sign(t, signers, msg)
+		for i := range shares {
+			valid, err := groupKey.VerifySignatureShare(&shares[i], msg, commitments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !valid {
+				t.Fatal("the signature share should be valid")
+			}
+		}
+		sig, err := groupKey.Aggregate(msg, commitments, shares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !groupKey.Verify(msg, sig) {
+			t.Fatal("the signature should be valid")
+		}
+		if groupKey.Verify([]byte("another message"), sig) {
+			t.Fatal("the signature should not be valid for another message")
+		}
+
+		// the signature is an eddsa signature
+		pub := eddsa.PublicKey{A: groupKey.PublicKey}
+		valid, err := pub.Verify(sig.Bytes(), msg, sha512.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the signature should be a valid eddsa signature")
+		}
+	}
+
+	// less than threshold signers
+	nonces, err := keyShares[0].Commit(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = keyShares[0].Sign(nonces, msg, []SigningCommitment{nonces.Commitment}); err != ErrInvalidCommitments {
+		t.Fatal("expected ErrInvalidCommitments, got", err)
+	}
+}
+
+func TestNoncesReuse(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 2
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonces := make([]*SigningNonces, n)
+	commitments := make([]SigningCommitment, n)
+	for i := range keyShares {
+		if nonces[i], err = keyShares[i].Commit(crand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		commitments[i] = nonces[i].Commitment
+	}
+	if _, err = keyShares[0].Sign(nonces[0], []byte("first"), commitments); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = keyShares[0].Sign(nonces[0], []byte("second"), commitments); err != ErrNoncesUsed {
+		t.Fatal("expected ErrNoncesUsed, got", err)
+	}
+}
+
+func TestCulprits(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 3, 4
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing FROST")
+	commitments, shares := sign(t, keyShares[1:], msg)
+
+	// the participant 3 sends an invalid share
+	shares[1].Z.Add(&shares[1].Z, big.NewInt(1)).Mod(&shares[1].Z, order)
+	valid, err := groupKey.VerifySignatureShare(&shares[1], msg, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Fatal("the signature share should not be valid")
+	}
+	_, err = groupKey.Aggregate(msg, commitments, shares)
+	if !errors.Is(err, ErrInvalidSignatureShare) || !strings.Contains(err.Error(), "[3]") {
+		t.Fatal("expected ErrInvalidSignatureShare from the participant 3, got", err)
+	}
+
+	// a share of another participant
+	shares[1] = shares[0]
+	if _, err = groupKey.Aggregate(msg, commitments, shares); !errors.Is(err, ErrMissingMessage) {
+		t.Fatal("expected ErrMissingMessage, got", err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 3
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("testing FROST")
+	commitments, shares := sign(t, keyShares[:threshold], msg)
+	sig, err := keyShares[0].Aggregate(msg, commitments, shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sig2 Signature
+	read, err := sig2.SetBytes(sig.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != SizeSignature || !bytes.Equal(sig2.Bytes(), sig.Bytes()) || !keyShares[0].Verify(msg, &sig2) {
+		t.Fatal("Error serialize(deserialize(.)) of the signature")
+	}
+	if _, err = sig2.SetBytes(sig.Bytes()[1:]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+
+	var commitment SigningCommitment
+	buf := commitments[0].Bytes()
+	if _, err = commitment.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(commitment.Bytes(), buf) {
+		t.Fatal("Error serialize(deserialize(.)) of the signing commitment")
+	}
+	if _, err = commitment.SetBytes(buf[:len(buf)-1]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSign(b *testing.B) {
+	const threshold, n = 3, 5
+	keyShares, _ := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	msg := []byte("benchmark")
+	commitments := make([]SigningCommitment, threshold)
+	nonces := make([]*SigningNonces, threshold)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range commitments {
+			nonces[j], _ = keyShares[j].Commit(crand.Reader)
+			commitments[j] = nonces[j].Commitment
+		}
+		keyShares[0].Sign(nonces[0], msg, commitments)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	const threshold, n = 3, 5
+	keyShares, _ := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	msg := []byte("benchmark")
+	commitments := make([]SigningCommitment, threshold)
+	shares := make([]SignatureShare, threshold)
+	nonces := make([]*SigningNonces, threshold)
+	for j := range commitments {
+		nonces[j], _ = keyShares[j].Commit(crand.Reader)
+		commitments[j] = nonces[j].Commitment
+	}
+	for j := range shares {
+		share, _ := keyShares[j].Sign(nonces[j], msg, commitments)
+		shares[j] = *share
+	}
+	sig, _ := keyShares[0].Aggregate(msg, commitments, shares)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keyShares[0].Verify(msg, sig)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards"
+)
+
+// contextString is the prefix of the domain separation tags of the hashes
+const contextString = "FROST-bls12-378-twistededwards-SHA512-v1"
+
+// point is an element of the group of the keys
+type point = twistededwards.PointAffine
+
+const (
+	// sizeScalar is the size of an encoded scalar
+	sizeScalar = fr.Bytes
+
+	// sizePoint is the size of a compressed point
+	sizePoint = fr.Bytes
+)
+
+var (
+	errInvalidPoint  = errors.New("invalid point encoding")
+	errInvalidScalar = errors.New("invalid scalar encoding")
+)
+
+// order is the order of the prime order subgroup
+var order = func() *big.Int {
+	curveParams := twistededwards.GetEdwardsCurve()
+	return &curveParams.Order
+}()
+
+// identity returns the neutral element of the group
+func identity() point {
+	var res point
+	res.Y.SetOne()
+	return res
+}
+
+// isIdentity reports whether p is the neutral element of the group
+func isIdentity(p *point) bool {
+	return p.IsZero()
+}
+
+// scalarBaseMult returns s⋅G, where G is the base point
+func scalarBaseMult(s *big.Int) point {
+	var res point
+	curveParams := twistededwards.GetEdwardsCurve()
+	res.ScalarMultiplication(&curveParams.Base, s)
+	return res
+}
+
+// scalarMult returns s⋅p
+func scalarMult(p *point, s *big.Int) point {
+	var res point
+	res.ScalarMultiplication(p, s)
+	return res
+}
+
+// addPoints returns p + q
+func addPoints(p, q *point) point {
+	var res point
+	res.Add(p, q)
+	return res
+}
+
+// encodePoint returns the compressed encoding of p
+func encodePoint(p *point) []byte {
+	res := p.Bytes()
+	return res[:]
+}
+
+// decodePoint returns the point of compressed encoding buf. It must be in the prime order
+// subgroup, and not be the identity.
+func decodePoint(buf []byte) (point, error) {
+	var p point
+	if len(buf) != sizePoint {
+		return p, errInvalidPoint
+	}
+	if _, err := p.SetBytes(buf); err != nil {
+		return p, errInvalidPoint
+	}
+	if !p.IsOnCurve() || isIdentity(&p) || !p.IsInSubGroup() {
+		return p, errInvalidPoint
+	}
+	return p, nil
+}
+
+// encodeScalar returns the big endian encoding of s ∈ [0, order-1] on sizeScalar bytes
+func encodeScalar(s *big.Int) []byte {
+	res := make([]byte, sizeScalar)
+	s.FillBytes(res)
+	return res
+}
+
+// decodeScalar returns the scalar of big endian encoding buf, which must be smaller than the order
+func decodeScalar(buf []byte) (*big.Int, error) {
+	if len(buf) != sizeScalar {
+		return nil, errInvalidScalar
+	}
+	s := new(big.Int).SetBytes(buf)
+	if s.Cmp(order) >= 0 {
+		return nil, errInvalidScalar
+	}
+	return s, nil
+}
+
+// randomScalar returns a uniformly random scalar in [1, order-1] read from rand
+func randomScalar(rand io.Reader) (*big.Int, error) {
+	// 128 extra bits make the bias of the reduction negligible
+	buf := make([]byte, (order.BitLen()+7)/8+16)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		s := new(big.Int).SetBytes(buf)
+		s.Mod(s, order)
+		if s.Sign() != 0 {
+			return s, nil
+		}
+	}
+}
+
+// hashToScalar returns SHA-512(contextString || dst || data) mod order
+func hashToScalar(dst string, data ...[]byte) *big.Int {
+	digest := hashBytes(dst, data...)
+	s := new(big.Int).SetBytes(digest)
+	return s.Mod(s, order)
+}
+
+// hashBytes returns SHA-512(contextString || dst || data)
+func hashBytes(dst string, data ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(contextString + dst))
+	for i := range data {
+		h.Write(data[i])
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package ecvrf provides the elliptic curve verifiable random function of RFC 9381 (ECVRF)
+// on bls12-381's bandersnatch curve.
+//
+// A VRF output beta (Proof.Output) is a pseudorandom function of an input alpha keyed by the
+// private key, and the proof π (PrivateKey.Prove) convinces anyone holding the public key that
+// beta is the output on alpha (PublicKey.Verify, or BatchVerify for many proofs at once).
+//
+// RFC 9381 only registers suites on P-256 and edwards25519. The suites implemented here are
+// adaptations of ECVRF-EDWARDS25519-SHA512-ELL2 and ECVRF-EDWARDS25519-SHA512-TAI, with
+// SHA-512 for the challenge, the nonce and the output, and cLen = 16:
+//   - ELL2 (suite_string 0x10, the default), where the input is encoded to the curve with the
+//     Elligator 2 map of RFC 9380 (EncodeToCurve);
+//   - TAI (suite_string 0x11, WithTryAndIncrement), where the input is encoded with the
+//     try-and-increment method;
+//   - a SNARK friendly suite (suite_string 0x12, WithFieldHash), where SHA-512 is replaced by
+//     a hash on field elements, typically MiMC, so that the proofs can be verified in a circuit.
+//
+// The scalars are encoded in big endian and the points are compressed as in eddsa.
+//
+// # See also
+//
+// https://www.rfc-editor.org/rfc/rfc9381.html
+package ecvrf
@@ -0,0 +1,309 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+var (
+	// ErrInvalidSignatureShare is returned when a signature share does not verify
+	ErrInvalidSignatureShare = errors.New("invalid signature share")
+
+	// ErrInvalidCommitments is returned when the list of signing commitments is not a
+	// valid list of distinct participants, of size at least the threshold
+	ErrInvalidCommitments = errors.New("invalid list of signing commitments")
+
+	// ErrNoncesUsed is returned when signing with nonces which have already been used
+	ErrNoncesUsed = errors.New("the signing nonces have already been used")
+)
+
+// SigningNonces are the secret nonces of a participant for one signature, generated with
+// its SigningCommitment in the first round of FROST. They must be used only once, and
+// are erased by KeyShare.Sign.
+type SigningNonces struct {
+	hiding, binding big.Int
+
+	// Commitment is the public commitment to the nonces
+	Commitment SigningCommitment
+}
+
+// SigningCommitment is the round 1 message of a participant of FROST, the commitment
+// (D, E) = (d⋅G, e⋅G) to its hiding and binding nonces.
+type SigningCommitment struct {
+	ID      uint32
+	Hiding  point
+	Binding point
+}
+
+// SignatureShare is the round 2 message of a participant of FROST, its share zᵢ of the
+// signature.
+type SignatureShare struct {
+	ID uint32
+	Z  big.Int
+}
+
+// Signature is a Schnorr signature (R, z) of the group public key Y, verifying
+// z⋅G = R + c⋅Y with c = H2(R, Y, msg).
+// Its encoding and challenge are those of the eddsa package with SHA-512, so it also
+// verifies with eddsa.PublicKey.Verify.
+type Signature struct {
+	R point
+	Z big.Int
+}
+
+// Commit generates the nonces of the participant for one signature and their commitment,
+// the round 1 message of FROST to send to the coordinator (RFC 9591 Section 5.1).
+func (ks *KeyShare) Commit(rand io.Reader) (*SigningNonces, error) {
+	var hidingRandomness, bindingRandomness [32]byte
+	if _, err := io.ReadFull(rand, hidingRandomness[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand, bindingRandomness[:]); err != nil {
+		return nil, err
+	}
+	return ks.commit(hidingRandomness[:], bindingRandomness[:]), nil
+}
+
+// commit returns the nonces derived from the random inputs, nonce = H3(randomness || sᵢ)
+func (ks *KeyShare) commit(hidingRandomness, bindingRandomness []byte) *SigningNonces {
+	secret := encodeScalar(&ks.Secret)
+	res := &SigningNonces{}
+	res.hiding.Set(hashToScalar("nonce", hidingRandomness, secret))
+	res.binding.Set(hashToScalar("nonce", bindingRandomness, secret))
+	res.Commitment = SigningCommitment{
+		ID:      ks.ID,
+		Hiding:  scalarBaseMult(&res.hiding),
+		Binding: scalarBaseMult(&res.binding),
+	}
+	return res
+}
+
+// Sign returns the signature share of the participant on msg, the round 2 message of FROST
+// (RFC 9591 Section 5.2):
+//
+//	zᵢ = dᵢ + eᵢ⋅ρᵢ + λᵢ⋅sᵢ⋅c
+//
+// commitments are the commitments of the signing participants chosen by the coordinator,
+// which must include the commitment of nonces. The nonces are erased.
+func (ks *KeyShare) Sign(nonces *SigningNonces, msg []byte, commitments []SigningCommitment) (*SignatureShare, error) {
+	if nonces.hiding.Sign() == 0 || nonces.binding.Sign() == 0 {
+		return nil, ErrNoncesUsed
+	}
+	sorted, err := ks.sortCommitments(commitments)
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(sorted), func(j int) bool { return sorted[j].ID >= ks.ID })
+	if i == len(sorted) || sorted[i].ID != ks.ID ||
+		!sorted[i].Hiding.Equal(&nonces.Commitment.Hiding) || !sorted[i].Binding.Equal(&nonces.Commitment.Binding) {
+		return nil, ErrInvalidCommitments
+	}
+
+	bindingFactors := ks.bindingFactors(sorted, msg)
+	R := groupCommitment(sorted, bindingFactors)
+	c := challenge(&R, &ks.PublicKey, msg)
+	lambda := lagrangeCoefficient(ks.ID, ids(sorted))
+
+	res := &SignatureShare{ID: ks.ID}
+	res.Z.Mul(lambda, &ks.Secret).
+		Mul(&res.Z, c).
+		Add(&res.Z, &nonces.hiding)
+	var eRho big.Int
+	eRho.Mul(&nonces.binding, bindingFactors[i])
+	res.Z.Add(&res.Z, &eRho).
+		Mod(&res.Z, order)
+
+	nonces.hiding.SetUint64(0)
+	nonces.binding.SetUint64(0)
+	return res, nil
+}
+
+// VerifySignatureShare reports whether share is the valid signature share on msg of the
+// participant of the commitments of identifier share.ID (RFC 9591 Section 5.4):
+//
+//	zᵢ⋅G = Dᵢ + ρᵢ⋅Eᵢ + (c⋅λᵢ)⋅Yᵢ
+func (gk *GroupKey) VerifySignatureShare(share *SignatureShare, msg []byte, commitments []SigningCommitment) (bool, error) {
+	sorted, err := gk.sortCommitments(commitments)
+	if err != nil {
+		return false, err
+	}
+	bindingFactors := gk.bindingFactors(sorted, msg)
+	R := groupCommitment(sorted, bindingFactors)
+	c := challenge(&R, &gk.PublicKey, msg)
+	return gk.verifySignatureShare(share, sorted, bindingFactors, c), nil
+}
+
+// Aggregate verifies the signature shares on msg of the participants of the commitments,
+// and returns the signature R = Σ Dᵢ + ρᵢ⋅Eᵢ, z = Σ zᵢ (RFC 9591 Section 5.3).
+//
+// If signature shares are invalid, it returns an error wrapping ErrInvalidSignatureShare
+// and the identifiers of the culprits.
+func (gk *GroupKey) Aggregate(msg []byte, commitments []SigningCommitment, shares []SignatureShare) (*Signature, error) {
+	sorted, err := gk.sortCommitments(commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(sorted) {
+		return nil, ErrInvalidCommitments
+	}
+	bindingFactors := gk.bindingFactors(sorted, msg)
+
+	res := &Signature{R: groupCommitment(sorted, bindingFactors)}
+	c := challenge(&res.R, &gk.PublicKey, msg)
+
+	seen := make(map[uint32]bool, len(shares))
+	var culprits []uint32
+	for i := range shares {
+		if seen[shares[i].ID] {
+			return nil, fmt.Errorf("participant %d: %w", shares[i].ID, ErrMissingMessage)
+		}
+		seen[shares[i].ID] = true
+		if !gk.verifySignatureShare(&shares[i], sorted, bindingFactors, c) {
+			culprits = append(culprits, shares[i].ID)
+		}
+		res.Z.Add(&res.Z, &shares[i].Z)
+	}
+	if len(culprits) != 0 {
+		return nil, fmt.Errorf("participants %v: %w", culprits, ErrInvalidSignatureShare)
+	}
+	res.Z.Mod(&res.Z, order)
+
+	return res, nil
+}
+
+// Verify reports whether sig is a valid signature of msg for the group public key:
+//
+//	z⋅G = R + c⋅Y, with c = H2(R, Y, msg)
+//
+// R must be in the prime order subgroup, which makes the verification equivalent to the
+// cofactored verification of the eddsa package.
+func (gk *GroupKey) Verify(msg []byte, sig *Signature) bool {
+	if sig.Z.Sign() < 0 || sig.Z.Cmp(order) >= 0 || isIdentity(&gk.PublicKey) || isIdentity(&sig.R) {
+		return false
+	}
+	if !sig.R.IsOnCurve() || !sig.R.IsInSubGroup() {
+		return false
+	}
+	c := challenge(&sig.R, &gk.PublicKey, msg)
+
+	lhs := scalarBaseMult(&sig.Z)
+	rhs := scalarMult(&gk.PublicKey, c)
+	rhs = addPoints(&rhs, &sig.R)
+	return lhs.Equal(&rhs)
+}
+
+// verifySignatureShare verifies the signature share, see VerifySignatureShare
+func (gk *GroupKey) verifySignatureShare(share *SignatureShare, sorted []SigningCommitment, bindingFactors []*big.Int, c *big.Int) bool {
+	i := sort.Search(len(sorted), func(j int) bool { return sorted[j].ID >= share.ID })
+	if i == len(sorted) || sorted[i].ID != share.ID || share.Z.Sign() < 0 || share.Z.Cmp(order) >= 0 {
+		return false
+	}
+
+	// Dᵢ + ρᵢ⋅Eᵢ + (c⋅λᵢ)⋅Yᵢ
+	rhs := scalarMult(&sorted[i].Binding, bindingFactors[i])
+	rhs = addPoints(&rhs, &sorted[i].Hiding)
+	var cLambda big.Int
+	cLambda.Mul(c, lagrangeCoefficient(share.ID, ids(sorted))).Mod(&cLambda, order)
+	yi := scalarMult(&gk.PublicShares[share.ID-1], &cLambda)
+	rhs = addPoints(&rhs, &yi)
+
+	lhs := scalarBaseMult(&share.Z)
+	return lhs.Equal(&rhs)
+}
+
+// sortCommitments returns the commitments sorted by identifier, after checking that they
+// are at least threshold, of distinct known participants
+func (gk *GroupKey) sortCommitments(commitments []SigningCommitment) ([]SigningCommitment, error) {
+	if len(commitments) < gk.Threshold || len(commitments) > len(gk.PublicShares) {
+		return nil, ErrInvalidCommitments
+	}
+	sorted := make([]SigningCommitment, len(commitments))
+	copy(sorted, commitments)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for i := range sorted {
+		if sorted[i].ID == 0 || int(sorted[i].ID) > len(gk.PublicShares) || (i > 0 && sorted[i].ID == sorted[i-1].ID) {
+			return nil, ErrInvalidCommitments
+		}
+		if isIdentity(&sorted[i].Hiding) || isIdentity(&sorted[i].Binding) {
+			return nil, ErrInvalidCommitments
+		}
+	}
+	return sorted, nil
+}
+
+// bindingFactors returns the binding factors ρᵢ of the sorted commitments
+// (RFC 9591 Section 4.4):
+//
+//	ρᵢ = H1(Y || H4(msg) || H5(encoded commitments) || i)
+func (gk *GroupKey) bindingFactors(sorted []SigningCommitment, msg []byte) []*big.Int {
+	var encodedCommitments []byte
+	for i := range sorted {
+		encodedCommitments = append(encodedCommitments, encodeID(sorted[i].ID)...)
+		encodedCommitments = append(encodedCommitments, encodePoint(&sorted[i].Hiding)...)
+		encodedCommitments = append(encodedCommitments, encodePoint(&sorted[i].Binding)...)
+	}
+	prefix := encodePoint(&gk.PublicKey)
+	prefix = append(prefix, hashBytes("msg", msg)...)
+	prefix = append(prefix, hashBytes("com", encodedCommitments)...)
+
+	res := make([]*big.Int, len(sorted))
+	for i := range sorted {
+		res[i] = hashToScalar("rho", prefix, encodeID(sorted[i].ID))
+	}
+	return res
+}
+
+// groupCommitment returns R = Σ Dᵢ + ρᵢ⋅Eᵢ (RFC 9591 Section 4.5)
+func groupCommitment(sorted []SigningCommitment, bindingFactors []*big.Int) point {
+	res := identity()
+	for i := range sorted {
+		bindingCommitment := scalarMult(&sorted[i].Binding, bindingFactors[i])
+		res = addPoints(&res, &bindingCommitment)
+		res = addPoints(&res, &sorted[i].Hiding)
+	}
+	return res
+}
+
+// challenge returns c = SHA-512(R.X || R.Y || Y.X || Y.Y || msg) mod order, the challenge
+// of the eddsa package
+func challenge(R, Y *point, msg []byte) *big.Int {
+	h := sha512.New()
+	rX, rY := R.X.Bytes(), R.Y.Bytes()
+	yX, yY := Y.X.Bytes(), Y.Y.Bytes()
+	h.Write(rX[:])
+	h.Write(rY[:])
+	h.Write(yX[:])
+	h.Write(yY[:])
+	h.Write(msg)
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, order)
+}
+
+// ids returns the identifiers of the commitments
+func ids(commitments []SigningCommitment) []uint32 {
+	res := make([]uint32, len(commitments))
+	for i := range commitments {
+		res[i] = commitments[i].ID
+	}
+	return res
+}
@@ -179,6 +179,29 @@ func (p *PointAffine) IsOnCurve() bool {
 	return lhs.Equal(&rhs)
 }
 
+// IsInSubGroup checks if a point is on the twisted Edwards curve and in the prime order subgroup,
+// that is if [Order]p = 0.
+//
+// The multiplication by the order uses the double-and-add algorithm, as the faster scalar
+// multiplications assume their input is in the subgroup.
+func (p *PointAffine) IsInSubGroup() bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	initOnce.Do(initCurveParams)
+
+	var q, res PointProj
+	q.FromAffine(p)
+	res.setInfinity()
+	for i := curveParams.Order.BitLen() - 1; i >= 0; i-- {
+		res.Double(&res)
+		if curveParams.Order.Bit(i) == 1 {
+			res.Add(&res, &q)
+		}
+	}
+	return res.IsZero()
+}
+
 // Neg sets p to -p1 and returns it
 func (p *PointAffine) Neg(p1 *PointAffine) *PointAffine {
 	p.Set(p1)
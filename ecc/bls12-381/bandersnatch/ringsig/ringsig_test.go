@@ -0,0 +1,414 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ringsig
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+// testSuites are the options of the hashes, with a message of each
+func testSuites() map[string]struct {
+	opts []Option
+	msg  []byte
+} {
+	var e fr.Element
+	e.SetUint64(42)
+	eBytes := e.Bytes()
+	return map[string]struct {
+		opts []Option
+		msg  []byte
+	}{
+		"SHA-512":   {nil, []byte("vote for proposal 42")},
+		"FieldHash": {[]Option{WithFieldHash(hash.MIMC_BLS12_381.New)}, eBytes[:]},
+	}
+}
+
+// newRing returns the private keys and the ring of n public keys
+func newRing(t testing.TB, n int) ([]*PrivateKey, []PublicKey) {
+	privKeys := make([]*PrivateKey, n)
+	ring := make([]PublicKey, n)
+	for i := range privKeys {
+		var err error
+		if privKeys[i], err = GenerateKey(crand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		ring[i] = privKeys[i].PublicKey
+	}
+	return privKeys, ring
+}
+
+// newCommitments returns the commitment keys zᵢ and the commitments zᵢ⋅Base
+func newCommitments(t testing.TB, n int) ([]*big.Int, []bandersnatch.PointAffine) {
+	z := make([]*big.Int, n)
+	commitments := make([]bandersnatch.PointAffine, n)
+	for i := range z {
+		var err error
+		if z[i], err = randomScalar(crand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		commitments[i].ScalarMultiplication(&curveParams.Base, z[i])
+	}
+	return z, commitments
+}
+
+// copyResponses returns a deep copy of the responses
+func copyResponses(s []big.Int) []big.Int {
+	res := make([]big.Int, len(s))
+	for i := range s {
+		res[i].Set(&s[i])
+	}
+	return res
+}
+
+func TestLSAG(t *testing.T) {
+	t.Parallel()
+
+	const n = 4
+	privKeys, ring := newRing(t, n)
+	_, others := newRing(t, n)
+
+	for name, suite := range testSuites() {
+		for i := range privKeys {
+			sig, err := SignLSAG(crand.Reader, privKeys[i], ring, suite.msg, suite.opts...)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			valid, err := sig.Verify(ring, suite.msg, suite.opts...)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			if !valid {
+				t.Fatal(name, "the signature should be valid")
+			}
+
+			// another message or another ring
+			msg := append([]byte{}, suite.msg...)
+			msg[len(msg)-1] ^= 1
+			if valid, _ = sig.Verify(ring, msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid for another message")
+			}
+			if valid, _ = sig.Verify(others, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid for another ring")
+			}
+			swapped := append([]PublicKey{}, ring...)
+			swapped[0], swapped[1] = swapped[1], swapped[0]
+			if valid, _ = sig.Verify(swapped, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid for another order of the ring")
+			}
+
+			// tampered signatures
+			tampered := *sig
+			tampered.S = copyResponses(sig.S)
+			tampered.S[i].Add(&tampered.S[i], big.NewInt(1))
+			if valid, _ = tampered.Verify(ring, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid with another response")
+			}
+			tampered = *sig
+			tampered.C = big.Int{}
+			tampered.C.Add(&sig.C, big.NewInt(1))
+			if valid, _ = tampered.Verify(ring, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid with another challenge")
+			}
+			tampered = *sig
+			tampered.KeyImage.I.Add(&tampered.KeyImage.I, &curveParams.Base)
+			if valid, _ = tampered.Verify(ring, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid with another key image")
+			}
+			if _, err = tampered.Verify(ring[:n-1], suite.msg, suite.opts...); err != errInconsistentSize {
+				t.Fatal(name, "expected errInconsistentSize, got", err)
+			}
+		}
+	}
+
+	// a ring of a single key
+	sig, err := SignLSAG(crand.Reader, privKeys[0], ring[:1], []byte("alone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid, err := sig.Verify(ring[:1], []byte("alone")); err != nil || !valid {
+		t.Fatal("the signature should be valid", err)
+	}
+}
+
+func TestCLSAG(t *testing.T) {
+	t.Parallel()
+
+	const n = 4
+	privKeys, ring := newRing(t, n)
+	z, commitments := newCommitments(t, n)
+	_, otherCommitments := newCommitments(t, n)
+
+	for name, suite := range testSuites() {
+		for i := range privKeys {
+			sig, err := SignCLSAG(crand.Reader, privKeys[i], z[i], ring, commitments, suite.msg, suite.opts...)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			valid, err := sig.Verify(ring, commitments, suite.msg, suite.opts...)
+			if err != nil {
+				t.Fatal(name, err)
+			}
+			if !valid {
+				t.Fatal(name, "the signature should be valid")
+			}
+
+			msg := append([]byte{}, suite.msg...)
+			msg[len(msg)-1] ^= 1
+			if valid, _ = sig.Verify(ring, commitments, msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid for another message")
+			}
+			if valid, _ = sig.Verify(ring, otherCommitments, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid for other commitments")
+			}
+
+			tampered := *sig
+			tampered.D.Add(&tampered.D, &curveParams.Base)
+			if valid, _ = tampered.Verify(ring, commitments, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid with another commitment key image")
+			}
+			tampered = *sig
+			tampered.S = copyResponses(sig.S)
+			tampered.S[(i+1)%n].Add(&tampered.S[(i+1)%n], big.NewInt(1))
+			if valid, _ = tampered.Verify(ring, commitments, suite.msg, suite.opts...); valid {
+				t.Fatal(name, "the signature should not be valid with another response")
+			}
+		}
+	}
+
+	// the commitment key must open the commitment of the signer
+	if _, err := SignCLSAG(crand.Reader, privKeys[0], z[1], ring, commitments, []byte("vote")); err != errCommitmentKey {
+		t.Fatal("expected errCommitmentKey, got", err)
+	}
+	if _, err := SignCLSAG(crand.Reader, privKeys[0], z[0], ring, commitments[:n-1], []byte("vote")); err != errInconsistentSize {
+		t.Fatal("expected errInconsistentSize, got", err)
+	}
+}
+
+func TestLinkability(t *testing.T) {
+	t.Parallel()
+
+	const n = 3
+	privKeys, ring := newRing(t, n)
+	z, commitments := newCommitments(t, n)
+	_, otherRing := newRing(t, n-1)
+	otherRing = append(otherRing, ring[1])
+
+	for name, suite := range testSuites() {
+		keyImage, err := NewKeyImage(privKeys[1], suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+
+		// the signatures of the same key on different messages and rings are linked
+		sig1, err := SignLSAG(crand.Reader, privKeys[1], ring, suite.msg, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		msg := append([]byte{}, suite.msg...)
+		msg[0] ^= 1
+		sig2, err := SignLSAG(crand.Reader, privKeys[1], otherRing, msg, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		sig3, err := SignCLSAG(crand.Reader, privKeys[1], z[1], ring, commitments, suite.msg, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !sig1.KeyImage.Equal(&keyImage) || !sig2.KeyImage.Equal(&keyImage) || !sig3.KeyImage.Equal(&keyImage) {
+			t.Fatal(name, "the signatures of the same key should be linked")
+		}
+
+		// the signatures of another key are not
+		sig4, err := SignLSAG(crand.Reader, privKeys[2], ring, suite.msg, suite.opts...)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if sig4.KeyImage.Equal(&keyImage) {
+			t.Fatal(name, "the signatures of different keys should not be linked")
+		}
+	}
+
+	// the key images depend on the hash
+	keyImage, _ := NewKeyImage(privKeys[0])
+	keyImageFieldHash, _ := NewKeyImage(privKeys[0], WithFieldHash(hash.MIMC_BLS12_381.New))
+	if keyImage.Equal(&keyImageFieldHash) {
+		t.Fatal("the key images with different hashes should differ")
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	t.Parallel()
+
+	const n = 3
+	privKeys, ring := newRing(t, n)
+	outsider, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("vote")
+
+	if _, err = SignLSAG(crand.Reader, outsider, ring, msg); err != errNotInRing {
+		t.Fatal("expected errNotInRing, got", err)
+	}
+	if _, err = SignLSAG(crand.Reader, privKeys[0], nil, msg); err != errEmptyRing {
+		t.Fatal("expected errEmptyRing, got", err)
+	}
+	if _, err = SignLSAG(crand.Reader, privKeys[0], ring, msg, WithFieldHash(hash.MIMC_BLS12_381.New)); err != errFieldHashInput {
+		t.Fatal("expected errFieldHashInput, got", err)
+	}
+	if _, err = SignLSAG(crand.Reader, privKeys[0], ring, msg, WithFieldHash(nil)); err != errNilFieldHash {
+		t.Fatal("expected errNilFieldHash, got", err)
+	}
+
+	sig, err := SignLSAG(crand.Reader, privKeys[0], ring, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the point (0, -1) of order 2 and the identity
+	var lowOrder, identity bandersnatch.PointAffine
+	lowOrder.Y.SetOne().Neg(&lowOrder.Y)
+	identity.Y.SetOne()
+	for _, p := range []bandersnatch.PointAffine{lowOrder, identity} {
+		invalid := append([]PublicKey{}, ring...)
+		invalid[1].A = p
+		if _, err = SignLSAG(crand.Reader, privKeys[0], invalid, msg); err != errInvalidKey {
+			t.Fatal("expected errInvalidKey, got", err)
+		}
+		if _, err = sig.Verify(invalid, msg); err != errInvalidKey {
+			t.Fatal("expected errInvalidKey, got", err)
+		}
+
+		tampered := *sig
+		tampered.KeyImage.I = p
+		if valid, _ := tampered.Verify(ring, msg); valid {
+			t.Fatal("the signature should not be valid with an invalid key image")
+		}
+		if _, err = tampered.KeyImage.SetBytes(tampered.KeyImage.Bytes()); err != errInvalidKeyImage {
+			t.Fatal("expected errInvalidKeyImage, got", err)
+		}
+	}
+
+	// a commitment out of the prime order subgroup
+	z, commitments := newCommitments(t, n)
+	commitments[2] = lowOrder
+	if _, err = SignCLSAG(crand.Reader, privKeys[0], z[0], ring, commitments, msg); err != errInvalidCommitment {
+		t.Fatal("expected errInvalidCommitment, got", err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	privKeys, ring := newRing(t, n)
+	z, commitments := newCommitments(t, n)
+	msg := []byte("vote")
+
+	lsag, err := SignLSAG(crand.Reader, privKeys[2], ring, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := lsag.Bytes()
+	var lsag2 LSAGSignature
+	read, err := lsag2.SetBytes(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != len(buf) || !bytes.Equal(lsag2.Bytes(), buf) {
+		t.Fatal("Error serialize(deserialize(.)) of the LSAG signature")
+	}
+	if valid, err := lsag2.Verify(ring, msg); err != nil || !valid {
+		t.Fatal("the deserialized LSAG signature should be valid", err)
+	}
+	if _, err = lsag2.SetBytes(buf[:len(buf)-1]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+
+	clsag, err := SignCLSAG(crand.Reader, privKeys[3], z[3], ring, commitments, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = clsag.Bytes()
+	var clsag2 CLSAGSignature
+	if read, err = clsag2.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if read != len(buf) || !bytes.Equal(clsag2.Bytes(), buf) {
+		t.Fatal("Error serialize(deserialize(.)) of the CLSAG signature")
+	}
+	if valid, err := clsag2.Verify(ring, commitments, msg); err != nil || !valid {
+		t.Fatal("the deserialized CLSAG signature should be valid", err)
+	}
+
+	// a response which is not reduced
+	buf = lsag.Bytes()
+	curveParams.Order.FillBytes(buf[len(buf)-sizeFr:])
+	if _, err = lsag2.SetBytes(buf); err != errInvalidScalar {
+		t.Fatal("expected errInvalidScalar, got", err)
+	}
+
+	var keyImage KeyImage
+	buf = lsag.KeyImage.Bytes()
+	if read, err = keyImage.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if read != sizeKeyImage || !keyImage.Equal(&lsag.KeyImage) {
+		t.Fatal("Error serialize(deserialize(.)) of the key image")
+	}
+
+	var privKey PrivateKey
+	buf = privKeys[0].Bytes()
+	if read, err = privKey.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if read != sizePrivateKey || !bytes.Equal(privKey.Bytes(), buf) {
+		t.Fatal("Error serialize(deserialize(.)) of the private key")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignLSAG(b *testing.B) {
+	const n = 16
+	privKeys, ring := newRing(b, n)
+	msg := []byte("benchmark")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SignLSAG(crand.Reader, privKeys[0], ring, msg)
+	}
+}
+
+func BenchmarkVerifyCLSAG(b *testing.B) {
+	const n = 16
+	privKeys, ring := newRing(b, n)
+	z, commitments := newCommitments(b, n)
+	msg := []byte("benchmark")
+	sig, _ := SignCLSAG(crand.Reader, privKeys[0], z[0], ring, commitments, msg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sig.Verify(ring, commitments, msg)
+	}
+}
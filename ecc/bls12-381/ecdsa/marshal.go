@@ -18,9 +18,15 @@ package ecdsa
 
 import (
 	"crypto/subtle"
+	"errors"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
 	"io"
+	"math/big"
 )
 
+var errInvalidDER = errors.New("invalid DER encoding of the signature")
+
 // Bytes returns the binary representation of the public key
 // follows https://tools.ietf.org/html/rfc8032#section-3.1
 // and returns a compressed representation of the point (x,y)
@@ -106,3 +112,55 @@ func (sig *Signature) SetBytes(buf []byte) (int, error) {
 	n += sizeFr
 	return n, nil
 }
+
+// BytesDER returns the ASN.1 DER encoding of sig, as in SEC 1, Version 2.0, Appendix C.8:
+//
+//	ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+func (sig *Signature) BytesDER() []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig.R[:]))
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig.S[:]))
+	})
+	// the builder can't fail on non-negative integers
+	return b.BytesOrPanic()
+}
+
+// SetBytesDER sets sig from its ASN.1 DER encoding, see BytesDER.
+// Non-canonical encodings (BER lengths, non-minimal or negative integers) are rejected,
+// as well as integers which don't fit in sizeFr bytes.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytesDER(buf []byte) (int, error) {
+	input := cryptobyte.String(buf)
+	var inner cryptobyte.String
+	r, s := new(big.Int), new(big.Int)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return 0, errInvalidDER
+	}
+	if r.Sign() < 0 || s.Sign() < 0 || r.BitLen() > 8*sizeFr || s.BitLen() > 8*sizeFr {
+		return 0, errInvalidDER
+	}
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+	return len(buf) - len(input), nil
+}
+
+// IsLowS returns true if s ≤ order/2. Both (r, s) and (r, order-s) are valid
+// signatures of a message; protocols requiring non-malleable signatures only
+// accept the one with the low s.
+func (sig *Signature) IsLowS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS sets s to order-s if s > order/2, see IsLowS.
+func (sig *Signature) NormalizeS() {
+	s := new(big.Int).SetBytes(sig.S[:])
+	if s.Cmp(halfOrder) > 0 && s.Cmp(order) < 0 {
+		s.Sub(order, s)
+		s.FillBytes(sig.S[:])
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip implements the BLS12-381 precompiled contracts of Ethereum (EIP-2537,
+// https://eips.ethereum.org/EIPS/eip-2537), with their exact input parsing, error
+// semantics and output encoding:
+//   - BLS12_G1ADD (0x0b) and BLS12_G2ADD (0x0d),
+//   - BLS12_G1MSM (0x0c) and BLS12_G2MSM (0x0e),
+//   - BLS12_PAIRING_CHECK (0x0f),
+//   - BLS12_MAP_FP_TO_G1 (0x10) and BLS12_MAP_FP2_TO_G2 (0x11).
+//
+// A base field element is encoded as a 64-byte big endian integer in [0, p-1], whose
+// 16 top bytes are zeroes. An element c0 + c1⋅u of 𝔽p² is encoded as c0||c1, and a
+// point (x, y) as x||y. The point at infinity is encoded with zeroes. A scalar is a
+// 32-byte big endian integer, which is not required to be smaller than the order.
+//
+// Gas accounting is left to the caller.
+package eip
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// SizeFp is the size in bytes of an encoded base field element
+	SizeFp = 64
+	// SizeFp2 is the size in bytes of an encoded element of 𝔽p²
+	SizeFp2 = 2 * SizeFp
+	// SizeG1 is the size in bytes of an encoded point of G1
+	SizeG1 = 2 * SizeFp
+	// SizeG2 is the size in bytes of an encoded point of G2
+	SizeG2 = 2 * SizeFp2
+	// SizeScalar is the size in bytes of an encoded scalar
+	SizeScalar = 32
+
+	// fpPadding is the number of leading zero bytes of an encoded field element
+	fpPadding = SizeFp - fp.Bytes
+
+	sizeG1MSMPair     = SizeG1 + SizeScalar
+	sizeG2MSMPair     = SizeG2 + SizeScalar
+	sizePairingCouple = SizeG1 + SizeG2
+)
+
+var (
+	ErrInvalidInputLength  = errors.New("invalid input length")
+	ErrInvalidFieldElement = errors.New("invalid encoding of a field element")
+	ErrPointNotOnCurve     = errors.New("point is not on the curve")
+	ErrPointNotInSubgroup  = errors.New("point is not in the prime order subgroup")
+)
+
+// G1Add is the BLS12_G1ADD precompile. The input encodes two points of the
+// curve, which are not required to be in G1, on 256 bytes. It returns the
+// encoding of their sum, on 128 bytes.
+func G1Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeG1 {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G1Affine
+	if err := DecodeG1(&p, input[:SizeG1]); err != nil {
+		return nil, err
+	}
+	if err := DecodeG1(&q, input[SizeG1:]); err != nil {
+		return nil, err
+	}
+	p.Add(&p, &q)
+	return EncodeG1(&p), nil
+}
+
+// G1MSM is the BLS12_G1MSM precompile. The input is a non empty sequence of
+// pairs (P, s) of a point of G1 and a scalar, 160 bytes each. It returns the
+// encoding of ∑[s]P, on 128 bytes.
+func G1MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizeG1MSMPair != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizeG1MSMPair
+	points := make([]bls12381.G1Affine, 0, n)
+	scalars := make([]fr.Element, 0, n)
+	for i := 0; i < n; i++ {
+		offset := i * sizeG1MSMPair
+		var p bls12381.G1Affine
+		if err := decodeG1Subgroup(&p, input[offset:offset+SizeG1]); err != nil {
+			return nil, err
+		}
+		var s fr.Element
+		s.SetBytes(input[offset+SizeG1 : offset+sizeG1MSMPair])
+		if p.IsInfinity() || s.IsZero() {
+			continue
+		}
+		points = append(points, p)
+		scalars = append(scalars, s)
+	}
+
+	var res bls12381.G1Affine
+	if len(points) > 0 {
+		if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+			return nil, err
+		}
+	}
+	return EncodeG1(&res), nil
+}
+
+// G2Add is the BLS12_G2ADD precompile. The input encodes two points of the
+// curve, which are not required to be in G2, on 512 bytes. It returns the
+// encoding of their sum, on 256 bytes.
+func G2Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeG2 {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G2Affine
+	if err := DecodeG2(&p, input[:SizeG2]); err != nil {
+		return nil, err
+	}
+	if err := DecodeG2(&q, input[SizeG2:]); err != nil {
+		return nil, err
+	}
+	p.Add(&p, &q)
+	return EncodeG2(&p), nil
+}
+
+// G2MSM is the BLS12_G2MSM precompile. The input is a non empty sequence of
+// pairs (P, s) of a point of G2 and a scalar, 288 bytes each. It returns the
+// encoding of ∑[s]P, on 256 bytes.
+func G2MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizeG2MSMPair != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizeG2MSMPair
+	points := make([]bls12381.G2Affine, 0, n)
+	scalars := make([]fr.Element, 0, n)
+	for i := 0; i < n; i++ {
+		offset := i * sizeG2MSMPair
+		var p bls12381.G2Affine
+		if err := decodeG2Subgroup(&p, input[offset:offset+SizeG2]); err != nil {
+			return nil, err
+		}
+		var s fr.Element
+		s.SetBytes(input[offset+SizeG2 : offset+sizeG2MSMPair])
+		if p.IsInfinity() || s.IsZero() {
+			continue
+		}
+		points = append(points, p)
+		scalars = append(scalars, s)
+	}
+
+	var res bls12381.G2Affine
+	if len(points) > 0 {
+		if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+			return nil, err
+		}
+	}
+	return EncodeG2(&res), nil
+}
+
+// PairingCheck is the BLS12_PAIRING_CHECK precompile. The input is a non empty
+// sequence of pairs (P, Q) of points of G1 and G2, 384 bytes each. It returns 1
+// on 32 bytes if ∏ e(P, Q) = 1, and 0 otherwise.
+func PairingCheck(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizePairingCouple != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizePairingCouple
+	P := make([]bls12381.G1Affine, 0, n)
+	Q := make([]bls12381.G2Affine, 0, n)
+	for i := 0; i < n; i++ {
+		offset := i * sizePairingCouple
+		var p bls12381.G1Affine
+		var q bls12381.G2Affine
+		if err := decodeG1Subgroup(&p, input[offset:offset+SizeG1]); err != nil {
+			return nil, err
+		}
+		if err := decodeG2Subgroup(&q, input[offset+SizeG1:offset+sizePairingCouple]); err != nil {
+			return nil, err
+		}
+		// e(P, 0) = e(0, Q) = 1
+		if p.IsInfinity() || q.IsInfinity() {
+			continue
+		}
+		P = append(P, p)
+		Q = append(Q, q)
+	}
+
+	res := make([]byte, 32)
+	if len(P) == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	ok, err := bls12381.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// MapFpToG1 is the BLS12_MAP_FP_TO_G1 precompile. The input is a base field
+// element u, on 64 bytes. It returns the encoding of the point of G1 obtained
+// with the simplified SWU map and the cofactor clearing of RFC 9380
+// (bls12381.MapToG1), on 128 bytes.
+func MapFpToG1(input []byte) ([]byte, error) {
+	if len(input) != SizeFp {
+		return nil, ErrInvalidInputLength
+	}
+	var u fp.Element
+	if err := decodeFp(&u, input); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToG1(u)
+	return EncodeG1(&res), nil
+}
+
+// MapFp2ToG2 is the BLS12_MAP_FP2_TO_G2 precompile. The input is an element u
+// of 𝔽p², on 128 bytes. It returns the encoding of the point of G2 obtained
+// with the simplified SWU map and the cofactor clearing of RFC 9380
+// (bls12381.MapToG2), on 256 bytes.
+func MapFp2ToG2(input []byte) ([]byte, error) {
+	if len(input) != SizeFp2 {
+		return nil, ErrInvalidInputLength
+	}
+	var u bls12381.E2
+	if err := decodeFp2(&u, input); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToG2(u)
+	return EncodeG2(&res), nil
+}
+
+// EncodeG1 returns the encoding x||y of p, on 128 bytes.
+func EncodeG1(p *bls12381.G1Affine) []byte {
+	res := make([]byte, SizeG1)
+	encodeFp(res[:SizeFp], &p.X)
+	encodeFp(res[SizeFp:], &p.Y)
+	return res
+}
+
+// DecodeG1 sets p from its encoding x||y, which must be a point of the curve
+// or (0, 0). p is not required to be in G1.
+func DecodeG1(p *bls12381.G1Affine, buf []byte) error {
+	if len(buf) != SizeG1 {
+		return ErrInvalidInputLength
+	}
+	if err := decodeFp(&p.X, buf[:SizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&p.Y, buf[SizeFp:]); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+// EncodeG2 returns the encoding x.A0||x.A1||y.A0||y.A1 of p, on 256 bytes.
+func EncodeG2(p *bls12381.G2Affine) []byte {
+	res := make([]byte, SizeG2)
+	encodeFp2(res[:SizeFp2], &p.X)
+	encodeFp2(res[SizeFp2:], &p.Y)
+	return res
+}
+
+// DecodeG2 sets p from its encoding x.A0||x.A1||y.A0||y.A1, which must be a
+// point of the curve or (0, 0). p is not required to be in G2.
+func DecodeG2(p *bls12381.G2Affine, buf []byte) error {
+	if len(buf) != SizeG2 {
+		return ErrInvalidInputLength
+	}
+	if err := decodeFp2(&p.X, buf[:SizeFp2]); err != nil {
+		return err
+	}
+	if err := decodeFp2(&p.Y, buf[SizeFp2:]); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+func decodeG1Subgroup(p *bls12381.G1Affine, buf []byte) error {
+	if err := DecodeG1(p, buf); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsInSubGroup() {
+		return ErrPointNotInSubgroup
+	}
+	return nil
+}
+
+func decodeG2Subgroup(p *bls12381.G2Affine, buf []byte) error {
+	if err := DecodeG2(p, buf); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsInSubGroup() {
+		return ErrPointNotInSubgroup
+	}
+	return nil
+}
+
+func encodeFp(buf []byte, z *fp.Element) {
+	b := z.Bytes()
+	copy(buf[fpPadding:SizeFp], b[:])
+}
+
+func encodeFp2(buf []byte, z *bls12381.E2) {
+	encodeFp(buf[:SizeFp], &z.A0)
+	encodeFp(buf[SizeFp:], &z.A1)
+}
+
+func decodeFp(z *fp.Element, buf []byte) error {
+	for i := 0; i < fpPadding; i++ {
+		if buf[i] != 0 {
+			return ErrInvalidFieldElement
+		}
+	}
+	if err := z.SetBytesCanonical(buf[fpPadding:SizeFp]); err != nil {
+		return ErrInvalidFieldElement
+	}
+	return nil
+}
+
+func decodeFp2(z *bls12381.E2, buf []byte) error {
+	if err := decodeFp(&z.A0, buf[:SizeFp]); err != nil {
+		return err
+	}
+	return decodeFp(&z.A1, buf[SizeFp:])
+}
@@ -0,0 +1,356 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// gethVector is the format of the test vectors of go-ethereum's precompiles
+type gethVector struct {
+	Input         string
+	Expected      string
+	ExpectedError string
+	Name          string
+}
+
+// TestGethVectors runs the EIP-2537 test vectors of go-ethereum's precompiles,
+// copied from core/vm/testdata/precompiles of go-ethereum v1.17.7 into testdata.
+func TestGethVectors(t *testing.T) {
+	precompiles := map[string]func([]byte) ([]byte, error){
+		"blsG1Add":      G1Add,
+		"blsG1Mul":      G1MSM,
+		"blsG1MultiExp": G1MSM,
+		"blsG2Add":      G2Add,
+		"blsG2Mul":      G2MSM,
+		"blsG2MultiExp": G2MSM,
+		"blsPairing":    PairingCheck,
+		"blsMapG1":      MapFpToG1,
+		"blsMapG2":      MapFp2ToG2,
+	}
+	for name, precompile := range precompiles {
+		for _, prefix := range []string{"", "fail-"} {
+			data, err := os.ReadFile(filepath.Join("testdata", prefix+name+".json"))
+			require.NoError(t, err, "missing test vectors")
+			var vectors []gethVector
+			require.NoError(t, json.Unmarshal(data, &vectors))
+			require.NotEmpty(t, vectors, prefix+name)
+			for _, v := range vectors {
+				input, err := hex.DecodeString(v.Input)
+				require.NoError(t, err, v.Name)
+				out, err := precompile(input)
+				if v.ExpectedError != "" {
+					require.Error(t, err, v.Name)
+					continue
+				}
+				require.NoError(t, err, v.Name)
+				require.Equal(t, v.Expected, hex.EncodeToString(out), v.Name)
+			}
+		}
+	}
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	var s fr.Element
+	_, err := s.SetRandom()
+	require.NoError(t, err)
+	return s.BigInt(new(big.Int))
+}
+
+func scalarBytes(s *big.Int) []byte {
+	res := make([]byte, SizeScalar)
+	s.FillBytes(res)
+	return res
+}
+
+// nonSubgroupG1 returns a point of the curve which is not in G1
+func nonSubgroupG1(t *testing.T) bls12381.G1Affine {
+	var b fp.Element
+	b.SetUint64(4)
+	var p bls12381.G1Affine
+	for {
+		_, err := p.X.SetRandom()
+		require.NoError(t, err)
+		var y2 fp.Element
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &b)
+		if p.Y.Sqrt(&y2) != nil {
+			break
+		}
+	}
+	require.True(t, p.IsOnCurve())
+	require.False(t, p.IsInSubGroup())
+	return p
+}
+
+// nonSubgroupG2 returns a point of the twist which is not in G2
+func nonSubgroupG2(t *testing.T) bls12381.G2Affine {
+	_, _, _, g2 := bls12381.Generators()
+	// b' = y² - x³
+	var b, x3 bls12381.E2
+	b.Square(&g2.Y)
+	x3.Square(&g2.X).Mul(&x3, &g2.X)
+	b.Sub(&b, &x3)
+
+	var p bls12381.G2Affine
+	for {
+		_, err := p.X.A0.SetRandom()
+		require.NoError(t, err)
+		var y2 bls12381.E2
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &b)
+		if y2.Legendre() == 1 {
+			p.Y.Sqrt(&y2)
+			break
+		}
+	}
+	require.True(t, p.IsOnCurve())
+	require.False(t, p.IsInSubGroup())
+	return p
+}
+
+func TestEncoding(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := bls12381.Generators()
+
+	buf := EncodeG1(&g1)
+	assert.Equal(make([]byte, fpPadding), buf[:fpPadding])
+	assert.Equal(g1.X.Marshal(), buf[fpPadding:SizeFp])
+	var p bls12381.G1Affine
+	assert.NoError(DecodeG1(&p, buf))
+	assert.True(p.Equal(&g1))
+
+	// c0 comes first
+	buf = EncodeG2(&g2)
+	assert.Equal(g2.X.A0.Marshal(), buf[fpPadding:SizeFp])
+	var q bls12381.G2Affine
+	assert.NoError(DecodeG2(&q, buf))
+	assert.True(q.Equal(&g2))
+
+	// the padding must be zero
+	buf = EncodeG1(&g1)
+	buf[0] = 1
+	assert.ErrorIs(DecodeG1(&p, buf), ErrInvalidFieldElement)
+
+	// the coordinates must be reduced
+	buf = EncodeG1(&g1)
+	fp.Modulus().FillBytes(buf[SizeFp:SizeG1])
+	assert.ErrorIs(DecodeG1(&p, buf), ErrInvalidFieldElement)
+
+	// the point must be on the curve
+	buf = EncodeG1(&g1)
+	buf[SizeG1-1] ^= 1
+	assert.ErrorIs(DecodeG1(&p, buf), ErrPointNotOnCurve)
+	buf = EncodeG2(&g2)
+	buf[SizeG2-1] ^= 1
+	assert.ErrorIs(DecodeG2(&q, buf), ErrPointNotOnCurve)
+}
+
+func TestG1(t *testing.T) {
+	assert := require.New(t)
+
+	var p, q, expected bls12381.G1Affine
+	k1, k2 := randomScalar(t), randomScalar(t)
+	p.ScalarMultiplicationBase(k1)
+	q.ScalarMultiplicationBase(k2)
+
+	// addition
+	expected.Add(&p, &q)
+	out, err := G1Add(append(EncodeG1(&p), EncodeG1(&q)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// the addition doesn't check the subgroup
+	r := nonSubgroupG1(t)
+	expected.Add(&p, &r)
+	out, err = G1Add(append(EncodeG1(&p), EncodeG1(&r)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	_, err = G1Add(EncodeG1(&p))
+	assert.ErrorIs(err, ErrInvalidInputLength)
+
+	// MSM, with a non reduced scalar
+	s1, s2 := randomScalar(t), randomScalar(t)
+	var t1, t2 bls12381.G1Affine
+	t1.ScalarMultiplication(&p, s1)
+	t2.ScalarMultiplication(&q, s2)
+	expected.Add(&t1, &t2)
+	s2.Add(s2, fr.Modulus())
+
+	var input []byte
+	input = append(input, EncodeG1(&p)...)
+	input = append(input, scalarBytes(s1)...)
+	input = append(input, EncodeG1(&q)...)
+	input = append(input, scalarBytes(s2)...)
+	input = append(input, make([]byte, sizeG1MSMPair)...)
+	out, err = G1MSM(input)
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// single pair
+	out, err = G1MSM(input[:sizeG1MSMPair])
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&t1), out)
+
+	// null scalar
+	out, err = G1MSM(append(EncodeG1(&p), make([]byte, SizeScalar)...))
+	assert.NoError(err)
+	assert.Equal(make([]byte, SizeG1), out)
+
+	// errors
+	_, err = G1MSM(nil)
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	_, err = G1MSM(input[:sizeG1MSMPair+1])
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	_, err = G1MSM(append(EncodeG1(&r), scalarBytes(s1)...))
+	assert.ErrorIs(err, ErrPointNotInSubgroup)
+}
+
+func TestG2(t *testing.T) {
+	assert := require.New(t)
+
+	var p, q, expected bls12381.G2Affine
+	_, _, _, g2 := bls12381.Generators()
+	p.ScalarMultiplication(&g2, randomScalar(t))
+	q.ScalarMultiplication(&g2, randomScalar(t))
+
+	// addition
+	expected.Add(&p, &q)
+	out, err := G2Add(append(EncodeG2(&p), EncodeG2(&q)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG2(&expected), out)
+
+	// the addition doesn't check the subgroup
+	r := nonSubgroupG2(t)
+	expected.Add(&p, &r)
+	out, err = G2Add(append(EncodeG2(&p), EncodeG2(&r)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG2(&expected), out)
+
+	_, err = G2Add(EncodeG2(&p))
+	assert.ErrorIs(err, ErrInvalidInputLength)
+
+	// MSM
+	s1, s2 := randomScalar(t), randomScalar(t)
+	var t1, t2 bls12381.G2Affine
+	t1.ScalarMultiplication(&p, s1)
+	t2.ScalarMultiplication(&q, s2)
+	expected.Add(&t1, &t2)
+
+	var input []byte
+	input = append(input, EncodeG2(&p)...)
+	input = append(input, scalarBytes(s1)...)
+	input = append(input, EncodeG2(&q)...)
+	input = append(input, scalarBytes(s2)...)
+	out, err = G2MSM(input)
+	assert.NoError(err)
+	assert.Equal(EncodeG2(&expected), out)
+
+	// errors
+	_, err = G2MSM(nil)
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	_, err = G2MSM(append(EncodeG2(&r), scalarBytes(s1)...))
+	assert.ErrorIs(err, ErrPointNotInSubgroup)
+}
+
+func TestPairingCheck(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := bls12381.Generators()
+	var a, b, ab fr.Element
+	a.SetRandom()
+	b.SetRandom()
+	ab.Mul(&a, &b).Neg(&ab)
+
+	// e([a]g1, [b]g2)⋅e([-ab]g1, g2) = 1
+	var p1, p2 bls12381.G1Affine
+	var q1 bls12381.G2Affine
+	p1.ScalarMultiplication(&g1, a.BigInt(new(big.Int)))
+	q1.ScalarMultiplication(&g2, b.BigInt(new(big.Int)))
+	p2.ScalarMultiplication(&g1, ab.BigInt(new(big.Int)))
+
+	var input []byte
+	input = append(input, EncodeG1(&p1)...)
+	input = append(input, EncodeG2(&q1)...)
+	input = append(input, EncodeG1(&p2)...)
+	input = append(input, EncodeG2(&g2)...)
+
+	one := make([]byte, 32)
+	one[31] = 1
+	out, err := PairingCheck(input)
+	assert.NoError(err)
+	assert.Equal(one, out)
+
+	out, err = PairingCheck(input[:sizePairingCouple])
+	assert.NoError(err)
+	assert.Equal(make([]byte, 32), out)
+
+	out, err = PairingCheck(make([]byte, sizePairingCouple))
+	assert.NoError(err)
+	assert.Equal(one, out)
+
+	// errors
+	_, err = PairingCheck(nil)
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	_, err = PairingCheck(input[1:])
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	r := nonSubgroupG1(t)
+	_, err = PairingCheck(append(EncodeG1(&r), EncodeG2(&q1)...))
+	assert.ErrorIs(err, ErrPointNotInSubgroup)
+	q := nonSubgroupG2(t)
+	_, err = PairingCheck(append(EncodeG1(&p1), EncodeG2(&q)...))
+	assert.ErrorIs(err, ErrPointNotInSubgroup)
+}
+
+func TestMapToCurve(t *testing.T) {
+	assert := require.New(t)
+
+	var u fp.Element
+	u.SetRandom()
+	input := make([]byte, SizeFp)
+	encodeFp(input, &u)
+	out, err := MapFpToG1(input)
+	assert.NoError(err)
+	expected := bls12381.MapToG1(u)
+	assert.True(expected.IsInSubGroup())
+	assert.Equal(EncodeG1(&expected), out)
+
+	var u2 bls12381.E2
+	u2.SetRandom()
+	input = make([]byte, SizeFp2)
+	encodeFp2(input, &u2)
+	out, err = MapFp2ToG2(input)
+	assert.NoError(err)
+	expected2 := bls12381.MapToG2(u2)
+	assert.True(expected2.IsInSubGroup())
+	assert.Equal(EncodeG2(&expected2), out)
+
+	// errors
+	_, err = MapFpToG1(input)
+	assert.ErrorIs(err, ErrInvalidInputLength)
+	fp.Modulus().FillBytes(input[fpPadding:SizeFp])
+	_, err = MapFp2ToG2(input)
+	assert.ErrorIs(err, ErrInvalidFieldElement)
+}
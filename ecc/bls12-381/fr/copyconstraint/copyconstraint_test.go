@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package copyconstraint
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+)
+
+// buildCopyConstraints returns nbColumns columns of size n where the entry
+// k = j*n+i is equal to k%nbValues, and the permutation which cycles through the
+// entries having the same value.
+func buildCopyConstraints(nbColumns, n, nbValues int) ([]fr.Vector, []int64) {
+	columns := make([]fr.Vector, nbColumns)
+	for j := range columns {
+		columns[j] = make(fr.Vector, n)
+		for i := range columns[j] {
+			columns[j][i].SetUint64(uint64((j*n + i) % nbValues))
+		}
+	}
+	permutation := make([]int64, nbColumns*n)
+	for k := range permutation {
+		next := k + nbValues
+		if next >= len(permutation) {
+			next = k % nbValues
+		}
+		permutation[k] = int64(next)
+	}
+	return columns, permutation
+}
+
+func TestCopyConstraint(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, nbColumns := range []int{1, 3, 4} {
+		columns, permutation := buildCopyConstraints(nbColumns, 8, 5)
+		pk, vk, err := Setup(srs, nbColumns, permutation)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// correct proof
+		proof, err := Prove(srs, &pk, columns...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = Verify(srs, &vk, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		// tampered proofs
+		{
+			_proof := proof
+			_proof.ZShiftedProof.ClaimedValue.SetOne()
+			if err = Verify(srs, &vk, _proof); err == nil {
+				t.Fatal("verifying wrong proof should have failed")
+			}
+		}
+		{
+			_proof := proof
+			_proof.BatchedProof.ClaimedValues = _proof.BatchedProof.ClaimedValues[1:]
+			if err = Verify(srs, &vk, _proof); err != ErrNbClaimedValues {
+				t.Fatal("expected ErrNbClaimedValues")
+			}
+		}
+
+		// the proof does not hold for another permutation
+		_, otherPermutation := buildCopyConstraints(nbColumns, 8, 3)
+		_, otherVk, err := Setup(srs, nbColumns, otherPermutation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = Verify(srs, &otherVk, proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+
+		// columns violating the copy constraints
+		columns[nbColumns-1][7].SetUint64(42)
+		if _, err = Prove(srs, &pk, columns...); err != ErrCopyConstraint {
+			t.Fatal("expected ErrCopyConstraint")
+		}
+	}
+}
+
+func TestCopyConstraintSetup(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = Setup(srs, 2, make([]int64, 12)); err != ErrPermutationSize {
+		t.Fatal("expected ErrPermutationSize")
+	}
+	permutation := make([]int64, 16)
+	permutation[3] = 16
+	if _, _, err = Setup(srs, 2, permutation); err != ErrPermutationRange {
+		t.Fatal("expected ErrPermutationRange")
+	}
+}
+
+func BenchmarkProver(b *testing.B) {
+
+	const nbColumns = 3
+	const n = 1 << 12
+
+	srs, err := kzg.NewSRS(nbColumns*n, big.NewInt(13))
+	if err != nil {
+		b.Fatal(err)
+	}
+	columns, permutation := buildCopyConstraints(nbColumns, n, n/2)
+	pk, _, err := Setup(srs, nbColumns, permutation)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Prove(srs, &pk, columns...)
+	}
+}
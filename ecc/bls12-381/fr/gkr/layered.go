@@ -0,0 +1,562 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/sumcheck"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+// Layered circuits: unlike Circuit, in which every wire is the same gate applied to all instances,
+// a LayeredCircuit is a general arithmetic circuit of fan-in two gates, organized in layers.
+// Each gate reads two values of the previous layer, through arbitrary (sparse) wiring.
+// The proof system is the linear-time GKR prover of Libra (https://eprint.iacr.org/2019/317):
+// for each layer, a sumcheck on 2s variables proves
+//
+//	V(z) = ∑_{x,y} add(z, x, y)(W(x) + W(y)) + mul(z, x, y)W(x)W(y)
+//
+// where V and W are the multilinear extensions of the values of the layer and the previous one.
+
+// LayeredGateType is the type of a gate in a layered circuit
+type LayeredGateType uint8
+
+const (
+	LayeredAdd LayeredGateType = iota // LayeredAdd computes left + right
+	LayeredMul                        // LayeredMul computes left × right
+)
+
+// LayeredGate is a fan-in two gate. Left and Right are the indexes of its inputs in the previous layer.
+type LayeredGate struct {
+	Type        LayeredGateType
+	Left, Right int
+}
+
+// Layer of a LayeredCircuit
+type Layer struct {
+	Gates []LayeredGate
+	// Output is set if the values of the layer are known to the verifier.
+	// The last layer is always considered an output layer.
+	Output bool
+}
+
+// LayeredCircuit is a layered arithmetic circuit. Layers[0] reads the inputs.
+type LayeredCircuit struct {
+	NbInputs int
+	Layers   []Layer
+}
+
+// LayeredAssignment holds the values of every layer of a LayeredCircuit: the inputs at index 0,
+// then the values of Layers[i] at index i+1. Each layer is padded with zeros to a power of two (at least 2).
+// The verifier only needs the input and output layers.
+type LayeredAssignment []polynomial.MultiLin
+
+// LayeredProof is a sumcheck proof for each layer, from the last to the first.
+// The final evaluation proof of each sumcheck is the pair of claimed values W(u), W(v) of the previous layer.
+type LayeredProof []sumcheck.Proof
+
+// paddedLog returns the number of variables needed to represent a layer of size n
+func paddedLog(n int) int {
+	s := 1
+	for 1<<s < n {
+		s++
+	}
+	return s
+}
+
+func (c *LayeredCircuit) nbVars(layer int) int {
+	if layer < 0 {
+		return paddedLog(c.NbInputs)
+	}
+	return paddedLog(len(c.Layers[layer].Gates))
+}
+
+func (c *LayeredCircuit) isOutput(layer int) bool {
+	return layer == len(c.Layers)-1 || c.Layers[layer].Output
+}
+
+// nbClaims returns the number of claims made on the values of a layer
+func (c *LayeredCircuit) nbClaims(layer int) int {
+	res := 0
+	if layer != len(c.Layers)-1 {
+		res = 2 // from the sumcheck of the next layer
+	}
+	if c.isOutput(layer) {
+		res++
+	}
+	return res
+}
+
+// check makes sure the wiring of the circuit is well-formed
+func (c *LayeredCircuit) check() error {
+	if len(c.Layers) == 0 {
+		return fmt.Errorf("empty circuit")
+	}
+	prevSize := c.NbInputs
+	for i := range c.Layers {
+		if len(c.Layers[i].Gates) == 0 {
+			return fmt.Errorf("layer %d is empty", i)
+		}
+		for j, g := range c.Layers[i].Gates {
+			if g.Left < 0 || g.Left >= prevSize || g.Right < 0 || g.Right >= prevSize {
+				return fmt.Errorf("layer %d, gate %d: input out of range", i, j)
+			}
+			if g.Type != LayeredAdd && g.Type != LayeredMul {
+				return fmt.Errorf("layer %d, gate %d: unknown gate type", i, j)
+			}
+		}
+		prevSize = len(c.Layers[i].Gates)
+	}
+	return nil
+}
+
+// Evaluate computes the values of all layers from the inputs
+func (c *LayeredCircuit) Evaluate(inputs []fr.Element) (LayeredAssignment, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if len(inputs) != c.NbInputs {
+		return nil, fmt.Errorf("%d inputs expected, %d given", c.NbInputs, len(inputs))
+	}
+	res := make(LayeredAssignment, len(c.Layers)+1)
+	res[0] = make(polynomial.MultiLin, 1<<c.nbVars(-1))
+	copy(res[0], inputs)
+	for i := range c.Layers {
+		prev := res[i]
+		res[i+1] = make(polynomial.MultiLin, 1<<c.nbVars(i))
+		for j, g := range c.Layers[i].Gates {
+			if g.Type == LayeredAdd {
+				res[i+1][j].Add(&prev[g.Left], &prev[g.Right])
+			} else {
+				res[i+1][j].Mul(&prev[g.Left], &prev[g.Right])
+			}
+		}
+	}
+	return res, nil
+}
+
+// layerClaims are the claims V(zⱼ) = cⱼ on the values of a layer
+type layerClaims struct {
+	points [][]fr.Element
+	values []fr.Element
+}
+
+func (l *layerClaims) add(point []fr.Element, value fr.Element) {
+	l.points = append(l.points, point)
+	l.values = append(l.values, value)
+}
+
+// combinedEq returns the table of ∑ⱼ aʲ eq(zⱼ, g) for all g ∈ {0,1}ˢ
+func (l *layerClaims) combinedEq(a fr.Element) polynomial.MultiLin {
+	s := len(l.points[0])
+	res := make(polynomial.MultiLin, 1<<s)
+	tmp := make(polynomial.MultiLin, 1<<s)
+	var aJ fr.Element
+	aJ.SetOne()
+	for j := range l.points {
+		for i := range tmp {
+			tmp[i].SetZero()
+		}
+		tmp[0].Set(&aJ)
+		tmp.Eq(l.points[j])
+		for i := range res {
+			res[i].Add(&res[i], &tmp[i])
+		}
+		aJ.Mul(&aJ, &a)
+	}
+	return res
+}
+
+// layerSumcheckClaims is the prover side of the sumcheck for one layer.
+// The first s variables are x (phase 1), the last s are y (phase 2).
+type layerSumcheckClaims struct {
+	gates  []LayeredGate
+	claims *layerClaims
+	w      polynomial.MultiLin // values of the previous layer, W(b) for b ∈ {0,1}ˢ
+	s      int
+	prev   *layerClaims // the claims on the previous layer, filled at the end of the sumcheck
+
+	// phase 1: f₁(x) = W(x)A(x) + B(x); phase 2: f₂(y) = W(y)C(y) + D(y)
+	gZ                 polynomial.MultiLin // ∑ⱼ aʲ eq(zⱼ, g)
+	wFolded, mul, plus polynomial.MultiLin
+	u                  []fr.Element
+	wU                 fr.Element
+}
+
+func (c *layerSumcheckClaims) VarsNum() int {
+	return 2 * c.s
+}
+
+func (c *layerSumcheckClaims) ClaimsNum() int {
+	return len(c.claims.values)
+}
+
+func (c *layerSumcheckClaims) Combine(a fr.Element) polynomial.Polynomial {
+	c.gZ = c.claims.combinedEq(a)
+
+	// A(x) = ∑_{g: left(g)=x} G(g)×(W(right(g)) if g is a mul gate, 1 otherwise)
+	// B(x) = ∑_{g add: left(g)=x} G(g)W(right(g))
+	c.mul = make(polynomial.MultiLin, len(c.w))
+	c.plus = make(polynomial.MultiLin, len(c.w))
+	var t fr.Element
+	for i, g := range c.gates {
+		if g.Type == LayeredMul {
+			t.Mul(&c.gZ[i], &c.w[g.Right])
+			c.mul[g.Left].Add(&c.mul[g.Left], &t)
+		} else {
+			c.mul[g.Left].Add(&c.mul[g.Left], &c.gZ[i])
+			t.Mul(&c.gZ[i], &c.w[g.Right])
+			c.plus[g.Left].Add(&c.plus[g.Left], &t)
+		}
+	}
+	c.wFolded = c.w.Clone()
+
+	return c.roundPolynomial()
+}
+
+// roundPolynomial returns the evaluations at 1 and 2 of ∑_{i} W(r, X, i)M(r, X, i) + P(r, X, i),
+// where W, M, P are the current (folded) tables.
+func (c *layerSumcheckClaims) roundPolynomial() polynomial.Polynomial {
+	mid := len(c.wFolded) / 2
+	res := make(polynomial.Polynomial, 2)
+	var w2, m2, p2, t fr.Element
+	for i := 0; i < mid; i++ {
+		w0, w1 := &c.wFolded[i], &c.wFolded[i+mid]
+		m0, m1 := &c.mul[i], &c.mul[i+mid]
+		p0, p1 := &c.plus[i], &c.plus[i+mid]
+
+		// X = 1
+		t.Mul(w1, m1)
+		res[0].Add(&res[0], &t)
+		res[0].Add(&res[0], p1)
+
+		// X = 2: f(2) = 2f(1) - f(0)
+		w2.Double(w1).Sub(&w2, w0)
+		m2.Double(m1).Sub(&m2, m0)
+		p2.Double(p1).Sub(&p2, p0)
+		t.Mul(&w2, &m2)
+		res[1].Add(&res[1], &t)
+		res[1].Add(&res[1], &p2)
+	}
+	return res
+}
+
+func (c *layerSumcheckClaims) Next(r fr.Element) polynomial.Polynomial {
+	c.wFolded.Fold(r)
+	c.mul.Fold(r)
+	c.plus.Fold(r)
+
+	if len(c.u) < c.s {
+		c.u = append(c.u, r)
+		if len(c.u) == c.s {
+			// end of phase 1, all the x variables are bound to u
+			c.startPhase2()
+		}
+	}
+
+	return c.roundPolynomial()
+}
+
+// startPhase2 sets the tables for the sumcheck on the y variables:
+// C(y) = ∑_{g: right(g)=y} G(g)eq(u, left(g))×(W(u) if g is a mul gate, 1 otherwise)
+// D(y) = ∑_{g add: right(g)=y} G(g)eq(u, left(g))W(u)
+func (c *layerSumcheckClaims) startPhase2() {
+	c.wU = c.wFolded[0]
+
+	eqU := make(polynomial.MultiLin, len(c.w))
+	eqU[0].SetOne()
+	eqU.Eq(c.u)
+
+	c.mul = make(polynomial.MultiLin, len(c.w))
+	c.plus = make(polynomial.MultiLin, len(c.w))
+	var t fr.Element
+	for i, g := range c.gates {
+		t.Mul(&c.gZ[i], &eqU[g.Left])
+		if g.Type == LayeredMul {
+			t.Mul(&t, &c.wU)
+			c.mul[g.Right].Add(&c.mul[g.Right], &t)
+		} else {
+			c.mul[g.Right].Add(&c.mul[g.Right], &t)
+			t.Mul(&t, &c.wU)
+			c.plus[g.Right].Add(&c.plus[g.Right], &t)
+		}
+	}
+	c.wFolded = c.w.Clone()
+}
+
+// ProveFinalEval returns the claimed values W(u), W(v) and adds them to the claims on the previous layer
+func (c *layerSumcheckClaims) ProveFinalEval(r []fr.Element) interface{} {
+	c.wFolded.Fold(r[len(r)-1])
+	wV := c.wFolded[0]
+
+	c.prev.add(r[:c.s], c.wU)
+	c.prev.add(r[c.s:], wV)
+	return []fr.Element{c.wU, wV}
+}
+
+// layerSumcheckLazyClaims is the verifier side of the sumcheck for one layer
+type layerSumcheckLazyClaims struct {
+	gates  []LayeredGate
+	claims *layerClaims
+	s      int
+	prev   *layerClaims // the claims on the previous layer, filled at the end of the sumcheck
+}
+
+func (c *layerSumcheckLazyClaims) ClaimsNum() int {
+	return len(c.claims.values)
+}
+
+func (c *layerSumcheckLazyClaims) VarsNum() int {
+	return 2 * c.s
+}
+
+func (c *layerSumcheckLazyClaims) CombinedSum(a fr.Element) fr.Element {
+	evalsAsPoly := polynomial.Polynomial(c.claims.values)
+	return evalsAsPoly.Eval(&a)
+}
+
+func (c *layerSumcheckLazyClaims) Degree(int) int {
+	return 2
+}
+
+// VerifyFinalEval evaluates the wiring predicates at (z, u, v) from the description of the layer
+func (c *layerSumcheckLazyClaims) VerifyFinalEval(r []fr.Element, combinationCoeff fr.Element, purportedValue fr.Element, proof interface{}) error {
+	claimedValues, ok := proof.([]fr.Element)
+	if !ok || len(claimedValues) != 2 {
+		return fmt.Errorf("malformed final evaluation proof")
+	}
+	u, v := r[:c.s], r[c.s:]
+	wU, wV := claimedValues[0], claimedValues[1]
+
+	gZ := c.claims.combinedEq(combinationCoeff)
+	eqU := make(polynomial.MultiLin, 1<<c.s)
+	eqU[0].SetOne()
+	eqU.Eq(u)
+	eqV := make(polynomial.MultiLin, 1<<c.s)
+	eqV[0].SetOne()
+	eqV.Eq(v)
+
+	// ∑_g G(g)eq(u, left(g))eq(v, right(g))×(W(u) + W(v) or W(u)W(v))
+	var sumWUV, prodWUV, addPredicate, mulPredicate, t fr.Element
+	sumWUV.Add(&wU, &wV)
+	prodWUV.Mul(&wU, &wV)
+	for i, g := range c.gates {
+		t.Mul(&gZ[i], &eqU[g.Left]).Mul(&t, &eqV[g.Right])
+		if g.Type == LayeredMul {
+			mulPredicate.Add(&mulPredicate, &t)
+		} else {
+			addPredicate.Add(&addPredicate, &t)
+		}
+	}
+	var expected fr.Element
+	expected.Mul(&addPredicate, &sumWUV)
+	t.Mul(&mulPredicate, &prodWUV)
+	expected.Add(&expected, &t)
+
+	if !expected.Equal(&purportedValue) {
+		return fmt.Errorf("incompatible evaluations")
+	}
+
+	c.prev.add(u, wU)
+	c.prev.add(v, wV)
+	return nil
+}
+
+// LayeredChallengeNames returns the names of the challenges of the Fiat-Shamir transcript, in order
+func LayeredChallengeNames(c *LayeredCircuit, prefix string) []string {
+	var res []string
+	for i := len(c.Layers) - 1; i >= 0; i-- {
+		layerPrefix := prefix + "l" + strconv.Itoa(i) + "."
+		if c.isOutput(i) {
+			for k := 0; k < c.nbVars(i); k++ {
+				res = append(res, layerPrefix+"fC."+strconv.Itoa(k))
+			}
+		}
+		if c.nbClaims(i) >= 2 {
+			res = append(res, layerPrefix+"comb")
+		}
+		for k := 0; k < 2*c.nbVars(i-1); k++ {
+			res = append(res, layerPrefix+"pSP."+strconv.Itoa(k))
+		}
+	}
+	return res
+}
+
+// layeredProtocol holds the state common to the prover and the verifier
+type layeredProtocol struct {
+	circuit          *LayeredCircuit
+	transcript       *fiatshamir.Transcript
+	transcriptPrefix string
+	claims           []layerClaims // claims[i+1] are the claims on Layers[i], claims[0] on the inputs
+	baseChallenge    [][]byte
+}
+
+func newLayeredProtocol(c *LayeredCircuit, transcriptSettings fiatshamir.Settings) (*layeredProtocol, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	p := layeredProtocol{
+		circuit:          c,
+		claims:           make([]layerClaims, len(c.Layers)+1),
+		transcriptPrefix: transcriptSettings.Prefix,
+	}
+	if transcriptSettings.Transcript == nil {
+		challengeNames := LayeredChallengeNames(c, transcriptSettings.Prefix)
+		transcript := fiatshamir.NewTranscript(transcriptSettings.Hash, challengeNames...)
+		p.transcript = &transcript
+		for i := range transcriptSettings.BaseChallenges {
+			if err := p.transcript.Bind(challengeNames[0], transcriptSettings.BaseChallenges[i]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		p.transcript = transcriptSettings.Transcript
+	}
+	return &p, nil
+}
+
+// outputClaim adds a claim V(z) on an output layer, for a random z
+func (p *layeredProtocol) outputClaim(layer int, values polynomial.MultiLin) error {
+	layerPrefix := p.transcriptPrefix + "l" + strconv.Itoa(layer) + "."
+	z := make([]fr.Element, p.circuit.nbVars(layer))
+	if len(values) != 1<<len(z) {
+		return fmt.Errorf("layer %d: %d values expected, %d given", layer, 1<<len(z), len(values))
+	}
+	for k := range z {
+		name := layerPrefix + "fC." + strconv.Itoa(k)
+		if k == 0 {
+			// bind the claims from the previous sumcheck
+			for _, b := range p.baseChallenge {
+				if err := p.transcript.Bind(name, b); err != nil {
+					return err
+				}
+			}
+			p.baseChallenge = nil
+		}
+		bytes, err := p.transcript.ComputeChallenge(name)
+		if err != nil {
+			return err
+		}
+		z[k].SetBytes(bytes)
+	}
+	p.claims[layer+1].add(z, values.Evaluate(z, nil))
+	return nil
+}
+
+func (p *layeredProtocol) sumcheckSettings(layer int) fiatshamir.Settings {
+	res := fiatshamir.WithTranscript(p.transcript, p.transcriptPrefix+"l"+strconv.Itoa(layer)+".", p.baseChallenge...)
+	p.baseChallenge = nil
+	return res
+}
+
+func (p *layeredProtocol) setBaseChallenge(claimedValues []fr.Element) {
+	p.baseChallenge = make([][]byte, len(claimedValues))
+	for j := range claimedValues {
+		bytes := claimedValues[j].Bytes()
+		p.baseChallenge[j] = bytes[:]
+	}
+}
+
+// ProveLayered proves that the assignment is a correct evaluation of the layered circuit.
+// The assignment must be complete, as returned by LayeredCircuit.Evaluate.
+func ProveLayered(c *LayeredCircuit, assignment LayeredAssignment, transcriptSettings fiatshamir.Settings) (LayeredProof, error) {
+	p, err := newLayeredProtocol(c, transcriptSettings)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignment) != len(c.Layers)+1 {
+		return nil, fmt.Errorf("%d layers expected in the assignment, %d given", len(c.Layers)+1, len(assignment))
+	}
+
+	proof := make(LayeredProof, len(c.Layers))
+	for i := len(c.Layers) - 1; i >= 0; i-- {
+		if c.isOutput(i) {
+			if err = p.outputClaim(i, assignment[i+1]); err != nil {
+				return nil, err
+			}
+		}
+
+		claims := &layerSumcheckClaims{
+			gates:  c.Layers[i].Gates,
+			claims: &p.claims[i+1],
+			w:      assignment[i],
+			s:      c.nbVars(i - 1),
+			prev:   &p.claims[i],
+		}
+		if len(claims.w) != 1<<claims.s {
+			return nil, fmt.Errorf("layer %d: %d values expected, %d given", i-1, 1<<claims.s, len(claims.w))
+		}
+		if proof[i], err = sumcheck.Prove(claims, p.sumcheckSettings(i)); err != nil {
+			return nil, err
+		}
+
+		p.setBaseChallenge(proof[i].FinalEvalProof.([]fr.Element))
+	}
+
+	return proof, nil
+}
+
+// VerifyLayered checks a proof that the circuit maps the inputs to the outputs.
+// Only the inputs (assignment[0]) and the values of the output layers need to be provided, padded as in LayeredAssignment.
+func VerifyLayered(c *LayeredCircuit, assignment LayeredAssignment, proof LayeredProof, transcriptSettings fiatshamir.Settings) error {
+	p, err := newLayeredProtocol(c, transcriptSettings)
+	if err != nil {
+		return err
+	}
+	if len(assignment) != len(c.Layers)+1 {
+		return fmt.Errorf("%d layers expected in the assignment, %d given", len(c.Layers)+1, len(assignment))
+	}
+	if len(proof) != len(c.Layers) {
+		return fmt.Errorf("%d sumcheck proofs expected, %d given", len(c.Layers), len(proof))
+	}
+
+	for i := len(c.Layers) - 1; i >= 0; i-- {
+		if c.isOutput(i) {
+			if err = p.outputClaim(i, assignment[i+1]); err != nil {
+				return err
+			}
+		}
+
+		claims := &layerSumcheckLazyClaims{
+			gates:  c.Layers[i].Gates,
+			claims: &p.claims[i+1],
+			s:      c.nbVars(i - 1),
+			prev:   &p.claims[i],
+		}
+		if err = sumcheck.Verify(claims, proof[i], p.sumcheckSettings(i)); err != nil {
+			return fmt.Errorf("layer %d: %w", i, err)
+		}
+
+		p.setBaseChallenge(proof[i].FinalEvalProof.([]fr.Element))
+	}
+
+	// the claims on the inputs are checked directly
+	inputs := assignment[0]
+	if len(inputs) != 1<<c.nbVars(-1) {
+		return fmt.Errorf("%d inputs expected, %d given", 1<<c.nbVars(-1), len(inputs))
+	}
+	for j := range p.claims[0].points {
+		if y := inputs.Evaluate(p.claims[0].points[j], nil); !y.Equal(&p.claims[0].values[j]) {
+			return fmt.Errorf("incompatible evaluations")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/test_vector_utils"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomLayeredCircuit returns a circuit with layers of the given sizes and random wiring
+func randomLayeredCircuit(nbInputs int, sizes ...int) *LayeredCircuit {
+	c := LayeredCircuit{NbInputs: nbInputs, Layers: make([]Layer, len(sizes))}
+	prevSize := nbInputs
+	seed := 1
+	for i, size := range sizes {
+		c.Layers[i].Gates = make([]LayeredGate, size)
+		for j := range c.Layers[i].Gates {
+			seed = (seed*1103515245 + 12345) & 0x7fffffff
+			c.Layers[i].Gates[j] = LayeredGate{
+				Type:  LayeredGateType(seed & 1),
+				Left:  (seed >> 1) % prevSize,
+				Right: (seed >> 8) % prevSize,
+			}
+		}
+		prevSize = size
+	}
+	return &c
+}
+
+func testLayeredInputs(nbInputs int) []fr.Element {
+	inputs := make([]fr.Element, nbInputs)
+	for i := range inputs {
+		inputs[i].SetInt64(int64(3*i + 1))
+	}
+	return inputs
+}
+
+// verifierAssignment keeps only the inputs and the outputs
+func verifierAssignment(c *LayeredCircuit, assignment LayeredAssignment) LayeredAssignment {
+	res := make(LayeredAssignment, len(assignment))
+	res[0] = assignment[0].Clone()
+	for i := range c.Layers {
+		if c.isOutput(i) {
+			res[i+1] = assignment[i+1].Clone()
+		}
+	}
+	return res
+}
+
+func testLayeredCircuit(t *testing.T, c *LayeredCircuit) {
+	assignment, err := c.Evaluate(testLayeredInputs(c.NbInputs))
+	assert.NoError(t, err)
+
+	proof, err := ProveLayered(c, assignment, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyLayered(c, verifierAssignment(c, assignment), proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+
+	// wrong output
+	wrong := verifierAssignment(c, assignment)
+	var one fr.Element
+	one.SetOne()
+	last := wrong[len(wrong)-1]
+	last[0].Add(&last[0], &one)
+	assert.Error(t, VerifyLayered(c, wrong, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))), "wrong output accepted")
+
+	// wrong input
+	wrong = verifierAssignment(c, assignment)
+	wrong[0][0].Add(&wrong[0][0], &one)
+	assert.Error(t, VerifyLayered(c, wrong, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))), "wrong input accepted")
+
+	// tampered proof
+	claimedValues := proof[0].FinalEvalProof.([]fr.Element)
+	claimedValues[0].Add(&claimedValues[0], &claimedValues[1])
+	assert.Error(t, VerifyLayered(c, verifierAssignment(c, assignment), proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))), "tampered proof accepted")
+}
+
+func TestLayeredSingleGate(t *testing.T) {
+	testLayeredCircuit(t, &LayeredCircuit{
+		NbInputs: 2,
+		Layers:   []Layer{{Gates: []LayeredGate{{Type: LayeredMul, Left: 0, Right: 1}}}},
+	})
+}
+
+func TestLayeredRandom(t *testing.T) {
+	testLayeredCircuit(t, randomLayeredCircuit(8, 8, 5, 16, 3))
+}
+
+func TestLayeredMultipleOutputs(t *testing.T) {
+	c := randomLayeredCircuit(5, 7, 4, 6, 2)
+	c.Layers[1].Output = true
+	c.Layers[2].Output = true
+	testLayeredCircuit(t, c)
+}
+
+// TestLayeredMerkle proves a binary tree of products, which only uses half of the wiring on each layer
+func TestLayeredMerkle(t *testing.T) {
+	const depth = 4
+	c := LayeredCircuit{NbInputs: 1 << depth}
+	for size := 1 << (depth - 1); size >= 1; size /= 2 {
+		layer := Layer{Gates: make([]LayeredGate, size)}
+		for j := range layer.Gates {
+			layer.Gates[j] = LayeredGate{Type: LayeredMul, Left: 2 * j, Right: 2*j + 1}
+		}
+		c.Layers = append(c.Layers, layer)
+	}
+	testLayeredCircuit(t, &c)
+
+	// the root is the product of the inputs
+	inputs := testLayeredInputs(c.NbInputs)
+	assignment, err := c.Evaluate(inputs)
+	assert.NoError(t, err)
+	var expected fr.Element
+	expected.SetOne()
+	for i := range inputs {
+		expected.Mul(&expected, &inputs[i])
+	}
+	root := assignment[len(assignment)-1]
+	assert.True(t, root[0].Equal(&expected))
+	assert.True(t, root[1].IsZero())
+}
+
+func TestLayeredCheck(t *testing.T) {
+	for _, c := range []*LayeredCircuit{
+		{NbInputs: 2},
+		{NbInputs: 2, Layers: []Layer{{}}},
+		{NbInputs: 2, Layers: []Layer{{Gates: []LayeredGate{{Left: 0, Right: 2}}}}},
+		{NbInputs: 2, Layers: []Layer{{Gates: []LayeredGate{{Type: 2}}}}},
+	} {
+		_, err := c.Evaluate(make([]fr.Element, 2))
+		assert.Error(t, err)
+	}
+
+	_, err := randomLayeredCircuit(4, 2).Evaluate(make([]fr.Element, 3))
+	assert.Error(t, err)
+}
+
+func TestLayeredAssignmentPadding(t *testing.T) {
+	c := randomLayeredCircuit(3, 5)
+	assignment, err := c.Evaluate(testLayeredInputs(3))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 8}, []int{len(assignment[0]), len(assignment[1])})
+	for _, padding := range []polynomial.MultiLin{assignment[0][3:], assignment[1][5:]} {
+		for i := range padding {
+			assert.True(t, padding[i].IsZero())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package mlkzg provides a KZG commitment scheme for multilinear polynomials (PST13).
+//
+// A multilinear polynomial f in n variables is committed in the Lagrange basis of the boolean hypercube,
+// that is as a polynomial.MultiLin. An opening proof at z ∈ 𝔽ⁿ consists of n commitments to the quotients qᵢ in
+//
+//	f(X) - f(z) = ∑ᵢ (Xᵢ - zᵢ) qᵢ(Xᵢ₊₁, ..., Xₙ)
+//
+// and is checked with n+1 pairings.
+//
+// See https://eprint.iacr.org/2011/587.pdf (Papamanthou, Shi, Tamassia).
+package mlkzg
@@ -0,0 +1,134 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHiding(t *testing.T, sizes ...int) []ProvingKey {
+	bases := make([][]bls12381.G1Affine, len(sizes))
+	for i := range bases {
+		bases[i] = randomBasis(t, sizes[i])
+	}
+	pk, _, err := Setup(bases)
+	assert.NoError(t, err)
+	return pk
+}
+
+func commitHiding(t *testing.T, pk *ProvingKey, values []fr.Element) (bls12381.G1Affine, fr.Element) {
+	var r fr.Element
+	_, err := r.SetRandom()
+	assert.NoError(t, err)
+	commitment, err := pk.CommitHiding(values, r)
+	assert.NoError(t, err)
+	return commitment, r
+}
+
+func TestCommitHiding(t *testing.T) {
+	pk := setupHiding(t, 3)
+	values := interfaceSliceToFrSlice(t, randomFrSlice(t, 3)...)
+
+	// two commitments to the same values differ
+	c1, _ := commitHiding(t, &pk[0], values)
+	c2, _ := commitHiding(t, &pk[0], values)
+	assert.NotEqual(t, c1, c2)
+
+	// with r = 0 it is the binding commitment
+	var zero fr.Element
+	c1, err := pk[0].CommitHiding(values, zero)
+	assert.NoError(t, err)
+	c2, err = pk[0].Commit(values)
+	assert.NoError(t, err)
+	assert.Equal(t, c1, c2)
+}
+
+func TestOpeningProof(t *testing.T) {
+	pk := setupHiding(t, 4)
+	values := interfaceSliceToFrSlice(t, randomFrSlice(t, 4)...)
+	commitment, r := commitHiding(t, &pk[0], values)
+
+	proof, err := pk[0].ProveOpening(commitment, values, r)
+	assert.NoError(t, err)
+	assert.NoError(t, pk[0].VerifyOpening(commitment, proof))
+
+	// wrong opening
+	r.SetOne()
+	proof, err = pk[0].ProveOpening(commitment, values, r)
+	assert.NoError(t, err)
+	assert.Equal(t, ErrOpeningProof, pk[0].VerifyOpening(commitment, proof))
+}
+
+func TestEqualityProof(t *testing.T) {
+	pk := setupHiding(t, 3, 3)
+	values := interfaceSliceToFrSlice(t, randomFrSlice(t, 3)...)
+	c1, r1 := commitHiding(t, &pk[0], values)
+	c2, r2 := commitHiding(t, &pk[1], values)
+
+	// same key and different keys
+	c3, r3 := commitHiding(t, &pk[0], values)
+	proof, err := ProveEquality(&pk[0], &pk[0], c1, c3, values, r1, r3)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyEquality(&pk[0], &pk[0], c1, c3, proof))
+
+	proof, err = ProveEquality(&pk[0], &pk[1], c1, c2, values, r1, r2)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyEquality(&pk[0], &pk[1], c1, c2, proof))
+	assert.Equal(t, ErrEqualityProof, VerifyEquality(&pk[1], &pk[0], c1, c2, proof))
+
+	// different values
+	otherValues := append([]fr.Element{}, values...)
+	otherValues[1].SetOne()
+	c2, r2 = commitHiding(t, &pk[1], otherValues)
+	proof, err = ProveEquality(&pk[0], &pk[1], c1, c2, values, r1, r2)
+	assert.NoError(t, err)
+	assert.Equal(t, ErrEqualityProof, VerifyEquality(&pk[0], &pk[1], c1, c2, proof))
+}
+
+func TestLinearRelationProof(t *testing.T) {
+	pk := setupHiding(t, 3)
+
+	// v₀ + 2v₁ = v₂ and v₀ = 5
+	values := interfaceSliceToFrSlice(t, 5, 7, 19)
+	relations := []LinearRelation{
+		{Coefficients: interfaceSliceToFrSlice(t, 1, 2, -1)},
+		{Coefficients: interfaceSliceToFrSlice(t, 1, 0, 0), Constant: fr.NewElement(5)},
+	}
+	commitment, r := commitHiding(t, &pk[0], values)
+
+	proof, err := pk[0].ProveLinearRelations(commitment, values, r, relations)
+	assert.NoError(t, err)
+	assert.NoError(t, pk[0].VerifyLinearRelations(commitment, relations, proof))
+
+	// another relation
+	relations[1].Constant = fr.NewElement(6)
+	assert.Equal(t, ErrLinearRelationProof, pk[0].VerifyLinearRelations(commitment, relations, proof))
+	assert.Equal(t, ErrNbRelations, pk[0].VerifyLinearRelations(commitment, relations[:1], proof))
+
+	// the prover refuses values not satisfying the relations
+	_, err = pk[0].ProveLinearRelations(commitment, values, r, relations)
+	assert.Equal(t, ErrLinearRelationProof, err)
+
+	// a forged T does not help
+	relations[1].Constant = fr.NewElement(5)
+	proof.T[0].SetOne()
+	assert.Equal(t, ErrLinearRelationProof, pk[0].VerifyLinearRelations(commitment, relations, proof))
+}
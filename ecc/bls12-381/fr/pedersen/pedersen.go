@@ -18,21 +18,37 @@ package pedersen
 
 import (
 	"crypto/rand"
-	"fmt"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
-	"math/big"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 )
 
-// Key for proof and verification
-type Key struct {
-	g             bls12381.G2Affine // TODO @tabaie: does this really have to be randomized?
-	gRootSigmaNeg bls12381.G2Affine //gRootSigmaNeg = g^{-1/σ}
-	basis         []bls12381.G1Affine
-	basisExpSigma []bls12381.G1Affine
+// ProvingKey for committing and proofs of knowledge
+type ProvingKey struct {
+	Basis         []bls12381.G1Affine
+	BasisExpSigma []bls12381.G1Affine
 }
 
+// VerifyingKey for verifying proofs of knowledge. It is shared by all
+// the proving keys generated by the same Setup.
+type VerifyingKey struct {
+	G             bls12381.G2Affine // TODO @tabaie: does this really have to be randomized?
+	GRootSigmaNeg bls12381.G2Affine //gRootSigmaNeg = g^{-1/σ}
+}
+
+var (
+	ErrNbValues         = errors.New("unexpected number of values")
+	ErrNbCommitments    = errors.New("unexpected number of commitments")
+	ErrProofRejected    = errors.New("proof rejected")
+	ErrSubgroupCheck    = errors.New("subgroup check failed")
+	ErrEmptyCommitments = errors.New("no commitments to fold")
+)
+
 func randomOnG2() (bls12381.G2Affine, error) { // TODO: Add to G2.go?
 	gBytes := make([]byte, fr.Bytes)
 	if _, err := rand.Read(gBytes); err != nil {
@@ -41,42 +57,94 @@ func randomOnG2() (bls12381.G2Affine, error) { // TODO: Add to G2.go?
 	return bls12381.HashToG2(gBytes, []byte("random on g2"))
 }
 
-func Setup(basis []bls12381.G1Affine) (Key, error) {
-	var (
-		k   Key
-		err error
-	)
+// randomSigma returns a random non zero σ
+func randomSigma() (*big.Int, error) {
+	var modMinusOne big.Int
+	modMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	sigma, err := rand.Int(rand.Reader, &modMinusOne)
+	if err != nil {
+		return nil, err
+	}
+	return sigma.Add(sigma, big.NewInt(1)), nil
+}
+
+// seededSetup returns g and σ derived from the seed
+func seededSetup(seed []byte) (bls12381.G2Affine, *big.Int, error) {
+	g, err := bls12381.HashToG2(seed, []byte("pedersen setup g"))
+	if err != nil {
+		return g, nil, err
+	}
+	sigma, err := fr.Hash(seed, []byte("pedersen setup sigma"), 1)
+	if err != nil {
+		return g, nil, err
+	}
+	if sigma[0].IsZero() {
+		return g, nil, errors.New("seed yields σ = 0")
+	}
+	return g, sigma[0].BigInt(new(big.Int)), nil
+}
+
+type setupConfig struct {
+	seed []byte
+}
 
-	if k.g, err = randomOnG2(); err != nil {
-		return k, err
+// SetupOption customizes the setup
+type SetupOption func(*setupConfig)
+
+// WithSeed makes the setup deterministic: the G2 generator and σ are derived
+// from the seed. The resulting keys are only as secret as the seed, this is
+// meant for reproducible tests and benchmarks.
+func WithSeed(seed []byte) SetupOption {
+	return func(c *setupConfig) {
+		c.seed = seed
+	}
+}
+
+// Setup returns one proving key per basis and a verifying key shared by all of
+// them: the keys use the same σ, so that their commitments and proofs of knowledge
+// can be folded together.
+func Setup(bases [][]bls12381.G1Affine, options ...SetupOption) (pk []ProvingKey, vk VerifyingKey, err error) {
+
+	var cfg setupConfig
+	for _, o := range options {
+		o(&cfg)
 	}
 
-	var modMinusOne big.Int
-	modMinusOne.Sub(fr.Modulus(), big.NewInt(1))
 	var sigma *big.Int
-	if sigma, err = rand.Int(rand.Reader, &modMinusOne); err != nil {
-		return k, err
+	if cfg.seed != nil {
+		if vk.G, sigma, err = seededSetup(cfg.seed); err != nil {
+			return
+		}
+	} else {
+		if vk.G, err = randomOnG2(); err != nil {
+			return
+		}
+		if sigma, err = randomSigma(); err != nil {
+			return
+		}
 	}
-	sigma.Add(sigma, big.NewInt(1))
 
 	var sigmaInvNeg big.Int
 	sigmaInvNeg.ModInverse(sigma, fr.Modulus())
 	sigmaInvNeg.Sub(fr.Modulus(), &sigmaInvNeg)
-	k.gRootSigmaNeg.ScalarMultiplication(&k.g, &sigmaInvNeg)
+	vk.GRootSigmaNeg.ScalarMultiplication(&vk.G, &sigmaInvNeg)
 
-	k.basisExpSigma = make([]bls12381.G1Affine, len(basis))
-	for i := range basis {
-		k.basisExpSigma[i].ScalarMultiplication(&basis[i], sigma)
+	pk = make([]ProvingKey, len(bases))
+	for i := range bases {
+		pk[i].BasisExpSigma = make([]bls12381.G1Affine, len(bases[i]))
+		for j := range bases[i] {
+			pk[i].BasisExpSigma[j].ScalarMultiplication(&bases[i][j], sigma)
+		}
+		pk[i].Basis = bases[i]
 	}
-
-	k.basis = basis
-	return k, err
+	return
 }
 
-func (k *Key) Commit(values []fr.Element) (commitment bls12381.G1Affine, knowledgeProof bls12381.G1Affine, err error) {
+// Commit returns the commitment to the values
+func (pk *ProvingKey) Commit(values []fr.Element) (commitment bls12381.G1Affine, err error) {
 
-	if len(values) != len(k.basis) {
-		err = fmt.Errorf("unexpected number of values")
+	if len(values) != len(pk.Basis) {
+		err = ErrNbValues
 		return
 	}
 
@@ -85,29 +153,268 @@ func (k *Key) Commit(values []fr.Element) (commitment bls12381.G1Affine, knowled
 	config := ecc.MultiExpConfig{
 		NbTasks: 1, // TODO Experiment
 	}
+	_, err = commitment.MultiExp(pk.Basis, values, config)
+
+	return
+}
+
+// ProveKnowledge returns a proof of knowledge of the values committed to
+func (pk *ProvingKey) ProveKnowledge(values []fr.Element) (pok bls12381.G1Affine, err error) {
+
+	if len(values) != len(pk.BasisExpSigma) {
+		err = ErrNbValues
+		return
+	}
+
+	config := ecc.MultiExpConfig{
+		NbTasks: 1,
+	}
+	_, err = pok.MultiExp(pk.BasisExpSigma, values, config)
+
+	return
+}
+
+// BatchProve returns a single proof of knowledge for the values committed to with
+// the proving keys, which must come from the same Setup. The proof verifies against
+// FoldCommitments(commitments, fiatshamirSeeds...).
+func BatchProve(pk []ProvingKey, values [][]fr.Element, commitments []bls12381.G1Affine, fiatshamirSeeds ...[]byte) (pok bls12381.G1Affine, err error) {
+
+	if len(values) != len(pk) {
+		err = ErrNbValues
+		return
+	}
+	if len(commitments) != len(pk) {
+		err = ErrNbCommitments
+		return
+	}
+	if len(pk) == 1 {
+		return pk[0].ProveKnowledge(values[0])
+	}
 
-	if _, err = commitment.MultiExp(k.basis, values, config); err != nil {
+	r, err := foldingChallenge(commitments, fiatshamirSeeds...)
+	if err != nil {
 		return
 	}
 
-	_, err = knowledgeProof.MultiExp(k.basisExpSigma, values, config)
+	// Σᵢ rⁱ MSM(BasisExpSigmaᵢ, valuesᵢ) = MSM(BasisExpSigma, rⁱ valuesᵢ)
+	nbPoints := 0
+	for i := range pk {
+		if len(values[i]) != len(pk[i].BasisExpSigma) {
+			err = ErrNbValues
+			return
+		}
+		nbPoints += len(values[i])
+	}
+	points := make([]bls12381.G1Affine, 0, nbPoints)
+	scalars := make([]fr.Element, 0, nbPoints)
+	var ri fr.Element
+	ri.SetOne()
+	for i := range pk {
+		points = append(points, pk[i].BasisExpSigma...)
+		for j := range values[i] {
+			var s fr.Element
+			s.Mul(&values[i][j], &ri)
+			scalars = append(scalars, s)
+		}
+		ri.Mul(&ri, &r)
+	}
+
+	config := ecc.MultiExpConfig{
+		NbTasks: 1,
+	}
+	_, err = pok.MultiExp(points, scalars, config)
 
 	return
 }
 
-// VerifyKnowledgeProof checks if the proof of knowledge is valid
-func (k *Key) VerifyKnowledgeProof(commitment bls12381.G1Affine, knowledgeProof bls12381.G1Affine) error {
+// FoldCommitments returns Σᵢ rⁱCᵢ where r is derived from the commitments and
+// the seeds by Fiat-Shamir.
+func FoldCommitments(commitments []bls12381.G1Affine, fiatshamirSeeds ...[]byte) (bls12381.G1Affine, error) {
+
+	var res bls12381.G1Affine
+	if len(commitments) == 0 {
+		return res, ErrEmptyCommitments
+	}
+	if len(commitments) == 1 {
+		return commitments[0], nil
+	}
+
+	r, err := foldingChallenge(commitments, fiatshamirSeeds...)
+	if err != nil {
+		return res, err
+	}
+
+	scalars := make([]fr.Element, len(commitments))
+	scalars[0].SetOne()
+	for i := 1; i < len(scalars); i++ {
+		scalars[i].Mul(&scalars[i-1], &r)
+	}
+
+	config := ecc.MultiExpConfig{
+		NbTasks: 1,
+	}
+	_, err = res.MultiExp(commitments, scalars, config)
+	return res, err
+}
+
+// foldingChallenge derives the folding challenge from the commitments and the seeds
+func foldingChallenge(commitments []bls12381.G1Affine, fiatshamirSeeds ...[]byte) (r fr.Element, err error) {
+
+	fs := fiatshamir.NewTranscript(sha256.New(), "r")
+	for i := range commitments {
+		b := commitments[i].RawBytes()
+		if err = fs.Bind("r", b[:]); err != nil {
+			return
+		}
+	}
+	for i := range fiatshamirSeeds {
+		if err = fs.Bind("r", fiatshamirSeeds[i]); err != nil {
+			return
+		}
+	}
+
+	b, err := fs.ComputeChallenge("r")
+	if err != nil {
+		return
+	}
+	r.SetBytes(b)
+	return
+}
+
+// Verify checks if the proof of knowledge is valid
+func (vk *VerifyingKey) Verify(commitment bls12381.G1Affine, knowledgeProof bls12381.G1Affine) error {
 
 	if !commitment.IsInSubGroup() || !knowledgeProof.IsInSubGroup() {
-		return fmt.Errorf("subgroup check failed")
+		return ErrSubgroupCheck
 	}
 
-	product, err := bls12381.Pair([]bls12381.G1Affine{commitment, knowledgeProof}, []bls12381.G2Affine{k.g, k.gRootSigmaNeg})
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{commitment, knowledgeProof}, []bls12381.G2Affine{vk.G, vk.GRootSigmaNeg})
 	if err != nil {
 		return err
 	}
-	if product.IsOne() {
+	if !ok {
+		return ErrProofRejected
+	}
+	return nil
+}
+
+// BatchVerify checks the proofs of knowledge of several commitments made with
+// keys sharing vk, with a single pairing check on a random linear combination.
+func (vk *VerifyingKey) BatchVerify(commitments, knowledgeProofs []bls12381.G1Affine) error {
+
+	if len(commitments) != len(knowledgeProofs) {
+		return ErrNbCommitments
+	}
+	if len(commitments) == 0 {
 		return nil
 	}
-	return fmt.Errorf("proof rejected")
+	if len(commitments) == 1 {
+		return vk.Verify(commitments[0], knowledgeProofs[0])
+	}
+
+	for i := range commitments {
+		if !commitments[i].IsInSubGroup() || !knowledgeProofs[i].IsInSubGroup() {
+			return ErrSubgroupCheck
+		}
+	}
+
+	// random coefficients
+	r := make([]fr.Element, len(commitments))
+	for i := range r {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	var foldedCommitment, foldedProof bls12381.G1Affine
+	config := ecc.MultiExpConfig{
+		NbTasks: 1,
+	}
+	if _, err := foldedCommitment.MultiExp(commitments, r, config); err != nil {
+		return err
+	}
+	if _, err := foldedProof.MultiExp(knowledgeProofs, r, config); err != nil {
+		return err
+	}
+
+	return vk.Verify(foldedCommitment, foldedProof)
+}
+
+// BatchVerifyMultiVk checks the proofs of knowledge of several commitments, each
+// with its own verifying key, with a single multi-pairing check.
+func BatchVerifyMultiVk(vk []VerifyingKey, commitments, knowledgeProofs []bls12381.G1Affine) error {
+
+	if len(commitments) != len(vk) || len(knowledgeProofs) != len(vk) {
+		return ErrNbCommitments
+	}
+	if len(vk) == 0 {
+		return nil
+	}
+
+	// e(Σ rᵢCᵢ, Gᵢ) e(Σ rᵢπᵢ, GRootSigmaNegᵢ) = 1
+	pairingG1 := make([]bls12381.G1Affine, 2*len(vk))
+	pairingG2 := make([]bls12381.G2Affine, 2*len(vk))
+	var r fr.Element
+	var rBigInt big.Int
+	for i := range vk {
+		if !commitments[i].IsInSubGroup() || !knowledgeProofs[i].IsInSubGroup() {
+			return ErrSubgroupCheck
+		}
+		if _, err := r.SetRandom(); err != nil {
+			return err
+		}
+		r.BigInt(&rBigInt)
+		pairingG1[2*i].ScalarMultiplication(&commitments[i], &rBigInt)
+		pairingG1[2*i+1].ScalarMultiplication(&knowledgeProofs[i], &rBigInt)
+		pairingG2[2*i] = vk[i].G
+		pairingG2[2*i+1] = vk[i].GRootSigmaNeg
+	}
+
+	ok, err := bls12381.PairingCheck(pairingG1, pairingG2)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrProofRejected
+	}
+	return nil
+}
+
+// Key for proof and verification, with a single basis.
+//
+// Deprecated: use ProvingKey and VerifyingKey, returned by Setup. Key will be removed
+// in the next release.
+type Key struct {
+	pk ProvingKey
+	vk VerifyingKey
+}
+
+// SetupKey returns a key for the basis.
+//
+// Deprecated: use Setup([][]bls12381.G1Affine{basis}), which returns the proving
+// key pk[0] and the verifying key.
+func SetupKey(basis []bls12381.G1Affine) (Key, error) {
+	pk, vk, err := Setup([][]bls12381.G1Affine{basis})
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{pk: pk[0], vk: vk}, nil
+}
+
+// Commit returns the commitment to the values and a proof of knowledge of them.
+//
+// Deprecated: use ProvingKey.Commit and ProvingKey.ProveKnowledge.
+func (k *Key) Commit(values []fr.Element) (commitment bls12381.G1Affine, knowledgeProof bls12381.G1Affine, err error) {
+	if commitment, err = k.pk.Commit(values); err != nil {
+		return
+	}
+	knowledgeProof, err = k.pk.ProveKnowledge(values)
+	return
+}
+
+// VerifyKnowledgeProof checks if the proof of knowledge is valid
+//
+// Deprecated: use VerifyingKey.Verify.
+func (k *Key) VerifyKnowledgeProof(commitment bls12381.G1Affine, knowledgeProof bls12381.G1Affine) error {
+	return k.vk.Verify(commitment, knowledgeProof)
 }
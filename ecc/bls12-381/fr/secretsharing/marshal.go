@@ -0,0 +1,87 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// WriteTo writes the binary encoding of the share, X || Y
+func (s *Share) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12381.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&s.X,
+		&s.Y,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a share written with WriteTo
+func (s *Share) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&s.X,
+		&s.Y,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the commitment, with compressed points
+func (c *Commitment) WriteTo(w io.Writer) (int64, error) {
+	return c.writeTo(bls12381.NewEncoder(w))
+}
+
+// WriteRawTo writes the binary encoding of the commitment, with uncompressed points
+func (c *Commitment) WriteRawTo(w io.Writer) (int64, error) {
+	return c.writeTo(bls12381.NewEncoder(w, bls12381.RawEncoding()))
+}
+
+func (c *Commitment) writeTo(enc *bls12381.Encoder) (int64, error) {
+	err := enc.Encode([]bls12381.G1Affine(*c))
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes a commitment written with WriteTo or WriteRawTo. The points are
+// checked to be in the subgroup.
+func (c *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+	if err := dec.Decode((*[]bls12381.G1Affine)(c)); err != nil {
+		return dec.BytesRead(), err
+	}
+	if len(*c) == 0 {
+		return dec.BytesRead(), ErrNbCommitments
+	}
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,324 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestSplitReconstruct(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 3, 5
+	shares, f, err := Split(&secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f) != threshold || !f[0].Equal(&secret) {
+		t.Fatal("f should have degree threshold-1 and f(0) = secret")
+	}
+
+	for _, subset := range [][]Share{shares[:threshold], shares[n-threshold:], shares, {shares[4], shares[0], shares[2]}} {
+		reconstructed, err := Reconstruct(subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reconstructed.Equal(&secret) {
+			t.Fatal("the shares should reconstruct the secret")
+		}
+	}
+
+	reconstructed, err := Reconstruct(shares[:threshold-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reconstructed.Equal(&secret) {
+		t.Fatal("less than threshold shares should not reconstruct the secret")
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetUint64(42)
+	if _, _, err := Split(&secret, 0, 3); err != ErrInvalidThreshold {
+		t.Fatal("expected ErrInvalidThreshold, got", err)
+	}
+	if _, _, err := Split(&secret, 4, 3); err != ErrInvalidThreshold {
+		t.Fatal("expected ErrInvalidThreshold, got", err)
+	}
+
+	xs := make([]fr.Element, 3)
+	xs[0].SetUint64(1)
+	xs[1].SetUint64(2)
+	if _, _, err := SplitAt(&secret, 2, xs); err != ErrInvalidPoints {
+		t.Fatal("a zero point should be rejected, got", err)
+	}
+	xs[2].SetUint64(1)
+	if _, _, err := SplitAt(&secret, 2, xs); err != ErrInvalidPoints {
+		t.Fatal("duplicated points should be rejected, got", err)
+	}
+	if _, err := Reconstruct(nil); err != ErrNbShares {
+		t.Fatal("expected ErrNbShares, got", err)
+	}
+}
+
+func TestCommitment(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 3, 5
+	shares, f, err := Split(&secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := Commit(f)
+	if commitment.Threshold() != threshold {
+		t.Fatal("wrong threshold of the commitment")
+	}
+
+	for i := range shares {
+		valid, err := commitment.Verify(&shares[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the share should be valid")
+		}
+	}
+
+	tampered := shares[1]
+	var one fr.Element
+	one.SetOne()
+	tampered.Y.Add(&tampered.Y, &one)
+	if valid, _ := commitment.Verify(&tampered); valid {
+		t.Fatal("the tampered share should not be valid")
+	}
+
+	// the public key of the secret is the commitment at 0
+	var zero fr.Element
+	public, err := commitment.Eval(&zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !public.Equal(&commitment[0]) {
+		t.Fatal("the commitment at 0 should be the first coefficient")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 2, 3
+	shares, f, err := Split(&secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := Commit(f)
+
+	xs := make([]fr.Element, n)
+	for i := range shares {
+		xs[i].Set(&shares[i].X)
+	}
+	newShares, newCommitment := reshare(t, commitment, shares, threshold, xs)
+
+	for i := range newShares {
+		if newShares[i].Y.Equal(&shares[i].Y) {
+			t.Fatal("the refreshed shares should differ from the old ones")
+		}
+	}
+	reconstructed, err := Reconstruct(newShares[:threshold])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reconstructed.Equal(&secret) {
+		t.Fatal("the refreshed shares should reconstruct the secret")
+	}
+	if !newCommitment[0].Equal(&commitment[0]) {
+		t.Fatal("the refresh should not change the public key")
+	}
+}
+
+func TestRedistribute(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 3, 4
+	shares, f, err := Split(&secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := Commit(f)
+
+	// 3 of the 4 old participants redistribute the secret to 5 new participants with a
+	// threshold of 2
+	const newThreshold, newN = 2, 5
+	newXs := make([]fr.Element, newN)
+	for i := range newXs {
+		newXs[i].SetUint64(uint64(10 + i))
+	}
+	newShares, newCommitment := reshare(t, commitment, shares[1:], newThreshold, newXs)
+
+	if newCommitment.Threshold() != newThreshold || !newCommitment[0].Equal(&commitment[0]) {
+		t.Fatal("the new commitment should commit to the same secret with the new threshold")
+	}
+	reconstructed, err := Reconstruct(newShares[newN-newThreshold:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reconstructed.Equal(&secret) {
+		t.Fatal("the new shares should reconstruct the secret")
+	}
+}
+
+// reshare runs a resharing from the old shares to the participants at xs, checking the
+// sub-shares and the commitments, and returns the new shares and commitment
+func reshare(t *testing.T, commitment Commitment, oldShares []Share, threshold int, xs []fr.Element) ([]Share, Commitment) {
+	t.Helper()
+	oldXs := make([]fr.Element, len(oldShares))
+	subShares := make([][]Share, len(xs)) // subShares[j][i]: from the old participant i to the new participant j
+	commitments := make([]Commitment, len(oldShares))
+	for i := range oldShares {
+		oldXs[i].Set(&oldShares[i].X)
+		sub, f, err := Reshare(&oldShares[i], threshold, xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments[i] = Commit(f)
+		valid, err := commitment.VerifyReshare(&oldXs[i], commitments[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the resharing commitment should be consistent with the old commitment")
+		}
+		for j := range sub {
+			if valid, err = commitments[i].Verify(&sub[j]); err != nil || !valid {
+				t.Fatal("the sub-share should be valid", err)
+			}
+			subShares[j] = append(subShares[j], sub[j])
+		}
+	}
+	if valid, _ := commitment.VerifyReshare(&oldXs[0], commitments[1]); valid {
+		t.Fatal("the resharing commitment of another share should not be valid")
+	}
+
+	newCommitment, err := CombineCommitments(oldXs, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newShares := make([]Share, len(xs))
+	for j := range newShares {
+		if newShares[j], err = CombineReshares(oldXs, subShares[j]); err != nil {
+			t.Fatal(err)
+		}
+		valid, err := newCommitment.Verify(&newShares[j])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the new share should be valid")
+		}
+	}
+	return newShares, newCommitment
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	var secret fr.Element
+	secret.SetRandom()
+	shares, f, err := Split(&secret, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := shares[0].WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var share Share
+	read, err := share.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != read || share != shares[0] {
+		t.Fatal("Error serialize(deserialize(.)) of the share")
+	}
+
+	commitment := Commit(f)
+	for _, raw := range []bool{false, true} {
+		buf.Reset()
+		if raw {
+			written, err = commitment.WriteRawTo(&buf)
+		} else {
+			written, err = commitment.WriteTo(&buf)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var commitment2 Commitment
+		if read, err = commitment2.ReadFrom(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if written != read || len(commitment2) != len(commitment) {
+			t.Fatal("Error serialize(deserialize(.)) of the commitment")
+		}
+		for i := range commitment {
+			if !commitment2[i].Equal(&commitment[i]) {
+				t.Fatal("Error serialize(deserialize(.)) of the commitment")
+			}
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkVerify(b *testing.B) {
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 16, 32
+	shares, f, _ := Split(&secret, threshold, n)
+	commitment := Commit(f)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		commitment.Verify(&shares[i%n])
+	}
+}
+
+func BenchmarkReconstruct(b *testing.B) {
+	var secret fr.Element
+	secret.SetRandom()
+	const threshold, n = 16, 32
+	shares, _, _ := Split(&secret, threshold, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Reconstruct(shares[:threshold])
+	}
+}
@@ -23,9 +23,11 @@ import (
 	"reflect"
 	"sync/atomic"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 )
 
@@ -47,11 +49,27 @@ const (
 	mCompressedInfinity   byte = 0b110 << 5
 )
 
+// To encode GT elements, we mask the two most significant bits of the first byte, which are unused
+// by the first coordinate, to tell apart the uncompressed encoding from the encoding compressed on the torus.
+// The identity has no torus representation, and is encoded with its own flag.
+const (
+	mGTMask               byte = 0b11 << 6
+	mGTUncompressed       byte = 0b00 << 6
+	mGTCompressed         byte = 0b10 << 6
+	mGTCompressedIdentity byte = 0b11 << 6
+)
+
 // SizeOfGT represents the size in bytes that a GT element need in binary form
 const SizeOfGT = fptower.SizeOfGT
 
+// SizeOfGTCompressed represents the size in bytes that a GT element need in binary form, compressed on the torus
+const SizeOfGTCompressed = SizeOfGT / 2
+
 var ErrInvalidInfinityEncoding = errors.New("invalid infinity point encoding")
 
+// ErrMaxLengthExceeded is returned by the decoder when a slice is longer than the maximum length
+var ErrMaxLengthExceeded = errors.New("slice length exceeds the maximum length")
+
 // Encoder writes bls12-381 object values to an output stream
 type Encoder struct {
 	w   io.Writer
@@ -62,8 +80,9 @@ type Encoder struct {
 // Decoder reads bls12-381 object values from an inbound stream
 type Decoder struct {
 	r             io.Reader
-	n             int64 // read bytes
-	subGroupCheck bool  // default to true
+	n             int64  // read bytes
+	subGroupCheck bool   // default to true
+	maxLength     uint32 // maximum length of the slices, 0 for no limit
 }
 
 // NewDecoder returns a binary decoder supporting curve bls12-381 objects in both
@@ -79,13 +98,23 @@ func NewDecoder(r io.Reader, options ...func(*Decoder)) *Decoder {
 }
 
 // Decode reads the binary encoding of v from the stream
-// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *[]G1Affine or *[]G2Affine
+// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *GT, *twistededwards.PointAffine, *bandersnatch.PointAffine,
+// a pointer to a slice or to a slice of slices of these types (*[]G1Affine, *[][]fr.Element, ...),
+// or implement io.ReaderFrom
 func (dec *Decoder) Decode(v interface{}) (err error) {
 	rv := reflect.ValueOf(v)
 	if v == nil || rv.Kind() != reflect.Ptr || rv.IsNil() || !rv.Elem().CanSet() {
 		return errors.New("bls12-381 decoder: unsupported type, need pointer")
 	}
 
+	// vectors are decoded as slices, to check their length against dec.maxLength
+	switch t := v.(type) {
+	case *fr.Vector:
+		v = (*[]fr.Element)(t)
+	case *fp.Vector:
+		v = (*[]fp.Element)(t)
+	}
+
 	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
 	// in particular, careful attention must be given to usage of Bytes() method on Elements and Points
 	// that return an array (not a slice) of bytes. Using this is beneficial to minimize memory allocations
@@ -120,13 +149,171 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		err = t.SetBytesCanonical(buf[:fp.Bytes])
 		return
 	case *[]fr.Element:
-		read64, err = (*fr.Vector)(t).ReadFrom(dec.r)
-		dec.n += read64
-		return
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]fr.Element, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			read, err = io.ReadFull(dec.r, buf[:fr.Bytes])
+			dec.n += int64(read)
+			if err != nil {
+				return
+			}
+			if err = (*t)[i].SetBytesCanonical(buf[:fr.Bytes]); err != nil {
+				return
+			}
+		}
+		return nil
 	case *[]fp.Element:
-		read64, err = (*fp.Vector)(t).ReadFrom(dec.r)
-		dec.n += read64
-		return
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]fp.Element, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			read, err = io.ReadFull(dec.r, buf[:fp.Bytes])
+			dec.n += int64(read)
+			if err != nil {
+				return
+			}
+			if err = (*t)[i].SetBytesCanonical(buf[:fp.Bytes]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *GT:
+		return dec.decodeGT(t)
+	case *[]GT:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]GT, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.decodeGT(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *twistededwards.PointAffine:
+		return dec.decodeTwistededwards(t)
+	case *[]twistededwards.PointAffine:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]twistededwards.PointAffine, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.decodeTwistededwards(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *bandersnatch.PointAffine:
+		return dec.decodeBandersnatch(t)
+	case *[]bandersnatch.PointAffine:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]bandersnatch.PointAffine, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.decodeBandersnatch(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *[][]fr.Element:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([][]fr.Element, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.Decode(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *[][]fp.Element:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([][]fp.Element, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.Decode(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *[][]G1Affine:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([][]G1Affine, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.Decode(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *[][]G2Affine:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([][]G2Affine, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.Decode(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *[][]GT:
+		var sliceLen uint32
+		sliceLen, err = dec.readSliceLen()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([][]GT, sliceLen)
+		}
+		for i := 0; i < len(*t); i++ {
+			if err = dec.Decode(&(*t)[i]); err != nil {
+				return
+			}
+		}
+		return nil
 	case *G1Affine:
 		// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
 		read, err = io.ReadFull(dec.r, buf[:SizeOfG1AffineCompressed])
@@ -169,7 +356,7 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		return
 	case *[]G1Affine:
 		var sliceLen uint32
-		sliceLen, err = dec.readUint32()
+		sliceLen, err = dec.readSliceLen()
 		if err != nil {
 			return
 		}
@@ -228,7 +415,7 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		return nil
 	case *[]G2Affine:
 		var sliceLen uint32
-		sliceLen, err = dec.readUint32()
+		sliceLen, err = dec.readSliceLen()
 		if err != nil {
 			return
 		}
@@ -315,6 +502,154 @@ func (dec *Decoder) readUint32() (r uint32, err error) {
 	return
 }
 
+// readSliceLen reads the length of a slice, and checks it against dec.maxLength
+func (dec *Decoder) readSliceLen() (uint32, error) {
+	sliceLen, err := dec.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	if dec.maxLength != 0 && sliceLen > dec.maxLength {
+		return 0, ErrMaxLengthExceeded
+	}
+	return sliceLen, nil
+}
+
+// decodeGT reads a GT element, compressed on the torus or not
+func (dec *Decoder) decodeGT(z *GT) (err error) {
+	var buf [SizeOfGT]byte
+	var read int
+	// we start by reading compressed element size, if metadata tells us it is uncompressed, we read more.
+	read, err = io.ReadFull(dec.r, buf[:SizeOfGTCompressed])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	if buf[0]&mGTMask != mGTUncompressed {
+		return setGTCompressedBytes(z, buf[:SizeOfGTCompressed], dec.subGroupCheck)
+	}
+	read, err = io.ReadFull(dec.r, buf[SizeOfGTCompressed:])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	if err = z.SetBytes(buf[:]); err != nil {
+		return
+	}
+	if dec.subGroupCheck && !z.IsInSubGroup() {
+		return errors.New("invalid GT element: not in subgroup")
+	}
+	return nil
+}
+
+// decodeTwistededwards reads a compressed point of the twistededwards curve
+func (dec *Decoder) decodeTwistededwards(p *twistededwards.PointAffine) (err error) {
+	var buf [fr.Bytes]byte
+	var read int
+	read, err = io.ReadFull(dec.r, buf[:])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	if _, err = p.SetBytes(buf[:]); err != nil {
+		return
+	}
+	if !p.IsOnCurve() {
+		return errors.New("invalid point: not on curve")
+	}
+	if dec.subGroupCheck && !p.IsInSubGroup() {
+		return errors.New("invalid point: subgroup check failed")
+	}
+	return nil
+}
+
+// decodeBandersnatch reads a compressed point of the bandersnatch curve
+func (dec *Decoder) decodeBandersnatch(p *bandersnatch.PointAffine) (err error) {
+	var buf [fr.Bytes]byte
+	var read int
+	read, err = io.ReadFull(dec.r, buf[:])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	if _, err = p.SetBytes(buf[:]); err != nil {
+		return
+	}
+	if !p.IsOnCurve() {
+		return errors.New("invalid point: not on curve")
+	}
+	if dec.subGroupCheck && !p.IsInSubGroup() {
+		return errors.New("invalid point: subgroup check failed")
+	}
+	return nil
+}
+
+// gtCompressedCoordinates returns pointers to the coordinates of an element of the torus
+func gtCompressedCoordinates(y *fptower.E6) []*fp.Element {
+	return []*fp.Element{
+		&y.B0.A0, &y.B0.A1,
+		&y.B1.A0, &y.B1.A1,
+		&y.B2.A0, &y.B2.A1,
+	}
+}
+
+// gtCompressedBytes returns the encoding of z compressed on the torus, on SizeOfGTCompressed bytes.
+// z must be in GT.
+func gtCompressedBytes(z *GT) (res [SizeOfGTCompressed]byte, err error) {
+	if z.IsOne() {
+		res[0] = mGTCompressedIdentity
+		return
+	}
+	y, err := z.CompressTorus()
+	if err != nil {
+		return
+	}
+	for i, c := range gtCompressedCoordinates(&y) {
+		b := c.Bytes()
+		copy(res[i*fp.Bytes:(i+1)*fp.Bytes], b[:])
+	}
+	res[0] |= mGTCompressed
+	return
+}
+
+// setGTCompressedBytes sets z from its encoding compressed on the torus
+func setGTCompressedBytes(z *GT, buf []byte, subGroupCheck bool) error {
+	if len(buf) != SizeOfGTCompressed {
+		return errors.New("invalid buffer size")
+	}
+	mData := buf[0] & mGTMask
+	if mData == mGTCompressedIdentity {
+		if !isZeroed(buf[0] & ^mGTMask, buf[1:]) {
+			return errors.New("invalid GT identity encoding")
+		}
+		z.SetOne()
+		return nil
+	}
+	if mData != mGTCompressed {
+		return errors.New("invalid GT compressed encoding")
+	}
+
+	// unmask the first coordinate
+	var first [fp.Bytes]byte
+	copy(first[:], buf[:fp.Bytes])
+	first[0] &= ^mGTMask
+
+	var y fptower.E6
+	for i, c := range gtCompressedCoordinates(&y) {
+		b := buf[i*fp.Bytes : (i+1)*fp.Bytes]
+		if i == 0 {
+			b = first[:]
+		}
+		if err := c.SetBytesCanonical(b); err != nil {
+			return err
+		}
+	}
+	*z = y.DecompressTorus()
+	if subGroupCheck && !z.IsInSubGroup() {
+		return errors.New("invalid GT element: not in subgroup")
+	}
+	return nil
+}
+
 func isCompressed(msb byte) bool {
 	mData := msb & mMask
 	return !((mData == mUncompressed) || (mData == mUncompressedInfinity))
@@ -338,7 +673,11 @@ func NewEncoder(w io.Writer, options ...func(*Encoder)) *Encoder {
 }
 
 // Encode writes the binary encoding of v to the stream
-// type must be uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, []G1Affine or []G2Affine
+// type must be uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *GT, *twistededwards.PointAffine, *bandersnatch.PointAffine,
+// a slice or a slice of slices of these types ([]G1Affine, [][]fr.Element, ...), or implement io.WriterTo
+//
+// Points of G1 and G2 are compressed unless the RawEncoding option is set, and GT elements are
+// compressed on the torus. Points of the twisted Edwards curves are always compressed.
 func (enc *Encoder) Encode(v interface{}) (err error) {
 	if enc.raw {
 		return enc.encodeRaw(v)
@@ -352,7 +691,7 @@ func (enc *Encoder) BytesWritten() int64 {
 }
 
 // RawEncoding returns an option to use in NewEncoder(...) which sets raw encoding mode to true
-// points will not be compressed using this option
+// points of G1 and G2 and elements of GT will not be compressed using this option
 func RawEncoding() func(*Encoder) {
 	return func(enc *Encoder) {
 		enc.raw = true
@@ -367,6 +706,15 @@ func NoSubgroupChecks() func(*Decoder) {
 	}
 }
 
+// MaxSliceLength returns an option to use in NewDecoder(...) which limits the length of the slices
+// the decoder will read to n. Decoding a longer slice returns ErrMaxLengthExceeded, before allocating it.
+// Use it when decoding untrusted input, as the length prefix is otherwise trusted.
+func MaxSliceLength(n uint32) func(*Decoder) {
+	return func(dec *Decoder) {
+		dec.maxLength = n
+	}
+}
+
 // isZeroed checks that the provided bytes are at 0
 func isZeroed(firstByte byte, buf []byte) bool {
 	if firstByte != 0 {
@@ -383,7 +731,7 @@ func isZeroed(firstByte byte, buf []byte) bool {
 func (enc *Encoder) encode(v interface{}) (err error) {
 	rv := reflect.ValueOf(v)
 	if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
-		return errors.New("<no value> encoder: can't encode <nil>")
+		return errors.New("bls12-381 encoder: can't encode <nil>")
 	}
 
 	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
@@ -472,10 +820,132 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 			}
 		}
 		return nil
+	case *GT:
+		return enc.encodeGT(t)
+	case []GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeGT(&t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *twistededwards.PointAffine:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case []twistededwards.PointAffine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			buf := t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case *bandersnatch.PointAffine:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case []bandersnatch.PointAffine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			buf := t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]fr.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encode(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]fp.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encode(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]G1Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encode(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]G2Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encode(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encode(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
 	default:
 		n := binary.Size(t)
 		if n == -1 {
-			return errors.New("<no value> encoder: unsupported type")
+			return errors.New("bls12-381 encoder: unsupported type")
 		}
 		err = binary.Write(enc.w, binary.BigEndian, t)
 		enc.n += int64(n)
@@ -483,10 +953,21 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 	}
 }
 
+// encodeGT writes the encoding of z, compressed on the torus
+func (enc *Encoder) encodeGT(z *GT) (err error) {
+	buf, err := gtCompressedBytes(z)
+	if err != nil {
+		return
+	}
+	written, err := enc.w.Write(buf[:])
+	enc.n += int64(written)
+	return
+}
+
 func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 	rv := reflect.ValueOf(v)
 	if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
-		return errors.New("<no value> encoder: can't encode <nil>")
+		return errors.New("bls12-381 encoder: can't encode <nil>")
 	}
 
 	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
@@ -575,10 +1056,132 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 			}
 		}
 		return nil
+	case *GT:
+		return enc.encodeRawGT(t)
+	case []GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRawGT(&t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case *twistededwards.PointAffine:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case []twistededwards.PointAffine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			buf := t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case *bandersnatch.PointAffine:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case []bandersnatch.PointAffine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			buf := t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]fr.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRaw(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]fp.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRaw(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]G1Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRaw(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]G2Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRaw(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
+	case [][]GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		for i := 0; i < len(t); i++ {
+			if err = enc.encodeRaw(t[i]); err != nil {
+				return
+			}
+		}
+		return nil
 	default:
 		n := binary.Size(t)
 		if n == -1 {
-			return errors.New("<no value> encoder: unsupported type")
+			return errors.New("bls12-381 encoder: unsupported type")
 		}
 		err = binary.Write(enc.w, binary.BigEndian, t)
 		enc.n += int64(n)
@@ -586,6 +1189,14 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 	}
 }
 
+// encodeRawGT writes the uncompressed encoding of z
+func (enc *Encoder) encodeRawGT(z *GT) (err error) {
+	buf := z.Bytes()
+	written, err := enc.w.Write(buf[:])
+	enc.n += int64(written)
+	return
+}
+
 // SizeOfG1AffineCompressed represents the size in bytes that a G1Affine need in binary form, compressed
 const SizeOfG1AffineCompressed = 48
 
@@ -0,0 +1,144 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package tbls
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// contextString is the prefix of the domain separation tags of the hashes
+const contextString = "TBLS-bls12-381-v1"
+
+// point is an element of the group of the keys
+type point = bls12381.G1Affine
+
+const (
+	// sizeScalar is the size of an encoded scalar
+	sizeScalar = fr.Bytes
+
+	// sizePoint is the size of a compressed point
+	sizePoint = bls12381.SizeOfG1AffineCompressed
+)
+
+var (
+	errInvalidPoint  = errors.New("invalid point encoding")
+	errInvalidScalar = errors.New("invalid scalar encoding")
+)
+
+// order is the order of the group
+var order = fr.Modulus()
+
+// isIdentity reports whether p is the neutral element of the group
+func isIdentity(p *point) bool {
+	return p.IsInfinity()
+}
+
+// scalarBaseMult returns s⋅G, where G is the base point
+func scalarBaseMult(s *big.Int) point {
+	var res point
+	res.ScalarMultiplicationBase(s)
+	return res
+}
+
+// scalarMult returns s⋅p
+func scalarMult(p *point, s *big.Int) point {
+	var res point
+	res.ScalarMultiplication(p, s)
+	return res
+}
+
+// addPoints returns p + q
+func addPoints(p, q *point) point {
+	var res point
+	res.Add(p, q)
+	return res
+}
+
+// encodePoint returns the compressed encoding of p
+func encodePoint(p *point) []byte {
+	res := p.Bytes()
+	return res[:]
+}
+
+// decodePoint returns the point of compressed encoding buf. It must be in the prime order
+// subgroup, and not be the identity.
+func decodePoint(buf []byte) (point, error) {
+	var p point
+	if len(buf) != sizePoint {
+		return p, errInvalidPoint
+	}
+	// SetBytes checks that the point is in the subgroup
+	if n, err := p.SetBytes(buf); err != nil || n != sizePoint || isIdentity(&p) {
+		return p, errInvalidPoint
+	}
+	return p, nil
+}
+
+// encodeScalar returns the big endian encoding of s ∈ [0, order-1] on sizeScalar bytes
+func encodeScalar(s *big.Int) []byte {
+	res := make([]byte, sizeScalar)
+	s.FillBytes(res)
+	return res
+}
+
+// decodeScalar returns the scalar of big endian encoding buf, which must be smaller than the order
+func decodeScalar(buf []byte) (*big.Int, error) {
+	if len(buf) != sizeScalar {
+		return nil, errInvalidScalar
+	}
+	s := new(big.Int).SetBytes(buf)
+	if s.Cmp(order) >= 0 {
+		return nil, errInvalidScalar
+	}
+	return s, nil
+}
+
+// randomScalar returns a uniformly random scalar in [1, order-1] read from rand
+func randomScalar(rand io.Reader) (*big.Int, error) {
+	// 128 extra bits make the bias of the reduction negligible
+	buf := make([]byte, (order.BitLen()+7)/8+16)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		s := new(big.Int).SetBytes(buf)
+		s.Mod(s, order)
+		if s.Sign() != 0 {
+			return s, nil
+		}
+	}
+}
+
+// hashToScalar returns hash_to_field(data, contextString || dst) as in RFC 9380, with
+// expand_message_xmd and SHA-256
+func hashToScalar(dst string, data ...[]byte) *big.Int {
+	var msg []byte
+	for i := range data {
+		msg = append(msg, data[i]...)
+	}
+	e, err := fr.Hash(msg, []byte(contextString+dst), 1)
+	if err != nil {
+		// the DST is shorter than 255 bytes
+		panic(err)
+	}
+	return e[0].BigInt(new(big.Int))
+}
@@ -0,0 +1,231 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package tbls
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const (
+	sizeID = 4
+
+	// sizeDKGShare is the size of an encoded DKGShare, From || To || share
+	sizeDKGShare = 2*sizeID + sizeScalar
+
+	// sizePartialSignature is the size of an encoded PartialSignature, ID || σᵢ
+	sizePartialSignature = sizeID + bls12381.SizeOfG2AffineCompressed
+)
+
+// Bytes returns the binary representation of the commitment, as
+// From || t || φ₀ || ... || φₜ₋₁ || R || z
+// where the integers are in big endian on 4 bytes and the points are compressed.
+func (c *DKGCommitment) Bytes() []byte {
+	res := make([]byte, 0, 2*sizeID+(len(c.Commitment)+1)*sizePoint+sizeScalar)
+	res = appendUint32(res, c.From)
+	res = appendUint32(res, uint32(len(c.Commitment)))
+	for i := range c.Commitment {
+		res = append(res, encodePoint(&c.Commitment[i])...)
+	}
+	res = append(res, encodePoint(&c.ProofR)...)
+	res = append(res, encodeScalar(&c.ProofZ)...)
+	return res
+}
+
+// SetBytes sets c from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (c *DKGCommitment) SetBytes(buf []byte) (int, error) {
+	if len(buf) < 2*sizeID {
+		return 0, io.ErrShortBuffer
+	}
+	from := binary.BigEndian.Uint32(buf)
+	t := binary.BigEndian.Uint32(buf[sizeID:])
+	if t == 0 || uint64(t) > math.MaxInt32/sizePoint {
+		return 0, ErrInvalidParameters
+	}
+	size := 2*sizeID + (int(t)+1)*sizePoint + sizeScalar
+	if len(buf) < size {
+		return 0, io.ErrShortBuffer
+	}
+	n := 2 * sizeID
+	commitment := make([]point, t)
+	for i := range commitment {
+		var err error
+		if commitment[i], err = decodePoint(buf[n : n+sizePoint]); err != nil {
+			return 0, err
+		}
+		n += sizePoint
+	}
+	R, err := decodePoint(buf[n : n+sizePoint])
+	if err != nil {
+		return 0, err
+	}
+	n += sizePoint
+	z, err := decodeScalar(buf[n : n+sizeScalar])
+	if err != nil {
+		return 0, err
+	}
+	n += sizeScalar
+
+	c.From = from
+	c.Commitment = commitment
+	c.ProofR = R
+	c.ProofZ.Set(z)
+	return n, nil
+}
+
+// Bytes returns the binary representation of the share, as From || To || share
+// where the identifiers are in big endian on 4 bytes.
+func (s *DKGShare) Bytes() []byte {
+	res := make([]byte, 0, sizeDKGShare)
+	res = appendUint32(res, s.From)
+	res = appendUint32(res, s.To)
+	return append(res, encodeScalar(&s.Share)...)
+}
+
+// SetBytes sets s from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (s *DKGShare) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizeDKGShare {
+		return 0, io.ErrShortBuffer
+	}
+	share, err := decodeScalar(buf[2*sizeID : sizeDKGShare])
+	if err != nil {
+		return 0, err
+	}
+	s.From = binary.BigEndian.Uint32(buf)
+	s.To = binary.BigEndian.Uint32(buf[sizeID:])
+	s.Share.Set(share)
+	return sizeDKGShare, nil
+}
+
+// Bytes returns the binary representation of the group key, as
+// threshold || n || PublicKey || PublicShares[0] || ... || PublicShares[n-1]
+// where the integers are in big endian on 4 bytes and the points are compressed.
+func (gk *GroupKey) Bytes() []byte {
+	res := make([]byte, 0, 2*sizeID+(len(gk.PublicShares)+1)*sizePoint)
+	res = appendUint32(res, uint32(gk.Threshold))
+	res = appendUint32(res, uint32(len(gk.PublicShares)))
+	res = append(res, encodePoint(&gk.PublicKey)...)
+	for i := range gk.PublicShares {
+		res = append(res, encodePoint(&gk.PublicShares[i])...)
+	}
+	return res
+}
+
+// SetBytes sets gk from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (gk *GroupKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < 2*sizeID {
+		return 0, io.ErrShortBuffer
+	}
+	t := binary.BigEndian.Uint32(buf)
+	nbShares := binary.BigEndian.Uint32(buf[sizeID:])
+	if uint64(nbShares) > math.MaxInt32/sizePoint-1 || checkParameters(int(t), int(nbShares)) != nil {
+		return 0, ErrInvalidParameters
+	}
+	size := 2*sizeID + (int(nbShares)+1)*sizePoint
+	if len(buf) < size {
+		return 0, io.ErrShortBuffer
+	}
+	n := 2 * sizeID
+	publicKey, err := decodePoint(buf[n : n+sizePoint])
+	if err != nil {
+		return 0, err
+	}
+	n += sizePoint
+	publicShares := make([]point, nbShares)
+	for i := range publicShares {
+		if publicShares[i], err = decodePoint(buf[n : n+sizePoint]); err != nil {
+			return 0, err
+		}
+		n += sizePoint
+	}
+
+	gk.Threshold = int(t)
+	gk.PublicKey = publicKey
+	gk.PublicShares = publicShares
+	return n, nil
+}
+
+// Bytes returns the binary representation of the key share, as ID || secret || group key
+// where ID is in big endian on 4 bytes and the group key is as GroupKey.Bytes.
+func (ks *KeyShare) Bytes() []byte {
+	res := make([]byte, 0, sizeID+sizeScalar)
+	res = appendUint32(res, ks.ID)
+	res = append(res, encodeScalar(&ks.Secret)...)
+	return append(res, ks.GroupKey.Bytes()...)
+}
+
+// SetBytes sets ks from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (ks *KeyShare) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizeID+sizeScalar {
+		return 0, io.ErrShortBuffer
+	}
+	secret, err := decodeScalar(buf[sizeID : sizeID+sizeScalar])
+	if err != nil {
+		return 0, err
+	}
+	var gk GroupKey
+	n, err := gk.SetBytes(buf[sizeID+sizeScalar:])
+	if err != nil {
+		return 0, err
+	}
+	id := binary.BigEndian.Uint32(buf)
+	if id == 0 || int(id) > len(gk.PublicShares) {
+		return 0, ErrInvalidParameters
+	}
+	ks.ID = id
+	ks.Secret.Set(secret)
+	ks.GroupKey = gk
+	return sizeID + sizeScalar + n, nil
+}
+
+// Bytes returns the binary representation of the partial signature, as ID || σᵢ
+// where ID is in big endian on 4 bytes and σᵢ is compressed.
+func (ps *PartialSignature) Bytes() []byte {
+	res := make([]byte, 0, sizePartialSignature)
+	res = appendUint32(res, ps.ID)
+	sBin := ps.S.Bytes()
+	return append(res, sBin[:]...)
+}
+
+// SetBytes sets ps from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (ps *PartialSignature) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePartialSignature {
+		return 0, io.ErrShortBuffer
+	}
+	var s bls12381.G2Affine
+	if _, err := s.SetBytes(buf[sizeID:sizePartialSignature]); err != nil {
+		return 0, err
+	}
+	ps.ID = binary.BigEndian.Uint32(buf)
+	ps.S = s
+	return sizePartialSignature, nil
+}
+
+// appendUint32 appends the big endian encoding of i to b
+func appendUint32(b []byte, i uint32) []byte {
+	var buf [sizeID]byte
+	binary.BigEndian.PutUint32(buf[:], i)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package tbls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// Suite is the domain separation tag of the hash of the messages to G2, the one of
+// the BLS signatures (draft-irtf-cfrg-bls-signature) with the public keys in G1
+const Suite = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+
+var (
+	// ErrInvalidPartialSignature is returned when a partial signature does not verify
+	ErrInvalidPartialSignature = errors.New("invalid partial signature")
+
+	// ErrNotEnoughSignatures is returned when combining less than threshold partial
+	// signatures
+	ErrNotEnoughSignatures = errors.New("not enough partial signatures")
+)
+
+// PartialSignature is the partial signature σᵢ = sᵢ⋅H(msg) of the participant of
+// identifier ID.
+type PartialSignature struct {
+	ID uint32
+	S  bls12381.G2Affine
+}
+
+// Sign returns the partial signature of the participant on msg. It needs no interaction
+// with the other participants.
+func (ks *KeyShare) Sign(msg []byte) (*PartialSignature, error) {
+	h, err := bls12381.HashToG2(msg, []byte(Suite))
+	if err != nil {
+		return nil, err
+	}
+	res := &PartialSignature{ID: ks.ID}
+	res.S.ScalarMultiplication(&h, &ks.Secret)
+	return res, nil
+}
+
+// VerifyPartial reports whether ps is a valid partial signature of msg for the public key
+// share of the participant ps.ID:
+//
+//	e(Yᵢ, H(msg)) = e(G, σᵢ)
+func (gk *GroupKey) VerifyPartial(msg []byte, ps *PartialSignature) (bool, error) {
+	if ps.ID == 0 || int(ps.ID) > len(gk.PublicShares) {
+		return false, ErrInvalidParameters
+	}
+	return verify(&gk.PublicShares[ps.ID-1], msg, &ps.S)
+}
+
+// Combine verifies the partial signatures of msg, and interpolates the signature
+// σ = Σ λᵢ⋅σᵢ of the group public key from threshold of them.
+//
+// If partial signatures are invalid, it returns an error wrapping ErrInvalidPartialSignature
+// and the identifiers of the culprits.
+func (gk *GroupKey) Combine(msg []byte, partials []PartialSignature) (*bls12381.G2Affine, error) {
+	var culprits []uint32
+	seen := make(map[uint32]bool, len(partials))
+	valid := make([]PartialSignature, 0, gk.Threshold)
+	for i := range partials {
+		if seen[partials[i].ID] {
+			return nil, fmt.Errorf("participant %d: %w", partials[i].ID, ErrMissingMessage)
+		}
+		seen[partials[i].ID] = true
+		ok, err := gk.VerifyPartial(msg, &partials[i])
+		if err != nil {
+			return nil, fmt.Errorf("participant %d: %w", partials[i].ID, err)
+		}
+		if !ok {
+			culprits = append(culprits, partials[i].ID)
+		} else if len(valid) < gk.Threshold {
+			valid = append(valid, partials[i])
+		}
+	}
+	if len(culprits) != 0 {
+		return nil, fmt.Errorf("participants %v: %w", culprits, ErrInvalidPartialSignature)
+	}
+	if len(valid) < gk.Threshold {
+		return nil, ErrNotEnoughSignatures
+	}
+
+	ids := make([]uint32, len(valid))
+	for i := range valid {
+		ids[i] = valid[i].ID
+	}
+	lambdas, err := lagrangeCoefficients(ids)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]bls12381.G2Affine, len(valid))
+	for i := range valid {
+		points[i] = valid[i].S
+	}
+	var res bls12381.G2Affine
+	if _, err := res.MultiExp(points, lambdas, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Verify reports whether sig is a valid BLS signature of msg for the group public key:
+//
+//	e(Y, H(msg)) = e(G, σ)
+func (gk *GroupKey) Verify(msg []byte, sig *bls12381.G2Affine) (bool, error) {
+	return verify(&gk.PublicKey, msg, sig)
+}
+
+// verify checks e(-G, σ)⋅e(Y, H(msg)) = 1
+func verify(Y *point, msg []byte, sig *bls12381.G2Affine) (bool, error) {
+	if isIdentity(Y) || sig.IsInfinity() || !sig.IsInSubGroup() {
+		return false, nil
+	}
+	h, err := bls12381.HashToG2(msg, []byte(Suite))
+	if err != nil {
+		return false, err
+	}
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 point
+	negG1.Neg(&g1)
+	return bls12381.PairingCheck([]point{negG1, *Y}, []bls12381.G2Affine{*sig, h})
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package tbls
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// partialSign returns the partial signatures of the signers, serialized and deserialized
+func partialSign(t *testing.T, signers []KeyShare, msg []byte) []PartialSignature {
+	t.Helper()
+	res := make([]PartialSignature, len(signers))
+	for i := range signers {
+		ps, err := signers[i].Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = res[i].SetBytes(ps.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 3, 5
+	keyShares, err := runDKG(newNetwork(n), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing threshold BLS")
+
+	partials := partialSign(t, keyShares, msg)
+	for i := range partials {
+		valid, err := groupKey.VerifyPartial(msg, &partials[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the partial signature should be valid")
+		}
+	}
+
+	// any threshold partial signatures give the same signature
+	var sig *bls12381.G2Affine
+	for _, subset := range [][]PartialSignature{partials[:threshold], partials[n-threshold:], partials} {
+		combined, err := groupKey.Combine(msg, subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig != nil && !sig.Equal(combined) {
+			t.Fatal("the signature should not depend on the signers")
+		}
+		sig = combined
+	}
+	valid, err := groupKey.Verify(msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Fatal("the signature should be valid")
+	}
+	if valid, _ = groupKey.Verify([]byte("another message"), sig); valid {
+		t.Fatal("the signature should not be valid for another message")
+	}
+
+	if _, err = groupKey.Combine(msg, partials[:threshold-1]); err != ErrNotEnoughSignatures {
+		t.Fatal("expected ErrNotEnoughSignatures, got", err)
+	}
+}
+
+// TestSignKnownAnswer checks a threshold signature against the first test vector of the suite
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_ of github.com/kwantam/bls_sigs_ref
+// (test-vectors/sig_g2_basic/P256, also in the testdata of cloudflare/circl's sign/bls). The
+// secret key is derived from the seed of the vector with KeyGen(seed, "BLS-SIG-KEYGEN-SALT-").
+func TestSignKnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	secret, _ := new(big.Int).SetString("2bfb7592b68fccd8db54461979d6a0d3d997b1405264b097232c1df29b5fade1", 16)
+	msg, err := hex.DecodeString("ff624d0ba02c7b6370c1622eec3fa2186ea681d1659e0a845448e777b75a8e77a77bb26e5733179d58ef9bc8a4e8b6971aef2539f77ab0963a3415bbd6258339bd1bf55de65db520c63f5b8eab3d55debd05e9494212170f5d65b3286b8b668705b1e2b2b5568610617abb51d2dd0cb450ef59df4b907da90cfa7b268de8c4c2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const threshold, n = 3, 5
+	keyShares, err := SplitKey(crand.Reader, secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	if hex.EncodeToString(encodePoint(&groupKey.PublicKey)) != "b2b79ab7a6f1e197ae543ae5fd3a38f69a4c85b82dc7fa5e4dc3be31677d1b0dec522b57876692fcc04f83e033f90a2e" {
+		t.Fatal("wrong group public key")
+	}
+
+	sig, err := groupKey.Combine(msg, partialSign(t, keyShares[n-threshold:], msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := sig.Bytes()
+	if hex.EncodeToString(b[:]) != "b1341b7f4fbaa9228ae3b98b8c070c8758d67e111fc20f11a49fac426384b148722791589aaacb4a1d48ec93fe838bca1217078d6b4ae284d985c1081a622b32e8122612bc0bab3596d052e82b7562fd48f7b2c78ac344ee784fd5f53d5a00ad" {
+		t.Fatalf("wrong signature %x", b)
+	}
+}
+
+func TestCulprits(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 4
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing threshold BLS")
+	partials := partialSign(t, keyShares, msg)
+
+	// the participant 2 sends the signature of another message
+	other, err := keyShares[1].Sign([]byte("another message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	partials[1] = *other
+	_, err = groupKey.Combine(msg, partials)
+	if !errors.Is(err, ErrInvalidPartialSignature) || !strings.Contains(err.Error(), "[2]") {
+		t.Fatal("expected ErrInvalidPartialSignature from the participant 2, got", err)
+	}
+
+	// a duplicated partial signature
+	partials[1] = partials[0]
+	if _, err = groupKey.Combine(msg, partials); !errors.Is(err, ErrMissingMessage) {
+		t.Fatal("expected ErrMissingMessage, got", err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := keyShares[0].Sign([]byte("testing threshold BLS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ps2 PartialSignature
+	read, err := ps2.SetBytes(ps.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != sizePartialSignature || ps2.ID != ps.ID || !ps2.S.Equal(&ps.S) {
+		t.Fatal("Error serialize(deserialize(.)) of the partial signature")
+	}
+	if _, err = ps2.SetBytes(ps.Bytes()[1:]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+	if !bytes.Equal(ps2.Bytes(), ps.Bytes()) {
+		t.Fatal("Error serialize(deserialize(.)) of the partial signature")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkCombine(b *testing.B) {
+	const threshold, n = 3, 5
+	keyShares, _ := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	msg := []byte("benchmark")
+	partials := make([]PartialSignature, threshold)
+	for i := range partials {
+		ps, _ := keyShares[i].Sign(msg)
+		partials[i] = *ps
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keyShares[0].Combine(msg, partials)
+	}
+}
@@ -15,4 +15,7 @@
 // Code generated by consensys/gnark-crypto DO NOT EDIT
 
 // Package twistededwards provides bls12-381's twisted edwards "companion curve" defined on fr.
+//
+// Messages can be hashed to the prime order subgroup (HashToCurve, EncodeToCurve) following
+// RFC 9380, with the Elligator 2 map on the birationally equivalent Montgomery curve.
 package twistededwards
@@ -0,0 +1,189 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ringsig
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+)
+
+// CLSAGSignature is a concise linkable spontaneous anonymous group signature
+// (c₀, s₀, ..., sₙ₋₁, I, D) on a ring of n public keys and commitments
+type CLSAGSignature struct {
+	C        big.Int   // first challenge c₀
+	S        []big.Int // responses sᵢ of the ring members
+	KeyImage KeyImage  // key image I of the signer
+	// D = z⋅Hp(Pπ) is the image of the commitment key z of the signer, which is not a
+	// linking tag
+	D twistededwards.PointAffine
+}
+
+// SignCLSAG signs msg with privKey on behalf of the ring, which must contain the public key of
+// privKey, and proves the knowledge of the commitment key z of the commitment of the signer,
+// commitments[π] = z⋅Base, at the same index π. The nonces are read from rand.
+//
+// The commitments are typically differences of Pedersen commitments to equal amounts, as in
+// Monero. With P the ring, C the commitments and x the secret scalar of the signer,
+// I = x⋅Hp(Pπ), D = z⋅Hp(Pπ) and the keys are aggregated into
+//
+//	Wᵢ = μP⋅Pᵢ + μC⋅Cᵢ, W̃ = μP⋅I + μC⋅D, w = μP⋅x + μC⋅z
+//	μP = H(0x04, P, C, I, D), μC = H(0x05, P, C, I, D)
+//
+// The signature is then an LSAG signature of the aggregated keys, with a single response per
+// ring member:
+//
+//	cᵢ₊₁ = H(prefix, sᵢ⋅Base + cᵢ⋅Wᵢ, sᵢ⋅Hp(Pᵢ) + cᵢ⋅W̃), prefix = H(P, C, I, D, msg)
+func SignCLSAG(rand io.Reader, privKey *PrivateKey, z *big.Int, ring []PublicKey, commitments []twistededwards.PointAffine, msg []byte, opts ...Option) (*CLSAGSignature, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.checkMessage(msg); err != nil {
+		return nil, err
+	}
+	P, C, err := ringCommitments(ring, commitments)
+	if err != nil {
+		return nil, err
+	}
+	pi, err := signerIndex(privKey, P)
+	if err != nil {
+		return nil, err
+	}
+	var zMod big.Int
+	zMod.Mod(z, &curveParams.Order)
+	var zBase twistededwards.PointAffine
+	zBase.ScalarMultiplication(&curveParams.Base, &zMod)
+	if !zBase.Equal(&C[pi]) {
+		return nil, errCommitmentKey
+	}
+	H, err := cfg.hashToPoints(P)
+	if err != nil {
+		return nil, err
+	}
+
+	var res CLSAGSignature
+	x := secretScalar(privKey)
+	res.KeyImage.I.ScalarMultiplication(&H[pi], x)
+	res.D.ScalarMultiplication(&H[pi], &zMod)
+
+	prefix, muP, muC, err := cfg.clsagParams(P, C, &res.KeyImage, &res.D, msg)
+	if err != nil {
+		return nil, err
+	}
+	W, image := aggregate(muP, muC, P, C, &res.KeyImage.I, &res.D)
+
+	var w, tmp big.Int
+	w.Mul(muP, x)
+	tmp.Mul(muC, &zMod)
+	w.Add(&w, &tmp).Mod(&w, &curveParams.Order)
+
+	c0, s, err := cfg.sign(rand, prefix, pi, &w, W, H, &image)
+	if err != nil {
+		return nil, err
+	}
+	res.C.Set(c0)
+	res.S = s
+	return &res, nil
+}
+
+// Verify reports whether sig is a valid CLSAG signature of msg by a member of the ring,
+// for the commitments.
+//
+// It returns an error if a public key of the ring is not in the prime order subgroup or is
+// the identity, or if a commitment is not in the prime order subgroup. The key image must be
+// in the prime order subgroup and not the identity, and D in the prime order subgroup, which
+// is the case of the honestly generated signatures.
+func (sig *CLSAGSignature) Verify(ring []PublicKey, commitments []twistededwards.PointAffine, msg []byte, opts ...Option) (bool, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return false, err
+	}
+	if err = cfg.checkMessage(msg); err != nil {
+		return false, err
+	}
+	P, C, err := ringCommitments(ring, commitments)
+	if err != nil {
+		return false, err
+	}
+	if len(sig.S) != len(P) {
+		return false, errInconsistentSize
+	}
+	if !checkKeyImage(&sig.KeyImage.I) || !sig.D.IsOnCurve() || !sig.D.IsInSubGroup() {
+		return false, nil
+	}
+	H, err := cfg.hashToPoints(P)
+	if err != nil {
+		return false, err
+	}
+
+	prefix, muP, muC, err := cfg.clsagParams(P, C, &sig.KeyImage, &sig.D, msg)
+	if err != nil {
+		return false, err
+	}
+	W, image := aggregate(muP, muC, P, C, &sig.KeyImage.I, &sig.D)
+	return cfg.verify(prefix, &sig.C, sig.S, W, H, &image)
+}
+
+// ringCommitments checks the ring as ringPoints, and that there is a commitment in the prime
+// order subgroup per public key. It returns the public keys and the commitments.
+func ringCommitments(ring []PublicKey, commitments []twistededwards.PointAffine) ([]twistededwards.PointAffine, []twistededwards.PointAffine, error) {
+	P, err := ringPoints(ring)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(commitments) != len(P) {
+		return nil, nil, errInconsistentSize
+	}
+	for i := range commitments {
+		if !commitments[i].IsOnCurve() || !commitments[i].IsInSubGroup() {
+			return nil, nil, errInvalidCommitment
+		}
+	}
+	return P, commitments, nil
+}
+
+// clsagParams returns the hash H(P, C, I, D, msg) which prefixes the hashes of the challenges,
+// and the aggregation coefficients μP and μC
+func (cfg *config) clsagParams(P, C []twistededwards.PointAffine, keyImage *KeyImage, D *twistededwards.PointAffine, msg []byte) (prefix []byte, muP, muC *big.Int, err error) {
+	points := append(pointers(P, C), &keyImage.I, D)
+	if muP, err = cfg.hashToScalar(domainAggKey, nil, points...); err != nil {
+		return
+	}
+	if muC, err = cfg.hashToScalar(domainAggCommit, nil, points...); err != nil {
+		return
+	}
+	prefix, err = cfg.digest(domainCLSAG, msg, points...)
+	return
+}
+
+// aggregate returns the aggregated keys Wᵢ = μP⋅Pᵢ + μC⋅Cᵢ and key image W̃ = μP⋅I + μC⋅D
+func aggregate(muP, muC *big.Int, P, C []twistededwards.PointAffine, I, D *twistededwards.PointAffine) ([]twistededwards.PointAffine, twistededwards.PointAffine) {
+	var tmp twistededwards.PointAffine
+	W := make([]twistededwards.PointAffine, len(P))
+	for i := range P {
+		W[i].ScalarMultiplication(&P[i], muP)
+		tmp.ScalarMultiplication(&C[i], muC)
+		W[i].Add(&W[i], &tmp)
+	}
+	var image twistededwards.PointAffine
+	image.ScalarMultiplication(I, muP)
+	tmp.ScalarMultiplication(D, muC)
+	image.Add(&image, &tmp)
+	return W, image
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package ringsig provides linkable ring signatures on bls12-381's twistededwards curve.
+//
+// A ring signature proves that the message was signed by one of the public keys of a ring,
+// without revealing which one. The signatures are linkable: each carries the key image
+// I = x⋅Hp(P) of the private key x of the signer (KeyImage), which is the same for all the
+// signatures made with the same key, so that for instance a second vote of the same voter is
+// detected by comparing the key images (KeyImage.Equal).
+//
+// Two schemes are implemented:
+//   - LSAG (SignLSAG), the linkable spontaneous anonymous group signature of Liu, Wei and Wong,
+//     as in Monero's MLSAG with a single key per ring member;
+//   - CLSAG (SignCLSAG), the concise linkable signature of Goodell, Noether and Blue, which in
+//     addition proves the knowledge of the commitment key of the commitment of the signer,
+//     with a single response per ring member. Its key image is the same as with LSAG.
+//
+// By default the challenges are SHA-512 of the inputs modulo the order, and the points are
+// hashed to the curve with Elligator 2 (EncodeToCurve). WithFieldHash selects a hash on field
+// elements instead, typically MiMC, so that the signatures can be verified in a circuit.
+//
+// The public keys of the ring must be in the prime order subgroup.
+// The keys are the eddsa keys of the curve (eddsa.PublicKey and eddsa.PrivateKey).
+// The scalars are encoded in big endian and the points are compressed as in eddsa.
+//
+// # See also
+//
+// https://eprint.iacr.org/2004/027.pdf
+//
+// https://eprint.iacr.org/2019/654.pdf
+package ringsig
@@ -0,0 +1,121 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ringsig
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+)
+
+// LSAGSignature is a linkable spontaneous anonymous group signature (c₀, s₀, ..., sₙ₋₁, I)
+// on a ring of n public keys
+type LSAGSignature struct {
+	C        big.Int   // first challenge c₀
+	S        []big.Int // responses sᵢ of the ring members
+	KeyImage KeyImage  // key image I of the signer
+}
+
+// SignLSAG signs msg with privKey on behalf of the ring, which must contain the public key of
+// privKey. The nonces are read from rand.
+//
+// With P the ring, π the index of the signer and x its secret scalar, I = x⋅Hp(Pπ) and
+//
+//	cᵢ₊₁ = H(prefix, sᵢ⋅Base + cᵢ⋅Pᵢ, sᵢ⋅Hp(Pᵢ) + cᵢ⋅I), prefix = H(P, I, msg)
+//
+// where the indices are modulo n, the sᵢ are random but sπ = α - cπ⋅x closes the ring, α being
+// the random nonce of cπ₊₁ = H(prefix, α⋅Base, α⋅Hp(Pπ)).
+func SignLSAG(rand io.Reader, privKey *PrivateKey, ring []PublicKey, msg []byte, opts ...Option) (*LSAGSignature, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.checkMessage(msg); err != nil {
+		return nil, err
+	}
+	P, err := ringPoints(ring)
+	if err != nil {
+		return nil, err
+	}
+	pi, err := signerIndex(privKey, P)
+	if err != nil {
+		return nil, err
+	}
+	H, err := cfg.hashToPoints(P)
+	if err != nil {
+		return nil, err
+	}
+
+	var res LSAGSignature
+	x := secretScalar(privKey)
+	res.KeyImage.I.ScalarMultiplication(&H[pi], x)
+
+	prefix, err := cfg.lsagPrefix(P, &res.KeyImage, msg)
+	if err != nil {
+		return nil, err
+	}
+	c0, s, err := cfg.sign(rand, prefix, pi, x, P, H, &res.KeyImage.I)
+	if err != nil {
+		return nil, err
+	}
+	res.C.Set(c0)
+	res.S = s
+	return &res, nil
+}
+
+// Verify reports whether sig is a valid LSAG signature of msg by a member of the ring.
+//
+// It returns an error if a public key of the ring is not in the prime order subgroup or is
+// the identity. The key image must be in the prime order subgroup and not the identity,
+// which is the case of the honestly generated signatures.
+func (sig *LSAGSignature) Verify(ring []PublicKey, msg []byte, opts ...Option) (bool, error) {
+	cfg, err := options(opts...)
+	if err != nil {
+		return false, err
+	}
+	if err = cfg.checkMessage(msg); err != nil {
+		return false, err
+	}
+	P, err := ringPoints(ring)
+	if err != nil {
+		return false, err
+	}
+	if len(sig.S) != len(P) {
+		return false, errInconsistentSize
+	}
+	if !checkKeyImage(&sig.KeyImage.I) {
+		return false, nil
+	}
+	H, err := cfg.hashToPoints(P)
+	if err != nil {
+		return false, err
+	}
+
+	prefix, err := cfg.lsagPrefix(P, &sig.KeyImage, msg)
+	if err != nil {
+		return false, err
+	}
+	return cfg.verify(prefix, &sig.C, sig.S, P, H, &sig.KeyImage.I)
+}
+
+// lsagPrefix returns the hash H(P, I, msg) of the ring, the key image and the message, which
+// prefixes the hashes of the challenges
+func (cfg *config) lsagPrefix(P []twistededwards.PointAffine, keyImage *KeyImage, msg []byte) ([]byte, error) {
+	points := append(pointers(P), &keyImage.I)
+	return cfg.digest(domainLSAG, msg, points...)
+}
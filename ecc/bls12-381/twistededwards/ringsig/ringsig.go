@@ -0,0 +1,381 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ringsig
+
+import (
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards/eddsa"
+)
+
+const (
+	sizeFr = fr.Bytes
+
+	// sizePoint is the size of a compressed point
+	sizePoint = fr.Bytes
+
+	sizePublicKey = sizePoint
+	sizeKeyImage  = sizePoint
+)
+
+// contextString is the prefix of the SHA-512 hashes
+const contextString = "bls12-381-twistededwards-ringsig"
+
+// hashToPointDST is the domain separation tag of EncodeToCurve in the hash to point
+const hashToPointDST = "RINGSIG_bls12-381-twistededwards_XMD:SHA-256_ELL2_NU_"
+
+// domain separators of the hashes
+const (
+	domainHashToPoint = 0x01
+	domainLSAG        = 0x02
+	domainCLSAG       = 0x03
+	domainAggKey      = 0x04
+	domainAggCommit   = 0x05
+	domainRound       = 0x06
+)
+
+var (
+	errNotOnCurve        = errors.New("point not on curve")
+	errInvalidKey        = errors.New("a public key is not in the prime order subgroup or is the identity")
+	errInvalidCommitment = errors.New("a commitment is not in the prime order subgroup")
+	errInvalidKeyImage   = errors.New("the key image is not in the prime order subgroup or is the identity")
+	errInvalidScalar     = errors.New("the scalar is not reduced modulo the order")
+	errEmptyRing         = errors.New("the ring must not be empty")
+	errNotInRing         = errors.New("the public key of the signer is not in the ring")
+	errCommitmentKey     = errors.New("the commitment key does not open the commitment of the signer")
+	errInconsistentSize  = errors.New("inconsistent number of public keys, commitments and responses")
+	errNilFieldHash      = errors.New("the field hash function must not be nil")
+	errFieldHashInput    = errors.New("with a field hash, the message must be a sequence of field elements")
+)
+
+// PublicKey is the public key of a ring member, an eddsa public key
+type PublicKey = eddsa.PublicKey
+
+// PrivateKey is the private key of a signer, an eddsa private key
+type PrivateKey = eddsa.PrivateKey
+
+// GenerateKey generates a public and private key pair, see eddsa.GenerateKey
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	return eddsa.GenerateKey(r)
+}
+
+// KeyImage is the key image I = x⋅Hp(P) of the private key x of public key P, where Hp hashes
+// the points to the prime order subgroup. It is deterministic for a given key and Option, and
+// unlinkable to P, so that two signatures with the same key image were made with the same key.
+type KeyImage struct {
+	I twistededwards.PointAffine
+}
+
+// Equal reports whether the signatures of the key images ki and other were made with the
+// same private key
+func (ki *KeyImage) Equal(other *KeyImage) bool {
+	return ki.I.Equal(&other.I)
+}
+
+// NewKeyImage returns the key image of privKey, which is also the key image of its LSAG and
+// CLSAG signatures made with the same options
+func NewKeyImage(privKey *PrivateKey, opts ...Option) (KeyImage, error) {
+	var res KeyImage
+	cfg, err := options(opts...)
+	if err != nil {
+		return res, err
+	}
+	hp, err := cfg.hashToPoint(&privKey.PublicKey.A)
+	if err != nil {
+		return res, err
+	}
+	res.I.ScalarMultiplication(&hp, secretScalar(privKey))
+	return res, nil
+}
+
+// Option selects the hash function of the signatures. The same options must be given to the
+// signature, its verification and NewKeyImage.
+type Option func(*config) error
+
+type config struct {
+	// fieldHash, if not nil, replaces SHA-512 and EncodeToCurve in the hashes
+	fieldHash func() hash.Hash
+}
+
+// WithFieldHash selects the circuit friendly hashes, computed with the hash function h on
+// field elements, typically hash.MIMC_BLS12_381.New. The inputs of the hashes are the domain
+// separator, the affine coordinates of the points and the data as field elements in big
+// endian, and the points are hashed with Hp(P) = MapToCurve(h(0x01, P)).
+//
+// The message must then be a sequence of field elements in big endian.
+func WithFieldHash(h func() hash.Hash) Option {
+	return func(cfg *config) error {
+		if h == nil {
+			return errNilFieldHash
+		}
+		cfg.fieldHash = h
+		return nil
+	}
+}
+
+func options(opts ...Option) (config, error) {
+	var cfg config
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// curveParams are the parameters of the twisted Edwards curve
+var curveParams = twistededwards.GetEdwardsCurve()
+
+// secretScalar returns the secret scalar x of privKey, with privKey.PublicKey = x⋅Base.
+// It is read from the encoding of privKey, publicKey||scalar||..., in both key types.
+func secretScalar(privKey *PrivateKey) *big.Int {
+	var x big.Int
+	x.SetBytes(privKey.Bytes()[sizePublicKey : sizePublicKey+sizeFr])
+	return x.Mod(&x, &curveParams.Order)
+}
+
+// randomScalar returns a uniformly random scalar in [1, order-1] read from rand
+func randomScalar(rand io.Reader) (*big.Int, error) {
+	// 128 extra bits make the bias of the reduction negligible
+	buf := make([]byte, (curveParams.Order.BitLen()+7)/8+16)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		s := new(big.Int).SetBytes(buf)
+		s.Mod(s, &curveParams.Order)
+		if s.Sign() != 0 {
+			return s, nil
+		}
+	}
+}
+
+// checkMessage checks that msg can be hashed with the options
+func (cfg *config) checkMessage(msg []byte) error {
+	if cfg.fieldHash != nil && len(msg)%fr.Bytes != 0 {
+		return errFieldHashInput
+	}
+	return nil
+}
+
+// ringPoints checks that the public keys of the ring are in the prime order subgroup and are
+// not the identity, and returns them
+func ringPoints(ring []PublicKey) ([]twistededwards.PointAffine, error) {
+	if len(ring) == 0 {
+		return nil, errEmptyRing
+	}
+	res := make([]twistededwards.PointAffine, len(ring))
+	for i := range ring {
+		if ring[i].A.IsZero() || !ring[i].A.IsOnCurve() || !ring[i].A.IsInSubGroup() {
+			return nil, errInvalidKey
+		}
+		res[i].Set(&ring[i].A)
+	}
+	return res, nil
+}
+
+// signerIndex returns the index of the public key of privKey in the ring of public keys
+func signerIndex(privKey *PrivateKey, ring []twistededwards.PointAffine) (int, error) {
+	for i := range ring {
+		if ring[i].Equal(&privKey.PublicKey.A) {
+			return i, nil
+		}
+	}
+	return 0, errNotInRing
+}
+
+// checkKeyImage reports whether the key image is in the prime order subgroup and is not the identity
+func checkKeyImage(p *twistededwards.PointAffine) bool {
+	return !p.IsZero() && p.IsOnCurve() && p.IsInSubGroup()
+}
+
+// pointers returns pointers to the points of the slices, in order
+func pointers(points ...[]twistededwards.PointAffine) []*twistededwards.PointAffine {
+	var n int
+	for _, s := range points {
+		n += len(s)
+	}
+	res := make([]*twistededwards.PointAffine, 0, n)
+	for _, s := range points {
+		for i := range s {
+			res = append(res, &s[i])
+		}
+	}
+	return res
+}
+
+// digest returns SHA-512(contextString || domain || points || data) with the points
+// compressed, or with a field hash, h(domain, points, data) with the affine coordinates of the
+// points as field elements
+func (cfg *config) digest(domain byte, data []byte, points ...*twistededwards.PointAffine) ([]byte, error) {
+	if cfg.fieldHash != nil {
+		h := cfg.fieldHash()
+		var prefix fr.Element
+		prefix.SetUint64(uint64(domain))
+		prefixBytes := prefix.Bytes()
+		if _, err := h.Write(prefixBytes[:]); err != nil {
+			return nil, err
+		}
+		for _, p := range points {
+			x, y := p.X.Bytes(), p.Y.Bytes()
+			if _, err := h.Write(x[:]); err != nil {
+				return nil, err
+			}
+			if _, err := h.Write(y[:]); err != nil {
+				return nil, err
+			}
+		}
+		if len(data) > 0 {
+			if _, err := h.Write(data); err != nil {
+				return nil, err
+			}
+		}
+		return h.Sum(nil), nil
+	}
+
+	h := sha512.New()
+	h.Write([]byte(contextString))
+	h.Write([]byte{domain})
+	for _, p := range points {
+		pString := p.Bytes()
+		h.Write(pString[:])
+	}
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// hashToScalar returns the digest of the inputs modulo the order
+func (cfg *config) hashToScalar(domain byte, data []byte, points ...*twistededwards.PointAffine) (*big.Int, error) {
+	digest, err := cfg.digest(domain, data, points...)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).SetBytes(digest)
+	return s.Mod(s, &curveParams.Order), nil
+}
+
+// hashToPoint returns Hp(p), in the prime order subgroup: EncodeToCurve(p) with the DST
+// hashToPointDST, or with a field hash, MapToCurve(h(domainHashToPoint, p))
+func (cfg *config) hashToPoint(p *twistededwards.PointAffine) (twistededwards.PointAffine, error) {
+	if cfg.fieldHash != nil {
+		digest, err := cfg.digest(domainHashToPoint, nil, p)
+		if err != nil {
+			return twistededwards.PointAffine{}, err
+		}
+		var u fr.Element
+		u.SetBytes(digest)
+		return twistededwards.MapToCurve(u), nil
+	}
+	pString := p.Bytes()
+	return twistededwards.EncodeToCurve(pString[:], []byte(hashToPointDST))
+}
+
+// hashToPoints returns Hp(p) for all the points
+func (cfg *config) hashToPoints(points []twistededwards.PointAffine) ([]twistededwards.PointAffine, error) {
+	res := make([]twistededwards.PointAffine, len(points))
+	for i := range points {
+		var err error
+		if res[i], err = cfg.hashToPoint(&points[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// round returns the challenge cᵢ₊₁ = H(prefix, sᵢ⋅Base + cᵢ⋅Wᵢ, sᵢ⋅Hᵢ + cᵢ⋅image) of a ring member
+func (cfg *config) round(prefix []byte, s, c *big.Int, W, H, image *twistededwards.PointAffine) (*big.Int, error) {
+	var L, R, tmp twistededwards.PointAffine
+	L.ScalarMultiplication(&curveParams.Base, s)
+	tmp.ScalarMultiplication(W, c)
+	L.Add(&L, &tmp)
+	R.ScalarMultiplication(H, s)
+	tmp.ScalarMultiplication(image, c)
+	R.Add(&R, &tmp)
+	return cfg.hashToScalar(domainRound, prefix, &L, &R)
+}
+
+// sign closes the ring of the signer at index pi, of secret w with W[pi] = w⋅Base and
+// image = w⋅H[pi]. It returns the first challenge c₀ and the responses sᵢ.
+//
+// The signer commits to α⋅Base, α⋅H[pi] for a random α, the challenges of the other members
+// follow from random responses, and the ring is closed with s_pi = α - c_pi⋅w.
+func (cfg *config) sign(rand io.Reader, prefix []byte, pi int, w *big.Int, W, H []twistededwards.PointAffine, image *twistededwards.PointAffine) (*big.Int, []big.Int, error) {
+	n := len(W)
+	s := make([]big.Int, n)
+	alpha, err := randomScalar(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var L, R twistededwards.PointAffine
+	L.ScalarMultiplication(&curveParams.Base, alpha)
+	R.ScalarMultiplication(&H[pi], alpha)
+	c, err := cfg.hashToScalar(domainRound, prefix, &L, &R)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// c is the challenge of the member i at the beginning of each iteration
+	var c0 big.Int
+	for i := (pi + 1) % n; i != pi; i = (i + 1) % n {
+		if i == 0 {
+			c0.Set(c)
+		}
+		si, err := randomScalar(rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		s[i].Set(si)
+		if c, err = cfg.round(prefix, &s[i], c, &W[i], &H[i], image); err != nil {
+			return nil, nil, err
+		}
+	}
+	if pi == 0 {
+		c0.Set(c)
+	}
+
+	s[pi].Mul(c, w).
+		Sub(alpha, &s[pi]).
+		Mod(&s[pi], &curveParams.Order)
+	return &c0, s, nil
+}
+
+// verify reports whether the challenges computed from c₀ and the responses close the ring
+func (cfg *config) verify(prefix []byte, c0 *big.Int, s []big.Int, W, H []twistededwards.PointAffine, image *twistededwards.PointAffine) (bool, error) {
+	if c0.Sign() < 0 || c0.Cmp(&curveParams.Order) >= 0 {
+		return false, nil
+	}
+	for i := range s {
+		if s[i].Sign() < 0 || s[i].Cmp(&curveParams.Order) >= 0 {
+			return false, nil
+		}
+	}
+	c := c0
+	for i := range W {
+		var err error
+		if c, err = cfg.round(prefix, &s[i], c, &W[i], &H[i], image); err != nil {
+			return false, err
+		}
+	}
+	return c.Cmp(c0) == 0, nil
+}
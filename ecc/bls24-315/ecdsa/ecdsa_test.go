@@ -17,12 +17,13 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
-	"testing"
-
+	"crypto/sha512"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
+	"testing"
 )
 
 func TestECDSA(t *testing.T) {
@@ -63,6 +64,133 @@ func TestECDSA(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestDeterministicNonce(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 10
+	}
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS24-315] RFC 6979 signatures should be deterministic and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			// the nonce depends on the HMAC hash function
+			sig3, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha512.New))
+			if err != nil {
+				return false
+			}
+			flag1, _ := publicKey.Verify(sig1, msg, hFunc)
+			flag3, _ := publicKey.Verify(sig3, msg, hFunc)
+
+			return flag1 && flag3 && bytes.Equal(sig1, sig2) && !bytes.Equal(sig1, sig3)
+		},
+	))
+
+	properties.Property("[BLS24-315] RFC 6979 signatures should be deterministic and valid (pre-hashed)", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			sig1, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sig1, msg, nil)
+
+			return flag && bytes.Equal(sig1, sig2)
+		},
+	))
+
+	properties.Property("[BLS24-315] hedged signatures should be randomized and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignWithOptions(msg, hFunc, WithHedgedNonce(sha256.New, rand.Reader))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, hFunc, WithHedgedNonce(sha256.New, rand.Reader))
+			if err != nil {
+				return false
+			}
+			flag1, _ := publicKey.Verify(sig1, msg, hFunc)
+			flag2, _ := publicKey.Verify(sig2, msg, hFunc)
+
+			return flag1 && flag2 && !bytes.Equal(sig1, sig2)
+		},
+	))
+
+	properties.Property("[BLS24-315] low-S signatures should be normalized and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sigBin, err := privKey.SignWithOptions(msg, hFunc, WithLowS())
+			if err != nil {
+				return false
+			}
+			var sig Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sigBin, msg, hFunc)
+
+			return flag && sig.IsLowS()
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestSignOptions(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("testing ECDSA")
+	if _, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(nil)); err == nil {
+		t.Fatal("nil hash function should be rejected")
+	}
+	if _, err := privKey.SignWithOptions(msg, nil, WithHedgedNonce(sha256.New, nil)); err == nil {
+		t.Fatal("nil entropy source should be rejected")
+	}
+
+	// out of range signatures are rejected
+	var sig Signature
+	order.FillBytes(sig.R[:])
+	sig.S[sizeFr-1] = 1
+	if flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, nil); flag {
+		t.Fatal("r = order should be rejected")
+	}
+	sig = Signature{}
+	sig.R[sizeFr-1] = 1
+	if flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, nil); flag {
+		t.Fatal("s = 0 should be rejected")
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -0,0 +1,153 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/test_vector_utils"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateDegreeDetection(t *testing.T) {
+	var ark, three fr.Element
+	ark.SetInt64(5)
+	three.SetInt64(3)
+
+	testCases := []struct {
+		name   string
+		f      GateFunction
+		nbIn   int
+		degree int
+	}{
+		{"sum3", Sum, 3, 1},
+		{"product3", Product, 3, 3},
+		{"linear-combination", LinearCombination(three, ark), 2, 1},
+		{"select-1", Select(1), 2, 1},
+		{"power-5", Power(5), 1, 5},
+		{"mimc-round-7", MiMCRound(ark, 7), 2, 7},
+		{"a-times-b-squared", func(in ...fr.Element) fr.Element {
+			var res fr.Element
+			res.Square(&in[1]).Mul(&res, &in[0])
+			return res
+		}, 2, 3},
+	}
+
+	for _, tc := range testCases {
+		degree, err := findGateDegree(tc.f, tc.nbIn)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.degree, degree, tc.name)
+
+		assert.NoError(t, checkGateDegree(tc.f, tc.nbIn, tc.degree), tc.name)
+		if tc.degree > 1 {
+			assert.Error(t, checkGateDegree(tc.f, tc.nbIn, tc.degree-1), tc.name)
+		}
+	}
+
+	_, err := findGateDegree(Power(maxAutoDegree+1), 1)
+	assert.Error(t, err, "degree larger than maxAutoDegree should not be detected")
+
+	assert.Panics(t, func() { Power(-1) }, "negative exponents must be rejected")
+}
+
+// unregisterGates removes the gates registered by a test from the global registry
+// once it is done
+func unregisterGates(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		gateRegistryLock.Lock()
+		defer gateRegistryLock.Unlock()
+		for _, name := range names {
+			delete(gateRegistry, name)
+		}
+	})
+}
+
+func TestRegisterGate(t *testing.T) {
+	unregisterGates(t, "test-cube", "test-wrong-degree")
+	assert.NoError(t, RegisterGate("test-cube", Power(3), 1))
+	g := GetGate("test-cube")
+	assert.NotNil(t, g)
+	assert.Equal(t, 3, g.Degree())
+	assert.Equal(t, 1, g.NbIn())
+	assert.Equal(t, "test-cube", g.Name())
+
+	assert.Error(t, RegisterGate("test-cube", Power(3), 1), "names must be unique")
+	assert.NoError(t, RegisterGate("test-cube", Power(3), 1, WithOverwrite(), WithDegree(4)))
+	assert.Equal(t, 4, GetGate("test-cube").Degree())
+
+	assert.Error(t, RegisterGate("test-wrong-degree", Power(3), 1, WithDegree(2)), "wrong degree must be caught")
+	assert.Nil(t, GetGate("test-wrong-degree"))
+
+	assert.Nil(t, GetGate("test-unknown"))
+}
+
+func TestCircuitDescription(t *testing.T) {
+	// a × b + b, serialized as the prover would send it
+	const serialized = `[{"gate":"","inputs":[]},{"gate":"","inputs":[]},{"gate":"mul2","inputs":[0,1]},{"gate":"add2","inputs":[2,1]}]`
+
+	var description CircuitDescription
+	assert.NoError(t, json.Unmarshal([]byte(serialized), &description))
+
+	c, err := description.ToCircuit()
+	assert.NoError(t, err)
+
+	// the description can be recovered from the circuit
+	redescription, err := c.Description()
+	assert.NoError(t, err)
+	reserialized, err := json.Marshal(redescription)
+	assert.NoError(t, err)
+	assert.Equal(t, serialized, string(reserialized))
+
+	// prove and verify with the described circuit
+	a := make([]fr.Element, 4)
+	b := make([]fr.Element, 4)
+	for i := range a {
+		a[i].SetInt64(int64(i + 1))
+		b[i].SetInt64(int64(2*i + 3))
+	}
+	assignment := WireAssignment{&c[0]: a, &c[1]: b}.Complete(c)
+
+	proof, err := Prove(c, assignment, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+	assert.NoError(t, err)
+
+	c, err = description.ToCircuit() // the verifier builds its own circuit
+	assert.NoError(t, err)
+	assignment = WireAssignment{&c[0]: a, &c[1]: b}.Complete(c)
+	assert.NoError(t, Verify(c, assignment, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+
+	// invalid descriptions
+	for _, invalid := range []string{
+		`[{"gate":"","inputs":[]},{"gate":"unregistered-gate","inputs":[0]}]`,
+		`[{"gate":"","inputs":[]},{"gate":"mul2","inputs":[0]}]`,
+		`[{"gate":"","inputs":[]},{"gate":"neg","inputs":[2]}]`,
+		`[{"gate":"neg","inputs":[]}]`,
+		`[{"gate":"","inputs":[]},{"gate":"add2","inputs":[0,2]},{"gate":"neg","inputs":[1]}]`,
+	} {
+		assert.NoError(t, json.Unmarshal([]byte(invalid), &description))
+		_, err = description.ToCircuit()
+		assert.Error(t, err, invalid)
+	}
+
+	// unregistered gates can't be described
+	c = make(Circuit, 2)
+	c[1] = Wire{Gate: mulGate{}, Inputs: []*Wire{&c[0], &c[0]}}
+	_, err = c.Description()
+	assert.Error(t, err)
+}
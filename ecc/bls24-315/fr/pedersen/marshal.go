@@ -0,0 +1,115 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+)
+
+// WriteTo writes the binary encoding of the proving key, with compressed points
+func (pk *ProvingKey) WriteTo(w io.Writer) (int64, error) {
+	return pk.writeTo(bls24315.NewEncoder(w))
+}
+
+// WriteRawTo writes the binary encoding of the proving key, with uncompressed points
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return pk.writeTo(bls24315.NewEncoder(w, bls24315.RawEncoding()))
+}
+
+func (pk *ProvingKey) writeTo(enc *bls24315.Encoder) (int64, error) {
+
+	toEncode := []interface{}{
+		pk.Basis,
+		pk.BasisExpSigma,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a proving key written with WriteTo or WriteRawTo
+func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24315.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&pk.Basis,
+		&pk.BasisExpSigma,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	if len(pk.Basis) != len(pk.BasisExpSigma) {
+		return dec.BytesRead(), ErrNbValues
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the verifying key, with compressed points
+func (vk *VerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	return vk.writeTo(bls24315.NewEncoder(w))
+}
+
+// WriteRawTo writes the binary encoding of the verifying key, with uncompressed points
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.writeTo(bls24315.NewEncoder(w, bls24315.RawEncoding()))
+}
+
+func (vk *VerifyingKey) writeTo(enc *bls24315.Encoder) (int64, error) {
+
+	toEncode := []interface{}{
+		&vk.G,
+		&vk.GRootSigmaNeg,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a verifying key written with WriteTo or WriteRawTo
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24315.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&vk.G,
+		&vk.GRootSigmaNeg,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
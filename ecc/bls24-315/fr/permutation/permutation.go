@@ -22,7 +22,6 @@ import (
 	"math/big"
 	"math/bits"
 
-	"github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
@@ -34,6 +33,7 @@ var (
 	ErrSize             = errors.New("t1 and t2 should be of size a power of 2")
 	ErrPermutationProof = errors.New("permutation proof verification failed")
 	ErrGenerator        = errors.New("wrong generator")
+	ErrNbClaimedValues  = errors.New("wrong number of claimed values")
 )
 
 // Proof proof that the commitments of t1 and t2 come from
@@ -60,6 +60,46 @@ type Proof struct {
 	shiftedProof kzg.OpeningProof
 }
 
+// Size returns the size of the permuted vectors.
+func (proof *Proof) Size() int {
+	return proof.size
+}
+
+// Generator returns the generator of the fft domain.
+func (proof *Proof) Generator() fr.Element {
+	return proof.g
+}
+
+// T1 returns the commitment to t1.
+func (proof *Proof) T1() kzg.Digest {
+	return proof.t1
+}
+
+// T2 returns the commitment to t2.
+func (proof *Proof) T2() kzg.Digest {
+	return proof.t2
+}
+
+// Z returns the commitment to the accumulation polynomial.
+func (proof *Proof) Z() kzg.Digest {
+	return proof.z
+}
+
+// Q returns the commitment to the quotient polynomial.
+func (proof *Proof) Q() kzg.Digest {
+	return proof.q
+}
+
+// BatchedProof returns the opening proof of t1, t2, z, q (in that order).
+func (proof *Proof) BatchedProof() kzg.BatchOpeningProof {
+	return proof.batchedProof
+}
+
+// ShiftedProof returns the shifted opening proof of z.
+func (proof *Proof) ShiftedProof() kzg.OpeningProof {
+	return proof.shiftedProof
+}
+
 // evaluateAccumulationPolynomialBitReversed returns the accumulation polynomial in Lagrange basis.
 func evaluateAccumulationPolynomialBitReversed(lt1, lt2 []fr.Element, epsilon fr.Element) []fr.Element {
 
@@ -156,7 +196,7 @@ func Prove(srs *kzg.SRS, t1, t2 []fr.Element) (Proof, error) {
 	hFunc := sha256.New()
 
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "epsilon", "omega", "eta")
+	fs := fiatshamir.NewSponge(sha256.New, "permutation")
 
 	// commit t1, t2
 	ct1 := make([]fr.Element, s)
@@ -177,7 +217,8 @@ func Prove(srs *kzg.SRS, t1, t2 []fr.Element) (Proof, error) {
 	}
 
 	// derive challenge for z
-	epsilon, err := deriveRandomness(&fs, "epsilon", &proof.t1, &proof.t2)
+	fs.AbsorbMarshalers("epsilon", &proof.t1, &proof.t2)
+	epsilon, err := fiatshamir.SqueezeElement[fr.Element](fs, "epsilon", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -206,7 +247,8 @@ func Prove(srs *kzg.SRS, t1, t2 []fr.Element) (Proof, error) {
 	lsNum := evaluateSecondPartNumReverse(lz, d)
 
 	// derive challenge used for the folding
-	omega, err := deriveRandomness(&fs, "omega", &proof.z)
+	fs.AbsorbMarshalers("omega", &proof.z)
+	omega, err := fiatshamir.SqueezeElement[fr.Element](fs, "omega", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -229,7 +271,8 @@ func Prove(srs *kzg.SRS, t1, t2 []fr.Element) (Proof, error) {
 	}
 
 	// derive the evaluation challenge
-	eta, err := deriveRandomness(&fs, "eta", &proof.q)
+	fs.AbsorbMarshalers("eta", &proof.q)
+	eta, err := fiatshamir.SqueezeElement[fr.Element](fs, "eta", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -275,24 +318,32 @@ func Prove(srs *kzg.SRS, t1, t2 []fr.Element) (Proof, error) {
 // Verify verifies a permutation proof.
 func Verify(srs *kzg.SRS, proof Proof) error {
 
+	// the proof may come from an untrusted source
+	if len(proof.batchedProof.ClaimedValues) != 4 {
+		return ErrNbClaimedValues
+	}
+
 	// hash function that is used for Fiat Shamir
 	hFunc := sha256.New()
 
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "epsilon", "omega", "eta")
+	fs := fiatshamir.NewSponge(sha256.New, "permutation")
 
 	// derive the challenges
-	epsilon, err := deriveRandomness(&fs, "epsilon", &proof.t1, &proof.t2)
+	fs.AbsorbMarshalers("epsilon", &proof.t1, &proof.t2)
+	epsilon, err := fiatshamir.SqueezeElement[fr.Element](fs, "epsilon", fr.Modulus())
 	if err != nil {
 		return err
 	}
 
-	omega, err := deriveRandomness(&fs, "omega", &proof.z)
+	fs.AbsorbMarshalers("omega", &proof.z)
+	omega, err := fiatshamir.SqueezeElement[fr.Element](fs, "omega", fr.Modulus())
 	if err != nil {
 		return err
 	}
 
-	eta, err := deriveRandomness(&fs, "eta", &proof.q)
+	fs.AbsorbMarshalers("eta", &proof.q)
+	eta, err := fiatshamir.SqueezeElement[fr.Element](fs, "eta", fr.Modulus())
 	if err != nil {
 		return err
 	}
@@ -356,24 +407,3 @@ func Verify(srs *kzg.SRS, proof Proof) error {
 
 	return nil
 }
-
-// TODO put that in fiat-shamir package
-func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*bls24315.G1Affine) (fr.Element, error) {
-
-	var buf [bls24315.SizeOfG1AffineUncompressed]byte
-	var r fr.Element
-
-	for _, p := range points {
-		buf = p.RawBytes()
-		if err := fs.Bind(challenge, buf[:]); err != nil {
-			return r, err
-		}
-	}
-
-	b, err := fs.ComputeChallenge(challenge)
-	if err != nil {
-		return r, err
-	}
-	r.SetBytes(b)
-	return r, nil
-}
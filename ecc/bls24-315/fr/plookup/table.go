@@ -22,7 +22,6 @@ import (
 	"math/big"
 	"sort"
 
-	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
@@ -33,7 +32,7 @@ import (
 var (
 	ErrIncompatibleSize = errors.New("the tables in f and t are not of the same size")
 	ErrFoldedCommitment = errors.New("the folded commitment is malformed")
-	ErrNumberDigests    = errors.New("proof.ts and proof.fs are not of the same length")
+	ErrNumberDigests    = errors.New("proof.ts and proof.fs are empty or not of the same length")
 )
 
 // ProofLookupTables proofs that a list of tables
@@ -52,6 +51,26 @@ type ProofLookupTables struct {
 	permutationProof permutation.Proof
 }
 
+// Fs returns the commitments to the rows of f.
+func (proof *ProofLookupTables) Fs() []kzg.Digest {
+	return proof.fs
+}
+
+// Ts returns the commitments to the rows of t.
+func (proof *ProofLookupTables) Ts() []kzg.Digest {
+	return proof.ts
+}
+
+// FoldedProof returns the lookup proof for f and t folded.
+func (proof *ProofLookupTables) FoldedProof() ProofLookupVector {
+	return proof.foldedProof
+}
+
+// PermutationProof returns the proof that the folded ts correspond to t in the folded proof.
+func (proof *ProofLookupTables) PermutationProof() permutation.Proof {
+	return proof.permutationProof
+}
+
 // ProveLookupTables generates a proof that f, seen as a multi dimensional table,
 // consists of vectors that are in t. In other words for each i, f[:][i] must be one
 // of the t[:][j].
@@ -66,11 +85,8 @@ func ProveLookupTables(srs *kzg.SRS, f, t []fr.Vector) (ProofLookupTables, error
 	proof := ProofLookupTables{}
 	var err error
 
-	// hash function used for Fiat Shamir
-	hFunc := sha256.New()
-
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "lambda")
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.table")
 
 	// check the sizes
 	if len(f) != len(t) {
@@ -138,14 +154,13 @@ func ProveLookupTables(srs *kzg.SRS, f, t []fr.Vector) (ProofLookupTables, error
 	}
 
 	// fold f and t
-	comms := make([]*kzg.Digest, 2*nbRows)
+	comms := make([]fiatshamir.Marshaler, 2*nbRows)
 	for i := 0; i < nbRows; i++ {
-		comms[i] = new(kzg.Digest)
-		comms[i].Set(&proof.fs[i])
-		comms[nbRows+i] = new(kzg.Digest)
-		comms[nbRows+i].Set(&proof.ts[i])
+		comms[i] = &proof.fs[i]
+		comms[nbRows+i] = &proof.ts[i]
 	}
-	lambda, err := deriveRandomness(&fs, "lambda", comms...)
+	fs.AbsorbMarshalers("lambda", comms...)
+	lambda, err := fiatshamir.SqueezeElement[fr.Element](fs, "lambda", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -179,25 +194,23 @@ func ProveLookupTables(srs *kzg.SRS, f, t []fr.Vector) (ProofLookupTables, error
 // VerifyLookupTables verifies that a ProofLookupTables proof is correct.
 func VerifyLookupTables(srs *kzg.SRS, proof ProofLookupTables) error {
 
-	// hash function used for Fiat Shamir
-	hFunc := sha256.New()
-
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "lambda")
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.table")
 
 	// check that the number of digests is the same
-	if len(proof.fs) != len(proof.ts) {
+	if len(proof.fs) != len(proof.ts) || len(proof.fs) == 0 {
 		return ErrNumberDigests
 	}
 
 	// fold the commitments fs and ts
 	nbRows := len(proof.fs)
-	comms := make([]*kzg.Digest, 2*nbRows)
+	comms := make([]fiatshamir.Marshaler, 2*nbRows)
 	for i := 0; i < nbRows; i++ {
 		comms[i] = &proof.fs[i]
 		comms[i+nbRows] = &proof.ts[i]
 	}
-	lambda, err := deriveRandomness(&fs, "lambda", comms...)
+	fs.AbsorbMarshalers("lambda", comms...)
+	lambda, err := fiatshamir.SqueezeElement[fr.Element](fs, "lambda", fr.Modulus())
 	if err != nil {
 		return err
 	}
@@ -229,24 +242,3 @@ func VerifyLookupTables(srs *kzg.SRS, proof ProofLookupTables) error {
 	// verify the inner proof
 	return VerifyLookupVector(srs, proof.foldedProof)
 }
-
-// TODO put that in fiat-shamir package
-func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*bls24315.G1Affine) (fr.Element, error) {
-
-	var buf [bls24315.SizeOfG1AffineUncompressed]byte
-	var r fr.Element
-
-	for _, p := range points {
-		buf = p.RawBytes()
-		if err := fs.Bind(challenge, buf[:]); err != nil {
-			return r, err
-		}
-	}
-
-	b, err := fs.ComputeChallenge(challenge)
-	if err != nil {
-		return r, err
-	}
-	r.SetBytes(b)
-	return r, nil
-}
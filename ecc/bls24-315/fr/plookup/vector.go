@@ -54,6 +54,46 @@ type ProofLookupVector struct {
 	BatchedProofShifted kzg.BatchOpeningProof
 }
 
+// Size returns the size of the system.
+func (proof *ProofLookupVector) Size() uint64 {
+	return proof.size
+}
+
+// Generator returns the generator of the fft domain.
+func (proof *ProofLookupVector) Generator() fr.Element {
+	return proof.g
+}
+
+// H1 returns the commitment to h1.
+func (proof *ProofLookupVector) H1() kzg.Digest {
+	return proof.h1
+}
+
+// H2 returns the commitment to h2.
+func (proof *ProofLookupVector) H2() kzg.Digest {
+	return proof.h2
+}
+
+// T returns the commitment to the lookup table t.
+func (proof *ProofLookupVector) T() kzg.Digest {
+	return proof.t
+}
+
+// Z returns the commitment to the accumulation polynomial.
+func (proof *ProofLookupVector) Z() kzg.Digest {
+	return proof.z
+}
+
+// F returns the commitment to the looked up vector f.
+func (proof *ProofLookupVector) F() kzg.Digest {
+	return proof.f
+}
+
+// H returns the commitment to the quotient polynomial.
+func (proof *ProofLookupVector) H() kzg.Digest {
+	return proof.h
+}
+
 // evaluateAccumulationPolynomial computes Z, in Lagrange basis. Z is the accumulation of the partial
 // ratios of 2 fully split polynomials (cf https://eprint.iacr.org/2020/315.pdf)
 // * lf is the list of values that should be in lt
@@ -363,7 +403,7 @@ func ProveLookupVector(srs *kzg.SRS, f, t fr.Vector) (ProofLookupVector, error)
 	hFunc := sha256.New()
 
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "beta", "gamma", "alpha", "nu")
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.vector")
 
 	// create domains
 	var domainSmall *fft.Domain
@@ -441,11 +481,12 @@ func ProveLookupVector(srs *kzg.SRS, f, t fr.Vector) (ProofLookupVector, error)
 	}
 
 	// derive beta, gamma
-	beta, err := deriveRandomness(&fs, "beta", &proof.t, &proof.f, &proof.h1, &proof.h2)
+	fs.AbsorbMarshalers("beta", &proof.t, &proof.f, &proof.h1, &proof.h2)
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
-	gamma, err := deriveRandomness(&fs, "gamma")
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -495,7 +536,8 @@ func ProveLookupVector(srs *kzg.SRS, f, t fr.Vector) (ProofLookupVector, error)
 	lh1h2 := evaluateOverlapH1h2BitReversed(_lh1, _lh2, domainBig)
 
 	// compute the quotient
-	alpha, err := deriveRandomness(&fs, "alpha", &proof.z)
+	fs.AbsorbMarshalers("alpha", &proof.z)
+	alpha, err := fiatshamir.SqueezeElement[fr.Element](fs, "alpha", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -506,7 +548,8 @@ func ProveLookupVector(srs *kzg.SRS, f, t fr.Vector) (ProofLookupVector, error)
 	}
 
 	// build the opening proofs
-	nu, err := deriveRandomness(&fs, "nu", &proof.h)
+	fs.AbsorbMarshalers("nu", &proof.h)
+	nu, err := fiatshamir.SqueezeElement[fr.Element](fs, "nu", fr.Modulus())
 	if err != nil {
 		return proof, err
 	}
@@ -567,25 +610,28 @@ func VerifyLookupVector(srs *kzg.SRS, proof ProofLookupVector) error {
 	hFunc := sha256.New()
 
 	// transcript to derive the challenge
-	fs := fiatshamir.NewTranscript(hFunc, "beta", "gamma", "alpha", "nu")
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.vector")
 
 	// derive the various challenges
-	beta, err := deriveRandomness(&fs, "beta", &proof.t, &proof.f, &proof.h1, &proof.h2)
+	fs.AbsorbMarshalers("beta", &proof.t, &proof.f, &proof.h1, &proof.h2)
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
 	if err != nil {
 		return err
 	}
 
-	gamma, err := deriveRandomness(&fs, "gamma")
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
 	if err != nil {
 		return err
 	}
 
-	alpha, err := deriveRandomness(&fs, "alpha", &proof.z)
+	fs.AbsorbMarshalers("alpha", &proof.z)
+	alpha, err := fiatshamir.SqueezeElement[fr.Element](fs, "alpha", fr.Modulus())
 	if err != nil {
 		return err
 	}
 
-	nu, err := deriveRandomness(&fs, "nu", &proof.h)
+	fs.AbsorbMarshalers("nu", &proof.h)
+	nu, err := fiatshamir.SqueezeElement[fr.Element](fs, "nu", fr.Modulus())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,653 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/polynomial"
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+// This file provides Claims and LazyClaims implementations for the common cases:
+// sums of products of multilinear polynomials, zero-checks and log-derivative lookups.
+// In all of them, the prover sends the values of the polynomials at the random point r as final evaluation proof.
+// These values are not checked against anything but the sumcheck itself: it is up to the caller to open
+// commitments to the polynomials at r, using Point and Evaluations on the verifier side.
+
+// Composition is a polynomial function of the values of multilinear polynomials at a point.
+// Any gkr.Gate is a Composition.
+type Composition interface {
+	Evaluate(...fr.Element) fr.Element
+	Degree() int
+}
+
+// ClaimsOption configures the prover-side claims
+type ClaimsOption func(*composedClaims)
+
+// WithWorkers parallelizes the prover over the given worker pool
+func WithWorkers(workers *utils.WorkerPool) ClaimsOption {
+	return func(c *composedClaims) {
+		c.workers = workers
+	}
+}
+
+// composedClaims is the common prover logic: the (combined) summand is a function of the values of some multilinear tables,
+// of degree at most degree in each variable.
+type composedClaims struct {
+	tables  []polynomial.MultiLin
+	degree  int
+	summand func(values []fr.Element) fr.Element
+	workers *utils.WorkerPool
+}
+
+func newComposedClaims(tables []polynomial.MultiLin, options []ClaimsOption) (composedClaims, error) {
+	c := composedClaims{tables: make([]polynomial.MultiLin, len(tables))}
+	if len(tables) == 0 {
+		return c, fmt.Errorf("no polynomials")
+	}
+	n := len(tables[0])
+	if n < 2 || n&(n-1) != 0 {
+		return c, fmt.Errorf("the size of the polynomials must be a power of two, at least 2")
+	}
+	for i := range tables {
+		if len(tables[i]) != n {
+			return c, fmt.Errorf("polynomial %d: size %d, expected %d", i, len(tables[i]), n)
+		}
+		// the tables are folded in place
+		c.tables[i] = tables[i].Clone()
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return c, nil
+}
+
+func (c *composedClaims) VarsNum() int {
+	return c.tables[0].NumVars()
+}
+
+// roundPolynomial returns gⱼ(1), ..., gⱼ(degree) where gⱼ(X) = ∑_{i} summand(tables(r₁, ..., rⱼ₋₁, X, i...))
+func (c *composedClaims) roundPolynomial() polynomial.Polynomial {
+	nbTables := len(c.tables)
+	mid := len(c.tables[0]) / 2
+
+	res := make(polynomial.Polynomial, c.degree)
+	var lock sync.Mutex
+	computeAll := func(start, end int) {
+		partial := make([]fr.Element, c.degree)
+		values := make([]fr.Element, nbTables)
+		steps := make([]fr.Element, nbTables)
+		for i := start; i < end; i++ {
+			// each table is linear in X: f(X+1) = f(X) + f(1) - f(0)
+			for k := range c.tables {
+				values[k].Set(&c.tables[k][i+mid])
+				steps[k].Sub(&values[k], &c.tables[k][i])
+			}
+			for d := 0; d < c.degree; d++ {
+				if d != 0 {
+					for k := range values {
+						values[k].Add(&values[k], &steps[k])
+					}
+				}
+				s := c.summand(values)
+				partial[d].Add(&partial[d], &s)
+			}
+		}
+		lock.Lock()
+		for d := range res {
+			res[d].Add(&res[d], &partial[d])
+		}
+		lock.Unlock()
+	}
+
+	const minBlockSize = 64
+	if c.workers == nil || mid < minBlockSize {
+		computeAll(0, mid)
+	} else {
+		c.workers.Submit(mid, computeAll, minBlockSize).Wait()
+	}
+	return res
+}
+
+func (c *composedClaims) Next(r fr.Element) polynomial.Polynomial {
+	const minBlockSize = 512
+	n := len(c.tables[0]) / 2
+	if c.workers == nil || n < minBlockSize {
+		for k := range c.tables {
+			c.tables[k].Fold(r)
+		}
+	} else {
+		wgs := make([]*sync.WaitGroup, len(c.tables))
+		for k := range c.tables {
+			wgs[k] = c.workers.Submit(n, c.tables[k].FoldParallel(r), minBlockSize)
+		}
+		for _, wg := range wgs {
+			wg.Wait()
+		}
+	}
+	return c.roundPolynomial()
+}
+
+// finalEvaluations returns the values of the first n tables, fully folded
+func (c *composedClaims) finalEvaluations(r fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for k := range res {
+		c.tables[k].Fold(r)
+		res[k] = c.tables[k][0]
+	}
+	return res
+}
+
+// lazyEvaluations holds the final evaluations received by the verifier
+type lazyEvaluations struct {
+	point       []fr.Element
+	evaluations []fr.Element
+}
+
+// Point returns the random point r at which the polynomials are evaluated. It is only set after a successful verification.
+func (e *lazyEvaluations) Point() []fr.Element {
+	return e.point
+}
+
+func (e *lazyEvaluations) receive(proof interface{}, nbEvaluations int) ([]fr.Element, error) {
+	evaluations, ok := proof.([]fr.Element)
+	if !ok || len(evaluations) != nbEvaluations {
+		return nil, fmt.Errorf("malformed final evaluation proof")
+	}
+	return evaluations, nil
+}
+
+func (e *lazyEvaluations) accept(r, evaluations []fr.Element) {
+	e.point = append([]fr.Element(nil), r...)
+	e.evaluations = evaluations
+}
+
+// powers returns 1, a, ..., aⁿ⁻¹
+func powers(a fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	if n == 0 {
+		return res
+	}
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &a)
+	}
+	return res
+}
+
+// ProductClaims are claims of the form ∑_{x ∈ {0,1}ⁿ} ∏ₖ fⱼₖ(x) = cⱼ, for one or more products of multilinear polynomials fⱼₖ.
+// The final evaluation proof is the list of the fⱼₖ(r), in order.
+type ProductClaims struct {
+	composedClaims
+	nbFactors []int
+}
+
+// NewProductClaims returns the claims on the sums of the given products. The polynomials are not modified.
+func NewProductClaims(products [][]polynomial.MultiLin, options ...ClaimsOption) (*ProductClaims, error) {
+	c := ProductClaims{nbFactors: make([]int, len(products))}
+	var tables []polynomial.MultiLin
+	for j := range products {
+		if len(products[j]) == 0 {
+			return nil, fmt.Errorf("product %d is empty", j)
+		}
+		c.nbFactors[j] = len(products[j])
+		tables = append(tables, products[j]...)
+	}
+	var err error
+	if c.composedClaims, err = newComposedClaims(tables, options); err != nil {
+		return nil, err
+	}
+	c.degree = maxInt(c.nbFactors)
+	return &c, nil
+}
+
+// Sums returns the claimed sums cⱼ. It must be called before proving.
+func (c *ProductClaims) Sums() []fr.Element {
+	res := make([]fr.Element, len(c.nbFactors))
+	values := make([]fr.Element, len(c.tables))
+	for i := range c.tables[0] {
+		for k := range c.tables {
+			values[k] = c.tables[k][i]
+		}
+		products := productsOf(values, c.nbFactors)
+		for j := range res {
+			res[j].Add(&res[j], &products[j])
+		}
+	}
+	return res
+}
+
+// productsOf computes the products of consecutive groups of values
+func productsOf(values []fr.Element, nbFactors []int) []fr.Element {
+	res := make([]fr.Element, len(nbFactors))
+	for j, n := range nbFactors {
+		res[j].Set(&values[0])
+		for k := 1; k < n; k++ {
+			res[j].Mul(&res[j], &values[k])
+		}
+		values = values[n:]
+	}
+	return res
+}
+
+func maxInt(s []int) int {
+	res := s[0]
+	for _, v := range s[1:] {
+		if v > res {
+			res = v
+		}
+	}
+	return res
+}
+
+func (c *ProductClaims) ClaimsNum() int {
+	return len(c.nbFactors)
+}
+
+func (c *ProductClaims) Combine(a fr.Element) polynomial.Polynomial {
+	aI := powers(a, len(c.nbFactors))
+	c.summand = func(values []fr.Element) fr.Element {
+		var res fr.Element
+		products := productsOf(values, c.nbFactors)
+		for j := range products {
+			products[j].Mul(&products[j], &aI[j])
+			res.Add(&res, &products[j])
+		}
+		return res
+	}
+	return c.roundPolynomial()
+}
+
+func (c *ProductClaims) ProveFinalEval(r []fr.Element) interface{} {
+	return c.finalEvaluations(r[len(r)-1], len(c.tables))
+}
+
+// ProductLazyClaims is the verifier side of ProductClaims
+type ProductLazyClaims struct {
+	lazyEvaluations
+	nbVars    int
+	nbFactors []int
+	sums      []fr.Element
+}
+
+// NewProductLazyClaims returns the claims ∑_{x ∈ {0,1}ⁿ} ∏ₖ fⱼₖ(x) = sums[j], where the j-th product has nbFactors[j] factors
+func NewProductLazyClaims(nbVars int, nbFactors []int, sums []fr.Element) (*ProductLazyClaims, error) {
+	if len(nbFactors) != len(sums) || len(sums) == 0 {
+		return nil, fmt.Errorf("one sum expected per product")
+	}
+	for j := range nbFactors {
+		if nbFactors[j] < 1 {
+			return nil, fmt.Errorf("product %d is empty", j)
+		}
+	}
+	return &ProductLazyClaims{nbVars: nbVars, nbFactors: nbFactors, sums: sums}, nil
+}
+
+// Evaluations returns the values fⱼₖ(r), indexed by product then factor. It is only set after a successful verification.
+func (c *ProductLazyClaims) Evaluations() [][]fr.Element {
+	if c.evaluations == nil {
+		return nil
+	}
+	res := make([][]fr.Element, len(c.nbFactors))
+	evaluations := c.evaluations
+	for j, n := range c.nbFactors {
+		res[j], evaluations = evaluations[:n], evaluations[n:]
+	}
+	return res
+}
+
+func (c *ProductLazyClaims) ClaimsNum() int {
+	return len(c.sums)
+}
+
+func (c *ProductLazyClaims) VarsNum() int {
+	return c.nbVars
+}
+
+func (c *ProductLazyClaims) CombinedSum(a fr.Element) fr.Element {
+	sumsAsPoly := polynomial.Polynomial(c.sums)
+	return sumsAsPoly.Eval(&a)
+}
+
+func (c *ProductLazyClaims) Degree(int) int {
+	return maxInt(c.nbFactors)
+}
+
+func (c *ProductLazyClaims) VerifyFinalEval(r []fr.Element, combinationCoeff fr.Element, purportedValue fr.Element, proof interface{}) error {
+	nbEvaluations := 0
+	for _, n := range c.nbFactors {
+		nbEvaluations += n
+	}
+	evaluations, err := c.receive(proof, nbEvaluations)
+	if err != nil {
+		return err
+	}
+
+	products := productsOf(evaluations, c.nbFactors)
+	productsAsPoly := polynomial.Polynomial(products)
+	if expected := productsAsPoly.Eval(&combinationCoeff); !expected.Equal(&purportedValue) {
+		return fmt.Errorf("incompatible evaluations")
+	}
+	c.accept(r, evaluations)
+	return nil
+}
+
+// ZeroCheckClaims is the claim ∑_{x ∈ {0,1}ⁿ} eq(τ, x) C(f₁(x), ..., fₖ(x)) = 0, which shows that
+// C(f₁, ..., fₖ) vanishes on the hypercube, if τ is chosen at random after the fᵢ are committed to.
+// The final evaluation proof is the list of the fᵢ(r).
+type ZeroCheckClaims struct {
+	composedClaims
+	c Composition
+}
+
+// NewZeroCheckClaims returns the zero-check claim at τ. The polynomials are not modified.
+func NewZeroCheckClaims(c Composition, tau []fr.Element, polys []polynomial.MultiLin, options ...ClaimsOption) (*ZeroCheckClaims, error) {
+	if len(polys) == 0 || len(polys[0]) != 1<<len(tau) {
+		return nil, fmt.Errorf("polynomials of size 2^%d expected", len(tau))
+	}
+	eq := make(polynomial.MultiLin, 1<<len(tau))
+	eq[0].SetOne()
+	eq.Eq(tau)
+
+	composed, err := newComposedClaims(append(polys[:len(polys):len(polys)], eq), options)
+	if err != nil {
+		return nil, err
+	}
+	res := ZeroCheckClaims{composedClaims: composed, c: c}
+	res.degree = c.Degree() + 1
+	res.summand = func(values []fr.Element) fr.Element {
+		nbPolys := len(values) - 1
+		s := c.Evaluate(values[:nbPolys]...)
+		s.Mul(&s, &values[nbPolys])
+		return s
+	}
+	return &res, nil
+}
+
+func (c *ZeroCheckClaims) ClaimsNum() int {
+	return 1
+}
+
+func (c *ZeroCheckClaims) Combine(fr.Element) polynomial.Polynomial {
+	return c.roundPolynomial()
+}
+
+func (c *ZeroCheckClaims) ProveFinalEval(r []fr.Element) interface{} {
+	return c.finalEvaluations(r[len(r)-1], len(c.tables)-1)
+}
+
+// ZeroCheckLazyClaims is the verifier side of ZeroCheckClaims
+type ZeroCheckLazyClaims struct {
+	lazyEvaluations
+	c       Composition
+	tau     []fr.Element
+	nbPolys int
+}
+
+// NewZeroCheckLazyClaims returns the zero-check claim at τ on nbPolys polynomials
+func NewZeroCheckLazyClaims(c Composition, tau []fr.Element, nbPolys int) *ZeroCheckLazyClaims {
+	return &ZeroCheckLazyClaims{c: c, tau: tau, nbPolys: nbPolys}
+}
+
+// Evaluations returns the values fᵢ(r). It is only set after a successful verification.
+func (c *ZeroCheckLazyClaims) Evaluations() []fr.Element {
+	return c.evaluations
+}
+
+func (c *ZeroCheckLazyClaims) ClaimsNum() int {
+	return 1
+}
+
+func (c *ZeroCheckLazyClaims) VarsNum() int {
+	return len(c.tau)
+}
+
+func (c *ZeroCheckLazyClaims) CombinedSum(fr.Element) fr.Element {
+	return fr.Element{}
+}
+
+func (c *ZeroCheckLazyClaims) Degree(int) int {
+	return c.c.Degree() + 1
+}
+
+func (c *ZeroCheckLazyClaims) VerifyFinalEval(r []fr.Element, _ fr.Element, purportedValue fr.Element, proof interface{}) error {
+	evaluations, err := c.receive(proof, c.nbPolys)
+	if err != nil {
+		return err
+	}
+
+	expected := c.c.Evaluate(evaluations...)
+	eq := polynomial.EvalEq(c.tau, r)
+	expected.Mul(&expected, &eq)
+	if !expected.Equal(&purportedValue) {
+		return fmt.Errorf("incompatible evaluations")
+	}
+	c.accept(r, evaluations)
+	return nil
+}
+
+// LogUpClaims prove that the values of the columns fₖ are looked up in the table t with multiplicities m, i.e.
+//
+//	∑ₖ ∑_{x ∈ {0,1}ⁿ} 1/(α - fₖ(x)) = ∑_{x ∈ {0,1}ⁿ} m(x)/(α - t(x))
+//
+// using the helper polynomials φₖ = 1/(α - fₖ) and ψ = m/(α - t), which must be committed to before τ is chosen.
+// The claims are
+//
+//	∑_x ψ(x) - ∑ₖ φₖ(x) = 0
+//	∑_x eq(τ, x)(φₖ(x)(α - fₖ(x)) - 1) = 0 for each k
+//	∑_x eq(τ, x)(ψ(x)(α - t(x)) - m(x)) = 0
+//
+// The final evaluation proof is the list f₁(r), ..., fₖ(r), t(r), m(r), φ₁(r), ..., φₖ(r), ψ(r).
+// See https://eprint.iacr.org/2022/1530
+type LogUpClaims struct {
+	composedClaims
+	alpha     fr.Element
+	nbColumns int
+}
+
+// LogUpHelpers returns the helper polynomials φₖ = 1/(α - fₖ) and ψ = m/(α - t).
+// It fails if α is one of the values of the table or the columns.
+func LogUpHelpers(alpha fr.Element, table, multiplicities polynomial.MultiLin, columns ...polynomial.MultiLin) (phi []polynomial.MultiLin, psi polynomial.MultiLin, err error) {
+	inverseShifted := func(f polynomial.MultiLin) (polynomial.MultiLin, error) {
+		res := make(polynomial.MultiLin, len(f))
+		for i := range f {
+			res[i].Sub(&alpha, &f[i])
+			if res[i].IsZero() {
+				return nil, fmt.Errorf("α is a root of α - f")
+			}
+			res[i].Inverse(&res[i])
+		}
+		return res, nil
+	}
+
+	phi = make([]polynomial.MultiLin, len(columns))
+	for k := range columns {
+		if phi[k], err = inverseShifted(columns[k]); err != nil {
+			return nil, nil, fmt.Errorf("column %d: %w", k, err)
+		}
+	}
+	if psi, err = inverseShifted(table); err != nil {
+		return nil, nil, fmt.Errorf("table: %w", err)
+	}
+	if len(multiplicities) != len(table) {
+		return nil, nil, fmt.Errorf("the table and the multiplicities must have the same size")
+	}
+	for i := range psi {
+		psi[i].Mul(&psi[i], &multiplicities[i])
+	}
+	return
+}
+
+// LogUpMultiplicities returns the number of occurrences of each entry of the table in the columns.
+// It fails if a value of the columns is not in the table. If the table has repeated entries, all occurrences are counted on the first one.
+func LogUpMultiplicities(table polynomial.MultiLin, columns ...polynomial.MultiLin) (polynomial.MultiLin, error) {
+	index := make(map[[fr.Bytes]byte]int, len(table))
+	for i := len(table) - 1; i >= 0; i-- {
+		index[table[i].Bytes()] = i
+	}
+
+	counts := make([]uint64, len(table))
+	for k := range columns {
+		for i := range columns[k] {
+			j, ok := index[columns[k][i].Bytes()]
+			if !ok {
+				return nil, fmt.Errorf("column %d, row %d: value not in the table", k, i)
+			}
+			counts[j]++
+		}
+	}
+
+	res := make(polynomial.MultiLin, len(table))
+	for i := range res {
+		res[i].SetUint64(counts[i])
+	}
+	return res, nil
+}
+
+// NewLogUpClaims returns the LogUp claims for the given table, multiplicities, columns and helpers, as returned by LogUpHelpers.
+// The polynomials are not modified.
+func NewLogUpClaims(alpha fr.Element, tau []fr.Element, table, multiplicities polynomial.MultiLin, columns, phi []polynomial.MultiLin, psi polynomial.MultiLin, options ...ClaimsOption) (*LogUpClaims, error) {
+	if len(phi) != len(columns) {
+		return nil, fmt.Errorf("one helper polynomial expected per column")
+	}
+	if len(table) != 1<<len(tau) {
+		return nil, fmt.Errorf("polynomials of size 2^%d expected", len(tau))
+	}
+	eq := make(polynomial.MultiLin, 1<<len(tau))
+	eq[0].SetOne()
+	eq.Eq(tau)
+
+	tables := make([]polynomial.MultiLin, 0, 2*len(columns)+4)
+	tables = append(tables, columns...)
+	tables = append(tables, table, multiplicities)
+	tables = append(tables, phi...)
+	tables = append(tables, psi, eq)
+
+	composed, err := newComposedClaims(tables, options)
+	if err != nil {
+		return nil, err
+	}
+	res := LogUpClaims{composedClaims: composed, nbColumns: len(columns)}
+	res.degree = 3
+	res.alpha = alpha
+	return &res, nil
+}
+
+// logUpSummand is the combined LogUp summand. values are f₁, ..., fₖ, t, m, φ₁, ..., φₖ, ψ, eq.
+func logUpSummand(alpha fr.Element, aI []fr.Element, nbColumns int, values []fr.Element) fr.Element {
+	f, t, m := values[:nbColumns], &values[nbColumns], &values[nbColumns+1]
+	phi, psi, eq := values[nbColumns+2:2*nbColumns+2], &values[2*nbColumns+2], &values[2*nbColumns+3]
+
+	var res, wellFormed, x, one fr.Element
+	one.SetOne()
+
+	// ψ - ∑ₖ φₖ
+	res.Set(psi)
+	for k := range phi {
+		res.Sub(&res, &phi[k])
+	}
+
+	// ∑ₖ aᵏ(φₖ(α - fₖ) - 1) + aᴷ⁺¹(ψ(α - t) - m)
+	for k := range phi {
+		x.Sub(&alpha, &f[k]).
+			Mul(&x, &phi[k]).
+			Sub(&x, &one)
+		x.Mul(&x, &aI[k+1])
+		wellFormed.Add(&wellFormed, &x)
+	}
+	x.Sub(&alpha, t).
+		Mul(&x, psi).
+		Sub(&x, m)
+	x.Mul(&x, &aI[nbColumns+1])
+	wellFormed.Add(&wellFormed, &x)
+
+	wellFormed.Mul(&wellFormed, eq)
+	res.Add(&res, &wellFormed)
+	return res
+}
+
+func (c *LogUpClaims) ClaimsNum() int {
+	return c.nbColumns + 2
+}
+
+func (c *LogUpClaims) Combine(a fr.Element) polynomial.Polynomial {
+	aI := powers(a, c.nbColumns+2)
+	c.summand = func(values []fr.Element) fr.Element {
+		return logUpSummand(c.alpha, aI, c.nbColumns, values)
+	}
+	return c.roundPolynomial()
+}
+
+func (c *LogUpClaims) ProveFinalEval(r []fr.Element) interface{} {
+	return c.finalEvaluations(r[len(r)-1], len(c.tables)-1)
+}
+
+// LogUpLazyClaims is the verifier side of LogUpClaims
+type LogUpLazyClaims struct {
+	lazyEvaluations
+	alpha     fr.Element
+	tau       []fr.Element
+	nbColumns int
+}
+
+// NewLogUpLazyClaims returns the LogUp claims on nbColumns columns, at τ
+func NewLogUpLazyClaims(alpha fr.Element, tau []fr.Element, nbColumns int) *LogUpLazyClaims {
+	return &LogUpLazyClaims{alpha: alpha, tau: tau, nbColumns: nbColumns}
+}
+
+// Evaluations returns the values fₖ(r), t(r), m(r), φₖ(r), ψ(r). It is only set after a successful verification.
+func (c *LogUpLazyClaims) Evaluations() (columns []fr.Element, table, multiplicities fr.Element, phi []fr.Element, psi fr.Element) {
+	if c.evaluations == nil {
+		return
+	}
+	e := c.evaluations
+	return e[:c.nbColumns], e[c.nbColumns], e[c.nbColumns+1], e[c.nbColumns+2 : 2*c.nbColumns+2], e[2*c.nbColumns+2]
+}
+
+func (c *LogUpLazyClaims) ClaimsNum() int {
+	return c.nbColumns + 2
+}
+
+func (c *LogUpLazyClaims) VarsNum() int {
+	return len(c.tau)
+}
+
+func (c *LogUpLazyClaims) CombinedSum(fr.Element) fr.Element {
+	return fr.Element{}
+}
+
+func (c *LogUpLazyClaims) Degree(int) int {
+	return 3
+}
+
+func (c *LogUpLazyClaims) VerifyFinalEval(r []fr.Element, combinationCoeff fr.Element, purportedValue fr.Element, proof interface{}) error {
+	evaluations, err := c.receive(proof, 2*c.nbColumns+3)
+	if err != nil {
+		return err
+	}
+
+	values := append(evaluations[:len(evaluations):len(evaluations)], polynomial.EvalEq(c.tau, r))
+	expected := logUpSummand(c.alpha, powers(combinationCoeff, c.nbColumns+2), c.nbColumns, values)
+	if !expected.Equal(&purportedValue) {
+		return fmt.Errorf("incompatible evaluations")
+	}
+	c.accept(r, evaluations)
+	return nil
+}
@@ -411,6 +411,89 @@ func TestG1AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS24-315] JointScalarMultiplicationBase should handle any point and negative scalars", prop.ForAll(
+		func(s1, s2, s3 fr.Element) bool {
+
+			var a G1Affine
+			var op1, op2, temp G1Jac
+			var k1, k2, k3 big.Int
+			s1.BigInt(&k1)
+			s2.BigInt(&k2)
+			s3.BigInt(&k3)
+			// -k1 and k2-r
+			k1.Neg(&k1)
+			k2.Sub(&k2, fr.Modulus())
+			temp.ScalarMultiplication(&g1Gen, &k3)
+			a.FromJacobian(&temp)
+
+			op1.JointScalarMultiplicationBase(&a, &k1, &k2)
+			temp.mulWindowed(&temp, s2.BigInt(new(big.Int)))
+			op2.mulWindowed(&g1Gen, s1.BigInt(new(big.Int))).
+				Neg(&op2).
+				AddAssign(&temp)
+
+			return op1.Equal(&op2)
+
+		},
+		genScalar,
+		genScalar,
+		genScalar,
+	))
+
+	properties.Property("[BLS24-315] JointScalarMultiplicationBase should handle null scalars and the point at infinity", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var infinity G1Affine
+			var zero, k big.Int
+			var op1, op2, op3 G1Jac
+			s.BigInt(&k)
+
+			op1.JointScalarMultiplicationBase(&infinity, &k, &k)
+			op2.JointScalarMultiplicationBase(&g1GenAff, &zero, &k)
+			op3.JointScalarMultiplicationBase(&g1GenAff, &zero, &zero)
+			expected := new(G1Jac).ScalarMultiplication(&g1Gen, &k)
+
+			return op1.Equal(expected) && op2.Equal(expected) && op3.Z.IsZero()
+
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS24-315] JointScalarMultiplicationBase should handle points outside of the subgroup and unreduced scalars", prop.ForAll(
+		func(s1, s2 fr.Element) bool {
+
+			// random point of the curve, outside of the subgroup
+			var a G1Affine
+			for a.IsInfinity() || a.IsInSubGroup() {
+				var x fp.Element
+				a.X.SetRandom()
+				x.Square(&a.X).Mul(&x, &a.X).Add(&x, &bCurveCoeff)
+				if a.Y.Sqrt(&x) == nil {
+					a.X.SetZero()
+					a.Y.SetZero()
+				}
+			}
+
+			var op1, op2, temp G1Jac
+			var k1, k2 big.Int
+			s1.BigInt(&k1)
+			s2.BigInt(&k2)
+			// k2+r
+			k2.Add(&k2, fr.Modulus())
+
+			op1.JointScalarMultiplicationBase(&a, &k1, &k2)
+			temp.FromAffine(&a)
+			temp.mulWindowed(&temp, &k2)
+			op2.mulWindowed(&g1Gen, &k1).
+				AddAssign(&temp)
+
+			return a.IsOnCurve() && op1.Equal(&op2)
+
+		},
+		genScalar,
+		genScalar,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -596,6 +679,36 @@ func BenchmarkG1JacScalarMultiplication(b *testing.B) {
 
 }
 
+func BenchmarkG1JacJointScalarMultiplicationBase(b *testing.B) {
+
+	var s1, s2 fr.Element
+	s1.SetRandom()
+	s2.SetRandom()
+	k1 := s1.BigInt(new(big.Int))
+	k2 := s2.BigInt(new(big.Int))
+
+	var a G1Affine
+	a.ScalarMultiplication(&g1GenAff, k1)
+
+	var res, temp G1Jac
+	b.Run("joint", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			res.JointScalarMultiplicationBase(&a, k1, k2)
+		}
+	})
+
+	b.Run("separate", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			res.ScalarMultiplication(&g1Gen, k1)
+			temp.FromAffine(&a)
+			temp.ScalarMultiplication(&temp, k2)
+			res.AddAssign(&temp)
+		}
+	})
+}
+
 func BenchmarkG1AffineCofactorClearing(b *testing.B) {
 	var a G1Jac
 	a.Set(&g1Gen)
@@ -30,6 +30,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
 )
 
 const (
@@ -150,6 +151,167 @@ func TestEncoder(t *testing.T) {
 
 }
 
+func TestEncoderGTEdwardsNested(t *testing.T) {
+	t.Parallel()
+
+	// GT elements, including the identity
+	var inA, inB GT
+	var err error
+	inA, err = Pair([]G1Affine{g1GenAff}, []G2Affine{g2GenAff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inB.SetOne()
+	inC := []GT{inA, inB}
+
+	// points of the twistededwards curve
+	params0 := twistededwards.GetEdwardsCurve()
+	var inD0 twistededwards.PointAffine
+	inD0.ScalarMultiplication(&params0.Base, big.NewInt(42))
+	inE0 := []twistededwards.PointAffine{params0.Base, inD0}
+
+	// nested slices
+	// (empty slices are decoded as nil)
+	inF := [][]fr.Element{make([]fr.Element, 3), nil, make([]fr.Element, 1)}
+	inF[0][2].SetUint64(42)
+	inF[2][0].SetRandom()
+	inG := [][]G1Affine{{g1GenAff}, nil, {g1GenAff, g1GenAff}}
+	inH := [][]GT{inC, {inA}}
+
+	toEncode := []interface{}{&inA, &inB, inC, &inD0, inE0, inF, inG, inH}
+
+	testEncodeDecode := func(t *testing.T, options ...func(*Encoder)) {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf, options...)
+		for _, v := range toEncode {
+			if err := enc.Encode(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		n := enc.BytesWritten()
+
+		dec := NewDecoder(&buf)
+		var outA, outB GT
+		var outC []GT
+		var outD0 twistededwards.PointAffine
+		var outE0 []twistededwards.PointAffine
+		var outF [][]fr.Element
+		var outG [][]G1Affine
+		var outH [][]GT
+		toDecode := []interface{}{&outA, &outB, &outC, &outD0, &outE0, &outF, &outG, &outH}
+		for _, v := range toDecode {
+			if err := dec.Decode(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if !inA.Equal(&outA) || !inB.Equal(&outB) || !reflect.DeepEqual(inC, outC) {
+			t.Fatal("decode(encode(GT)) failed")
+		}
+		if !inD0.Equal(&outD0) || !reflect.DeepEqual(inE0, outE0) {
+			t.Fatal("decode(encode(twisted Edwards point)) failed")
+		}
+		if !reflect.DeepEqual(inF, outF) || !reflect.DeepEqual(inG, outG) || !reflect.DeepEqual(inH, outH) {
+			t.Fatal("decode(encode(nested slices)) failed")
+		}
+		if n != dec.BytesRead() {
+			t.Fatal("bytes read don't match bytes written")
+		}
+	}
+
+	t.Run("compressed", func(t *testing.T) {
+		testEncodeDecode(t)
+	})
+	t.Run("raw", func(t *testing.T) {
+		testEncodeDecode(t, RawEncoding())
+	})
+
+	// the GT elements are compressed on the torus
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(&inA); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != SizeOfGTCompressed {
+		t.Fatal("GT element should be compressed")
+	}
+	buf.Reset()
+	if err := NewEncoder(&buf, RawEncoding()).Encode(&inA); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != SizeOfGT {
+		t.Fatal("GT element should not be compressed with RawEncoding")
+	}
+}
+
+func TestDecoderInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	// slice lengths
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	inA := make([]fr.Element, 10)
+	inB := []G1Affine{g1GenAff, g1GenAff}
+	for _, v := range []interface{}{inA, inB} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	encoded := buf.Bytes()
+
+	var outA []fr.Element
+	var outB []G1Affine
+	dec := NewDecoder(bytes.NewReader(encoded), MaxSliceLength(10))
+	if err := dec.Decode(&outA); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.Decode(&outB); err != nil {
+		t.Fatal(err)
+	}
+
+	dec = NewDecoder(bytes.NewReader(encoded), MaxSliceLength(9))
+	if err := dec.Decode(&outA); err != ErrMaxLengthExceeded {
+		t.Fatal("expected ErrMaxLengthExceeded, got", err)
+	}
+
+	// a huge length is rejected before allocating the slice
+	huge := []byte{0xff, 0xff, 0xff, 0xff}
+	for _, v := range []interface{}{&outA, &outB, new(fr.Vector), new([]GT), new([][]fr.Element)} {
+		dec = NewDecoder(bytes.NewReader(huge), MaxSliceLength(1<<20))
+		if err := dec.Decode(v); err != ErrMaxLengthExceeded {
+			t.Fatal("expected ErrMaxLengthExceeded, got", err)
+		}
+	}
+
+	// GT elements outside of the subgroup
+	var z GT
+	z.SetRandom()
+	buf.Reset()
+	if err := NewEncoder(&buf, RawEncoding()).Encode(&z); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&z); err == nil {
+		t.Fatal("decoding a GT element outside of the subgroup should fail")
+	}
+	if err := NewDecoder(bytes.NewReader(buf.Bytes()), NoSubgroupChecks()).Decode(&z); err != nil {
+		t.Fatal(err)
+	}
+
+	// twistededwards points outside of the subgroup
+	{
+		var p, t2 twistededwards.PointAffine
+		params := twistededwards.GetEdwardsCurve()
+		t2.Y.SetOne().Neg(&t2.Y)
+		p.Add(&params.Base, &t2)
+		buf.Reset()
+		if err := NewEncoder(&buf).Encode(&p); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&p); err == nil {
+			t.Fatal("decoding a point outside of the subgroup should fail")
+		}
+	}
+}
+
 func TestIsCompressed(t *testing.T) {
 	t.Parallel()
 	var g1Inf, g1 G1Affine
@@ -0,0 +1,195 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+// The twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2 is birationally equivalent to
+// the Montgomery curve K*t^2 = s^3 + J*s^2 + s, with J = 2(a+d)/(a-d) and K = 4/(a-d).
+// The hash to curve functions map field elements to the Montgomery curve with
+// Elligator 2, then to the twisted Edwards curve with the rational map
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-D.1, and clear the cofactor.
+var (
+	elligatorOnce   sync.Once
+	elligatorParams struct {
+		jOverK     fr.Element // J/K
+		invKSquare fr.Element // 1/K^2
+		k          fr.Element // K
+		z          fr.Element // non-square
+	}
+)
+
+func initElligatorParams() {
+	initOnce.Do(initCurveParams)
+
+	var aMinusD, j fr.Element
+	aMinusD.Sub(&curveParams.A, &curveParams.D)
+
+	// K = 4/(a-d)
+	elligatorParams.k.SetUint64(4).Div(&elligatorParams.k, &aMinusD)
+
+	// J = 2(a+d)/(a-d)
+	j.Add(&curveParams.A, &curveParams.D).Double(&j).Div(&j, &aMinusD)
+
+	elligatorParams.jOverK.Div(&j, &elligatorParams.k)
+	elligatorParams.invKSquare.Square(&elligatorParams.k).Inverse(&elligatorParams.invKSquare)
+
+	// Z is the first non-square in 1, -1, 2, -2, ...
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-H.3
+	for ctr := uint64(1); ; ctr++ {
+		elligatorParams.z.SetUint64(ctr)
+		if elligatorParams.z.Legendre() == -1 {
+			return
+		}
+		elligatorParams.z.Neg(&elligatorParams.z)
+		if elligatorParams.z.Legendre() == -1 {
+			return
+		}
+	}
+}
+
+// sgn0 returns the sign of z, as defined in https://www.rfc-editor.org/rfc/rfc9380.html#name-the-sgn0-function.
+// The sign of an element is not obviously related to that of its Montgomery form
+func sgn0(z *fr.Element) uint64 {
+	nonMont := z.Bits()
+	return nonMont[0] % 2
+}
+
+// mapToMontgomery maps u to a point (s, t) of the Montgomery curve K*t^2 = s^3 + J*s^2 + s
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-elligator-2-method
+func mapToMontgomery(u *fr.Element) (s, t fr.Element) {
+	params := &elligatorParams
+
+	var x1, x2, gx1, gx2, tv fr.Element
+
+	// x1 = -(J/K) / (1 + Z*u^2), or -(J/K) if the denominator is 0
+	tv.Square(u).Mul(&tv, &params.z)
+	tv.Add(&tv, new(fr.Element).SetOne())
+	tv.Inverse(&tv) // 1/0 == 0
+	x1.Mul(&tv, &params.jOverK).Neg(&x1)
+	if x1.IsZero() {
+		x1.Neg(&params.jOverK)
+	}
+
+	// x2 = -x1 - J/K
+	x2.Add(&x1, &params.jOverK).Neg(&x2)
+
+	// g(x) = x^3 + (J/K)*x^2 + x/K^2
+	g := func(res, x *fr.Element) {
+		res.Add(x, &params.jOverK).Mul(res, x)
+		res.Add(res, &params.invKSquare).Mul(res, x)
+	}
+	g(&gx1, &x1)
+	g(&gx2, &x2)
+
+	var x, y fr.Element
+	if gx1.Legendre() != -1 {
+		x.Set(&x1)
+		y.Sqrt(&gx1)
+		if sgn0(&y) != 1 {
+			y.Neg(&y)
+		}
+	} else {
+		// g(x2) = Z*u^2*g(x1) is a square
+		x.Set(&x2)
+		y.Sqrt(&gx2)
+		if sgn0(&y) != 0 {
+			y.Neg(&y)
+		}
+	}
+
+	s.Mul(&x, &params.k)
+	t.Mul(&y, &params.k)
+	return
+}
+
+// montgomeryToEdwards maps the point (s, t) of the Montgomery curve to the twisted Edwards curve,
+// with (x, y) = (s/t, (s-1)/(s+1)). The exceptional points t = 0 or s = -1 are mapped to the identity.
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-D.1
+func montgomeryToEdwards(s, t *fr.Element) PointProj {
+	var res PointProj
+	var one, sMinusOne, sPlusOne fr.Element
+	one.SetOne()
+	sMinusOne.Sub(s, &one)
+	sPlusOne.Add(s, &one)
+	if t.IsZero() || sPlusOne.IsZero() {
+		res.setInfinity()
+		return res
+	}
+
+	// (x:y:z) = (s(s+1) : t(s-1) : t(s+1))
+	res.X.Mul(s, &sPlusOne)
+	res.Y.Mul(t, &sMinusOne)
+	res.Z.Mul(t, &sPlusOne)
+	return res
+}
+
+// mapToCurve maps u to the twisted Edwards curve, without clearing the cofactor
+func mapToCurve(u *fr.Element) PointProj {
+	s, t := mapToMontgomery(u)
+	return montgomeryToEdwards(&s, &t)
+}
+
+// clearCofactor sets p to [cofactor]p and returns it in affine coordinates
+func clearCofactor(p *PointProj) PointAffine {
+	var cofactor big.Int
+	curveParams.Cofactor.BigInt(&cofactor)
+	p.ScalarMultiplication(p, &cofactor)
+	var res PointAffine
+	res.FromProj(p)
+	return res
+}
+
+// MapToCurve invokes the Elligator 2 map, and guarantees that the result is in the prime order subgroup
+func MapToCurve(u fr.Element) PointAffine {
+	elligatorOnce.Do(initElligatorParams)
+	p := mapToCurve(&u)
+	return clearCofactor(&p)
+}
+
+// EncodeToCurve hashes a message to a point on the twisted Edwards curve using the Elligator 2 map.
+// It is faster than HashToCurve, but the result is not uniformly distributed. Unsuitable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-encoding-byte-strings-to-el
+func EncodeToCurve(msg, dst []byte) (PointAffine, error) {
+	u, err := fr.Hash(msg, dst, 1)
+	if err != nil {
+		return PointAffine{}, err
+	}
+	return MapToCurve(u[0]), nil
+}
+
+// HashToCurve hashes a message to a point on the twisted Edwards curve using the Elligator 2 map.
+// Slower than EncodeToCurve, but usable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-encoding-byte-strings-to-el
+func HashToCurve(msg, dst []byte) (PointAffine, error) {
+	u, err := fr.Hash(msg, dst, 2)
+	if err != nil {
+		return PointAffine{}, err
+	}
+	elligatorOnce.Do(initElligatorParams)
+	q0 := mapToCurve(&u[0])
+	q1 := mapToCurve(&u[1])
+	q0.Add(&q0, &q1)
+	return clearCofactor(&q0), nil
+}
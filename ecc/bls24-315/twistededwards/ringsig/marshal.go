@@ -0,0 +1,164 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ringsig
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+)
+
+// sizeRingSize is the size of the encoded number of responses of a signature
+const sizeRingSize = 4
+
+// Bytes returns the compressed representation of the key image, see PointAffine.Bytes
+func (ki *KeyImage) Bytes() []byte {
+	res := ki.I.Bytes()
+	return res[:]
+}
+
+// SetBytes sets ki from its compressed representation in buf. It returns an error if the
+// key image is not in the prime order subgroup or is the identity.
+// It returns the number of bytes read from the buffer.
+func (ki *KeyImage) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizeKeyImage {
+		return 0, io.ErrShortBuffer
+	}
+	if _, err := ki.I.SetBytes(buf[:sizeKeyImage]); err != nil {
+		return 0, err
+	}
+	if !checkKeyImage(&ki.I) {
+		return sizeKeyImage, errInvalidKeyImage
+	}
+	return sizeKeyImage, nil
+}
+
+// Bytes returns the binary representation of the signature, as I||c₀||n||s₀||...||sₙ₋₁
+// where the key image I is compressed, the scalars are in big endian on sizeFr bytes and
+// the number of responses n is in big endian on 4 bytes.
+func (sig *LSAGSignature) Bytes() []byte {
+	res := make([]byte, 0, sizeKeyImage+sizeFr+sizeRingSize+len(sig.S)*sizeFr)
+	res = append(res, sig.KeyImage.Bytes()...)
+	return appendResponses(res, &sig.C, sig.S)
+}
+
+// SetBytes sets sig from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (sig *LSAGSignature) SetBytes(buf []byte) (int, error) {
+	n, err := sig.KeyImage.SetBytes(buf)
+	if err != nil {
+		return 0, err
+	}
+	m, err := decodeResponses(buf[n:], &sig.C, &sig.S)
+	if err != nil {
+		return 0, err
+	}
+	return n + m, nil
+}
+
+// Bytes returns the binary representation of the signature, as I||D||c₀||n||s₀||...||sₙ₋₁
+// where the points are compressed, see LSAGSignature.Bytes.
+func (sig *CLSAGSignature) Bytes() []byte {
+	res := make([]byte, 0, sizeKeyImage+sizePoint+sizeFr+sizeRingSize+len(sig.S)*sizeFr)
+	res = append(res, sig.KeyImage.Bytes()...)
+	dBin := sig.D.Bytes()
+	res = append(res, dBin[:]...)
+	return appendResponses(res, &sig.C, sig.S)
+}
+
+// SetBytes sets sig from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (sig *CLSAGSignature) SetBytes(buf []byte) (int, error) {
+	n, err := sig.KeyImage.SetBytes(buf)
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) < n+sizePoint {
+		return 0, io.ErrShortBuffer
+	}
+	var D twistededwards.PointAffine
+	if _, err = D.SetBytes(buf[n : n+sizePoint]); err != nil {
+		return 0, err
+	}
+	if !D.IsOnCurve() {
+		return 0, errNotOnCurve
+	}
+	n += sizePoint
+	m, err := decodeResponses(buf[n:], &sig.C, &sig.S)
+	if err != nil {
+		return 0, err
+	}
+	sig.D = D
+	return n + m, nil
+}
+
+// appendResponses appends c₀||n||s₀||...||sₙ₋₁ to buf
+func appendResponses(buf []byte, c *big.Int, s []big.Int) []byte {
+	var scalar [sizeFr]byte
+	buf = append(buf, c.FillBytes(scalar[:])...)
+	var ringSize [sizeRingSize]byte
+	binary.BigEndian.PutUint32(ringSize[:], uint32(len(s)))
+	buf = append(buf, ringSize[:]...)
+	for i := range s {
+		buf = append(buf, s[i].FillBytes(scalar[:])...)
+	}
+	return buf
+}
+
+// decodeResponses sets c and s from c₀||n||s₀||...||sₙ₋₁ in buf, and returns the number of
+// bytes read. The scalars must be reduced modulo the order.
+func decodeResponses(buf []byte, c *big.Int, s *[]big.Int) (int, error) {
+	if len(buf) < sizeFr+sizeRingSize {
+		return 0, io.ErrShortBuffer
+	}
+	var c0 big.Int
+	if err := decodeScalar(&c0, buf[:sizeFr]); err != nil {
+		return 0, err
+	}
+	n := sizeFr
+	ringSize := binary.BigEndian.Uint32(buf[n:])
+	n += sizeRingSize
+	if ringSize == 0 {
+		return 0, errEmptyRing
+	}
+	if uint64(ringSize) > math.MaxInt32/sizeFr || len(buf) < n+int(ringSize)*sizeFr {
+		return 0, io.ErrShortBuffer
+	}
+	responses := make([]big.Int, ringSize)
+	for i := range responses {
+		if err := decodeScalar(&responses[i], buf[n:n+sizeFr]); err != nil {
+			return 0, err
+		}
+		n += sizeFr
+	}
+	c.Set(&c0)
+	*s = responses
+	return n, nil
+}
+
+// decodeScalar sets s from its big endian representation in buf, which must be reduced
+// modulo the order
+func decodeScalar(s *big.Int, buf []byte) error {
+	s.SetBytes(buf)
+	if s.Cmp(&curveParams.Order) >= 0 {
+		return errInvalidScalar
+	}
+	return nil
+}
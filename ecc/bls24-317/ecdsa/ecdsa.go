@@ -40,7 +40,10 @@ const (
 	sizeSignature  = 2 * sizeFr
 )
 
-var order = fr.Modulus()
+var (
+	order     = fr.Modulus()
+	halfOrder = new(big.Int).Rsh(order, 1)
+)
 
 // PublicKey represents an ECDSA public key
 type PublicKey struct {
@@ -100,7 +103,7 @@ func HashToInt(hash []byte) *big.Int {
 		hash = hash[:sizeFr]
 	}
 	ret := new(big.Int).SetBytes(hash)
-	excess := len(hash)*8 - sizeFr
+	excess := len(hash)*8 - fr.Bits
 	if excess > 0 {
 		ret.Rsh(ret, uint(excess))
 	}
@@ -192,17 +195,50 @@ func (privKey *PrivateKey) Public() signature.PublicKey {
 //
 // SEC 1, Version 2.0, Section 4.1.3
 func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
-	scalar, r, s, kInv := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	return privKey.SignWithOptions(message, hFunc)
+}
+
+// SignWithOptions performs the ECDSA signature as Sign, with the nonce derivation
+// and the normalization of s set by opts.
+func (privKey *PrivateKey) SignWithOptions(message []byte, hFunc hash.Hash, opts ...SignOption) ([]byte, error) {
+	_, r, s, err := privKey.sign(message, hFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	r.FillBytes(sig.R[:sizeFr])
+	s.FillBytes(sig.S[:sizeFr])
+
+	return sig.Bytes(), nil
+}
+
+// sign performs the ECDSA signature and returns the signature {r, s}
+func (privKey *PrivateKey) sign(message []byte, hFunc hash.Hash, opts ...SignOption) (v uint, r, s *big.Int, err error) {
+	cfg, err := signOptions(opts...)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	r, s = new(big.Int), new(big.Int)
+
+	// compute the hash of the message
+	hramBin, err := hashMessage(message, hFunc)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	m := HashToInt(hramBin)
+
+	nonces, err := privKey.newNonceGenerator(message, hramBin, cfg)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	scalar, kInv := new(big.Int), new(big.Int)
 	scalar.SetBytes(privKey.scalar[:sizeFr])
 	for {
 		for {
-			csprng, err := nonce(privKey, message)
+			k, err := nonces.next()
 			if err != nil {
-				return nil, err
-			}
-			k, err := randFieldElement(csprng)
-			if err != nil {
-				return nil, err
+				return 0, nil, nil, err
 			}
 
 			var P bls24317.G1Affine
@@ -218,22 +254,6 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 		}
 		s.Mul(r, scalar)
 
-		var m *big.Int
-		if hFunc != nil {
-			// compute the hash of the message as an integer
-			dataToHash := make([]byte, len(message))
-			copy(dataToHash[:], message[:])
-			hFunc.Reset()
-			_, err := hFunc.Write(dataToHash[:])
-			if err != nil {
-				return nil, err
-			}
-			hramBin := hFunc.Sum(nil)
-			m = HashToInt(hramBin)
-		} else {
-			m = HashToInt(message)
-		}
-
 		s.Add(m, s).
 			Mul(kInv, s).
 			Mod(s, order) // order != 0
@@ -242,11 +262,26 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 		}
 	}
 
-	var sig Signature
-	r.FillBytes(sig.R[:sizeFr])
-	s.FillBytes(sig.S[:sizeFr])
+	// s and -s are both valid, the latter corresponding to the nonce -k
+	if cfg.lowS && s.Cmp(halfOrder) > 0 {
+		s.Sub(order, s)
+	}
 
-	return sig.Bytes(), nil
+	return v, r, s, nil
+}
+
+// hashMessage returns hFunc(message), or message if hFunc is nil.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	dataToHash := make([]byte, len(message))
+	copy(dataToHash[:], message[:])
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
 }
 
 // Verify validates the ECDSA signature
@@ -266,23 +301,19 @@ func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (boo
 	r.SetBytes(sig.R[:sizeFr])
 	s.SetBytes(sig.S[:sizeFr])
 
+	// r, s ∈ [1, order-1]
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(order) >= 0 || s.Cmp(order) >= 0 {
+		return false, nil
+	}
+
 	sInv := new(big.Int).ModInverse(s, order)
 
-	var m *big.Int
-	if hFunc != nil {
-		// compute the hash of the message as an integer
-		dataToHash := make([]byte, len(message))
-		copy(dataToHash[:], message[:])
-		hFunc.Reset()
-		_, err := hFunc.Write(dataToHash[:])
-		if err != nil {
-			return false, err
-		}
-		hramBin := hFunc.Sum(nil)
-		m = HashToInt(hramBin)
-	} else {
-		m = HashToInt(message)
+	// compute the hash of the message as an integer
+	hramBin, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
 	}
+	m := HashToInt(hramBin)
 
 	u1 := new(big.Int).Mul(m, sInv)
 	u1.Mod(u1, order)
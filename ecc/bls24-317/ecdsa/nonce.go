@@ -0,0 +1,215 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/hmac"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+)
+
+var (
+	errNilHashFunction = errors.New("the hash function of the nonce derivation must not be nil")
+	errNilEntropy      = errors.New("the entropy source of the hedged nonce derivation must not be nil")
+)
+
+// SignOption customizes the signature, see PrivateKey.SignWithOptions.
+type SignOption func(*signConfig) error
+
+type signConfig struct {
+	// nonceHash is the hash function of the HMAC used to derive the nonces as in RFC 6979.
+	// If nil, the nonces are drawn from a CSPRNG seeded by crypto/rand.
+	nonceHash func() hash.Hash
+
+	// entropy is the source of the additional data k' of RFC 6979 Section 3.6, if any
+	entropy io.Reader
+
+	// lowS sets s to min(s, order-s)
+	lowS bool
+}
+
+// WithDeterministicNonce derives the nonce deterministically from the private key
+// and the hash of the message, as specified in RFC 6979, using HMAC with the hash
+// function h (typically the one used to hash the message).
+//
+// When hFunc is nil in Sign, the message is interpreted as the hash H(m) of RFC 6979.
+func WithDeterministicNonce(h func() hash.Hash) SignOption {
+	return func(cfg *signConfig) error {
+		if h == nil {
+			return errNilHashFunction
+		}
+		cfg.nonceHash = h
+		cfg.entropy = nil
+		return nil
+	}
+}
+
+// WithHedgedNonce derives the nonce as in WithDeterministicNonce, but adds
+// sizeFr bytes read from entropy as additional data (RFC 6979 Section 3.6).
+// The signatures are not deterministic anymore, but the nonce remains secure
+// if the entropy source is weak, and fault attacks on deterministic signatures
+// are mitigated.
+func WithHedgedNonce(h func() hash.Hash, entropy io.Reader) SignOption {
+	return func(cfg *signConfig) error {
+		if h == nil {
+			return errNilHashFunction
+		}
+		if entropy == nil {
+			return errNilEntropy
+		}
+		cfg.nonceHash = h
+		cfg.entropy = entropy
+		return nil
+	}
+}
+
+// WithLowS normalizes the signature such that s ≤ order/2, see Signature.IsLowS.
+func WithLowS() SignOption {
+	return func(cfg *signConfig) error {
+		cfg.lowS = true
+		return nil
+	}
+}
+
+func signOptions(opts ...SignOption) (signConfig, error) {
+	var cfg signConfig
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// nonceGenerator returns the successive candidates for the nonce k ∈ [1, order-1].
+type nonceGenerator interface {
+	next() (*big.Int, error)
+}
+
+// newNonceGenerator returns the nonce generator set by cfg. digest is the hash
+// of the message, H(m) in RFC 6979.
+func (privKey *PrivateKey) newNonceGenerator(message, digest []byte, cfg signConfig) (nonceGenerator, error) {
+	if cfg.nonceHash == nil {
+		return &randomNonces{privKey: privKey, message: message}, nil
+	}
+	var extra []byte
+	if cfg.entropy != nil {
+		extra = make([]byte, sizeFr)
+		if _, err := io.ReadFull(cfg.entropy, extra); err != nil {
+			return nil, err
+		}
+	}
+	return newRFC6979Nonces(cfg.nonceHash, privKey.scalar[:], digest, extra), nil
+}
+
+// randomNonces draws the nonces from the CSPRNG returned by nonce.
+type randomNonces struct {
+	privKey *PrivateKey
+	message []byte
+}
+
+func (n *randomNonces) next() (*big.Int, error) {
+	csprng, err := nonce(n.privKey, n.message)
+	if err != nil {
+		return nil, err
+	}
+	return randFieldElement(csprng)
+}
+
+// rfc6979Nonces is the HMAC_DRBG of RFC 6979 Section 3.2.
+type rfc6979Nonces struct {
+	h    func() hash.Hash
+	k, v []byte
+	// first is true if no candidate has been returned yet
+	first bool
+}
+
+// rlen is the length in bytes of the order
+const rlen = (fr.Bits + 7) / 8
+
+func newRFC6979Nonces(h func() hash.Hash, scalar, digest, extra []byte) *rfc6979Nonces {
+	hlen := h().Size()
+
+	// b. V = 0x01 0x01 0x01 ... 0x01
+	v := make([]byte, hlen)
+	for i := range v {
+		v[i] = 0x01
+	}
+	// c. K = 0x00 0x00 0x00 ... 0x00
+	k := make([]byte, hlen)
+
+	// int2octets(x) || bits2octets(h1) || k'
+	x := new(big.Int).SetBytes(scalar)
+	h1 := HashToInt(digest)
+	if h1.Cmp(order) >= 0 {
+		h1.Sub(h1, order)
+	}
+	seed := make([]byte, 2*rlen, 2*rlen+len(extra))
+	x.FillBytes(seed[:rlen])
+	h1.FillBytes(seed[rlen:])
+	seed = append(seed, extra...)
+
+	n := &rfc6979Nonces{h: h, k: k, v: v, first: true}
+	// d. K = HMAC_K(V || 0x00 || seed), e. V = HMAC_K(V)
+	n.update(0x00, seed)
+	// f. K = HMAC_K(V || 0x01 || seed), g. V = HMAC_K(V)
+	n.update(0x01, seed)
+
+	return n
+}
+
+// hmac returns HMAC_K(data[0] || data[1] || ..)
+func (n *rfc6979Nonces) hmac(data ...[]byte) []byte {
+	mac := hmac.New(n.h, n.k)
+	for i := range data {
+		mac.Write(data[i])
+	}
+	return mac.Sum(nil)
+}
+
+// update sets K = HMAC_K(V || b || data) and V = HMAC_K(V)
+func (n *rfc6979Nonces) update(b byte, data []byte) {
+	n.k = n.hmac(n.v, []byte{b}, data)
+	n.v = n.hmac(n.v)
+}
+
+func (n *rfc6979Nonces) next() (*big.Int, error) {
+	for {
+		if !n.first {
+			// h.3 K = HMAC_K(V || 0x00), V = HMAC_K(V)
+			n.update(0x00, nil)
+		}
+		n.first = false
+
+		// h.2 T = V_1 || V_2 || .. until tlen ≥ qlen
+		t := make([]byte, 0, rlen+len(n.v))
+		for len(t) < rlen {
+			n.v = n.hmac(n.v)
+			t = append(t, n.v...)
+		}
+
+		// k = bits2int(T), accepted if k ∈ [1, order-1]
+		k := HashToInt(t)
+		if k.Sign() > 0 && k.Cmp(order) < 0 {
+			return k, nil
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mlkzg
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (must be 2ⁿ where n is the number of variables of the SRS)")
+	ErrInvalidPointSize      = errors.New("invalid point size (must be the number of variables of the SRS)")
+	ErrInvalidNbQuotients    = errors.New("number of quotients is not the number of variables of the SRS")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+	ErrMinSRSSize            = errors.New("minimum number of variables is 1")
+)
+
+// Digest commitment of a multilinear polynomial.
+type Digest = bls24317.G1Affine
+
+// SRS stores the result of the MPC
+type SRS struct {
+	// G1[k] = [eq((τₖ₊₁, ..., τₙ), b)]G₁ for b ∈ {0,1}ⁿ⁻ᵏ, i.e. the Lagrange basis
+	// of the hypercube in the variables Xₖ₊₁, ..., Xₙ. In particular, G1[n] = [G₁].
+	G1 [][]bls24317.G1Affine
+	// G2 = [G₂, [τ₁]G₂, ..., [τₙ]G₂]
+	G2 []bls24317.G2Affine
+}
+
+// NewSRS returns a new SRS for multilinear polynomials in nbVars variables,
+// using bTau as randomness source.
+//
+// In production, a SRS generated through MPC should be used.
+//
+// implements io.ReaderFrom and io.WriterTo
+func NewSRS(nbVars int, bTau []*big.Int) (*SRS, error) {
+
+	if nbVars < 1 {
+		return nil, ErrMinSRSSize
+	}
+	if len(bTau) != nbVars {
+		return nil, ErrInvalidPointSize
+	}
+
+	tau := make([]fr.Element, nbVars)
+	for i := range tau {
+		tau[i].SetBigInt(bTau[i])
+	}
+
+	_, _, gen1Aff, gen2Aff := bls24317.Generators()
+
+	var srs SRS
+	srs.G2 = make([]bls24317.G2Affine, nbVars+1)
+	srs.G2[0] = gen2Aff
+	for i := 0; i < nbVars; i++ {
+		srs.G2[i+1].ScalarMultiplication(&gen2Aff, bTau[i])
+	}
+
+	srs.G1 = make([][]bls24317.G1Affine, nbVars+1)
+	for k := 0; k <= nbVars; k++ {
+		eq := make(polynomial.MultiLin, 1<<(nbVars-k))
+		eq[0].SetOne()
+		eq.Eq(tau[k:])
+		srs.G1[k] = bls24317.BatchScalarMultiplicationG1(&gen1Aff, eq)
+	}
+
+	return &srs, nil
+}
+
+// NbVars returns the number of variables of the polynomials that can be committed with the SRS.
+func (srs *SRS) NbVars() int {
+	return len(srs.G2) - 1
+}
+
+// OpeningProof KZG proof for opening a multilinear polynomial at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// Quotients commitments to the quotients qᵢ such that f - f(z) = ∑ᵢ (Xᵢ - zᵢ) qᵢ(Xᵢ₊₁, ..., Xₙ)
+	Quotients []bls24317.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many multilinear polynomials at the same point
+//
+// implements io.ReaderFrom and io.WriterTo
+type BatchOpeningProof struct {
+	// Quotients commitments to the quotients of ∑ⱼγʲfⱼ
+	Quotients []bls24317.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// Commit commits to a multilinear polynomial using a multi exponentiation with the SRS.
+// The polynomial is given by its evaluations on the hypercube, in Montgomery form.
+func Commit(p polynomial.MultiLin, srs *SRS, nbTasks ...int) (Digest, error) {
+
+	if len(p) != len(srs.G1[0]) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+
+	var res bls24317.G1Affine
+	if _, err := res.MultiExp(srs.G1[0], p, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// Open computes an opening proof of the multilinear polynomial p at the given point.
+func Open(p polynomial.MultiLin, point []fr.Element, srs *SRS) (OpeningProof, error) {
+
+	if len(p) != len(srs.G1[0]) {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(point) != srs.NbVars() {
+		return OpeningProof{}, ErrInvalidPointSize
+	}
+
+	var res OpeningProof
+	res.Quotients = make([]bls24317.G1Affine, len(point))
+
+	// f(X₁, ..., Xₙ) = f(0, X₂, ..., Xₙ) + X₁(f(1, X₂, ..., Xₙ) - f(0, X₂, ..., Xₙ)), so that
+	// f(X₁, ..., Xₙ) - f(z₁, X₂, ..., Xₙ) = (X₁ - z₁) q₁(X₂, ..., Xₙ) with q₁ = f(1, X₂, ..., Xₙ) - f(0, X₂, ..., Xₙ).
+	// We repeat the process on f(z₁, X₂, ..., Xₙ).
+	f := p.Clone()
+	q := make(polynomial.MultiLin, len(f)/2)
+	for i := range point {
+		mid := len(f) / 2
+		q = q[:mid]
+		for j := 0; j < mid; j++ {
+			q[j].Sub(&f[mid+j], &f[j])
+		}
+		if _, err := res.Quotients[i].MultiExp(srs.G1[i+1], q, ecc.MultiExpConfig{}); err != nil {
+			return OpeningProof{}, err
+		}
+		f.Fold(point[i])
+	}
+	res.ClaimedValue = f[0]
+
+	return res, nil
+}
+
+// Verify verifies a multilinear KZG opening proof at a single point
+func Verify(commitment *Digest, proof *OpeningProof, point []fr.Element, srs *SRS) error {
+	return BatchVerifyMultiPoints([]Digest{*commitment}, []OpeningProof{*proof}, [][]fr.Element{point}, srs)
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of multilinear polynomials.
+// It's an interactive protocol, made non interactive using Fiat Shamir.
+//
+// * point is the point at which the polynomials are opened.
+// * digests is the list of committed polynomials to open, need to derive the challenge using Fiat Shamir.
+// * polynomials is the list of polynomials to open, they are supposed to be of the same size.
+func BatchOpenSinglePoint(polynomials []polynomial.MultiLin, digests []Digest, point []fr.Element, hf hash.Hash, srs *SRS) (BatchOpeningProof, error) {
+
+	nbDigests := len(digests)
+	if nbDigests != len(polynomials) || nbDigests == 0 {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	for _, p := range polynomials {
+		if len(p) != len(srs.G1[0]) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+	}
+	if len(point) != srs.NbVars() {
+		return BatchOpeningProof{}, ErrInvalidPointSize
+	}
+
+	var res BatchOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, nbDigests)
+	for i := range polynomials {
+		res.ClaimedValues[i] = polynomials[i].Evaluate(point, nil)
+	}
+
+	// derive the challenge γ, binded to the point, the commitments and the claimed values
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	// compute ∑ⱼγʲfⱼ
+	folded := polynomials[nbDigests-1].Clone()
+	for i := nbDigests - 2; i >= 0; i-- {
+		for j := range folded {
+			folded[j].Mul(&folded[j], &gamma).Add(&folded[j], &polynomials[i][j])
+		}
+	}
+
+	proof, err := Open(folded, point, srs)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+	res.Quotients = proof.Quotients
+
+	return res, nil
+}
+
+// FoldProof fold the digests and the proofs in batchOpeningProof using Fiat Shamir
+// to obtain an opening proof at a single point.
+//
+// * digests list of digests on which batchOpeningProof is based
+// * batchOpeningProof opening proof of digests
+// * returns the folded version of batchOpeningProof, Digest, the folded version of digests
+func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash) (OpeningProof, Digest, error) {
+
+	nbDigests := len(digests)
+
+	// check consistancy between numbers of claims vs number of digests
+	if nbDigests != len(batchOpeningProof.ClaimedValues) || nbDigests == 0 {
+		return OpeningProof{}, Digest{}, ErrInvalidNbDigests
+	}
+
+	// derive the challenge γ, binded to the point, the commitments and the claimed values
+	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, hf)
+	if err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+
+	// gammai = [1,γ,γ²,..,γⁿ⁻¹]
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+
+	// fold the claimed values ∑ᵢγⁱfᵢ(z)
+	var res OpeningProof
+	var tmp fr.Element
+	for i := 0; i < nbDigests; i++ {
+		tmp.Mul(&batchOpeningProof.ClaimedValues[i], &gammai[i])
+		res.ClaimedValue.Add(&res.ClaimedValue, &tmp)
+	}
+	res.Quotients = batchOpeningProof.Quotients
+
+	// fold the digests ∑ᵢγⁱ[fᵢ(τ)]G₁
+	var foldedDigest Digest
+	if _, err := foldedDigest.MultiExp(digests, gammai, ecc.MultiExpConfig{}); err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+
+	return res, foldedDigest, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of multilinear polynomials.
+//
+// * digests list of digests on which opening proof is done
+// * batchOpeningProof proof of correct opening on the digests
+func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, srs *SRS) error {
+
+	// fold the proof
+	foldedProof, foldedDigest, err := FoldProof(digests, batchOpeningProof, point, hf)
+	if err != nil {
+		return err
+	}
+
+	// verify the foldedProof againts the foldedDigest
+	return Verify(&foldedDigest, &foldedProof, point, srs)
+}
+
+// BatchVerifyMultiPoints batch verifies a list of opening proofs at different points.
+// The purpose of the batching is to have only n+1 pairings for verifying several proofs.
+//
+// * digests list of committed polynomials
+// * proofs list of opening proofs, one for each digest
+// * points the list of points at which the opening are done
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, points [][]fr.Element, srs *SRS) error {
+
+	// check consistancy nb proofs vs nb digests
+	if len(digests) != len(proofs) || len(digests) != len(points) || len(digests) == 0 {
+		return ErrInvalidNbDigests
+	}
+	nbVars := srs.NbVars()
+	for i := range proofs {
+		if len(proofs[i].Quotients) != nbVars {
+			return ErrInvalidNbQuotients
+		}
+		if len(points[i]) != nbVars {
+			return ErrInvalidPointSize
+		}
+	}
+
+	// sample random numbers λᵢ for sampling
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// for each proof, the verification equation is
+	// e([fᵢ(τ) - fᵢ(zᵢ)]G₁, G₂) = ∏ⱼ e([qᵢⱼ(τ)]G₁, [τⱼ - zᵢⱼ]G₂), that is
+	// e([fᵢ(τ) - fᵢ(zᵢ) + ∑ⱼzᵢⱼqᵢⱼ(τ)]G₁, G₂) = ∏ⱼ e([qᵢⱼ(τ)]G₁, [τⱼ]G₂)
+	// we fold the equations using the λᵢ.
+
+	// points and scalars for ∑ᵢλᵢ([fᵢ(τ)]G₁ - fᵢ(zᵢ)G₁ + ∑ⱼzᵢⱼ[qᵢⱼ(τ)]G₁)
+	nbProofs := len(proofs)
+	bases := make([]bls24317.G1Affine, 0, nbProofs*(nbVars+1)+1)
+	scalars := make([]fr.Element, 0, nbProofs*(nbVars+1)+1)
+	var foldedEvals, tmp fr.Element
+	for i := range proofs {
+		bases = append(bases, digests[i])
+		scalars = append(scalars, randomNumbers[i])
+		for j := 0; j < nbVars; j++ {
+			bases = append(bases, proofs[i].Quotients[j])
+			scalars = append(scalars, *tmp.Mul(&randomNumbers[i], &points[i][j]))
+		}
+		tmp.Mul(&randomNumbers[i], &proofs[i].ClaimedValue)
+		foldedEvals.Add(&foldedEvals, &tmp)
+	}
+	bases = append(bases, srs.G1[nbVars][0])
+	scalars = append(scalars, *foldedEvals.Neg(&foldedEvals))
+
+	config := ecc.MultiExpConfig{}
+	var left bls24317.G1Affine
+	if _, err := left.MultiExp(bases, scalars, config); err != nil {
+		return err
+	}
+
+	// -∑ᵢλᵢ[qᵢⱼ(τ)]G₁ for each variable j
+	g1 := make([]bls24317.G1Affine, nbVars+1)
+	g1[0] = left
+	quotients := make([]bls24317.G1Affine, nbProofs)
+	for j := 0; j < nbVars; j++ {
+		for i := range proofs {
+			quotients[i] = proofs[i].Quotients[j]
+		}
+		if _, err := g1[j+1].MultiExp(quotients, randomNumbers, config); err != nil {
+			return err
+		}
+		g1[j+1].Neg(&g1[j+1])
+	}
+
+	// pairing check
+	// e([∑ᵢλᵢ(fᵢ(τ) - fᵢ(zᵢ) + ∑ⱼzᵢⱼqᵢⱼ(τ))]G₁, G₂) ∏ⱼ e([-∑ᵢλᵢqᵢⱼ(τ)]G₁, [τⱼ]G₂) ==? 1
+	check, err := bls24317.PairingCheck(g1, srs.G2)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold proofs.
+// The claimed values must be bound, otherwise a prover knowing γ could shift them
+// while keeping ∑ᵢγⁱfᵢ(z) unchanged.
+func deriveGamma(point []fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash) (fr.Element, error) {
+
+	// derive the challenge gamma, binded to the point, the commitments and the claimed values
+	fs := fiatshamir.NewTranscript(hf, "gamma")
+	for i := range point {
+		if err := fs.Bind("gamma", point[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range claimedValues {
+		if err := fs.Bind("gamma", claimedValues[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
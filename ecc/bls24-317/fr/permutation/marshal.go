@@ -0,0 +1,91 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package permutation
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+)
+
+// WriteTo writes the binary encoding of the proof, with compressed points
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes the binary encoding of the proof, with uncompressed points
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
+	var enc *bls24317.Encoder
+	if raw {
+		enc = bls24317.NewEncoder(w, bls24317.RawEncoding())
+	} else {
+		enc = bls24317.NewEncoder(w)
+	}
+
+	toEncode := []interface{}{
+		uint64(proof.size),
+		&proof.g,
+		&proof.t1,
+		&proof.t2,
+		&proof.z,
+		&proof.q,
+		&proof.batchedProof.H,
+		proof.batchedProof.ClaimedValues,
+		&proof.shiftedProof.H,
+		&proof.shiftedProof.ClaimedValue,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a proof written with WriteTo or WriteRawTo
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24317.NewDecoder(r)
+
+	var size uint64
+	toDecode := []interface{}{
+		&size,
+		&proof.g,
+		&proof.t1,
+		&proof.t2,
+		&proof.z,
+		&proof.q,
+		&proof.batchedProof.H,
+		&proof.batchedProof.ClaimedValues,
+		&proof.shiftedProof.H,
+		&proof.shiftedProof.ClaimedValue,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	proof.size = int(size)
+
+	return dec.BytesRead(), nil
+}
@@ -17,6 +17,7 @@
 package permutation
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -70,6 +71,80 @@ func TestProof(t *testing.T) {
 
 }
 
+func TestProofSerialization(t *testing.T) {
+
+	srs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := make([]fr.Element, 8)
+	b := make([]fr.Element, 8)
+	for i := 0; i < 8; i++ {
+		a[i].SetUint64(uint64(4*i + 1))
+	}
+	for i := 0; i < 8; i++ {
+		b[i].Set(&a[(5*i)%8])
+	}
+
+	proof, err := Prove(srs, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the verifier only knows the serialized srs and proof
+	var bufSRS bytes.Buffer
+	if _, err = srs.WriteTo(&bufSRS); err != nil {
+		t.Fatal(err)
+	}
+	var verifierSRS kzg.SRS
+	if _, err = verifierSRS.ReadFrom(&bufSRS); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+		var written int64
+		if raw {
+			written, err = proof.WriteRawTo(&buf)
+		} else {
+			written, err = proof.WriteTo(&buf)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Proof
+		read, err := decoded.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatalf("read %d bytes, wrote %d", read, written)
+		}
+		if decoded.T1() != proof.T1() || decoded.T2() != proof.T2() || decoded.Z() != proof.Z() || decoded.Q() != proof.Q() {
+			t.Fatal("decoded commitments differ")
+		}
+		if err = Verify(&verifierSRS, decoded); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// truncated claimed values
+	var buf bytes.Buffer
+	proof.batchedProof.ClaimedValues = proof.batchedProof.ClaimedValues[:3]
+	if _, err = proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Proof
+	if _, err = decoded.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err = Verify(&verifierSRS, decoded); err != ErrNbClaimedValues {
+		t.Fatal("expected ErrNbClaimedValues")
+	}
+}
+
 func BenchmarkProver(b *testing.B) {
 
 	srsSize := 1 << 15
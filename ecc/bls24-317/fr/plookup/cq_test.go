@@ -0,0 +1,150 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"math/big"
+	"testing"
+
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+)
+
+func TestCqCachedQuotients(t *testing.T) {
+
+	const size = 16
+	tau := big.NewInt(13)
+	srs, err := NewCqSRS(size, tau)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	column := make(fr.Vector, size)
+	for i := range column {
+		column[i].SetUint64(uint64(i * i))
+	}
+	table, err := NewCqTable(srs, column)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Qᵢ(τ) = (T(τ) - tᵢ)Lᵢ(τ)/(τᴺ - 1) = ωⁱ(T(τ) - tᵢ)/(N(τ - ωⁱ))
+	domain := fft.NewDomain(size)
+	var _tau, tTau, omegaI, n, q fr.Element
+	_tau.SetBigInt(tau)
+	tTau = eval(toCanonical(column, domain), _tau)
+	omegaI.SetOne()
+	n.SetUint64(size)
+	_, _, g1, _ := bls24317.Generators()
+	for i := 0; i < size; i++ {
+		var num, den fr.Element
+		num.Sub(&tTau, &column[i]).Mul(&num, &omegaI)
+		den.Sub(&_tau, &omegaI).Mul(&den, &n)
+		q.Div(&num, &den)
+
+		var expected bls24317.G1Affine
+		var qBigInt big.Int
+		expected.ScalarMultiplication(&g1, q.BigInt(&qBigInt))
+		if !expected.Equal(&table.quotients[0][i]) {
+			t.Fatalf("wrong cached quotient %d", i)
+		}
+
+		omegaI.Mul(&omegaI, &domain.Generator)
+	}
+}
+
+// eval evaluates the polynomial given by its coefficients
+func eval(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+func TestCq(t *testing.T) {
+
+	srs, err := NewCqSRS(32, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// table of the AND function on 2 bits
+	lookupTable := make([]fr.Vector, 3)
+	fTable := make([]fr.Vector, 3)
+	for i := range lookupTable {
+		lookupTable[i] = make(fr.Vector, 16)
+		fTable[i] = make(fr.Vector, 7)
+	}
+	for j := 0; j < 16; j++ {
+		lookupTable[0][j].SetUint64(uint64(j >> 2))
+		lookupTable[1][j].SetUint64(uint64(j & 3))
+		lookupTable[2][j].SetUint64(uint64((j >> 2) & (j & 3)))
+	}
+	for j := 0; j < 7; j++ {
+		for i := range fTable {
+			fTable[i][j] = lookupTable[i][(5*j+2)%16]
+		}
+	}
+
+	table, err := NewCqTable(srs, lookupTable...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// correct proof
+	proof, err := ProveCq(srs, table, fTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifyCq(srs, table.Digests, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	// tampered proofs
+	{
+		_proof := proof
+		_proof.AZero.SetOne()
+		if err = VerifyCq(srs, table.Digests, _proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		_proof := proof
+		_proof.Size *= 2 // another domain H
+		if err = VerifyCq(srs, table.Digests, _proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+	{
+		otherTable, err := NewCqTable(srs, lookupTable[1], lookupTable[0], lookupTable[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = VerifyCq(srs, otherTable.Digests, proof); err == nil {
+			t.Fatal("verifying wrong proof should have failed")
+		}
+	}
+
+	// value not in the table
+	fTable[2][3].SetUint64(3)
+	fTable[0][3].SetUint64(1)
+	if _, err = ProveCq(srs, table, fTable); err != ErrNotInTable {
+		t.Fatal("expected ErrNotInTable")
+	}
+}
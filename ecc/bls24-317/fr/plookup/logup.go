@@ -0,0 +1,508 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package plookup
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrLogUpVerification = errors.New("logup verification failed")
+	ErrDomainSize        = errors.New("the size of the domain is not a power of two")
+)
+
+// ProofLogUp is a log-derivative lookup proof (https://eprint.iacr.org/2022/1530), showing that
+// the rows of f are rows of t. With β a random challenge and mᵢ the multiplicity of the i-th row of t in f:
+//
+//	∑ᵢ 1/(β + fᵢ) = ∑ᵢ mᵢ/(β + tᵢ)
+//
+// The columns of f and t are folded into single vectors using a random λ. The sum is proven
+// with an accumulator z such that z(ωX) = z(X) + a(X) - b(X), where a = m/(β + t), b = 1/(β + f).
+type ProofLogUp struct {
+
+	// Size of the domain
+	Size uint64
+
+	// Commitments to the columns of f and t
+	Fs, Ts []kzg.Digest
+
+	// Commitments to the multiplicities m, to a, b, the accumulator z and the quotient h
+	M, A, B, Z, H kzg.Digest
+
+	// Batch opening proof of f, t (folded), m, a, b, z, h at ζ
+	BatchedProof kzg.BatchOpeningProof
+
+	// Opening proof of z at ωζ
+	ZShiftedProof kzg.OpeningProof
+}
+
+// rowKey returns the concatenation of the bytes of the i-th row of the columns
+func rowKey(columns []fr.Vector, i int) string {
+	res := make([]byte, 0, len(columns)*fr.Bytes)
+	for k := range columns {
+		b := columns[k][i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return string(res)
+}
+
+// rowIndex maps the rows of t to the index of their first occurrence
+func rowIndex(t []fr.Vector) map[string]int {
+	index := make(map[string]int, len(t[0]))
+	for i := len(t[0]) - 1; i >= 0; i-- {
+		index[rowKey(t, i)] = i
+	}
+	return index
+}
+
+// computeMultiplicities returns the number of occurrences in f of each row of a table of the given size.
+// The occurrences of repeated rows of the table are counted on the first one.
+func computeMultiplicities(f []fr.Vector, index map[string]int, size int) (fr.Vector, error) {
+	counts := make([]uint64, size)
+	for i := range f[0] {
+		j, ok := index[rowKey(f, i)]
+		if !ok {
+			return nil, ErrNotInTable
+		}
+		counts[j]++
+	}
+	m := make(fr.Vector, size)
+	for i := range m {
+		m[i].SetUint64(counts[i])
+	}
+	return m, nil
+}
+
+// checkColumns checks that f and t have the same number of columns, and that the columns of each have the same length
+func checkColumns(f, t []fr.Vector) error {
+	if len(f) != len(t) || len(f) == 0 {
+		return ErrIncompatibleSize
+	}
+	for _, columns := range [][]fr.Vector{f, t} {
+		if len(columns[0]) == 0 {
+			return ErrIncompatibleSize
+		}
+		for i := 1; i < len(columns); i++ {
+			if len(columns[i]) != len(columns[0]) {
+				return ErrIncompatibleSize
+			}
+		}
+	}
+	return nil
+}
+
+// padColumns resizes the columns to size by repeating the given row
+func padColumns(columns []fr.Vector, size, row int) []fr.Vector {
+	res := make([]fr.Vector, len(columns))
+	for k := range columns {
+		res[k] = make(fr.Vector, size)
+		copy(res[k], columns[k])
+		for i := len(columns[k]); i < size; i++ {
+			res[k][i] = columns[k][row]
+		}
+	}
+	return res
+}
+
+// foldColumns returns ∑ₖ λᵏ columns[k]
+func foldColumns(columns []fr.Vector, lambda fr.Element) fr.Vector {
+	res := make(fr.Vector, len(columns[0]))
+	for i := range res {
+		for k := len(columns) - 1; k >= 0; k-- {
+			res[i].Mul(&res[i], &lambda).
+				Add(&res[i], &columns[k][i])
+		}
+	}
+	return res
+}
+
+// foldDigests returns ∑ₖ λᵏ digests[k]
+func foldDigests(digests []kzg.Digest, lambda fr.Element) (kzg.Digest, error) {
+	var res kzg.Digest
+	lambdas := make([]fr.Element, len(digests))
+	lambdas[0].SetOne()
+	for k := 1; k < len(lambdas); k++ {
+		lambdas[k].Mul(&lambdas[k-1], &lambda)
+	}
+	_, err := res.MultiExp(digests, lambdas, ecc.MultiExpConfig{})
+	return res, err
+}
+
+// toCanonical returns the coefficients of the polynomial whose values on the domain are given
+func toCanonical(lagrange []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, len(lagrange))
+	copy(res, lagrange)
+	domain.FFTInverse(res, fft.DIF)
+	fft.BitReverse(res)
+	return res
+}
+
+// toLagrangeCosetBitReversed returns the values of the polynomial on the coset of domainBig, in bit reversed order
+func toLagrangeCosetBitReversed(canonical []fr.Element, domainBig *fft.Domain) []fr.Element {
+	res := make([]fr.Element, domainBig.Cardinality)
+	copy(res, canonical)
+	domainBig.FFT(res, fft.DIF, fft.OnCoset())
+	return res
+}
+
+// divideByXnMinusOne divides the numerator, given on the coset of domainBig in bit reversed order,
+// by Xⁿ-1 where n is half the size of domainBig. It returns the quotient in canonical basis.
+func divideByXnMinusOne(num []fr.Element, domainBig *fft.Domain) []fr.Element {
+	xnMinusOne := evaluateXnMinusOneDomainBig(domainBig)
+	xnMinusOne[0].Inverse(&xnMinusOne[0])
+	xnMinusOne[1].Inverse(&xnMinusOne[1])
+	nn := uint64(64 - bits.TrailingZeros64(domainBig.Cardinality))
+	for i := 0; i < len(num); i++ {
+		_i := int(bits.Reverse64(uint64(i)) >> nn)
+		num[_i].Mul(&num[_i], &xnMinusOne[i%2])
+	}
+	domainBig.FFTInverse(num, fft.DIT, fft.OnCoset())
+	return num
+}
+
+// invertShifted returns 1/(β + v) for all values v. It fails if β + v = 0.
+func invertShifted(v fr.Vector, beta fr.Element) (fr.Vector, error) {
+	res := make(fr.Vector, len(v))
+	for i := range v {
+		res[i].Add(&beta, &v[i])
+		if res[i].IsZero() {
+			return nil, ErrLogUpVerification
+		}
+	}
+	return fr.BatchInvert(res), nil
+}
+
+// bindSize binds the size of the domain to the challenge, so that the prover can not
+// choose it after the fact
+func bindSize(fs *fiatshamir.Sponge, challenge string, size uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], size)
+	fs.Absorb(challenge, buf[:])
+}
+
+// ProveLogUp returns a proof that the rows of f are rows of t, f and t being given by columns.
+// t may contain repeated rows.
+//
+// As for ProveLookupTables, the verifier must check that proof.Ts are the commitments to the
+// expected table.
+func ProveLogUp(srs *kzg.SRS, f, t []fr.Vector) (ProofLogUp, error) {
+
+	var proof ProofLogUp
+	var err error
+
+	if err = checkColumns(f, t); err != nil {
+		return proof, err
+	}
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.logup")
+
+	// create the domain and resize f and t
+	sizeF, sizeT := len(f[0]), len(t[0])
+	if sizeF < sizeT {
+		sizeF = sizeT
+	}
+	domain := fft.NewDomain(uint64(sizeF))
+	n := int(domain.Cardinality)
+	proof.Size = domain.Cardinality
+	f = padColumns(f, n, 0)
+	t = padColumns(t, n, sizeT-1)
+
+	m, err := computeMultiplicities(f, rowIndex(t), n)
+	if err != nil {
+		return proof, err
+	}
+
+	// commit to the columns and the multiplicities
+	proof.Fs = make([]kzg.Digest, len(f))
+	proof.Ts = make([]kzg.Digest, len(t))
+	for k := range f {
+		if proof.Fs[k], err = kzg.Commit(toCanonical(f[k], domain), srs); err != nil {
+			return proof, err
+		}
+		if proof.Ts[k], err = kzg.Commit(toCanonical(t[k], domain), srs); err != nil {
+			return proof, err
+		}
+	}
+	cm := toCanonical(m, domain)
+	if proof.M, err = kzg.Commit(cm, srs); err != nil {
+		return proof, err
+	}
+
+	// fold the columns
+	comms := make([]fiatshamir.Marshaler, 0, 2*len(f)+1)
+	for k := range f {
+		comms = append(comms, &proof.Fs[k])
+	}
+	for k := range t {
+		comms = append(comms, &proof.Ts[k])
+	}
+	comms = append(comms, &proof.M)
+	bindSize(fs, "lambda", proof.Size)
+	fs.AbsorbMarshalers("lambda", comms...)
+	lambda, err := fiatshamir.SqueezeElement[fr.Element](fs, "lambda", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+	lf := foldColumns(f, lambda)
+	lt := foldColumns(t, lambda)
+	cf := toCanonical(lf, domain)
+	ct := toCanonical(lt, domain)
+
+	// a = m/(β + t), b = 1/(β + f)
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+	la, err := invertShifted(lt, beta)
+	if err != nil {
+		return proof, err
+	}
+	for i := range la {
+		la[i].Mul(&la[i], &m[i])
+	}
+	lb, err := invertShifted(lf, beta)
+	if err != nil {
+		return proof, err
+	}
+
+	// z(ωⁱ) = ∑_{j<i} a(ωʲ) - b(ωʲ)
+	lz := make(fr.Vector, n)
+	for i := 0; i+1 < n; i++ {
+		lz[i+1].Add(&lz[i], &la[i]).
+			Sub(&lz[i+1], &lb[i])
+	}
+
+	ca := toCanonical(la, domain)
+	cb := toCanonical(lb, domain)
+	cz := toCanonical(lz, domain)
+	if proof.A, err = kzg.Commit(ca, srs); err != nil {
+		return proof, err
+	}
+	if proof.B, err = kzg.Commit(cb, srs); err != nil {
+		return proof, err
+	}
+	if proof.Z, err = kzg.Commit(cz, srs); err != nil {
+		return proof, err
+	}
+
+	// quotient h = (a(β + t) - m + γ(b(β + f) - 1) + γ²(z(ωX) - z - a + b)) / (Xⁿ - 1)
+	fs.AbsorbMarshalers("gamma", &proof.A, &proof.B, &proof.Z)
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+	domainBig := fft.NewDomain(uint64(2 * n))
+	_lf := toLagrangeCosetBitReversed(cf, domainBig)
+	_lt := toLagrangeCosetBitReversed(ct, domainBig)
+	_lm := toLagrangeCosetBitReversed(cm, domainBig)
+	_la := toLagrangeCosetBitReversed(ca, domainBig)
+	_lb := toLagrangeCosetBitReversed(cb, domainBig)
+	_lz := toLagrangeCosetBitReversed(cz, domainBig)
+
+	s := int(domainBig.Cardinality)
+	nn := uint64(64 - bits.TrailingZeros64(domainBig.Cardinality))
+	num := make([]fr.Element, s)
+	var u, v, one fr.Element
+	one.SetOne()
+	for i := 0; i < s; i++ {
+		_i := int(bits.Reverse64(uint64(i)) >> nn)
+		_is := int(bits.Reverse64(uint64((i+2)%s)) >> nn)
+
+		// γ²(z(ωX) - z - a + b)
+		u.Sub(&_lz[_is], &_lz[_i]).
+			Sub(&u, &_la[_i]).
+			Add(&u, &_lb[_i]).
+			Mul(&u, &gamma)
+
+		// γ(b(β + f) - 1)
+		v.Add(&beta, &_lf[_i]).
+			Mul(&v, &_lb[_i]).
+			Sub(&v, &one)
+		u.Add(&u, &v).
+			Mul(&u, &gamma)
+
+		// a(β + t) - m
+		v.Add(&beta, &_lt[_i]).
+			Mul(&v, &_la[_i]).
+			Sub(&v, &_lm[_i])
+		num[_i].Add(&u, &v)
+	}
+	ch := divideByXnMinusOne(num, domainBig)
+	if proof.H, err = kzg.Commit(ch, srs); err != nil {
+		return proof, err
+	}
+
+	// build the opening proofs
+	fs.AbsorbMarshalers("zeta", &proof.H)
+	zeta, err := fiatshamir.SqueezeElement[fr.Element](fs, "zeta", fr.Modulus())
+	if err != nil {
+		return proof, err
+	}
+	foldedF, err := foldDigests(proof.Fs, lambda)
+	if err != nil {
+		return proof, err
+	}
+	foldedT, err := foldDigests(proof.Ts, lambda)
+	if err != nil {
+		return proof, err
+	}
+	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
+		[][]fr.Element{cf, ct, cm, ca, cb, cz, ch},
+		[]kzg.Digest{foldedF, foldedT, proof.M, proof.A, proof.B, proof.Z, proof.H},
+		zeta,
+		hFunc,
+		srs,
+	)
+	if err != nil {
+		return proof, err
+	}
+
+	zeta.Mul(&zeta, &domain.Generator)
+	proof.ZShiftedProof, err = kzg.Open(cz, zeta, srs)
+
+	return proof, err
+}
+
+// VerifyLogUp verifies a ProofLogUp proof
+func VerifyLogUp(srs *kzg.SRS, proof ProofLogUp) error {
+
+	if len(proof.Fs) != len(proof.Ts) || len(proof.Fs) == 0 {
+		return ErrNumberDigests
+	}
+	if proof.Size < 2 || proof.Size&(proof.Size-1) != 0 {
+		return ErrDomainSize
+	}
+	if len(proof.BatchedProof.ClaimedValues) != 7 {
+		return ErrLogUpVerification
+	}
+
+	// hash function that is used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewSponge(sha256.New, "plookup.logup")
+
+	comms := make([]fiatshamir.Marshaler, 0, 2*len(proof.Fs)+1)
+	for k := range proof.Fs {
+		comms = append(comms, &proof.Fs[k])
+	}
+	for k := range proof.Ts {
+		comms = append(comms, &proof.Ts[k])
+	}
+	comms = append(comms, &proof.M)
+	bindSize(fs, "lambda", proof.Size)
+	fs.AbsorbMarshalers("lambda", comms...)
+	lambda, err := fiatshamir.SqueezeElement[fr.Element](fs, "lambda", fr.Modulus())
+	if err != nil {
+		return err
+	}
+	beta, err := fiatshamir.SqueezeElement[fr.Element](fs, "beta", fr.Modulus())
+	if err != nil {
+		return err
+	}
+	fs.AbsorbMarshalers("gamma", &proof.A, &proof.B, &proof.Z)
+	gamma, err := fiatshamir.SqueezeElement[fr.Element](fs, "gamma", fr.Modulus())
+	if err != nil {
+		return err
+	}
+	fs.AbsorbMarshalers("zeta", &proof.H)
+	zeta, err := fiatshamir.SqueezeElement[fr.Element](fs, "zeta", fr.Modulus())
+	if err != nil {
+		return err
+	}
+
+	// check the opening proofs
+	foldedF, err := foldDigests(proof.Fs, lambda)
+	if err != nil {
+		return err
+	}
+	foldedT, err := foldDigests(proof.Ts, lambda)
+	if err != nil {
+		return err
+	}
+	err = kzg.BatchVerifySinglePoint(
+		[]kzg.Digest{foldedF, foldedT, proof.M, proof.A, proof.B, proof.Z, proof.H},
+		&proof.BatchedProof,
+		zeta,
+		hFunc,
+		srs,
+	)
+	if err != nil {
+		return err
+	}
+
+	generator, err := fft.Generator(proof.Size)
+	if err != nil {
+		return err
+	}
+	var shiftedZeta fr.Element
+	shiftedZeta.Mul(&zeta, &generator)
+	if err = kzg.Verify(&proof.Z, &proof.ZShiftedProof, shiftedZeta, srs); err != nil {
+		return err
+	}
+
+	// check the polynomial relation at ζ
+	claimed := proof.BatchedProof.ClaimedValues
+	vf, vt, vm, va, vb, vz, vh := claimed[0], claimed[1], claimed[2], claimed[3], claimed[4], claimed[5], claimed[6]
+
+	var lhs, u, one fr.Element
+	one.SetOne()
+
+	// γ²(z(ωζ) - z - a + b)
+	lhs.Sub(&proof.ZShiftedProof.ClaimedValue, &vz).
+		Sub(&lhs, &va).
+		Add(&lhs, &vb).
+		Mul(&lhs, &gamma)
+
+	// γ(b(β + f) - 1)
+	u.Add(&beta, &vf).
+		Mul(&u, &vb).
+		Sub(&u, &one)
+	lhs.Add(&lhs, &u).
+		Mul(&lhs, &gamma)
+
+	// a(β + t) - m
+	u.Add(&beta, &vt).
+		Mul(&u, &va).
+		Sub(&u, &vm)
+	lhs.Add(&lhs, &u)
+
+	// h(ζ)(ζⁿ - 1)
+	var rhs fr.Element
+	rhs.Exp(zeta, new(big.Int).SetUint64(proof.Size)).
+		Sub(&rhs, &one).
+		Mul(&rhs, &vh)
+
+	if !lhs.Equal(&rhs) {
+		return ErrLogUpVerification
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/test_vector_utils"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMultiLin(nbVars int, seed int64) polynomial.MultiLin {
+	res := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range res {
+		res[i].SetInt64(seed + int64(i*i))
+	}
+	return res
+}
+
+func testPoint(nbVars int, seed int64) []fr.Element {
+	res := make([]fr.Element, nbVars)
+	for i := range res {
+		res[i].SetInt64(seed + 7*int64(i))
+	}
+	return res
+}
+
+func TestProductClaims(t *testing.T) {
+	const nbVars = 10 // large enough for the parallel prover to kick in
+	f, g, h := testMultiLin(nbVars, 1), testMultiLin(nbVars, 2), testMultiLin(nbVars, 3)
+	products := [][]polynomial.MultiLin{{f, g, h}, {h}, {f, f}}
+
+	for _, options := range [][]ClaimsOption{nil, {WithWorkers(utils.NewWorkerPool())}} {
+		claims, err := NewProductClaims(products, options...)
+		assert.NoError(t, err)
+		sums := claims.Sums()
+
+		proof, err := Prove(claims, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+		assert.NoError(t, err)
+
+		lazyClaims, err := NewProductLazyClaims(nbVars, []int{3, 1, 2}, sums)
+		assert.NoError(t, err)
+		assert.NoError(t, Verify(lazyClaims, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+
+		// the evaluations are those of the polynomials at r
+		r := lazyClaims.Point()
+		evaluations := lazyClaims.Evaluations()
+		for j := range products {
+			for k := range products[j] {
+				expected := products[j][k].Evaluate(r, nil)
+				assert.True(t, expected.Equal(&evaluations[j][k]))
+			}
+		}
+
+		// wrong sum
+		sums[1].Add(&sums[1], &sums[0])
+		lazyClaims, err = NewProductLazyClaims(nbVars, []int{3, 1, 2}, sums)
+		assert.NoError(t, err)
+		assert.Error(t, Verify(lazyClaims, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+	}
+
+	// the input polynomials are not modified
+	assert.Equal(t, testMultiLin(nbVars, 1), f)
+}
+
+// mulSubGate is the composition (a, b, c) ↦ ab - c
+type mulSubGate struct{}
+
+func (mulSubGate) Evaluate(in ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Mul(&in[0], &in[1]).
+		Sub(&res, &in[2])
+	return res
+}
+
+func (mulSubGate) Degree() int {
+	return 2
+}
+
+func TestZeroCheckClaims(t *testing.T) {
+	const nbVars = 3
+	a, b := testMultiLin(nbVars, 4), testMultiLin(nbVars, 5)
+	c := make(polynomial.MultiLin, len(a))
+	for i := range c {
+		c[i].Mul(&a[i], &b[i])
+	}
+	tau := testPoint(nbVars, 11)
+
+	prove := func() Proof {
+		claims, err := NewZeroCheckClaims(mulSubGate{}, tau, []polynomial.MultiLin{a, b, c})
+		assert.NoError(t, err)
+		proof, err := Prove(claims, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+		assert.NoError(t, err)
+		return proof
+	}
+
+	lazyClaims := NewZeroCheckLazyClaims(mulSubGate{}, tau, 3)
+	assert.NoError(t, Verify(lazyClaims, prove(), fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+	expected := c.Evaluate(lazyClaims.Point(), nil)
+	assert.True(t, expected.Equal(&lazyClaims.Evaluations()[2]))
+
+	// c no longer equals ab on the hypercube
+	c[3].Add(&c[3], &a[0])
+	lazyClaims = NewZeroCheckLazyClaims(mulSubGate{}, tau, 3)
+	assert.Error(t, Verify(lazyClaims, prove(), fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+}
+
+func TestLogUpClaims(t *testing.T) {
+	const nbVars = 3
+	table := testMultiLin(nbVars, 0)
+	columns := []polynomial.MultiLin{make(polynomial.MultiLin, 1<<nbVars), make(polynomial.MultiLin, 1<<nbVars)}
+	for i := range columns[0] {
+		columns[0][i] = table[(3*i)%len(table)]
+		columns[1][i] = table[(i*i)%len(table)]
+	}
+	var alpha fr.Element
+	alpha.SetInt64(-5)
+	tau := testPoint(nbVars, 13)
+
+	prove := func() Proof {
+		multiplicities, err := LogUpMultiplicities(table, columns...)
+		assert.NoError(t, err)
+		phi, psi, err := LogUpHelpers(alpha, table, multiplicities, columns...)
+		assert.NoError(t, err)
+		claims, err := NewLogUpClaims(alpha, tau, table, multiplicities, columns, phi, psi)
+		assert.NoError(t, err)
+		proof, err := Prove(claims, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+		assert.NoError(t, err)
+		return proof
+	}
+
+	lazyClaims := NewLogUpLazyClaims(alpha, tau, len(columns))
+	assert.NoError(t, Verify(lazyClaims, prove(), fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+	_, tableEvaluation, _, _, _ := lazyClaims.Evaluations()
+	expected := table.Evaluate(lazyClaims.Point(), nil)
+	assert.True(t, expected.Equal(&tableEvaluation))
+
+	// a value missing from the table is caught when computing the multiplicities
+	columns[1][2].SetInt64(-1)
+	_, err := LogUpMultiplicities(table, columns...)
+	assert.Error(t, err)
+
+	// lying about the multiplicities breaks the sum
+	multiplicities, err := LogUpMultiplicities(table, columns[0])
+	assert.NoError(t, err)
+	phi, psi, err := LogUpHelpers(alpha, table, multiplicities, columns...)
+	assert.NoError(t, err)
+	claims, err := NewLogUpClaims(alpha, tau, table, multiplicities, columns, phi, psi)
+	assert.NoError(t, err)
+	proof, err := Prove(claims, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+	assert.NoError(t, err)
+	lazyClaims = NewLogUpLazyClaims(alpha, tau, len(columns))
+	assert.Error(t, Verify(lazyClaims, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))))
+}
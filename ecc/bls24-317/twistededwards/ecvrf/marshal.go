@@ -0,0 +1,104 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecvrf
+
+import (
+	"crypto/subtle"
+	"io"
+)
+
+// Bytes returns the compressed representation of the public key, see PointAffine.Bytes
+func (pk *PublicKey) Bytes() []byte {
+	var res [sizePublicKey]byte
+	pkBin := pk.A.Bytes()
+	subtle.ConstantTimeCopy(1, res[:], pkBin[:])
+	return res[:]
+}
+
+// SetBytes sets pk from its compressed representation in buf.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	if _, err := pk.A.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	if !pk.A.IsOnCurve() {
+		return sizePublicKey, errNotOnCurve
+	}
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar||randSrc
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.A.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:sizePublicKey+sizeFr], privKey.scalar[:])
+	subtle.ConstantTimeCopy(1, res[sizePublicKey+sizeFr:], privKey.randSrc[:])
+	return res[:]
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as publicKey||scalar||randSrc, see Bytes.
+// It returns the number of bytes read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePrivateKey {
+		return 0, io.ErrShortBuffer
+	}
+	n, err := privKey.PublicKey.SetBytes(buf)
+	if err != nil {
+		return n, err
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	n += sizeFr
+	subtle.ConstantTimeCopy(1, privKey.randSrc[:], buf[n:n+32])
+	n += 32
+	return n, nil
+}
+
+// Bytes returns the binary representation of the proof, as in RFC 9381:
+// Γ||c||s where Γ is compressed, and c and s are in big endian.
+func (proof *Proof) Bytes() []byte {
+	var res [sizeProof]byte
+	gammaBin := proof.Gamma.Bytes()
+	copy(res[:sizePoint], gammaBin[:])
+	copy(res[sizePoint:sizePoint+sizeChallenge], proof.C[:])
+	copy(res[sizePoint+sizeChallenge:], proof.S[:])
+	return res[:]
+}
+
+// SetBytes sets proof from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (proof *Proof) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizeProof {
+		return 0, io.ErrShortBuffer
+	}
+	if _, err := proof.Gamma.SetBytes(buf[:sizePoint]); err != nil {
+		return 0, err
+	}
+	if !proof.Gamma.IsOnCurve() {
+		return sizePoint, errNotOnCurve
+	}
+	copy(proof.C[:], buf[sizePoint:sizePoint+sizeChallenge])
+	copy(proof.S[:], buf[sizePoint+sizeChallenge:sizeProof])
+	return sizeProof, nil
+}
@@ -16,6 +16,9 @@
 
 // Package eddsa provides EdDSA signature scheme on bls24-317's twisted edwards curve.
 //
+// The keys can also be used for ECDH key agreement (PrivateKey.SharedSecret), with cofactor
+// clearing, and for hybrid encryption (ECIES with HKDF-SHA256 and AES-256-GCM, see Encrypt).
+//
 // # See also
 //
 // https://en.wikipedia.org/wiki/EdDSA
@@ -713,6 +713,37 @@ func TestOps(t *testing.T) {
 
 }
 
+func TestIsInSubGroup(t *testing.T) {
+	t.Parallel()
+	params := GetEdwardsCurve()
+	if !params.Base.IsInSubGroup() {
+		t.Fatal("the base point should be in the subgroup")
+	}
+
+	var p PointAffine
+	p.setInfinity()
+	if !p.IsInSubGroup() {
+		t.Fatal("the identity should be in the subgroup")
+	}
+
+	// (0, -1) has order 2
+	var t2 PointAffine
+	t2.Y.SetOne().Neg(&t2.Y)
+	if t2.IsInSubGroup() {
+		t.Fatal("(0, -1) should not be in the subgroup")
+	}
+	p.Add(&params.Base, &t2)
+	if !p.IsOnCurve() || p.IsInSubGroup() {
+		t.Fatal("base + (0, -1) should be on the curve, but not in the subgroup")
+	}
+
+	p.Set(&params.Base)
+	p.X.Double(&p.X)
+	if p.IsInSubGroup() {
+		t.Fatal("a point which is not on the curve should not be in the subgroup")
+	}
+}
+
 func TestMarshal(t *testing.T) {
 	t.Parallel()
 	initOnce.Do(initCurveParams)
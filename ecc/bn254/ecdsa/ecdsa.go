@@ -41,7 +41,10 @@ const (
 	sizeSignature  = 2 * sizeFr
 )
 
-var order = fr.Modulus()
+var (
+	order     = fr.Modulus()
+	halfOrder = new(big.Int).Rsh(order, 1)
+)
 
 // PublicKey represents an ECDSA public key
 type PublicKey struct {
@@ -101,7 +104,7 @@ func HashToInt(hash []byte) *big.Int {
 		hash = hash[:sizeFr]
 	}
 	ret := new(big.Int).SetBytes(hash)
-	excess := len(hash)*8 - sizeFr
+	excess := len(hash)*8 - fr.Bits
 	if excess > 0 {
 		ret.Rsh(ret, uint(excess))
 	}
@@ -111,6 +114,9 @@ func HashToInt(hash []byte) *big.Int {
 // RecoverP recovers the value P (prover commitment) when creating a signature.
 // It uses the recovery information v and part of the decomposed signature r. It
 // is used internally for recovering the public key.
+//
+// The second bit of v tells if x_P = r + order, and its first bit is the parity of y_P,
+// as in the recovery id of Ethereum.
 func RecoverP(v uint, r *big.Int) (*bn254.G1Affine, error) {
 	if r.Cmp(fr.Modulus()) >= 0 {
 		return nil, errors.New("r is larger than modulus")
@@ -121,39 +127,36 @@ func RecoverP(v uint, r *big.Int) (*bn254.G1Affine, error) {
 	x := new(big.Int).Set(r)
 	// if x is r or r+N
 	xChoice := (v & 2) >> 1
-	// if y is y or -y
+	// parity of y
 	yChoice := v & 1
-	// decompose limbs into big.Int value
 	// conditional +n based on xChoice
-	kn := big.NewInt(int64(xChoice))
-	kn.Mul(kn, fr.Modulus())
-	x.Add(x, kn)
+	if xChoice == 1 {
+		x.Add(x, fr.Modulus())
+	}
+	if x.Cmp(fp.Modulus()) >= 0 {
+		return nil, errors.New("x is larger than modulus")
+	}
+	var P bn254.G1Affine
+	P.X.SetBigInt(x)
 	// y^2 = x^3+ax+b
 	a, b := bn254.CurveCoefficients()
-	y := new(big.Int).Exp(x, big.NewInt(3), fp.Modulus())
+	var y2 fp.Element
+	y2.Square(&P.X).Mul(&y2, &P.X)
 	if !a.IsZero() {
-		y.Add(y, new(big.Int).Mul(a.BigInt(new(big.Int)), x))
+		var ax fp.Element
+		ax.Mul(&a, &P.X)
+		y2.Add(&y2, &ax)
 	}
-	y.Add(y, b.BigInt(new(big.Int)))
-	y.Mod(y, fp.Modulus())
+	y2.Add(&y2, &b)
 	// y = sqrt(y^2)
-	if y.ModSqrt(y, fp.Modulus()) == nil {
+	if P.Y.Sqrt(&y2) == nil {
 		return nil, errors.New("no square root")
 	}
-	// y2 is -y
-	y2 := new(big.Int).Sub(fp.Modulus(), y)
-	// if yChoice == 0, return min(y, y2), else max(y, y2)
-	if (y.Cmp(y2) < 0) == (yChoice == 0) {
-		return &bn254.G1Affine{
-			X: *new(fp.Element).SetBigInt(x),
-			Y: *new(fp.Element).SetBigInt(y),
-		}, nil
-	} else {
-		return &bn254.G1Affine{
-			X: *new(fp.Element).SetBigInt(x),
-			Y: *new(fp.Element).SetBigInt(y2),
-		}, nil
+	// y and -y have different parities, select the one of parity yChoice
+	if P.Y.BigInt(new(big.Int)).Bit(0) != yChoice {
+		P.Y.Neg(&P.Y)
 	}
+	return &P, nil
 }
 
 type zr struct{}
@@ -233,27 +236,70 @@ func (privKey *PrivateKey) Public() signature.PublicKey {
 
 // SignForRecover performs the ECDSA signature and returns public key recovery information
 //
+// k ← 𝔽r (random, or derived as in RFC 6979, see SignOption)
+// P = k ⋅ g1Gen
+// r = x_P (mod order)
+// s = k⁻¹ . (m + sk ⋅ r)
+// v = (div(x_P, order)<<1) || y_P[-1], with y_P[-1] the parity of y_P
+//
+// SEC 1, Version 2.0, Section 4.1.3
+func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash, opts ...SignOption) (v uint, r, s *big.Int, err error) {
+	return privKey.sign(message, hFunc, opts...)
+}
+
+// Sign performs the ECDSA signature
+//
 // k ← 𝔽r (random)
 // P = k ⋅ g1Gen
 // r = x_P (mod order)
 // s = k⁻¹ . (m + sk ⋅ r)
-// v = (div(x_P, order)<<1) || y_P[-1]
+// signature = {r, s}
 //
 // SEC 1, Version 2.0, Section 4.1.3
-func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v uint, r, s *big.Int, err error) {
-	halfp := new(big.Int).Sub(fp.Modulus(), big.NewInt(1))
-	halfp.Div(halfp, big.NewInt(2))
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	return privKey.SignWithOptions(message, hFunc)
+}
+
+// SignWithOptions performs the ECDSA signature as Sign, with the nonce derivation
+// and the normalization of s set by opts.
+func (privKey *PrivateKey) SignWithOptions(message []byte, hFunc hash.Hash, opts ...SignOption) ([]byte, error) {
+	_, r, s, err := privKey.sign(message, hFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	r.FillBytes(sig.R[:sizeFr])
+	s.FillBytes(sig.S[:sizeFr])
+
+	return sig.Bytes(), nil
+}
+
+// sign performs the ECDSA signature and returns the signature {r, s}
+// along with the public key recovery information v
+func (privKey *PrivateKey) sign(message []byte, hFunc hash.Hash, opts ...SignOption) (v uint, r, s *big.Int, err error) {
+	cfg, err := signOptions(opts...)
+	if err != nil {
+		return 0, nil, nil, err
+	}
 	r, s = new(big.Int), new(big.Int)
 
+	// compute the hash of the message
+	hramBin, err := hashMessage(message, hFunc)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	m := HashToInt(hramBin)
+
+	nonces, err := privKey.newNonceGenerator(message, hramBin, cfg)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
 	scalar, kInv := new(big.Int), new(big.Int)
 	scalar.SetBytes(privKey.scalar[:sizeFr])
 	for {
 		for {
-			csprng, err := nonce(privKey, message)
-			if err != nil {
-				return 0, nil, nil, err
-			}
-			k, err := randFieldElement(csprng)
+			k, err := nonces.next()
 			if err != nil {
 				return 0, nil, nil, err
 			}
@@ -264,11 +310,9 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 
 			P.X.BigInt(r)
 			// set how many times we overflow the scalar field
-			v |= (uint(new(big.Int).Div(r, order).Uint64())) << 1
-			// set if y is small or big
-			if P.Y.BigInt(new(big.Int)).Cmp(halfp) >= 0 {
-				v |= 1
-			}
+			v = (uint(new(big.Int).Div(r, order).Uint64())) << 1
+			// set the parity of y
+			v |= P.Y.BigInt(new(big.Int)).Bit(0)
 
 			r.Mod(r, order)
 			if r.Sign() != 0 {
@@ -277,22 +321,6 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 		}
 		s.Mul(r, scalar)
 
-		var m *big.Int
-		if hFunc != nil {
-			// compute the hash of the message as an integer
-			dataToHash := make([]byte, len(message))
-			copy(dataToHash[:], message[:])
-			hFunc.Reset()
-			_, err := hFunc.Write(dataToHash[:])
-			if err != nil {
-				return 0, nil, nil, err
-			}
-			hramBin := hFunc.Sum(nil)
-			m = HashToInt(hramBin)
-		} else {
-			m = HashToInt(message)
-		}
-
 		s.Add(m, s).
 			Mul(kInv, s).
 			Mod(s, order) // order != 0
@@ -301,28 +329,27 @@ func (privKey *PrivateKey) SignForRecover(message []byte, hFunc hash.Hash) (v ui
 		}
 	}
 
+	// s and -s are both valid, the latter corresponding to the nonce -k
+	if cfg.lowS && s.Cmp(halfOrder) > 0 {
+		s.Sub(order, s)
+		v ^= 1
+	}
+
 	return v, r, s, nil
 }
 
-// Sign performs the ECDSA signature
-//
-// k ← 𝔽r (random)
-// P = k ⋅ g1Gen
-// r = x_P (mod order)
-// s = k⁻¹ . (m + sk ⋅ r)
-// signature = {r, s}
-//
-// SEC 1, Version 2.0, Section 4.1.3
-func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
-	_, r, s, err := privKey.SignForRecover(message, hFunc)
-	if err != nil {
+// hashMessage returns hFunc(message), or message if hFunc is nil.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	dataToHash := make([]byte, len(message))
+	copy(dataToHash[:], message[:])
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
 		return nil, err
 	}
-	var sig Signature
-	r.FillBytes(sig.R[:sizeFr])
-	s.FillBytes(sig.S[:sizeFr])
-
-	return sig.Bytes(), nil
+	return hFunc.Sum(nil), nil
 }
 
 // Verify validates the ECDSA signature
@@ -342,23 +369,19 @@ func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (boo
 	r.SetBytes(sig.R[:sizeFr])
 	s.SetBytes(sig.S[:sizeFr])
 
+	// r, s ∈ [1, order-1]
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(order) >= 0 || s.Cmp(order) >= 0 {
+		return false, nil
+	}
+
 	sInv := new(big.Int).ModInverse(s, order)
 
-	var m *big.Int
-	if hFunc != nil {
-		// compute the hash of the message as an integer
-		dataToHash := make([]byte, len(message))
-		copy(dataToHash[:], message[:])
-		hFunc.Reset()
-		_, err := hFunc.Write(dataToHash[:])
-		if err != nil {
-			return false, err
-		}
-		hramBin := hFunc.Sum(nil)
-		m = HashToInt(hramBin)
-	} else {
-		m = HashToInt(message)
+	// compute the hash of the message as an integer
+	hramBin, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
 	}
+	m := HashToInt(hramBin)
 
 	u1 := new(big.Int).Mul(m, sInv)
 	u1.Mod(u1, order)
@@ -0,0 +1,212 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip implements the BN254 precompiled contracts of Ethereum, with their
+// exact input parsing, error semantics and output encoding:
+//   - ecAdd (0x06) and ecMul (0x07), EIP-196: https://eips.ethereum.org/EIPS/eip-196
+//   - ecPairing (0x08), EIP-197: https://eips.ethereum.org/EIPS/eip-197
+//
+// The points of G1 are encoded as x||y and the points of G2 as x.A1||x.A0||y.A1||y.A0,
+// where each coordinate is a 32-byte big endian integer in [0, p-1]. The point at
+// infinity is encoded with zeroes.
+//
+// Gas accounting is left to the caller.
+package eip
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+const (
+	// SizeFp is the size in bytes of an encoded coordinate
+	SizeFp = fp.Bytes
+	// SizeG1 is the size in bytes of an encoded point of G1
+	SizeG1 = 2 * SizeFp
+	// SizeG2 is the size in bytes of an encoded point of G2
+	SizeG2 = 4 * SizeFp
+	// SizeScalar is the size in bytes of an encoded scalar
+	SizeScalar = 32
+
+	sizeAddInput     = 2 * SizeG1
+	sizeMulInput     = SizeG1 + SizeScalar
+	sizePairingInput = SizeG1 + SizeG2
+)
+
+var (
+	ErrInvalidInputLength  = errors.New("invalid input length")
+	ErrInvalidFieldElement = errors.New("coordinate exceeds the field modulus")
+	ErrPointNotOnCurve     = errors.New("point is not on the curve")
+	ErrPointNotInSubgroup  = errors.New("point is not in the prime order subgroup")
+)
+
+// ECAdd is the ecAdd precompile. The input is right padded with zeroes, or
+// truncated, to 128 bytes, encoding two points of G1. It returns the encoding
+// of their sum, on 64 bytes.
+func ECAdd(input []byte) ([]byte, error) {
+	input = rightPad(input, sizeAddInput)
+	var p, q bn254.G1Affine
+	if err := DecodeG1(&p, input[:SizeG1]); err != nil {
+		return nil, err
+	}
+	if err := DecodeG1(&q, input[SizeG1:]); err != nil {
+		return nil, err
+	}
+	var res bn254.G1Jac
+	res.FromAffine(&p)
+	res.AddMixed(&q)
+	p.FromJacobian(&res)
+	return EncodeG1(&p), nil
+}
+
+// ECMul is the ecMul precompile. The input is right padded with zeroes, or
+// truncated, to 96 bytes, encoding a point of G1 and a 32-byte big endian
+// scalar. It returns the encoding of the scalar multiple of the point, on 64
+// bytes.
+func ECMul(input []byte) ([]byte, error) {
+	input = rightPad(input, sizeMulInput)
+	var p bn254.G1Affine
+	if err := DecodeG1(&p, input[:SizeG1]); err != nil {
+		return nil, err
+	}
+	var s big.Int
+	s.SetBytes(input[SizeG1:sizeMulInput])
+	p.ScalarMultiplication(&p, &s)
+	return EncodeG1(&p), nil
+}
+
+// ECPairing is the ecPairing precompile. The input is a sequence of pairs
+// (P, Q) of points of G1 and G2, 192 bytes each. It returns 1 on 32 bytes if
+// ∏ e(P, Q) = 1, and 0 otherwise. The empty input returns 1.
+//
+// The points of G2 must be in the prime order subgroup.
+func ECPairing(input []byte) ([]byte, error) {
+	if len(input)%sizePairingInput != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizePairingInput
+	P := make([]bn254.G1Affine, 0, n)
+	Q := make([]bn254.G2Affine, 0, n)
+	for i := 0; i < n; i++ {
+		var p bn254.G1Affine
+		var q bn254.G2Affine
+		offset := i * sizePairingInput
+		if err := DecodeG1(&p, input[offset:offset+SizeG1]); err != nil {
+			return nil, err
+		}
+		if err := DecodeG2(&q, input[offset+SizeG1:offset+sizePairingInput]); err != nil {
+			return nil, err
+		}
+		// e(P, 0) = e(0, Q) = 1
+		if p.IsInfinity() || q.IsInfinity() {
+			continue
+		}
+		P = append(P, p)
+		Q = append(Q, q)
+	}
+
+	res := make([]byte, 32)
+	if len(P) == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	ok, err := bn254.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// EncodeG1 returns the encoding x||y of p, on 64 bytes.
+func EncodeG1(p *bn254.G1Affine) []byte {
+	res := make([]byte, SizeG1)
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	copy(res, x[:])
+	copy(res[SizeFp:], y[:])
+	return res
+}
+
+// DecodeG1 sets p from its encoding x||y, which must be a point of the curve
+// or (0, 0).
+func DecodeG1(p *bn254.G1Affine, buf []byte) error {
+	if len(buf) != SizeG1 {
+		return ErrInvalidInputLength
+	}
+	if err := decodeFp(&p.X, buf[:SizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&p.Y, buf[SizeFp:]); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+// EncodeG2 returns the encoding x.A1||x.A0||y.A1||y.A0 of p, on 128 bytes.
+func EncodeG2(p *bn254.G2Affine) []byte {
+	res := make([]byte, SizeG2)
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		b := e.Bytes()
+		copy(res[i*SizeFp:], b[:])
+	}
+	return res
+}
+
+// DecodeG2 sets p from its encoding x.A1||x.A0||y.A1||y.A0, which must be a
+// point of the prime order subgroup or (0, 0).
+func DecodeG2(p *bn254.G2Affine, buf []byte) error {
+	if len(buf) != SizeG2 {
+		return ErrInvalidInputLength
+	}
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		if err := decodeFp(e, buf[i*SizeFp:(i+1)*SizeFp]); err != nil {
+			return err
+		}
+	}
+	if p.IsInfinity() {
+		return nil
+	}
+	if !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	if !p.IsInSubGroup() {
+		return ErrPointNotInSubgroup
+	}
+	return nil
+}
+
+func decodeFp(z *fp.Element, buf []byte) error {
+	if err := z.SetBytesCanonical(buf); err != nil {
+		return ErrInvalidFieldElement
+	}
+	return nil
+}
+
+// rightPad returns buf right padded with zeroes, or truncated, to size bytes.
+func rightPad(buf []byte, size int) []byte {
+	if len(buf) >= size {
+		return buf[:size]
+	}
+	res := make([]byte, size)
+	copy(res, buf)
+	return res
+}
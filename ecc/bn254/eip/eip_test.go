@@ -0,0 +1,263 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVectors(t *testing.T) {
+	assert := require.New(t)
+
+	// chfast1 vectors of go-ethereum's core/vm/testdata/precompiles
+	out, err := ECAdd(decodeHex(t, "18b18acfb4c2c30276db5411368e7185b311dd124691610c5d3b74034e093dc9063c909c4720840cb5134cb9f59fa749755796819658d32efc0d288198f3726607c2b7f58a84bd6145f00c9c2bc0bb1a187f20ff2c92963a88019e7c6a014eed06614e20c147e940f2d70da3f74c9a17df361706a4485c742bd6788478fa17d7"))
+	assert.NoError(err)
+	assert.Equal("2243525c5efd4b9c3d3c45ac0ca3fe4dd85e830a4ce6b65fa1eeaee202839703301d1d33be6da8e509df21cc35964723180eed7532537db9ae5e7d48f195c915", hex.EncodeToString(out))
+
+	out, err = ECMul(decodeHex(t, "2bd3e6d0f3b142924f5ca7b49ce5b9d54c4703d7ae5648e61d02268b1a0a9fb721611ce0a6af85915e2f1d70300909ce2e49dfad4a4619c8390cae66cefdb20400000000000000000000000000000000000000000000000011138ce750fa15c2"))
+	assert.NoError(err)
+	assert.Equal("070a8d6a982153cae4be29d434e8faef8a47b274a053f5a4ee2a6c9c13c31e5c031b8ce914eba3a9ffb989f9cdd5b0f01943074bf4f0f315690ec3cec6981afc", hex.EncodeToString(out))
+}
+
+// gethVector is the format of the test vectors of go-ethereum's precompiles
+type gethVector struct {
+	Input         string
+	Expected      string
+	ExpectedError string
+	Name          string
+}
+
+// TestGethVectors runs the test vectors of go-ethereum's precompiles, copied
+// from core/vm/testdata/precompiles of go-ethereum v1.17.7 into testdata.
+// go-ethereum has no failure vectors for these precompiles.
+func TestGethVectors(t *testing.T) {
+	precompiles := map[string]func([]byte) ([]byte, error){
+		"bn256Add.json":       ECAdd,
+		"bn256ScalarMul.json": ECMul,
+		"bn256Pairing.json":   ECPairing,
+	}
+	for file, precompile := range precompiles {
+		data, err := os.ReadFile(filepath.Join("testdata", file))
+		require.NoError(t, err, "missing test vectors")
+		var vectors []gethVector
+		require.NoError(t, json.Unmarshal(data, &vectors))
+		require.NotEmpty(t, vectors, file)
+		for _, v := range vectors {
+			out, err := precompile(decodeHex(t, v.Input))
+			if v.ExpectedError != "" {
+				require.Error(t, err, v.Name)
+				continue
+			}
+			require.NoError(t, err, v.Name)
+			require.Equal(t, v.Expected, hex.EncodeToString(out), v.Name)
+		}
+	}
+}
+
+func randomG1(t *testing.T) (bn254.G1Affine, big.Int) {
+	var s fr.Element
+	_, err := s.SetRandom()
+	require.NoError(t, err)
+	var k big.Int
+	s.BigInt(&k)
+	var p bn254.G1Affine
+	p.ScalarMultiplicationBase(&k)
+	return p, k
+}
+
+func TestECAdd(t *testing.T) {
+	assert := require.New(t)
+
+	p, _ := randomG1(t)
+	q, _ := randomG1(t)
+	var expected bn254.G1Affine
+	expected.Add(&p, &q)
+
+	out, err := ECAdd(append(EncodeG1(&p), EncodeG1(&q)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// P + P
+	expected.Add(&p, &p)
+	out, err = ECAdd(append(EncodeG1(&p), EncodeG1(&p)...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// P - P = 0
+	var neg bn254.G1Affine
+	neg.Neg(&p)
+	out, err = ECAdd(append(EncodeG1(&p), EncodeG1(&neg)...))
+	assert.NoError(err)
+	assert.Equal(make([]byte, SizeG1), out)
+
+	// the input is padded with zeroes, and the extra bytes are ignored
+	out, err = ECAdd(EncodeG1(&p))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&p), out)
+	out, err = ECAdd(nil)
+	assert.NoError(err)
+	assert.Equal(make([]byte, SizeG1), out)
+	out, err = ECAdd(append(append(EncodeG1(&p), make([]byte, SizeG1)...), 0xff))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&p), out)
+
+	// invalid points
+	invalid := EncodeG1(&p)
+	invalid[SizeG1-1] ^= 1
+	_, err = ECAdd(invalid)
+	assert.ErrorIs(err, ErrPointNotOnCurve)
+
+	invalid = EncodeG1(&p)
+	fp.Modulus().FillBytes(invalid[:SizeFp])
+	_, err = ECAdd(invalid)
+	assert.ErrorIs(err, ErrInvalidFieldElement)
+}
+
+func TestECMul(t *testing.T) {
+	assert := require.New(t)
+
+	p, _ := randomG1(t)
+	_, k := randomG1(t)
+
+	var expected bn254.G1Affine
+	expected.ScalarMultiplication(&p, &k)
+	scalar := make([]byte, SizeScalar)
+	k.FillBytes(scalar)
+	out, err := ECMul(append(EncodeG1(&p), scalar...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// the scalar is not reduced
+	k.Add(&k, fr.Modulus())
+	k.FillBytes(scalar)
+	out, err = ECMul(append(EncodeG1(&p), scalar...))
+	assert.NoError(err)
+	assert.Equal(EncodeG1(&expected), out)
+
+	// [r]P = 0
+	fr.Modulus().FillBytes(scalar)
+	out, err = ECMul(append(EncodeG1(&p), scalar...))
+	assert.NoError(err)
+	assert.Equal(make([]byte, SizeG1), out)
+
+	// missing scalar
+	out, err = ECMul(EncodeG1(&p))
+	assert.NoError(err)
+	assert.Equal(make([]byte, SizeG1), out)
+}
+
+// nonSubgroupG2 returns a point on the twist which is not in G2
+func nonSubgroupG2(t *testing.T) bn254.G2Affine {
+	_, _, _, g2 := bn254.Generators()
+	// b' = y² - x³
+	var b, x3 bn254.E2
+	b.Square(&g2.Y)
+	x3.Square(&g2.X).Mul(&x3, &g2.X)
+	b.Sub(&b, &x3)
+
+	var p bn254.G2Affine
+	for {
+		_, err := p.X.A0.SetRandom()
+		require.NoError(t, err)
+		var y2 bn254.E2
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &b)
+		if y2.Legendre() == 1 {
+			p.Y.Sqrt(&y2)
+			break
+		}
+	}
+	require.True(t, p.IsOnCurve())
+	require.False(t, p.IsInSubGroup())
+	return p
+}
+
+func TestECPairing(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := bn254.Generators()
+	var a, b fr.Element
+	a.SetRandom()
+	b.SetRandom()
+	var ab fr.Element
+	ab.Mul(&a, &b).Neg(&ab)
+
+	// e([a]g1, [b]g2)⋅e([-ab]g1, g2) = 1
+	var p1, p2 bn254.G1Affine
+	var q1 bn254.G2Affine
+	p1.ScalarMultiplication(&g1, a.BigInt(new(big.Int)))
+	q1.ScalarMultiplication(&g2, b.BigInt(new(big.Int)))
+	p2.ScalarMultiplication(&g1, ab.BigInt(new(big.Int)))
+
+	var input []byte
+	input = append(input, EncodeG1(&p1)...)
+	input = append(input, EncodeG2(&q1)...)
+	input = append(input, EncodeG1(&p2)...)
+	input = append(input, EncodeG2(&g2)...)
+
+	one := make([]byte, 32)
+	one[31] = 1
+	out, err := ECPairing(input)
+	assert.NoError(err)
+	assert.Equal(one, out)
+
+	// e([a]g1, [b]g2) ≠ 1
+	out, err = ECPairing(input[:sizePairingInput])
+	assert.NoError(err)
+	assert.Equal(make([]byte, 32), out)
+
+	// the empty product and the points at infinity
+	out, err = ECPairing(nil)
+	assert.NoError(err)
+	assert.Equal(one, out)
+	out, err = ECPairing(make([]byte, sizePairingInput))
+	assert.NoError(err)
+	assert.Equal(one, out)
+
+	// encoding of G2
+	var decoded bn254.G2Affine
+	assert.NoError(DecodeG2(&decoded, EncodeG2(&q1)))
+	assert.True(decoded.Equal(&q1))
+	assert.True(bytes.Equal(EncodeG2(&g2)[:SizeFp], g2.X.A1.Marshal()), "x.A1 must come first")
+
+	// errors
+	_, err = ECPairing(input[:sizePairingInput-1])
+	assert.ErrorIs(err, ErrInvalidInputLength)
+
+	q := nonSubgroupG2(t)
+	_, err = ECPairing(append(EncodeG1(&p1), EncodeG2(&q)...))
+	assert.ErrorIs(err, ErrPointNotInSubgroup)
+
+	invalid := EncodeG2(&q1)
+	invalid[SizeG2-1] ^= 1
+	_, err = ECPairing(append(EncodeG1(&p1), invalid...))
+	assert.ErrorIs(err, ErrPointNotOnCurve)
+}
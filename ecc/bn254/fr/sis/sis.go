@@ -18,7 +18,9 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"hash"
+	"io"
 	"math/big"
 	"math/bits"
 
@@ -33,6 +35,52 @@ var (
 	ErrNotAPowerOfTwo = errors.New("d must be a power of 2")
 )
 
+const (
+	// maxLogTwoDegree is the largest degree, the shift of the domain being a 2ᵈ⁺¹-th
+	// root of unity in the 2²⁸-th roots of unity
+	maxLogTwoDegree = 27
+
+	// maxLogTwoBound is the largest bound, the limbs being set in the first word of the elements
+	maxLogTwoBound = 64
+
+	// maxReadKeySize is the largest number of coefficients of the key accepted by ReadFrom
+	maxReadKeySize = 1 << 24
+)
+
+// Params are the parameters of a ring-SIS instance.
+type Params struct {
+	// LogTwoDegree, the ring is ℤ_{p}[X]/Xᵈ+1 with d = 2^LogTwoDegree
+	LogTwoDegree int
+
+	// LogTwoBound, the hashed vector is decomposed in limbs of LogTwoBound bits
+	LogTwoBound int
+}
+
+// Presets of parameters over the scalar field of bn254, the ones of the benchmarks of the
+// package, chosen in https://hackmd.io/7OODKWQZRRW9RxM5BaXtIw. Their security level is not
+// estimated by this package: it depends on the number of polynomials of the key, and must
+// be checked for each use (e.g. with the lattice estimator).
+// Larger degrees mean fewer, larger polynomials in the key and larger digests;
+// larger bounds mean fewer limbs to hash.
+var (
+	ParamsDegree8   = Params{LogTwoDegree: 3, LogTwoBound: 2}
+	ParamsDegree16  = Params{LogTwoDegree: 4, LogTwoBound: 4}
+	ParamsDegree32  = Params{LogTwoDegree: 5, LogTwoBound: 6}
+	ParamsDegree64  = Params{LogTwoDegree: 6, LogTwoBound: 10}
+	ParamsDegree128 = Params{LogTwoDegree: 7, LogTwoBound: 16}
+	ParamsDegree256 = Params{LogTwoDegree: 8, LogTwoBound: 32}
+)
+
+// Presets lists the presets of parameters.
+var Presets = []Params{
+	ParamsDegree8,
+	ParamsDegree16,
+	ParamsDegree32,
+	ParamsDegree64,
+	ParamsDegree128,
+	ParamsDegree256,
+}
+
 // Ring-SIS instance
 type RSis struct {
 
@@ -74,30 +122,51 @@ type RSis struct {
 // used to derived n, the number of polynomials in A, and max size of instance's internal buffer.
 func NewRSis(seed int64, logTwoDegree, logTwoBound, maxNbElementsToHash int) (*RSis, error) {
 
-	if logTwoBound > 64 {
+	r, err := newRSis(logTwoDegree, logTwoBound, maxNbElementsToHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// filling A
+	parallel.Execute(len(r.A), func(start, end int) {
+		var buf bytes.Buffer
+		for i := start; i < end; i++ {
+			for j := 0; j < r.Degree; j++ {
+				r.A[i][j] = genRandom(seed, int64(i), int64(j), &buf)
+			}
+
+			// fill Ag the evaluation form of the polynomials in A on the coset √(g) * <g>
+			copy(r.Ag[i], r.A[i])
+			r.Domain.FFT(r.Ag[i], fft.DIF, fft.OnCoset())
+		}
+	})
+	// TODO @gbotrel add nbtasks here; whiile in tests it's more convenient to paralellize
+	// in tensor-commitment we may not want to do that.
+
+	return r, nil
+}
+
+// NewRSisWithParams creates an instance of RSis from parameters, see NewRSis.
+func NewRSisWithParams(seed int64, params Params, maxNbElementsToHash int) (*RSis, error) {
+	return NewRSis(seed, params.LogTwoDegree, params.LogTwoBound, maxNbElementsToHash)
+}
+
+// newRSis allocates an instance of RSis, without filling the key.
+func newRSis(logTwoDegree, logTwoBound, maxNbElementsToHash int) (*RSis, error) {
+
+	if logTwoBound > maxLogTwoBound {
 		return nil, errors.New("logTwoBound too large")
 	}
+	if logTwoBound < 1 || logTwoDegree < 0 || logTwoDegree > maxLogTwoDegree || maxNbElementsToHash < 0 {
+		return nil, errors.New("invalid parameters")
+	}
 	if bits.UintSize == 32 {
 		return nil, errors.New("unsupported architecture; need 64bit target")
 	}
 
 	degree := 1 << logTwoDegree
 	capacity := maxNbElementsToHash * fr.Bytes
-
-	// n: number of polynomials in A
-	// len(m) == degree * n
-	// with each element in m being logTwoBounds bits from the instance buffer.
-	// that is, to fill m, we need [degree * n * logTwoBound] bits of data
-	// capacity == [degree * n * logTwoBound] / 8
-	// n == (capacity*8)/(degree*logTwoBound)
-
-	n := capacity * 8 / logTwoBound // number of coefficients
-	if n%degree == 0 {
-		n /= degree
-	} else {
-		n /= degree // number of polynomials
-		n++
-	}
+	n := nbPolynomials(logTwoDegree, logTwoBound, maxNbElementsToHash)
 
 	// domains (shift is √{gen} )
 	var shift fr.Element
@@ -118,29 +187,36 @@ func NewRSis(seed int64, logTwoDegree, logTwoBound, maxNbElementsToHash int) (*R
 		maxNbElementsToHash: maxNbElementsToHash,
 	}
 
-	// filling A
 	a := make([]fr.Element, n*r.Degree)
 	ag := make([]fr.Element, n*r.Degree)
+	for i := 0; i < n; i++ {
+		rstart, rend := i*r.Degree, (i+1)*r.Degree
+		r.A[i] = a[rstart:rend:rend]
+		r.Ag[i] = ag[rstart:rend:rend]
+	}
 
-	parallel.Execute(n, func(start, end int) {
-		var buf bytes.Buffer
-		for i := start; i < end; i++ {
-			rstart, rend := i*r.Degree, (i+1)*r.Degree
-			r.A[i] = a[rstart:rend:rend]
-			r.Ag[i] = ag[rstart:rend:rend]
-			for j := 0; j < r.Degree; j++ {
-				r.A[i][j] = genRandom(seed, int64(i), int64(j), &buf)
-			}
+	return r, nil
+}
 
-			// fill Ag the evaluation form of the polynomials in A on the coset √(g) * <g>
-			copy(r.Ag[i], r.A[i])
-			r.Domain.FFT(r.Ag[i], fft.DIF, fft.OnCoset())
-		}
-	})
-	// TODO @gbotrel add nbtasks here; whiile in tests it's more convenient to paralellize
-	// in tensor-commitment we may not want to do that.
+// nbPolynomials returns n, the number of polynomials in A.
+func nbPolynomials(logTwoDegree, logTwoBound, maxNbElementsToHash int) int {
+	degree := 1 << logTwoDegree
+	capacity := maxNbElementsToHash * fr.Bytes
 
-	return r, nil
+	// len(m) == degree * n
+	// with each element in m being logTwoBounds bits from the instance buffer.
+	// that is, to fill m, we need [degree * n * logTwoBound] bits of data
+	// capacity == [degree * n * logTwoBound] / 8
+	// n == (capacity*8)/(degree*logTwoBound)
+
+	n := capacity * 8 / logTwoBound // number of coefficients
+	if n%degree == 0 {
+		n /= degree
+	} else {
+		n /= degree // number of polynomials
+		n++
+	}
+	return n
 }
 
 func (r *RSis) Write(p []byte) (n int, err error) {
@@ -160,20 +236,156 @@ func (r *RSis) Sum(b []byte) []byte {
 	}
 
 	// clear the buffers of the instance.
-	defer func() {
-		r.bufMValues.ClearAll()
-		for i := 0; i < len(r.bufM); i++ {
-			r.bufM[i].SetZero()
-		}
-		for i := 0; i < len(r.bufRes); i++ {
-			r.bufRes[i].SetZero()
-		}
-	}()
+	defer r.cleanupBuffers()
 
 	// bitwise decomposition of the buffer, in order to build m (the vector to hash)
 	// as a list of polynomials, whose coefficients are less than r.B bits long.
-	// Say buf=[0xbe,0x0f]. As a stream of bits it is interpreted like this:
-	// 10111110 00001111. BitAt(0)=1 (=leftmost bit), bitAt(1)=0 (=second leftmost bit), etc.
+	limbDecomposeBytes(buf, r.bufM, r.LogTwoBound, r.Degree, r.bufMValues)
+
+	// we can hash now.
+	r.innerHash(r.bufM, r.bufMValues, r.bufRes)
+
+	resBytes, err := r.bufRes.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	return append(b, resBytes[4:]...) // first 4 bytes are uint32(len(res))
+}
+
+// Hash returns the hash of the field elements. It is equivalent to writing
+// the big endian encodings of the elements and calling Sum, without the conversions
+// from and to bytes of the result. It does not use nor change the hash state.
+func (r *RSis) Hash(v []fr.Element) ([]fr.Element, error) {
+	if len(v) > r.maxNbElementsToHash {
+		return nil, fmt.Errorf("can't hash more than %d elements with params provided in constructor", r.maxNbElementsToHash)
+	}
+
+	defer r.cleanupBuffers()
+
+	limbDecomposeElements(v, r.bufM, r.LogTwoBound, r.Degree, r.bufMValues)
+	res := make([]fr.Element, r.Degree)
+	r.innerHash(r.bufM, r.bufMValues, res)
+
+	return res, nil
+}
+
+// HashLimbs returns Σᵢ A[i]*m[i] mod Xᵈ+1 where m[i] = limbs[i*d:(i+1)*d] (padded
+// with zeroes). Unlike Hash, the limbs are not decomposed: the map is linear,
+// HashLimbs(a+b) = HashLimbs(a)+HashLimbs(b), which allows hashing inputs which are
+// already decomposed, or linear combinations of them. The binding property of the hash
+// only holds for limbs of less than LogTwoBound bits, as returned by LimbDecompose.
+func (r *RSis) HashLimbs(limbs []fr.Element) ([]fr.Element, error) {
+	if len(limbs) > len(r.bufM) {
+		return nil, fmt.Errorf("can't hash more than %d limbs with params provided in constructor", len(r.bufM))
+	}
+
+	defer r.cleanupBuffers()
+
+	copy(r.bufM, limbs)
+	for i := 0; i < len(limbs); i++ {
+		if !limbs[i].IsZero() {
+			r.bufMValues.Set(uint(i / r.Degree))
+		}
+	}
+	res := make([]fr.Element, r.Degree)
+	r.innerHash(r.bufM, r.bufMValues, res)
+
+	return res, nil
+}
+
+// LimbDecompose returns the limbs hashed by Hash(v), such that
+// HashLimbs(LimbDecompose(v)) = Hash(v). Each limb holds LogTwoBound bits of the
+// input, set in the first word of its (Montgomery) representation.
+func (r *RSis) LimbDecompose(v []fr.Element) []fr.Element {
+	nbBits := len(v) * fr.Bytes * 8
+	nbLimbs := (nbBits + r.LogTwoBound - 1) / r.LogTwoBound
+	nbLimbs = (nbLimbs + r.Degree - 1) / r.Degree * r.Degree
+	res := make([]fr.Element, nbLimbs)
+	limbDecomposeElements(v, res, r.LogTwoBound, r.Degree, nil)
+	return res
+}
+
+// HashBatch returns the hashes of several vectors of field elements, Hash(inputs[i]).
+// The key is read once per batch instead of once per input: for each polynomial A[i],
+// the i-th block of limbs of every input is decomposed, transformed and accumulated,
+// then each hash is inverse transformed once. The limbs are decomposed block by block,
+// so that the memory used does not depend on the size of the key.
+// It does not use nor change the hash state.
+func (r *RSis) HashBatch(inputs [][]fr.Element) ([][]fr.Element, error) {
+	for i := range inputs {
+		if len(inputs[i]) > r.maxNbElementsToHash {
+			return nil, fmt.Errorf("can't hash more than %d elements with params provided in constructor", r.maxNbElementsToHash)
+		}
+	}
+
+	res := make([][]fr.Element, len(inputs))
+	hashes := make([]fr.Element, len(inputs)*r.Degree)
+	parallel.Execute(len(inputs), func(start, end int) {
+		encodings := make([][]byte, end-start)
+		for j := start; j < end; j++ {
+			res[j] = hashes[j*r.Degree : (j+1)*r.Degree : (j+1)*r.Degree]
+			encodings[j-start] = make([]byte, 0, len(inputs[j])*fr.Bytes)
+			for k := range inputs[j] {
+				b := inputs[j][k].Bytes()
+				encodings[j-start] = append(encodings[j-start], b[:]...)
+			}
+		}
+
+		// one pass over the key for the batch
+		k := make(fr.Vector, r.Degree)
+		for i := range r.Ag {
+			for j := start; j < end; j++ {
+				if !limbDecomposeBlock(encodings[j-start], k, r.LogTwoBound, i*r.Degree) {
+					// FFT(0) = 0
+					continue
+				}
+				r.Domain.FFT(k, fft.DIF, fft.OnCoset(), fft.WithNbTasks(1))
+				mulModAcc(res[j], r.Ag[i], k)
+			}
+		}
+
+		for j := start; j < end; j++ {
+			r.Domain.FFTInverse(res[j], fft.DIT, fft.OnCoset(), fft.WithNbTasks(1)) // -> reduces mod Xᵈ+1
+		}
+	})
+
+	return res, nil
+}
+
+// innerHash sets res to Σᵢ A[i]*m[i] mod Xᵈ+1, skipping the polynomials m[i]
+// which are not marked in mValues. m is modified. res must be zeroed.
+func (r *RSis) innerHash(m fr.Vector, mValues *bitset.BitSet, res []fr.Element) {
+	for i := 0; i < len(r.Ag); i++ {
+		if !mValues.Test(uint(i)) {
+			// means m[i*r.Degree : (i+1)*r.Degree] == [0...0]
+			// we can skip this, FFT(0) = 0
+			continue
+		}
+		k := m[i*r.Degree : (i+1)*r.Degree]
+		r.Domain.FFT(k, fft.DIF, fft.OnCoset(), fft.WithNbTasks(1))
+		mulModAcc(res, r.Ag[i], k)
+	}
+	r.Domain.FFTInverse(res, fft.DIT, fft.OnCoset(), fft.WithNbTasks(1)) // -> reduces mod Xᵈ+1
+}
+
+// cleanupBuffers clears the buffers of the instance.
+func (r *RSis) cleanupBuffers() {
+	r.bufMValues.ClearAll()
+	for i := 0; i < len(r.bufM); i++ {
+		r.bufM[i].SetZero()
+	}
+	for i := 0; i < len(r.bufRes); i++ {
+		r.bufRes[i].SetZero()
+	}
+}
+
+// limbDecomposeBytes decomposes buf in limbs of logTwoBound bits, stored in m.
+// Say buf=[0xbe,0x0f]. As a stream of bits it is interpreted like this:
+// 10111110 00001111. BitAt(0)=1 (=leftmost bit), bitAt(1)=0 (=second leftmost bit), etc.
+// Each limb is set from LSB to MSB in the first word of the element.
+// If mValues is not nil, the polynomials m[k*degree:(k+1)*degree] which are not zero are marked.
+func limbDecomposeBytes(buf []byte, m fr.Vector, logTwoBound, degree int, mValues *bitset.BitSet) {
 	nbBits := len(buf) * 8
 	bitAt := func(i int) uint8 {
 		k := i / 8
@@ -185,51 +397,150 @@ func (r *RSis) Sum(b []byte) []byte {
 		return b >> (7 - j) & 1
 	}
 
-	// now we can construct m. The input to hash consists of the polynomials
-	// m[k*r.Degree:(k+1)*r.Degree]
-	m := r.bufM
-
-	// mark blocks m[i*r.Degree : (i+1)*r.Degree] != [0...0]
-	mValues := r.bufMValues
-
-	// we process the input buffer by blocks of r.LogTwoBound bits
+	// we process the input buffer by blocks of logTwoBound bits
 	// each of these block (<< 64bits) are interpreted as a coefficient
 	mPos := 0
 	for i := 0; i < nbBits; mPos++ {
-		for j := 0; j < r.LogTwoBound; j++ {
-			// r.LogTwoBound < 64; we just use the first word of our element here,
+		for j := 0; j < logTwoBound; j++ {
+			// logTwoBound < 64; we just use the first word of our element here,
 			// and set the bits from LSB to MSB.
-			m[mPos][0] |= uint64(bitAt(i) << j)
+			m[mPos][0] |= uint64(bitAt(i)) << j
 			i++
 		}
-		if m[mPos][0] == 0 {
+		if m[mPos][0] == 0 || mValues == nil {
 			continue
 		}
-		mValues.Set(uint(mPos / r.Degree))
+		mValues.Set(uint(mPos / degree))
 	}
+}
 
-	// we can hash now.
-	res := r.bufRes
+// limbDecomposeBlock sets m to the limbs [offset, offset+len(m)) of the decomposition
+// of buf by limbDecomposeBytes, and reports whether one of them is not zero.
+func limbDecomposeBlock(buf []byte, m fr.Vector, logTwoBound, offset int) bool {
+	nonZero := false
+	i := offset * logTwoBound
+	for mPos := range m {
+		var limb uint64
+		for j := 0; j < logTwoBound; j, i = j+1, i+1 {
+			k := i / 8
+			if k >= len(buf) {
+				break
+			}
+			limb |= uint64(buf[k]>>(7-i%8)&1) << j
+		}
+		m[mPos] = fr.Element{limb}
+		nonZero = nonZero || limb != 0
+	}
+	return nonZero
+}
 
-	// method 1: fft
-	for i := 0; i < len(r.Ag); i++ {
-		if !mValues.Test(uint(i)) {
-			// means m[i*r.Degree : (i+1)*r.Degree] == [0...0]
-			// we can skip this, FFT(0) = 0
-			continue
+// limbDecomposeElements decomposes the big endian encoding of v as limbDecomposeBytes.
+func limbDecomposeElements(v []fr.Element, m fr.Vector, logTwoBound, degree int, mValues *bitset.BitSet) {
+	buf := make([]byte, 0, len(v)*fr.Bytes)
+	for i := range v {
+		b := v[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+	limbDecomposeBytes(buf, m, logTwoBound, degree, mValues)
+}
+
+// WriteTo writes the parameters and the key of the instance to w. The key is
+// written in both coefficient (A) and evaluation (Ag) forms.
+func (r *RSis) WriteTo(w io.Writer) (int64, error) {
+	header := []uint64{
+		uint64(bits.TrailingZeros(uint(r.Degree))),
+		uint64(r.LogTwoBound),
+		uint64(r.maxNbElementsToHash),
+	}
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return 0, err
+	}
+	n := int64(8 * len(header))
+
+	for _, key := range [][][]fr.Element{r.A, r.Ag} {
+		for i := range key {
+			v := fr.Vector(key[i])
+			m, err := v.WriteTo(w)
+			n += m
+			if err != nil {
+				return n, err
+			}
 		}
-		k := m[i*r.Degree : (i+1)*r.Degree]
-		r.Domain.FFT(k, fft.DIF, fft.OnCoset(), fft.WithNbTasks(1))
-		mulModAcc(res, r.Ag[i], k)
 	}
-	r.Domain.FFTInverse(res, fft.DIT, fft.OnCoset(), fft.WithNbTasks(1)) // -> reduces mod Xᵈ+1
 
-	resBytes, err := res.MarshalBinary()
+	return n, nil
+}
+
+// ReadFrom reads an instance written by WriteTo from reader, and sets r to it.
+// The key must have at most 2²⁴ coefficients, and Ag must be the evaluation form of A.
+func (r *RSis) ReadFrom(reader io.Reader) (int64, error) {
+	var header [3]uint64
+	if err := binary.Read(reader, binary.BigEndian, &header); err != nil {
+		return 0, err
+	}
+	n := int64(8 * len(header))
+
+	// check the parameters before allocating the instance
+	if header[0] > maxLogTwoDegree || header[1] < 1 || header[1] > maxLogTwoBound || header[2] > maxReadKeySize {
+		return n, errors.New("invalid parameters")
+	}
+	logTwoDegree, logTwoBound, maxNbElementsToHash := int(header[0]), int(header[1]), int(header[2])
+	if nbPolynomials(logTwoDegree, logTwoBound, maxNbElementsToHash)<<logTwoDegree > maxReadKeySize {
+		return n, errors.New("invalid parameters: key too large")
+	}
+	res, err := newRSis(logTwoDegree, logTwoBound, maxNbElementsToHash)
 	if err != nil {
-		panic(err)
+		return n, err
 	}
 
-	return append(b, resBytes[4:]...) // first 4 bytes are uint32(len(res))
+	for _, key := range [][][]fr.Element{res.A, res.Ag} {
+		for i := range key {
+			m, err := readPolynomial(reader, key[i])
+			n += m
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+
+	// the hashes are computed with Ag, check that it matches A
+	ag := make(fr.Vector, res.Degree)
+	for i := range res.A {
+		copy(ag, res.A[i])
+		res.Domain.FFT(ag, fft.DIF, fft.OnCoset())
+		for j := range ag {
+			if !ag[j].Equal(&res.Ag[i][j]) {
+				return n, errors.New("invalid key: Ag is not the evaluation form of A")
+			}
+		}
+	}
+
+	*r = *res
+	return n, nil
+}
+
+// readPolynomial reads a polynomial written by fr.Vector.WriteTo in p, which must have
+// the same size.
+func readPolynomial(reader io.Reader, p []fr.Element) (int64, error) {
+	var buf [fr.Bytes]byte
+	if read, err := io.ReadFull(reader, buf[:4]); err != nil {
+		return int64(read), err
+	}
+	n := int64(4)
+	if binary.BigEndian.Uint32(buf[:4]) != uint32(len(p)) {
+		return n, errors.New("invalid key: polynomial of wrong degree")
+	}
+	for i := range p {
+		read, err := io.ReadFull(reader, buf[:])
+		n += int64(read)
+		if err != nil {
+			return n, err
+		}
+		if p[i], err = fr.BigEndian.Element(&buf); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
 }
 
 // Reset resets the Hash to its initial state.
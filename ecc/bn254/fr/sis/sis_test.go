@@ -19,7 +19,6 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"math/bits"
 	"testing"
@@ -277,111 +276,213 @@ func benchmarkSIS(b *testing.B, input []fr.Element, sparse bool, logTwoBound, lo
 	})
 }
 
-// Hash interprets the input vector as a sequence of coefficients of size r.LogTwoBound bits long,
-// and return the hash of the polynomial corresponding to the sum sum_i A[i]*m Mod X^{d}+1
-//
-// It is equivalent to calling r.Write(element.Marshal()); outBytes = r.Sum(nil);
-// ! note @gbotrel: this is a place holder, may not make sense
-func (r *RSis) Hash(v []fr.Element) ([]fr.Element, error) {
-	if len(v) > r.maxNbElementsToHash {
-		return nil, fmt.Errorf("can't hash more than %d elements with params provided in constructor", r.maxNbElementsToHash)
+func BenchmarkHashBatch(b *testing.B) {
+	const nbInputs, inputSize = 256, 256
+
+	inputs := make([][]fr.Element, nbInputs)
+	for i := range inputs {
+		inputs[i] = make([]fr.Element, inputSize)
+		for j := range inputs[i] {
+			inputs[i][j].SetRandom()
+		}
 	}
 
-	r.Reset()
-	for _, e := range v {
-		r.Write(e.Marshal())
+	for _, param := range Presets {
+		instance, err := NewRSisWithParams(0, param, inputSize)
+		if err != nil {
+			b.Fatal(err)
+		}
+		name := fmt.Sprintf("inputs=%v/log2-bound=%v/log2-degree=%v", nbInputs, param.LogTwoBound, param.LogTwoDegree)
+
+		b.Run(name+"/hash", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range inputs {
+					if _, err := instance.Hash(inputs[j]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(name+"/hash-batch", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := instance.HashBatch(inputs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
-	sum := r.Sum(nil)
-	var rlen [4]byte
-	binary.BigEndian.PutUint32(rlen[:], uint32(len(sum)/fr.Bytes))
-	reader := io.MultiReader(bytes.NewReader(rlen[:]), bytes.NewReader(sum))
-	var result fr.Vector
-	_, err := result.ReadFrom(reader)
-	if err != nil {
-		return nil, err
+}
+
+func TestPresets(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(len(params128Bits), len(Presets))
+	for i, p := range Presets {
+		assert.Equal(params128Bits[i].logTwoDegree, p.LogTwoDegree)
+		assert.Equal(params128Bits[i].logTwoBound, p.LogTwoBound)
+
+		sis, err := NewRSisWithParams(5, p, 4)
+		assert.NoError(err)
+		assert.Equal(1<<p.LogTwoDegree, sis.Degree)
+		assert.Equal(p.LogTwoBound, sis.LogTwoBound)
 	}
-	return result, nil
 }
 
-// Hash version without mem copy
-// // Hash interprets the input vector as a sequence of coefficients of size r.LogTwoBound bits long,
-// // and return the hash of the polynomial corresponding to the sum sum_i A[i]*m Mod X^{d}+1
-// //
-// // It is equivalent to calling r.Write(element.Marshal()); outBytes = r.Sum(nil);
-// func (r *RSis) Hash(v []fr.Element) ([]fr.Element, error) {
-// 	if len(v) > r.maxNbElementsToHash {
-// 		return nil, fmt.Errorf("can't hash more than %d elements with params provided in constructor", r.maxNbElementsToHash)
-// 	}
-
-// 	// clear the buffers of the instance.
-// 	defer func() {
-// 		r.bufMValues.ClearAll()
-// 		for i := 0; i < len(r.bufM); i++ {
-// 			r.bufM[i].SetZero()
-// 		}
-// 	}()
-
-// 	// bitwise decomposition of the buffer, in order to build m (the vector to hash)
-// 	// as a list of polynomials, whose coefficients are less than r.B bits long.
-
-// 	bitAt := func(v []fr.Element, i int) uint8 {
-// 		// v --> slice of bits
-// 		// return bit at position i
-// 		const n = fr.Bytes * 8 // nb bits per element
-// 		nbBits := len(v) * n
-
-// 		if i >= nbBits {
-// 			return 0
-// 		}
-
-// 		eIndex := i / n
-// 		i %= n
-
-// 		// we want bit i of v[eIndex]
-// 		j := i / 64
-// 		return uint8(v[eIndex][j] >> (i % 64) & 1)
-
-// 	}
-
-// 	// now we can construct m. The input to hash consists of the polynomials
-// 	// m[k*r.Degree:(k+1)*r.Degree]
-// 	m := r.bufM
-
-// 	// mark blocks m[i*r.Degree : (i+1)*r.Degree] != [0...0]
-// 	mValues := r.bufMValues
-
-// 	// we process the input buffer by blocks of r.LogTwoBound bits
-// 	// each of these block (<< 64bits) are interpreted as a coefficient
-// 	mPos := 0
-// 	nbBits := len(v) * fr.Bytes * 8
-// 	for i := 0; i < nbBits; mPos++ {
-// 		for j := 0; j < r.LogTwoBound; j++ {
-// 			// r.LogTwoBound < 64; we just use the first word of our element here,
-// 			// and set the bits from LSB to MSB.
-// 			m[mPos][0] |= uint64(bitAt(v, i) << j)
-// 			i++
-// 		}
-// 		if m[mPos][0] == 0 {
-// 			continue
-// 		}
-// 		mValues.Set(uint(mPos / r.Degree))
-// 	}
-
-// 	// we can hash now.
-// 	res := make(fr.Vector, r.Degree)
-
-// 	// method 1: fft
-// 	for i := 0; i < len(r.Ag); i++ {
-// 		if !mValues.Test(uint(i)) {
-// 			// means m[i*r.Degree : (i+1)*r.Degree] == [0...0]
-// 			// we can skip this, FFT(0) = 0
-// 			continue
-// 		}
-// 		k := m[i*r.Degree : (i+1)*r.Degree]
-// 		r.Domain.FFT(k, fft.DIF, fft.OnCoset(), fft.WithNbTasks(1))
-// 		mulModAcc(res, r.Ag[i], k)
-// 	}
-// 	r.Domain.FFTInverse(res, fft.DIT, fft.OnCoset(), fft.WithNbTasks(1)) // -> reduces mod Xᵈ+1
-
-// 	return res, nil
-// }
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	for _, p := range Presets {
+		sis, err := NewRSisWithParams(5, p, 8)
+		assert.NoError(err)
+
+		in := make([]fr.Element, 6)
+		for i := range in {
+			in[i].SetRandom()
+		}
+
+		// Hash must match Write + Sum
+		for _, e := range in {
+			sis.Write(e.Marshal())
+		}
+		sum := sis.Sum(nil)
+		sis.Reset()
+		got, err := sis.Hash(in)
+		assert.NoError(err)
+		assert.Equal(sis.Degree, len(got))
+		for i := range got {
+			var e fr.Element
+			e.SetBytes(sum[i*fr.Bytes : (i+1)*fr.Bytes])
+			assert.True(e.Equal(&got[i]), "Hash and Sum mismatch")
+		}
+
+		// Hash does not depend on previous calls
+		got2, err := sis.Hash(in)
+		assert.NoError(err)
+		assert.Equal(got, got2)
+
+		// HashLimbs of the decomposition matches Hash
+		got2, err = sis.HashLimbs(sis.LimbDecompose(in))
+		assert.NoError(err)
+		assert.Equal(got, got2)
+
+		_, err = sis.Hash(make([]fr.Element, 9))
+		assert.Error(err)
+	}
+}
+
+func TestHashLimbsLinearity(t *testing.T) {
+	assert := require.New(t)
+
+	sis, err := NewRSisWithParams(5, ParamsDegree64, 8)
+	assert.NoError(err)
+
+	a := sis.LimbDecompose(make([]fr.Element, 8))
+	b := make([]fr.Element, len(a))
+	c := make([]fr.Element, len(a))
+	var x fr.Element
+	x.SetRandom()
+	for i := range a {
+		a[i].SetRandom()
+		b[i].SetRandom()
+		c[i].Mul(&b[i], &x).Add(&c[i], &a[i])
+	}
+
+	ha, err := sis.HashLimbs(a)
+	assert.NoError(err)
+	hb, err := sis.HashLimbs(b)
+	assert.NoError(err)
+	hc, err := sis.HashLimbs(c)
+	assert.NoError(err)
+
+	for i := range hc {
+		var e fr.Element
+		e.Mul(&hb[i], &x).Add(&e, &ha[i])
+		assert.True(e.Equal(&hc[i]), "HashLimbs is not linear")
+	}
+
+	_, err = sis.HashLimbs(make([]fr.Element, len(a)+1))
+	assert.Error(err)
+}
+
+func TestHashBatch(t *testing.T) {
+	assert := require.New(t)
+
+	sis, err := NewRSisWithParams(5, ParamsDegree16, 8)
+	assert.NoError(err)
+
+	inputs := make([][]fr.Element, 17)
+	for i := range inputs {
+		inputs[i] = make([]fr.Element, i%9)
+		for j := range inputs[i] {
+			inputs[i][j].SetRandom()
+		}
+	}
+
+	got, err := sis.HashBatch(inputs)
+	assert.NoError(err)
+	assert.Equal(len(inputs), len(got))
+	for i := range inputs {
+		expected, err := sis.Hash(inputs[i])
+		assert.NoError(err)
+		assert.Equal(expected, got[i])
+	}
+
+	_, err = sis.HashBatch([][]fr.Element{make([]fr.Element, 9)})
+	assert.Error(err)
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	sis, err := NewRSisWithParams(5, ParamsDegree32, 8)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	written, err := sis.WriteTo(&buf)
+	assert.NoError(err)
+
+	var sis2 RSis
+	read, err := sis2.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(sis.A, sis2.A)
+	assert.Equal(sis.Ag, sis2.Ag)
+	assert.Equal(sis.Degree, sis2.Degree)
+	assert.Equal(sis.LogTwoBound, sis2.LogTwoBound)
+
+	in := make([]fr.Element, 8)
+	for i := range in {
+		in[i].SetRandom()
+	}
+	h, err := sis.Hash(in)
+	assert.NoError(err)
+	h2, err := sis2.Hash(in)
+	assert.NoError(err)
+	assert.Equal(h, h2)
+
+	// truncated input
+	_, err = sis2.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+
+	// tampered key: the last coefficient of Ag, then the first coefficient of A
+	for _, offset := range []int{buf.Len() - 1, 8*3 + 4 + fr.Bytes - 1} {
+		tampered := append([]byte(nil), buf.Bytes()...)
+		tampered[offset] ^= 1
+		_, err = sis2.ReadFrom(bytes.NewReader(tampered))
+		assert.Error(err, "offset %d", offset)
+	}
+
+	// parameters out of bounds, rejected before allocating the key
+	for _, header := range [][3]uint64{
+		{maxLogTwoDegree + 1, 6, 8},
+		{5, 0, 8},
+		{5, maxLogTwoBound + 1, 8},
+		{5, 6, 1 << 32},
+		{20, 1, maxReadKeySize},
+	} {
+		var b bytes.Buffer
+		assert.NoError(binary.Write(&b, binary.BigEndian, header[:]))
+		_, err = sis2.ReadFrom(&b)
+		assert.Error(err, "header %v", header)
+	}
+}
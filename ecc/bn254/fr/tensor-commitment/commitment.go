@@ -84,6 +84,9 @@ type TcParams struct {
 	// columns. We use this and not directly a hasher for threadsafety hasher. Indeed, if different
 	// thread share the same hasher, they will end up mixing hash inputs that should remain separate.
 	MakeHash func() hash.Hash
+
+	// NbQueries number of columns opened in an OpeningProof, see NbQueries
+	NbQueries int
 }
 
 // TensorCommitment stores the data to use a tensor commitment
@@ -125,7 +128,9 @@ type TensorCommitment struct {
 // * ρ rate of the code ( > 1)
 // * size size of the polynomial to be committed. The size of the commitment is
 // then ρ * √(m) where m² = size
-func NewTCParams(codeRate, NbColumns, NbRows int, makeHash func() hash.Hash) (*TcParams, error) {
+// * opts options setting the number of columns opened in the proofs (by default, it
+// is derived from DefaultSecurityLevel)
+func NewTCParams(codeRate, NbColumns, NbRows int, makeHash func() hash.Hash, opts ...TcOption) (*TcParams, error) {
 	var res TcParams
 
 	// domain[0]: domain to perform the FFT^-1, of size capacity * sqrt
@@ -143,6 +148,14 @@ func NewTCParams(codeRate, NbColumns, NbRows int, makeHash func() hash.Hash) (*T
 	// Hash function
 	res.MakeHash = makeHash
 
+	// number of queries
+	res.NbQueries = NbQueries(DefaultSecurityLevel, codeRate)
+	for _, opt := range opts {
+		if err := opt(&res); err != nil {
+			return nil, err
+		}
+	}
+
 	return &res, nil
 }
 
@@ -428,12 +441,9 @@ func evalAtPower(p []fr.Element, x fr.Element, n int) fr.Element {
 // digest: hash of the polynomial
 // l: random coefficients for the linear combination, chosen by the verifier
 // h: hash function that is used for hashing the columns of the polynomial
-// TODO make this function private and add a Verify function that derives
-// the randomness using Fiat Shamir
 //
-// Note (alex), A more convenient API would be to expose two functions,
-// one that does FS for you and what that let you do it for yourself. And likewise
-// for the prover.
+// The randomness is provided by the caller. See TensorCommitment.Open and VerifyOpening
+// for the non-interactive version, which derives it using Fiat Shamir.
 func Verify(proof Proof, digest Digest, l []fr.Element, h hash.Hash) error {
 
 	// for each entry in the list -> it corresponds to the sampling
@@ -441,6 +451,10 @@ func Verify(proof Proof, digest Digest, l []fr.Element, h hash.Hash) error {
 	// Encoded(linear_combination) = linear_combination(encoded)
 	for i := 0; i < len(proof.EntryList); i++ {
 
+		if proof.EntryList[i] < 0 || proof.EntryList[i] >= len(digest) {
+			return ErrProofFailedOob
+		}
+
 		// check that the hash of the columns correspond to what's in the digest
 		h.Reset()
 		for j := 0; j < len(proof.Columns[i]); j++ {
@@ -451,10 +465,6 @@ func Verify(proof Proof, digest Digest, l []fr.Element, h hash.Hash) error {
 			return ErrProofFailedHash
 		}
 
-		if proof.EntryList[i] >= len(digest) {
-			return ErrProofFailedOob
-		}
-
 		// linear combination of the i-th column, whose entries
 		// are the entryList[i]-th entries of the encoded lines
 		// of p
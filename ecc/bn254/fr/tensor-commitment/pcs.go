@@ -0,0 +1,448 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensorcommitment
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrNbPolynomials      = errors.New("the sizes of the polynomials don't match the number of claimed values")
+	ErrPolynomialsLayout  = errors.New("the polynomials don't fit in the matrix")
+	ErrProofShape         = errors.New("the proof is malformed")
+	ErrProofFailedValue   = errors.New("a claimed value is wrong")
+	ErrInvalidNbQueries   = errors.New("the number of queries must be positive")
+	ErrInvalidSecurityLvl = errors.New("the security level must be positive")
+)
+
+// DefaultSecurityLevel is the security level, in bits, of the tensor commitment
+// when none is provided in NewTCParams.
+const DefaultSecurityLevel = 128
+
+// TcOption configures the number of columns opened by the non-interactive
+// opening proofs.
+type TcOption func(*TcParams) error
+
+// WithNbQueries sets the number of columns opened in a proof.
+func WithNbQueries(nbQueries int) TcOption {
+	return func(p *TcParams) error {
+		if nbQueries <= 0 {
+			return ErrInvalidNbQueries
+		}
+		p.NbQueries = nbQueries
+		return nil
+	}
+}
+
+// WithSecurityLevel sets the number of columns opened in a proof so that a
+// cheating prover succeeds with probability at most 2^{-securityLevel}, see NbQueries.
+func WithSecurityLevel(securityLevel int) TcOption {
+	return func(p *TcParams) error {
+		if securityLevel <= 0 {
+			return ErrInvalidSecurityLvl
+		}
+		p.NbQueries = NbQueries(securityLevel, p.Rho)
+		return nil
+	}
+}
+
+// NbQueries returns the number of columns to open so that a cheating prover
+// succeeds with probability at most 2^{-securityLevel}, for a Reed Solomon code of rate 1/rho.
+//
+// The relative distance of the code is δ = 1-1/ρ. Each query detects a matrix which is
+// δ/3-far from the code with probability δ/3 (cf Ligero, Ames et al.),
+// so t queries give a soundness error of (1-δ/3)ᵗ. The error of the random linear combinations
+// is bounded by n/|𝔽| and is negligible for this field.
+func NbQueries(securityLevel, rho int) int {
+	delta := (1 - 1/float64(rho)) / 3
+	return int(math.Ceil(float64(securityLevel) / -math.Log2(1-delta)))
+}
+
+// OpeningProof proves the evaluations at a common point x of the polynomials
+// appended to a TensorCommitment.
+//
+// Writing the state as a matrix M, the evaluation of the polynomials at x is
+// derived from the combination of the rows uₓ = (1,x,..,x^{NbRows-1})ᵀ·M, evaluated
+// at (1,y,..) where y=x^{NbRows}. The proximity of M to the code is checked with
+// an independent combination u_γ = (1,γ,..,γ^{NbRows-1})ᵀ·M. Both are checked
+// against the columns of the encoded matrix sampled by the verifier.
+type OpeningProof struct {
+
+	// ClaimedValues[i] = p_i(x), where p_i is the i-th polynomial appended
+	ClaimedValues []fr.Element
+
+	// RowsCombination (1,x,..,x^{NbRows-1})ᵀ·M
+	RowsCombination []fr.Element
+
+	// RandomCombination (1,γ,..,γ^{NbRows-1})ᵀ·M, where γ is derived using Fiat Shamir
+	RandomCombination []fr.Element
+
+	// Columns of the encoded matrix, sampled using Fiat Shamir
+	Columns [][]fr.Element
+}
+
+// Open builds a non-interactive proof of the evaluations at x of the polynomials
+// which have been appended to the tensor commitment. The commitment must have
+// been computed (Commit) before calling Open. sizes are the sizes of the appended
+// polynomials, in order.
+func (tc *TensorCommitment) Open(x fr.Element, sizes []int, digest Digest) (OpeningProof, error) {
+
+	var proof OpeningProof
+
+	// check that the digest has been computed
+	if !tc.isCommitted {
+		return proof, ErrCommitmentNotDone
+	}
+	layout, err := polynomialsLayout(tc.params, sizes)
+	if err != nil {
+		return proof, err
+	}
+
+	fs := fiatshamir.NewTranscript(sha256.New(), "gamma", "columns")
+	gamma, err := deriveGamma(&fs, digest, x, sizes)
+	if err != nil {
+		return proof, err
+	}
+
+	proof.RowsCombination, err = tc.ProverComputeLinComb(powers(x, tc.params.NbRows))
+	if err != nil {
+		return proof, err
+	}
+	proof.RandomCombination, err = tc.ProverComputeLinComb(powers(gamma, tc.params.NbRows))
+	if err != nil {
+		return proof, err
+	}
+	proof.ClaimedValues = evaluateLayout(proof.RowsCombination, layout, x, tc.params.NbRows)
+
+	entryList, err := deriveEntryList(&fs, tc.params, &proof)
+	if err != nil {
+		return proof, err
+	}
+	proof.Columns, err = tc.ProverOpenColumns(entryList)
+	if err != nil {
+		return proof, err
+	}
+
+	return proof, nil
+}
+
+// VerifyOpening verifies a proof that the polynomials committed in digest, of sizes
+// sizes, evaluate to proof.ClaimedValues at x.
+func VerifyOpening(params *TcParams, digest Digest, x fr.Element, sizes []int, proof *OpeningProof) error {
+
+	layout, err := polynomialsLayout(params, sizes)
+	if err != nil {
+		return err
+	}
+	if len(proof.ClaimedValues) != len(sizes) {
+		return ErrNbPolynomials
+	}
+	if len(proof.RowsCombination) != params.NbColumns || len(proof.RandomCombination) != params.NbColumns {
+		return ErrProofShape
+	}
+	if len(digest) != int(params.Domains[1].Cardinality) {
+		return ErrProofShape
+	}
+
+	fs := fiatshamir.NewTranscript(sha256.New(), "gamma", "columns")
+	gamma, err := deriveGamma(&fs, digest, x, sizes)
+	if err != nil {
+		return err
+	}
+	entryList, err := deriveEntryList(&fs, params, proof)
+	if err != nil {
+		return err
+	}
+	if len(proof.Columns) != len(entryList) {
+		return ErrProofShape
+	}
+
+	// the claimed values are read from the combination of the rows
+	values := evaluateLayout(proof.RowsCombination, layout, x, params.NbRows)
+	for i := range values {
+		if !values[i].Equal(&proof.ClaimedValues[i]) {
+			return ErrProofFailedValue
+		}
+	}
+
+	// canonical forms of the combinations, to evaluate them on the big domain
+	rowsCombination := toCanonical(params, proof.RowsCombination)
+	randomCombination := toCanonical(params, proof.RandomCombination)
+	xs := powers(x, params.NbRows)
+	gammas := powers(gamma, params.NbRows)
+
+	h := params.MakeHash()
+	for i, entry := range entryList {
+
+		if len(proof.Columns[i]) != params.NbRows {
+			return ErrProofShape
+		}
+
+		// check that the hash of the column correspond to what's in the digest
+		h.Reset()
+		for j := 0; j < len(proof.Columns[i]); j++ {
+			h.Write(proof.Columns[i][j].Marshal())
+		}
+		if !bytes.Equal(h.Sum(nil), digest[entry]) {
+			return ErrProofFailedHash
+		}
+
+		// Encoded(combination)[entry] == combination(column)
+		var g fr.Element
+		g.Exp(params.Domains[1].Generator, bigInt(entry))
+		for _, c := range []struct {
+			canonical, coeffs []fr.Element
+		}{
+			{rowsCombination, xs},
+			{randomCombination, gammas},
+		} {
+			encoded := evalCanonical(c.canonical, g)
+			combined := innerProduct(proof.Columns[i], c.coeffs)
+			if !encoded.Equal(&combined) {
+				return ErrProofFailedEncoding
+			}
+		}
+	}
+
+	return nil
+}
+
+// polynomialsLayout returns the index of the first column of each polynomial,
+// the last entry being the total number of columns.
+func polynomialsLayout(params *TcParams, sizes []int) ([]int, error) {
+	res := make([]int, len(sizes)+1)
+	for i, s := range sizes {
+		if s < 0 {
+			return nil, ErrPolynomialsLayout
+		}
+		res[i+1] = res[i] + (s+params.NbRows-1)/params.NbRows
+	}
+	if res[len(sizes)] > params.NbColumns {
+		return nil, ErrPolynomialsLayout
+	}
+	return res, nil
+}
+
+// evaluateLayout returns the evaluations at x of the polynomials, given the
+// combination of the rows of the matrix by (1,x,..,x^{nbRows-1}).
+// The i-th polynomial is Σⱼ x^{j*nbRows} rowsCombination[layout[i]+j].
+func evaluateLayout(rowsCombination []fr.Element, layout []int, x fr.Element, nbRows int) []fr.Element {
+	var y fr.Element
+	y.Exp(x, bigInt(nbRows))
+	res := make([]fr.Element, len(layout)-1)
+	for i := range res {
+		res[i] = evalCanonical(rowsCombination[layout[i]:layout[i+1]], y)
+	}
+	return res
+}
+
+// deriveGamma binds the public data to the transcript and returns the
+// challenge for the proximity test.
+func deriveGamma(fs *fiatshamir.Transcript, digest Digest, x fr.Element, sizes []int) (fr.Element, error) {
+	var gamma fr.Element
+	for i := range digest {
+		if err := fs.Bind("gamma", digest[i]); err != nil {
+			return gamma, err
+		}
+	}
+	if err := fs.Bind("gamma", x.Marshal()); err != nil {
+		return gamma, err
+	}
+	var buf [8]byte
+	for _, s := range sizes {
+		binary.BigEndian.PutUint64(buf[:], uint64(s))
+		if err := fs.Bind("gamma", buf[:]); err != nil {
+			return gamma, err
+		}
+	}
+	b, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return gamma, err
+	}
+	gamma.SetBytes(b)
+	return gamma, nil
+}
+
+// deriveEntryList binds the combinations of the rows to the transcript and
+// returns the sorted list of distinct columns to open.
+func deriveEntryList(fs *fiatshamir.Transcript, params *TcParams, proof *OpeningProof) ([]int, error) {
+	for _, v := range [][]fr.Element{proof.RowsCombination, proof.RandomCombination} {
+		for i := range v {
+			if err := fs.Bind("columns", v[i].Marshal()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	seed, err := fs.ComputeChallenge("columns")
+	if err != nil {
+		return nil, err
+	}
+
+	codeSize := int(params.Domains[1].Cardinality)
+	nbQueries := params.NbQueries
+	if nbQueries == 0 {
+		nbQueries = NbQueries(DefaultSecurityLevel, params.Rho)
+	}
+	if nbQueries > codeSize {
+		nbQueries = codeSize
+	}
+
+	// the size of the code is a power of 2, so there is no bias modulo codeSize
+	selected := make([]bool, codeSize)
+	var counter [8]byte
+	h := sha256.New()
+	for nbSelected, c := 0, uint64(0); nbSelected < nbQueries; c++ {
+		h.Reset()
+		h.Write(seed)
+		binary.BigEndian.PutUint64(counter[:], c)
+		h.Write(counter[:])
+		entry := int(binary.BigEndian.Uint64(h.Sum(nil)) % uint64(codeSize))
+		if !selected[entry] {
+			selected[entry] = true
+			nbSelected++
+		}
+	}
+
+	res := make([]int, 0, nbQueries)
+	for i := range selected {
+		if selected[i] {
+			res = append(res, i)
+		}
+	}
+	return res, nil
+}
+
+// toCanonical returns the coefficients of the polynomial interpolating v on Domains[0].
+func toCanonical(params *TcParams, v []fr.Element) []fr.Element {
+	res := make([]fr.Element, params.Domains[0].Cardinality)
+	copy(res, v)
+	params.Domains[0].FFTInverse(res, fft.DIF)
+	fft.BitReverse(res)
+	return res
+}
+
+// evalCanonical returns p(x) where p is given in canonical form.
+func evalCanonical(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x)
+		res.Add(&res, &p[i])
+	}
+	return res
+}
+
+// bigInt returns i as a *big.Int
+func bigInt(i int) *big.Int {
+	return big.NewInt(int64(i))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// powers returns [1, x, .., xⁿ⁻¹]
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	if n == 0 {
+		return res
+	}
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
+
+// innerProduct returns Σᵢ a[i]*b[i]
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, tmp fr.Element
+	for i := range a {
+		tmp.Mul(&a[i], &b[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+// WriteTo writes the proof to w.
+func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, v := range []fr.Vector{proof.ClaimedValues, proof.RowsCombination, proof.RandomCombination} {
+		m, err := v.WriteTo(w)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+
+	if err := binary.Write(w, binary.BigEndian, uint32(len(proof.Columns))); err != nil {
+		return n, err
+	}
+	n += 4
+	for i := range proof.Columns {
+		v := fr.Vector(proof.Columns[i])
+		m, err := v.WriteTo(w)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom reads a proof written by WriteTo from r.
+func (proof *OpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	for _, v := range []*[]fr.Element{&proof.ClaimedValues, &proof.RowsCombination, &proof.RandomCombination} {
+		var vector fr.Vector
+		m, err := vector.ReadFrom(r)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		*v = vector
+	}
+
+	var nbColumns uint32
+	if err := binary.Read(r, binary.BigEndian, &nbColumns); err != nil {
+		return n, err
+	}
+	n += 4
+	proof.Columns = make([][]fr.Element, 0, minInt(int(nbColumns), 1<<16))
+	for i := uint32(0); i < nbColumns; i++ {
+		var vector fr.Vector
+		m, err := vector.ReadFrom(r)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		proof.Columns = append(proof.Columns, vector)
+	}
+
+	return n, nil
+}
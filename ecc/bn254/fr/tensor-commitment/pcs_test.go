@@ -0,0 +1,195 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tensorcommitment
+
+import (
+	"bytes"
+	"math/bits"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/sis"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPolynomial(size int) []fr.Element {
+	res := make([]fr.Element, size)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func evalPolynomial(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+func TestOpening(t *testing.T) {
+	if bits.UintSize == 32 {
+		t.Skip("skipping this test in 32bit.")
+	}
+
+	assert := require.New(t)
+
+	const (
+		rho       = 4
+		nbRows    = 8
+		nbColumns = 16
+	)
+	hMaker, err := sis.NewRingSISMaker(5, 1, 4, nbRows)
+	assert.NoError(err)
+	params, err := NewTCParams(rho, nbColumns, nbRows, hMaker, WithSecurityLevel(40))
+	assert.NoError(err)
+	assert.Equal(NbQueries(40, rho), params.NbQueries)
+
+	// polynomials of different sizes, some of them padded
+	sizes := []int{nbRows, 3*nbRows + 2, 5, 4 * nbRows}
+	ps := make([][]fr.Element, len(sizes))
+	for i := range ps {
+		ps[i] = randomPolynomial(sizes[i])
+	}
+
+	tc := NewTensorCommitment(params)
+	_, err = tc.Append(ps...)
+	assert.NoError(err)
+
+	var x fr.Element
+	x.SetRandom()
+
+	// the proof can't be built before the commitment
+	_, err = tc.Open(x, sizes, nil)
+	assert.ErrorIs(err, ErrCommitmentNotDone)
+
+	digest, err := tc.Commit()
+	assert.NoError(err)
+
+	proof, err := tc.Open(x, sizes, digest)
+	assert.NoError(err)
+	// the number of queries is capped by the size of the code
+	assert.Equal(minInt(params.NbQueries, int(params.Domains[1].Cardinality)), len(proof.Columns))
+	for i := range ps {
+		expected := evalPolynomial(ps[i], x)
+		assert.True(expected.Equal(&proof.ClaimedValues[i]), "wrong claimed value")
+	}
+
+	assert.NoError(VerifyOpening(params, digest, x, sizes, &proof))
+
+	// serialization
+	{
+		var buf bytes.Buffer
+		written, err := proof.WriteTo(&buf)
+		assert.NoError(err)
+		var decoded OpeningProof
+		read, err := decoded.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(written, read)
+		assert.Equal(proof, decoded)
+		assert.NoError(VerifyOpening(params, digest, x, sizes, &decoded))
+	}
+
+	// wrong point
+	{
+		var y fr.Element
+		y.SetRandom()
+		assert.Error(VerifyOpening(params, digest, y, sizes, &proof))
+	}
+
+	// wrong claimed value
+	{
+		proof.ClaimedValues[1].Double(&proof.ClaimedValues[1])
+		assert.ErrorIs(VerifyOpening(params, digest, x, sizes, &proof), ErrProofFailedValue)
+		proof.ClaimedValues[1] = evalPolynomial(ps[1], x)
+	}
+
+	// wrong sizes
+	{
+		wrongSizes := []int{nbRows, 3 * nbRows, 5, 4 * nbRows}
+		assert.Error(VerifyOpening(params, digest, x, wrongSizes, &proof))
+		assert.ErrorIs(VerifyOpening(params, digest, x, sizes[:3], &proof), ErrNbPolynomials)
+	}
+
+	// tampered opened column
+	{
+		proof.Columns[0][0].Double(&proof.Columns[0][0])
+		assert.ErrorIs(VerifyOpening(params, digest, x, sizes, &proof), ErrProofFailedHash)
+		proof.Columns[0][0].Halve()
+	}
+
+	assert.NoError(VerifyOpening(params, digest, x, sizes, &proof))
+}
+
+func TestOpeningWrongCombination(t *testing.T) {
+	if bits.UintSize == 32 {
+		t.Skip("skipping this test in 32bit.")
+	}
+
+	assert := require.New(t)
+
+	const (
+		rho       = 2
+		nbRows    = 4
+		nbColumns = 8
+	)
+	hMaker, err := sis.NewRingSISMaker(5, 1, 4, nbRows)
+	assert.NoError(err)
+	// all the columns are opened, so that the test is deterministic
+	params, err := NewTCParams(rho, nbColumns, nbRows, hMaker, WithNbQueries(rho*nbColumns))
+	assert.NoError(err)
+
+	sizes := []int{nbRows * nbColumns}
+	p := randomPolynomial(sizes[0])
+	tc := NewTensorCommitment(params)
+	_, err = tc.Append(p)
+	assert.NoError(err)
+	digest, err := tc.Commit()
+	assert.NoError(err)
+
+	var x fr.Element
+	x.SetRandom()
+
+	// the prover modifies the linear combination and the claimed value accordingly
+	proof, err := tc.Open(x, sizes, digest)
+	assert.NoError(err)
+	var one fr.Element
+	one.SetOne()
+	proof.RowsCombination[0].Add(&proof.RowsCombination[0], &one)
+	proof.ClaimedValues[0].Add(&proof.ClaimedValues[0], &one)
+
+	assert.ErrorIs(VerifyOpening(params, digest, x, sizes, &proof), ErrProofFailedEncoding)
+}
+
+func TestNbQueries(t *testing.T) {
+	assert := require.New(t)
+
+	// more redundancy means less queries
+	assert.Greater(NbQueries(128, 2), NbQueries(128, 4))
+	assert.Greater(NbQueries(128, 4), NbQueries(128, 8))
+
+	// (1 - δ/3)^t <= 2⁻¹²⁸
+	assert.Equal(309, NbQueries(128, 4))
+
+	_, err := NewTCParams(4, 16, 8, DummyHashMaker, WithNbQueries(0))
+	assert.ErrorIs(err, ErrInvalidNbQueries)
+	_, err = NewTCParams(4, 16, 8, DummyHashMaker, WithSecurityLevel(-1))
+	assert.ErrorIs(err, ErrInvalidSecurityLvl)
+
+	params, err := NewTCParams(4, 16, 8, DummyHashMaker)
+	assert.NoError(err)
+	assert.Equal(NbQueries(DefaultSecurityLevel, 4), params.NbQueries)
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package tbls provides threshold BLS signatures on bn254, with the public keys in G1 and
+// the signatures in G2.
+//
+// A (threshold, n) group key is shared among n participants, either by a trusted dealer
+// (SplitKey) or with a distributed key generation (DKGParticipant). Each participant signs
+// on its own (KeyShare.Sign), and any threshold valid partial signatures are combined by
+// Lagrange interpolation into a BLS signature of the group key (GroupKey.Combine), which
+// verifies as a plain BLS signature (GroupKey.Verify). The polynomials, Feldman commitments
+// and Lagrange coefficients of the sharing are those of the package fr/secretsharing.
+//
+// The messages are hashed to G2 with the suite BLS_SIG_BN254G2_XMD:SHA-256_SVDW_RO_NUL_.
+//
+// # See also
+//
+// https://datatracker.ietf.org/doc/draft-irtf-cfrg-bls-signature/
+package tbls
@@ -0,0 +1,169 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package tbls
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// partialSign returns the partial signatures of the signers, serialized and deserialized
+func partialSign(t *testing.T, signers []KeyShare, msg []byte) []PartialSignature {
+	t.Helper()
+	res := make([]PartialSignature, len(signers))
+	for i := range signers {
+		ps, err := signers[i].Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = res[i].SetBytes(ps.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 3, 5
+	keyShares, err := runDKG(newNetwork(n), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing threshold BLS")
+
+	partials := partialSign(t, keyShares, msg)
+	for i := range partials {
+		valid, err := groupKey.VerifyPartial(msg, &partials[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.Fatal("the partial signature should be valid")
+		}
+	}
+
+	// any threshold partial signatures give the same signature
+	var sig *bn254.G2Affine
+	for _, subset := range [][]PartialSignature{partials[:threshold], partials[n-threshold:], partials} {
+		combined, err := groupKey.Combine(msg, subset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig != nil && !sig.Equal(combined) {
+			t.Fatal("the signature should not depend on the signers")
+		}
+		sig = combined
+	}
+	valid, err := groupKey.Verify(msg, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Fatal("the signature should be valid")
+	}
+	if valid, _ = groupKey.Verify([]byte("another message"), sig); valid {
+		t.Fatal("the signature should not be valid for another message")
+	}
+
+	if _, err = groupKey.Combine(msg, partials[:threshold-1]); err != ErrNotEnoughSignatures {
+		t.Fatal("expected ErrNotEnoughSignatures, got", err)
+	}
+}
+
+func TestCulprits(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 4
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groupKey := &keyShares[0].GroupKey
+	msg := []byte("testing threshold BLS")
+	partials := partialSign(t, keyShares, msg)
+
+	// the participant 2 sends the signature of another message
+	other, err := keyShares[1].Sign([]byte("another message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	partials[1] = *other
+	_, err = groupKey.Combine(msg, partials)
+	if !errors.Is(err, ErrInvalidPartialSignature) || !strings.Contains(err.Error(), "[2]") {
+		t.Fatal("expected ErrInvalidPartialSignature from the participant 2, got", err)
+	}
+
+	// a duplicated partial signature
+	partials[1] = partials[0]
+	if _, err = groupKey.Combine(msg, partials); !errors.Is(err, ErrMissingMessage) {
+		t.Fatal("expected ErrMissingMessage, got", err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := keyShares[0].Sign([]byte("testing threshold BLS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ps2 PartialSignature
+	read, err := ps2.SetBytes(ps.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != sizePartialSignature || ps2.ID != ps.ID || !ps2.S.Equal(&ps.S) {
+		t.Fatal("Error serialize(deserialize(.)) of the partial signature")
+	}
+	if _, err = ps2.SetBytes(ps.Bytes()[1:]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+	if !bytes.Equal(ps2.Bytes(), ps.Bytes()) {
+		t.Fatal("Error serialize(deserialize(.)) of the partial signature")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkCombine(b *testing.B) {
+	const threshold, n = 3, 5
+	keyShares, _ := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	msg := []byte("benchmark")
+	partials := make([]PartialSignature, threshold)
+	for i := range partials {
+		ps, _ := keyShares[i].Sign(msg)
+		partials[i] = *ps
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keyShares[0].Combine(msg, partials)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/gnark-crypto/internal/parallel"
 	"math/big"
 	"runtime"
+	"sync"
 )
 
 // G1Affine point in affine coordinates
@@ -565,10 +566,107 @@ func (p *G1Jac) ClearCofactor(a *G1Jac) *G1Jac {
 
 }
 
+const (
+	// jointWindowGen is the width of the naf decomposition of the scalar of g in JointScalarMultiplicationBase
+	jointWindowGen = 8
+	// jointWindow is the width of the naf decomposition of the scalar of the other point
+	jointWindow = 5
+)
+
+var (
+	jointTableGenOnce sync.Once
+	// jointTableGen[0][i] = (2i+1)⋅g and jointTableGen[1][i] = ϕ((2i+1)⋅g)
+	jointTableGen [2][1 << (jointWindowGen - 2)]G1Affine
+)
+
+// oddMultiplesG1 sets table[0][i] = (2i+1)⋅a and table[1][i] = ϕ((2i+1)⋅a)
+func oddMultiplesG1(a *G1Affine, table [2][]G1Affine) {
+	jac := make([]G1Jac, len(table[0]))
+	var a2 G1Jac
+	jac[0].FromAffine(a)
+	a2.Double(&jac[0])
+	for i := 1; i < len(jac); i++ {
+		jac[i].Set(&jac[i-1]).AddAssign(&a2)
+	}
+	copy(table[0], BatchJacobianToAffineG1(jac))
+	for i := range table[0] {
+		table[1][i].X.Mul(&table[0][i].X, &thirdRootOneG1)
+		table[1][i].Y = table[0][i].Y
+	}
+}
+
 // JointScalarMultiplicationBase computes [s1]g+[s2]a using Straus-Shamir technique
-// where g is the prime subgroup generator
+// where g is the prime subgroup generator.
+//
+// The scalars are split using the GLV endomorphism ϕ and written in width-w naf,
+// and the four resulting scalar multiplications share their doublings. The odd
+// multiples of g and ϕ(g) are precomputed once.
+// As the decomposition of s2 is only valid on the prime order subgroup, a 2-bits
+// window is used instead if a is not in the subgroup.
 func (p *G1Jac) JointScalarMultiplicationBase(a *G1Affine, s1, s2 *big.Int) *G1Jac {
 
+	if !a.IsInSubGroup() {
+		return p.jointScalarMultiplicationBaseGeneric(a, s1, s2)
+	}
+
+	jointTableGenOnce.Do(func() {
+		var g G1Affine
+		g.FromJacobian(&g1Gen)
+		oddMultiplesG1(&g, [2][]G1Affine{jointTableGen[0][:], jointTableGen[1][:]})
+	})
+
+	var tableA [2][1 << (jointWindow - 2)]G1Affine
+	oddMultiplesG1(a, [2][]G1Affine{tableA[0][:], tableA[1][:]})
+
+	// s1 = u[0] + λ⋅u[1] and s2 = v[0] + λ⋅v[1] mod r
+	var k big.Int
+	k.Mod(s1, fr.Modulus())
+	u := ecc.SplitScalar(&k, &glvBasis)
+	k.Mod(s2, fr.Modulus())
+	v := ecc.SplitScalar(&k, &glvBasis)
+
+	scalars := [4]*big.Int{&u[0], &u[1], &v[0], &v[1]}
+	tables := [4][]G1Affine{jointTableGen[0][:], jointTableGen[1][:], tableA[0][:], tableA[1][:]}
+	windows := [4]uint{jointWindowGen, jointWindowGen, jointWindow, jointWindow}
+
+	var nafs [4][fr.Bits + 8]int8
+	var neg [4]bool
+	maxLen := 0
+	for i := range scalars {
+		neg[i] = scalars[i].Sign() == -1
+		k.Abs(scalars[i])
+		if n := ecc.WnafDecomposition(&k, windows[i], nafs[i][:]); n > maxLen {
+			maxLen = n
+		}
+	}
+
+	var res g1JacExtended
+	res.setInfinity()
+	for i := maxLen - 1; i >= 0; i-- {
+		res.double(&res)
+		for j := range nafs {
+			d := nafs[j][i]
+			if neg[j] {
+				d = -d
+			}
+			if d > 0 {
+				res.addMixed(&tables[j][d>>1])
+			} else if d < 0 {
+				res.subMixed(&tables[j][(-d)>>1])
+			}
+		}
+	}
+
+	p.fromJacExtended(&res)
+	return p
+
+}
+
+// jointScalarMultiplicationBaseGeneric computes [s1]g+[s2]a using Straus-Shamir technique
+// with a 2-bits window, where g is the prime subgroup generator. The scalars are not
+// reduced modulo r, so that a may be any point of the curve.
+func (p *G1Jac) jointScalarMultiplicationBaseGeneric(a *G1Affine, s1, s2 *big.Int) *G1Jac {
+
 	var res, p1, p2 G1Jac
 	res.Set(&g1Infinity)
 	p1.Set(&g1Gen)
@@ -607,33 +705,21 @@ func (p *G1Jac) JointScalarMultiplicationBase(a *G1Affine, s1, s2 *big.Int) *G1J
 	table[13].Set(&table[11]).AddAssign(&table[1])
 	table[14].Set(&table[11]).AddAssign(&table[2])
 
-	var s [2]fr.Element
-	s[0] = s[0].SetBigInt(&k1).Bits()
-	s[1] = s[1].SetBigInt(&k2).Bits()
-
 	maxBit := k1.BitLen()
 	if k2.BitLen() > maxBit {
 		maxBit = k2.BitLen()
 	}
-	hiWordIndex := (maxBit - 1) / 64
-
-	for i := hiWordIndex; i >= 0; i-- {
-		mask := uint64(3) << 62
-		for j := 0; j < 32; j++ {
-			res.Double(&res).Double(&res)
-			b1 := (s[0][i] & mask) >> (62 - 2*j)
-			b2 := (s[1][i] & mask) >> (62 - 2*j)
-			if b1|b2 != 0 {
-				s := (b2<<2 | b1)
-				res.AddAssign(&table[s-1])
-			}
-			mask = mask >> 2
+	for i := (maxBit + 1) &^ 1; i > 0; i -= 2 {
+		res.Double(&res).Double(&res)
+		b1 := k1.Bit(i-1)<<1 | k1.Bit(i-2)
+		b2 := k2.Bit(i-1)<<1 | k2.Bit(i-2)
+		if b1|b2 != 0 {
+			res.AddAssign(&table[(b2<<2|b1)-1])
 		}
 	}
 
 	p.Set(&res)
 	return p
-
 }
 
 // -------------------------------------------------------------------------------------------------
@@ -15,4 +15,9 @@
 // Code generated by consensys/gnark-crypto DO NOT EDIT
 
 // Package plookup provides an API to build plookup proofs.
+//
+// Besides the original plookup argument, it provides two log-derivative lookup arguments:
+// a univariate LogUp argument supporting multiplicities and multi-column tables (ProveLogUp, VerifyLogUp),
+// and the cached quotients argument cq (ProveCq, VerifyCq), whose prover cost is independent of the
+// table size once the table has been preprocessed (NewCqTable).
 package plookup
@@ -0,0 +1,162 @@
+// Package bip39 implements the BIP-39 mnemonic codes, with the English word list: the
+// encoding of an entropy as a mnemonic sentence, and the derivation of the binary seed
+// of a mnemonic, from which the BIP-32 master key is computed (see ecdsa.NewMasterKey).
+//
+// The mnemonics and the passphrases are normalized to the Unicode normalization form NFKD
+// before the derivation of the seed, as specified by BIP-39.
+//
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki
+package bip39
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// nbBitsWord is the number of bits encoded by a word
+	nbBitsWord = 11
+
+	// seedIterations is the number of iterations of PBKDF2 in NewSeed
+	seedIterations = 2048
+
+	// SeedSize is the size in bytes of the seeds returned by NewSeed
+	SeedSize = 64
+)
+
+var (
+	errEntropySize      = errors.New("the entropy must be 16, 20, 24, 28 or 32 bytes long")
+	errMnemonicSize     = errors.New("a mnemonic must have 12, 15, 18, 21 or 24 words")
+	errUnknownWord      = errors.New("the mnemonic has a word which is not in the word list")
+	errInvalidChecksum  = errors.New("invalid mnemonic checksum")
+	errInvalidBitLength = errors.New("the entropy must have 128, 160, 192, 224 or 256 bits")
+)
+
+// english.txt is the English word list of BIP-39, one word per line
+//
+//go:embed english.txt
+var englishWords string
+
+var (
+	wordsOnce sync.Once
+	words     []string
+	wordIndex map[string]int
+)
+
+func initWords() {
+	words = strings.Fields(englishWords)
+	wordIndex = make(map[string]int, len(words))
+	for i, w := range words {
+		wordIndex[w] = i
+	}
+}
+
+// NewEntropy returns bitSize random bits read from rand, bitSize being
+// 128, 160, 192, 224 or 256.
+func NewEntropy(rand io.Reader, bitSize int) ([]byte, error) {
+	if bitSize%32 != 0 || bitSize < 128 || bitSize > 256 {
+		return nil, errInvalidBitLength
+	}
+	entropy := make([]byte, bitSize/8)
+	if _, err := io.ReadFull(rand, entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic returns the mnemonic sentence encoding entropy: the bits of entropy,
+// followed by the len(entropy)/4 first bits of sha256(entropy) as a checksum, are
+// split into groups of 11 bits, each of which is the index of a word.
+func NewMnemonic(entropy []byte) (string, error) {
+	if len(entropy)%4 != 0 || len(entropy) < 16 || len(entropy) > 32 {
+		return "", errEntropySize
+	}
+	wordsOnce.Do(initWords)
+
+	checksum := sha256.Sum256(entropy)
+	data := append(append(make([]byte, 0, len(entropy)+1), entropy...), checksum[0])
+
+	nbWords := (len(entropy)*8 + len(entropy)/4) / nbBitsWord
+	sentence := make([]string, nbWords)
+	for i := range sentence {
+		sentence[i] = words[readBits(data, i*nbBitsWord, nbBitsWord)]
+	}
+	return strings.Join(sentence, " "), nil
+}
+
+// EntropyFromMnemonic returns the entropy encoded by mnemonic, or an error if mnemonic
+// has an unknown word or an invalid checksum.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	sentence := strings.Fields(mnemonic)
+	if len(sentence)%3 != 0 || len(sentence) < 12 || len(sentence) > 24 {
+		return nil, errMnemonicSize
+	}
+	wordsOnce.Do(initWords)
+
+	// entropy || checksum, the checksum having at most 8 bits
+	nbBits := len(sentence) * nbBitsWord
+	nbChecksumBits := nbBits / 33
+	data := make([]byte, (nbBits+7)/8)
+	for i, w := range sentence {
+		index, ok := wordIndex[w]
+		if !ok {
+			return nil, errUnknownWord
+		}
+		writeBits(data, i*nbBitsWord, nbBitsWord, index)
+	}
+
+	entropy := data[:(nbBits-nbChecksumBits)/8]
+	checksum := sha256.Sum256(entropy)
+	if readBits(data, len(entropy)*8, nbChecksumBits) != readBits(checksum[:], 0, nbChecksumBits) {
+		return nil, errInvalidChecksum
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid reports whether mnemonic only has words of the word list and a valid checksum.
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := EntropyFromMnemonic(mnemonic)
+	return err == nil
+}
+
+// NewSeed returns the seed of mnemonic protected by passphrase, which may be empty:
+//
+//	PBKDF2-HMAC-SHA512(NFKD(mnemonic), "mnemonic" || NFKD(passphrase), 2048 iterations)
+//
+// It returns an error if the mnemonic is not valid.
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = norm.NFKD.String(mnemonic)
+	if _, err := EntropyFromMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	// the words are separated by a single space
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+norm.NFKD.String(passphrase)), seedIterations, SeedSize, sha512.New), nil
+}
+
+// readBits returns the n ≤ 11 bits of data starting at bit offset, in big endian
+func readBits(data []byte, offset, n int) int {
+	res := 0
+	for i := offset; i < offset+n; i++ {
+		res = res<<1 | int(data[i/8]>>(7-i%8)&1)
+	}
+	return res
+}
+
+// writeBits writes the n least significant bits of v at bit offset of data, in big endian
+func writeBits(data []byte, offset, n, v int) {
+	for i := 0; i < n; i++ {
+		if v>>(n-1-i)&1 == 1 {
+			bit := offset + i
+			data[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// HardenedIndex is the index of the first hardened child key (2³¹)
+	HardenedIndex uint32 = 1 << 31
+
+	// sizeExtendedKey is the size of the serialization of an extended key:
+	// version (4) || depth (1) || parent fingerprint (4) || child number (4) || chain code (32) || key (33)
+	sizeExtendedKey = 78
+
+	// sizeCompressedPoint is the size of the SEC 1 compressed encoding of a point
+	sizeCompressedPoint = 1 + sizeFp
+)
+
+// versions of the mainnet extended keys (xprv and xpub)
+var (
+	versionPrivate = [4]byte{0x04, 0x88, 0xad, 0xe4}
+	versionPublic  = [4]byte{0x04, 0x88, 0xb2, 0x1e}
+)
+
+var (
+	errInvalidSeedLength  = errors.New("the seed must be between 16 and 64 bytes long")
+	errInvalidChildKey    = errors.New("the derived key is invalid, the next index should be used")
+	errHardenedFromPublic = errors.New("a hardened child key can't be derived from a public key")
+	errMaxDepth           = errors.New("the maximum depth of the tree is 255")
+	errInvalidExtendedKey = errors.New("invalid extended key")
+	errInvalidChecksum    = errors.New("invalid base58 checksum")
+	errInvalidPath        = errors.New("invalid derivation path")
+)
+
+// ExtendedPrivateKey is a BIP-32 extended private key: a private key and a chain code,
+// with the position of the key in the tree.
+//
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+type ExtendedPrivateKey struct {
+	PrivateKey
+	ChainCode         [32]byte
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+}
+
+// ExtendedPublicKey is a BIP-32 extended public key: a public key and a chain code,
+// with the position of the key in the tree.
+type ExtendedPublicKey struct {
+	PublicKey
+	ChainCode         [32]byte
+	Depth             uint8
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+}
+
+// NewMasterKey returns the master key of the tree derived from seed, typically
+// obtained from a BIP-39 mnemonic:
+//
+//	I = HMAC-SHA512("Bitcoin seed", seed), k = I[:32], c = I[32:]
+func NewMasterKey(seed []byte) (*ExtendedPrivateKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errInvalidSeedLength
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	I := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(I[:32])
+	if k.Sign() == 0 || k.Cmp(order) >= 0 {
+		return nil, errInvalidChildKey
+	}
+	res := new(ExtendedPrivateKey)
+	res.setScalar(k)
+	copy(res.ChainCode[:], I[32:])
+	return res, nil
+}
+
+// setScalar sets the private key to k ∈ [1, order-1]
+func (k *ExtendedPrivateKey) setScalar(s *big.Int) {
+	s.FillBytes(k.scalar[:sizeFr])
+	k.PublicKey.A.ScalarMultiplicationBase(s)
+}
+
+// Child returns the child key of index i, which is hardened if i ≥ HardenedIndex (CKDpriv):
+//
+//	I = HMAC-SHA512(c, 0x00 || k || i) if i is hardened, HMAC-SHA512(c, K || i) otherwise
+//	k_i = I[:32] + k (mod order), c_i = I[32:]
+//
+// It returns an error, with probability lower than 2⁻¹²⁷, if I[:32] ≥ order or k_i = 0,
+// in which case BIP-32 proceeds with the next index.
+func (k *ExtendedPrivateKey) Child(i uint32) (*ExtendedPrivateKey, error) {
+	if k.Depth == 255 {
+		return nil, errMaxDepth
+	}
+	data := make([]byte, 0, sizeCompressedPoint+4)
+	if i >= HardenedIndex {
+		data = append(data, 0)
+		data = append(data, k.scalar[:]...)
+	} else {
+		pub := compressPoint(&k.PublicKey.A)
+		data = append(data, pub[:]...)
+	}
+	data = appendUint32(data, i)
+
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	mac.Write(data)
+	I := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(I[:32])
+	if tweak.Cmp(order) >= 0 {
+		return nil, errInvalidChildKey
+	}
+	tweak.Add(tweak, new(big.Int).SetBytes(k.scalar[:]))
+	tweak.Mod(tweak, order)
+	if tweak.Sign() == 0 {
+		return nil, errInvalidChildKey
+	}
+
+	res := &ExtendedPrivateKey{
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.PublicKey.Fingerprint(),
+		ChildNumber:       i,
+	}
+	res.setScalar(tweak)
+	copy(res.ChainCode[:], I[32:])
+	return res, nil
+}
+
+// Derive returns the key at path relative to k, see ParsePath.
+func (k *ExtendedPrivateKey) Derive(path []uint32) (*ExtendedPrivateKey, error) {
+	res := k
+	for _, i := range path {
+		var err error
+		if res, err = res.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ExtendedPublicKey returns the extended public key of k (N in BIP-32), from which
+// the public keys of the non-hardened children can be derived.
+func (k *ExtendedPrivateKey) ExtendedPublicKey() *ExtendedPublicKey {
+	return &ExtendedPublicKey{
+		PublicKey:         k.PublicKey,
+		ChainCode:         k.ChainCode,
+		Depth:             k.Depth,
+		ParentFingerprint: k.ParentFingerprint,
+		ChildNumber:       k.ChildNumber,
+	}
+}
+
+// Child returns the public key of the non-hardened child of index i (CKDpub):
+//
+//	I = HMAC-SHA512(c, K || i)
+//	K_i = I[:32] ⋅ G + K, c_i = I[32:]
+//
+// It returns an error, with probability lower than 2⁻¹²⁷, if I[:32] ≥ order or K_i
+// is the point at infinity, in which case BIP-32 proceeds with the next index.
+func (k *ExtendedPublicKey) Child(i uint32) (*ExtendedPublicKey, error) {
+	if i >= HardenedIndex {
+		return nil, errHardenedFromPublic
+	}
+	if k.Depth == 255 {
+		return nil, errMaxDepth
+	}
+	pub := compressPoint(&k.PublicKey.A)
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	mac.Write(pub[:])
+	mac.Write(appendUint32(nil, i))
+	I := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(I[:32])
+	if tweak.Cmp(order) >= 0 {
+		return nil, errInvalidChildKey
+	}
+	res := &ExtendedPublicKey{
+		Depth:             k.Depth + 1,
+		ParentFingerprint: k.PublicKey.Fingerprint(),
+		ChildNumber:       i,
+	}
+	res.PublicKey.A.ScalarMultiplicationBase(tweak)
+	res.PublicKey.A.Add(&res.PublicKey.A, &k.PublicKey.A)
+	if res.PublicKey.A.IsInfinity() {
+		return nil, errInvalidChildKey
+	}
+	copy(res.ChainCode[:], I[32:])
+	return res, nil
+}
+
+// Derive returns the public key at path relative to k, which must not have
+// hardened indices, see ParsePath.
+func (k *ExtendedPublicKey) Derive(path []uint32) (*ExtendedPublicKey, error) {
+	res := k
+	for _, i := range path {
+		var err error
+		if res, err = res.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Fingerprint returns the first 4 bytes of the identifier of the key,
+// RIPEMD160(SHA256(K)) where K is the SEC 1 compressed encoding of the public key.
+func (pk *PublicKey) Fingerprint() [4]byte {
+	pub := compressPoint(&pk.A)
+	h := sha256.Sum256(pub[:])
+	r := ripemd160.New()
+	r.Write(h[:])
+	var res [4]byte
+	copy(res[:], r.Sum(nil))
+	return res
+}
+
+// ParsePath parses a derivation path such as m/44'/60'/0'/0/0, where the hardened
+// indices are marked with ' or h. The leading m/ is optional.
+func ParsePath(path string) ([]uint32, error) {
+	if path == "m" || path == "" {
+		return []uint32{}, nil
+	}
+	path = strings.TrimPrefix(path, "m/")
+	elements := strings.Split(path, "/")
+	res := make([]uint32, len(elements))
+	for j, e := range elements {
+		hardened := strings.HasSuffix(e, "'") || strings.HasSuffix(e, "h") || strings.HasSuffix(e, "H")
+		if hardened {
+			e = e[:len(e)-1]
+		}
+		i, err := strconv.ParseUint(e, 10, 32)
+		if err != nil || uint32(i) >= HardenedIndex {
+			return nil, errInvalidPath
+		}
+		res[j] = uint32(i)
+		if hardened {
+			res[j] += HardenedIndex
+		}
+	}
+	return res, nil
+}
+
+// Bytes returns the 78-byte serialization of k:
+//
+//	version || depth || parent fingerprint || child number || chain code || 0x00 || k
+func (k *ExtendedPrivateKey) Bytes() []byte {
+	var key [sizeCompressedPoint]byte
+	copy(key[1:], k.scalar[:])
+	return serializeExtendedKey(versionPrivate, k.Depth, k.ParentFingerprint, k.ChildNumber, &k.ChainCode, &key)
+}
+
+// SetBytes sets k from the 78-byte serialization buf, see Bytes.
+// It returns the number of bytes read.
+func (k *ExtendedPrivateKey) SetBytes(buf []byte) (int, error) {
+	var key [sizeCompressedPoint]byte
+	var res ExtendedPrivateKey
+	if err := deserializeExtendedKey(buf, versionPrivate, &res.Depth, &res.ParentFingerprint, &res.ChildNumber, &res.ChainCode, &key); err != nil {
+		return 0, err
+	}
+	s := new(big.Int).SetBytes(key[1:])
+	if key[0] != 0 || s.Sign() == 0 || s.Cmp(order) >= 0 {
+		return 0, errInvalidExtendedKey
+	}
+	res.setScalar(s)
+	*k = res
+	return sizeExtendedKey, nil
+}
+
+// String returns the Base58Check encoding of k (xprv..).
+func (k *ExtendedPrivateKey) String() string {
+	return base58CheckEncode(k.Bytes())
+}
+
+// SetString sets k from its Base58Check encoding (xprv..).
+func (k *ExtendedPrivateKey) SetString(s string) (*ExtendedPrivateKey, error) {
+	buf, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := k.SetBytes(buf); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Bytes returns the 78-byte serialization of k:
+//
+//	version || depth || parent fingerprint || child number || chain code || K
+//
+// where K is the SEC 1 compressed encoding of the public key.
+func (k *ExtendedPublicKey) Bytes() []byte {
+	key := compressPoint(&k.PublicKey.A)
+	return serializeExtendedKey(versionPublic, k.Depth, k.ParentFingerprint, k.ChildNumber, &k.ChainCode, &key)
+}
+
+// SetBytes sets k from the 78-byte serialization buf, see Bytes.
+// It returns the number of bytes read.
+func (k *ExtendedPublicKey) SetBytes(buf []byte) (int, error) {
+	var key [sizeCompressedPoint]byte
+	var res ExtendedPublicKey
+	if err := deserializeExtendedKey(buf, versionPublic, &res.Depth, &res.ParentFingerprint, &res.ChildNumber, &res.ChainCode, &key); err != nil {
+		return 0, err
+	}
+	if err := decompressPoint(&res.PublicKey.A, &key); err != nil {
+		return 0, err
+	}
+	*k = res
+	return sizeExtendedKey, nil
+}
+
+// String returns the Base58Check encoding of k (xpub..).
+func (k *ExtendedPublicKey) String() string {
+	return base58CheckEncode(k.Bytes())
+}
+
+// SetString sets k from its Base58Check encoding (xpub..).
+func (k *ExtendedPublicKey) SetString(s string) (*ExtendedPublicKey, error) {
+	buf, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := k.SetBytes(buf); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func serializeExtendedKey(version [4]byte, depth uint8, parentFingerprint [4]byte, childNumber uint32, chainCode *[32]byte, key *[sizeCompressedPoint]byte) []byte {
+	res := make([]byte, 0, sizeExtendedKey)
+	res = append(res, version[:]...)
+	res = append(res, depth)
+	res = append(res, parentFingerprint[:]...)
+	res = appendUint32(res, childNumber)
+	res = append(res, chainCode[:]...)
+	res = append(res, key[:]...)
+	return res
+}
+
+func deserializeExtendedKey(buf []byte, version [4]byte, depth *uint8, parentFingerprint *[4]byte, childNumber *uint32, chainCode *[32]byte, key *[sizeCompressedPoint]byte) error {
+	if len(buf) != sizeExtendedKey || !bytes.Equal(buf[:4], version[:]) {
+		return errInvalidExtendedKey
+	}
+	*depth = buf[4]
+	copy(parentFingerprint[:], buf[5:9])
+	*childNumber = binary.BigEndian.Uint32(buf[9:13])
+	copy(chainCode[:], buf[13:45])
+	copy(key[:], buf[45:])
+
+	// the master key has no parent
+	if *depth == 0 && (*parentFingerprint != [4]byte{} || *childNumber != 0) {
+		return errInvalidExtendedKey
+	}
+	return nil
+}
+
+// appendUint32 appends the big endian encoding of i to b
+func appendUint32(b []byte, i uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], i)
+	return append(b, buf[:]...)
+}
+
+// compressPoint returns the SEC 1 compressed encoding of p, 0x02 || x if y is even,
+// 0x03 || x otherwise
+func compressPoint(p *secp256k1.G1Affine) [sizeCompressedPoint]byte {
+	var res [sizeCompressedPoint]byte
+	res[0] = 0x02
+	if p.Y.Bits()[0]&1 == 1 {
+		res[0] = 0x03
+	}
+	x := p.X.Bytes()
+	copy(res[1:], x[:])
+	return res
+}
+
+// decompressPoint sets p from its SEC 1 compressed encoding, see compressPoint
+func decompressPoint(p *secp256k1.G1Affine, buf *[sizeCompressedPoint]byte) error {
+	if buf[0] != 0x02 && buf[0] != 0x03 {
+		return errInvalidExtendedKey
+	}
+	if err := p.X.SetBytesCanonical(buf[1:]); err != nil {
+		return errInvalidExtendedKey
+	}
+	// y² = x³ + b
+	_, b := secp256k1.CurveCoefficients()
+	var y2 fp.Element
+	y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &b)
+	if p.Y.Sqrt(&y2) == nil {
+		return errInvalidExtendedKey
+	}
+	if byte(p.Y.Bits()[0]&1) != buf[0]&1 {
+		p.Y.Neg(&p.Y)
+	}
+	return nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58CheckEncode returns the Base58 encoding of data || SHA256(SHA256(data))[:4]
+func base58CheckEncode(data []byte) string {
+	h := sha256.Sum256(data)
+	h = sha256.Sum256(h[:])
+	data = append(data[:len(data):len(data)], h[:4]...)
+
+	n := new(big.Int).SetBytes(data)
+	radix, mod := big.NewInt(58), new(big.Int)
+	var res []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		res = append(res, base58Alphabet[mod.Int64()])
+	}
+	// the leading zero bytes are encoded as 1
+	for i := 0; i < len(data) && data[i] == 0; i++ {
+		res = append(res, base58Alphabet[0])
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return string(res)
+}
+
+// base58CheckDecode decodes s and checks the checksum, see base58CheckEncode
+func base58CheckDecode(s string) ([]byte, error) {
+	n, radix := new(big.Int), big.NewInt(58)
+	nbZeros := 0
+	for i := 0; i < len(s) && s[i] == base58Alphabet[0]; i++ {
+		nbZeros++
+	}
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(d)))
+	}
+	data := append(make([]byte, nbZeros), n.Bytes()...)
+	if len(data) < 4 {
+		return nil, errInvalidChecksum
+	}
+	data, checksum := data[:len(data)-4], data[len(data)-4:]
+	h := sha256.Sum256(data)
+	h = sha256.Sum256(h[:])
+	if !hmac.Equal(h[:4], checksum) {
+		return nil, errInvalidChecksum
+	}
+	return data, nil
+}
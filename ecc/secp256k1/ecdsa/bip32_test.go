@@ -0,0 +1,332 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+type bip32TestKey struct {
+	index      uint32
+	xprv, xpub string
+}
+
+// test vectors 1, 2 and 3 of BIP-32
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+var bip32TestVectors = []struct {
+	seed       string
+	xprv, xpub string
+	children   []bip32TestKey
+}{
+	{
+		seed: "000102030405060708090a0b0c0d0e0f",
+		xprv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		xpub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		children: []bip32TestKey{
+			{
+				HardenedIndex,
+				"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+				"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			},
+			{
+				1,
+				"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+				"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+			},
+			{
+				2 + HardenedIndex,
+				"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+				"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			},
+			{
+				2,
+				"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+				"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+			},
+			{
+				1000000000,
+				"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+				"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+			},
+		},
+	},
+	{
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		xprv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+		xpub: "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+		children: []bip32TestKey{
+			{
+				0,
+				"xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+				"xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+			},
+			{
+				2147483647 + HardenedIndex,
+				"xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+				"xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+			},
+			{
+				1,
+				"xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+				"xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+			},
+			{
+				2147483646 + HardenedIndex,
+				"xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+				"xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+			},
+			{
+				2,
+				"xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+				"xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+			},
+		},
+	},
+	{
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		xprv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+		xpub: "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+		children: []bip32TestKey{
+			{
+				HardenedIndex + 0,
+				"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+				"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+			},
+		},
+	},
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range bip32TestVectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := NewMasterKey(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkExtendedKey(t, key, v.xprv, v.xpub)
+
+		for _, child := range v.children {
+			parent := key
+			if key, err = key.Child(child.index); err != nil {
+				t.Fatal(err)
+			}
+			checkExtendedKey(t, key, child.xprv, child.xpub)
+
+			// public derivation, for the non-hardened indices
+			pub, err := parent.ExtendedPublicKey().Child(child.index)
+			if child.index >= HardenedIndex {
+				if err == nil {
+					t.Fatal("a hardened key should not be derived from a public key")
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pub.String() != child.xpub {
+				t.Fatalf("public derivation: got %s, want %s", pub.String(), child.xpub)
+			}
+		}
+	}
+}
+
+func checkExtendedKey(t *testing.T, key *ExtendedPrivateKey, xprv, xpub string) {
+	t.Helper()
+	if key.String() != xprv {
+		t.Fatalf("got %s, want %s", key.String(), xprv)
+	}
+	if key.ExtendedPublicKey().String() != xpub {
+		t.Fatalf("got %s, want %s", key.ExtendedPublicKey().String(), xpub)
+	}
+
+	var priv ExtendedPrivateKey
+	if _, err := priv.SetString(xprv); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Bytes(), key.Bytes()) || !priv.PublicKey.Equal(&key.PublicKey) {
+		t.Fatal("the deserialized private key should match the key")
+	}
+	var pub ExtendedPublicKey
+	if _, err := pub.SetString(xpub); err != nil {
+		t.Fatal(err)
+	}
+	if !pub.PublicKey.Equal(&key.PublicKey) || pub.ChainCode != key.ChainCode {
+		t.Fatal("the deserialized public key should match the key")
+	}
+}
+
+func TestBIP32PublicDerivation(t *testing.T) {
+	t.Parallel()
+
+	// m/44'/60'/0'/0 of the mnemonic generated by https://iancoleman.io/bip39/
+	var xpub ExtendedPublicKey
+	if _, err := xpub.SetString("xpub6DxSCdWu6jKqr4isjo7bsPeDD6s3J4YVQV1JSHZg12Eagdqnf7XX4fxqyW2sLhUoFWutL7tAELU2LiGZrEXtjVbvYptvTX5Eoa4Mamdjm9u"); err != nil {
+		t.Fatal(err)
+	}
+	children := []struct {
+		index uint32
+		key   string
+	}{
+		{0, "0243187e1a2ba9ba824f5f81090650c8f4faa82b7baf93060d10b81f4b705afd46"},
+		{1, "023790d11eb715c4320d8e31fba3a09b700051dc2cdbcce03f44b11c274d1e220b"},
+		{2, "0302c5749c3c75cea234878ae3f4d8f65b75d584bcd7ed0943b016d6f6b59a2bad"},
+		{3, "03f0440c94e5b14ea5b15875934597afff541bec287c6e65dc1102cafc07f69699"},
+		{4, "026419d0d8996707605508ac44c5871edc7fe206a79ef615b74f2eea09c5852e2b"},
+		{5, "02f63c6f195eea98bdb163c4a094260dea71d264b21234bed4df3899236e6c2298"},
+		{6, "02d74709cd522081064858f393d009ead5a0ecd43ede3a1f57befcc942025cb5f9"},
+		{7, "03e54bb92630c943d38bbd8a4a2e65fca7605e672d30a0e545a7198cbb60729ceb"},
+		{8, "027e9d5acd14d39c4938697fba388cd2e8f31fc1c5dc02fafb93a10a280de85199"},
+		{9, "02a167a9f0d57468fb6abf2f3f7967e2cadf574314753a06a9ef29bc76c54638d2"},
+		{100, "020db9ba00ddf68428e3f5bfe54252bbcd75b21e42f51bf3bfc4172bf0e5fa7905"},
+		{101, "0299e3790956570737d6164e6fcda5a3daa304065ca95ba46bc73d436b84f34d46"},
+		{102, "0202e0732c4c5d2b1036af173640e01957998cfd4f9cdaefab6ffe76eb869e2c59"},
+		{103, "03d050adbd996c0c5d737ff638402dfbb8c08e451fef10e6d62fb57887c1ac6cb2"},
+		{104, "038d466399e2d68b4b16043ad4d88893b3b2f84fc443368729a973df1e66f4f530"},
+		{105, "034811e2f0c8c50440c08c2c9799b99c911c036e877e8325386ff61723ae3ffdce"},
+		{106, "026339fd5842921888e711a6ba9104a5f0c94cc0569855273cf5faefdfbcd3cc29"},
+		{107, "02833705c1069fab2aa92c6b0dac27807290d72e9f52378d493ac44849ca003b22"},
+		{108, "032d2639bde1eb7bdf8444bd4f6cc26a9d1bdecd8ea15fac3b992c3da68d9d1df5"},
+		{109, "02479c6d4a64b93a2f4343aa862c938fbc658c99219dd7bebb4830307cbd76c9e9"},
+	}
+	for _, child := range children {
+		key, err := xpub.Child(child.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub := compressPoint(&key.PublicKey.A)
+		if hex.EncodeToString(pub[:]) != child.key {
+			t.Fatalf("child %d: got %x, want %s", child.index, pub, child.key)
+		}
+
+		var decompressed PublicKey
+		if err := decompressPoint(&decompressed.A, &pub); err != nil {
+			t.Fatal(err)
+		}
+		if !decompressed.Equal(&key.PublicKey) {
+			t.Fatal("decompressPoint should invert compressPoint")
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	path, err := ParsePath("m/44'/60h/0H/0/7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint32{44 + HardenedIndex, 60 + HardenedIndex, HardenedIndex, 0, 7}
+	if len(path) != len(want) {
+		t.Fatalf("got %v, want %v", path, want)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("got %v, want %v", path, want)
+		}
+	}
+	if path, err := ParsePath("m"); err != nil || len(path) != 0 {
+		t.Fatal("m should be the empty path")
+	}
+
+	for _, invalid := range []string{"m/", "m/a", "m/1/", "m/2147483648", "m/-1", "m/1''"} {
+		if _, err := ParsePath(invalid); err == nil {
+			t.Errorf("ParsePath(%q) should fail", invalid)
+		}
+	}
+
+	// Derive follows the path
+	seed, _ := hex.DecodeString(bip32TestVectors[0].seed)
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err = ParsePath("m/0'/1/2'/2/1000000000"); err != nil {
+		t.Fatal(err)
+	}
+	key, err := master.Derive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := bip32TestVectors[0].children
+	if key.String() != children[len(children)-1].xprv {
+		t.Fatal("Derive should follow the path")
+	}
+}
+
+func TestExtendedKeyInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewMasterKey(make([]byte, 15)); err == nil {
+		t.Fatal("a short seed should be rejected")
+	}
+
+	// invalid keys of the test vector 5 of BIP-32
+	invalid := []string{
+		// pubkey version / prvkey mismatch
+		"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm",
+		// prvkey version / pubkey mismatch
+		"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH",
+		// invalid pubkey prefix 04
+		"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn",
+		// invalid prvkey prefix 04
+		"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ",
+		// zero depth with non-zero parent fingerprint
+		"xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv",
+		// zero depth with non-zero index
+		"xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+		// private key 0 not in 1..n-1
+		"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx",
+		// private key n not in 1..n-1
+		"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G",
+		// invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007
+		"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY",
+		// invalid checksum
+		"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL",
+	}
+	for _, s := range invalid {
+		var priv ExtendedPrivateKey
+		var pub ExtendedPublicKey
+		_, errPriv := priv.SetString(s)
+		_, errPub := pub.SetString(s)
+		if errPriv == nil || errPub == nil {
+			t.Errorf("%s should be rejected", s)
+		}
+	}
+}
+
+func BenchmarkBIP32Child(b *testing.B) {
+	seed, _ := hex.DecodeString(bip32TestVectors[0].seed)
+	master, _ := NewMasterKey(seed)
+	b.Run("private", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			master.Child(uint32(i) % HardenedIndex)
+		}
+	})
+	b.Run("public", func(b *testing.B) {
+		pub := master.ExtendedPublicKey()
+		for i := 0; i < b.N; i++ {
+			pub.Child(uint32(i) % HardenedIndex)
+		}
+	})
+}
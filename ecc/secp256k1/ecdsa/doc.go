@@ -21,8 +21,31 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 //
+// By default the nonces are drawn from a CSPRNG seeded by crypto/rand. PrivateKey.SignWithOptions
+// supports deterministic (RFC 6979) and hedged nonces, and low-S normalization. Signatures can be
+// encoded as r||s (Signature.Bytes), in ASN.1 DER (Signature.BytesDER),
+// or as r||s||v with the public key recovery information (BytesRecoverable).
+//
+// Public keys can be recovered from a signature (PublicKey.RecoverFrom), or from many
+// signatures in parallel (BatchRecover).
+//
+// Curve returns an elliptic.Curve, and the keys can be converted to and from crypto/ecdsa
+// keys (PublicKey.ToECDSA, PrivateKey.FromECDSA, ...).
+//
+// The keys can also be used for ECDH key agreement (PrivateKey.SharedSecret) and for hybrid
+// encryption (ECIES with HKDF-SHA256 and AES-256-GCM, see Encrypt).
+// EncryptDevp2p implements the variant used by Ethereum's devp2p (ConcatKDF, AES-128-CTR
+// and HMAC-SHA256).
+//
+// The keys can be derived hierarchically as in BIP-32 (NewMasterKey, ExtendedPrivateKey.Derive),
+// from a seed typically obtained from a BIP-39 mnemonic (see ecc/secp256k1/bip39).
+//
+// The keys can also be used for the verifiable random function of RFC 9381, in the
+// ECVRF-SECP256K1-SHA256-TAI suite (PrivateKey.ProveVRF, PublicKey.VerifyVRF, BatchVerifyVRF).
+//
 // Documentation:
 // - Wikipedia: https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
 // - FIPS 186-4: https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.186-4.pdf
 // - SEC 1, v-2: https://www.secg.org/sec1-v2.pdf
+// - RFC 6979: https://www.rfc-editor.org/rfc/rfc6979
 package ecdsa
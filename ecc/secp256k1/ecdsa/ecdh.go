@@ -0,0 +1,254 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	errInvalidPoint     = errors.New("the public key is not a valid point of the curve")
+	errShortCiphertext  = errors.New("the ciphertext is too short")
+	errDecryptionFailed = errors.New("decryption failed")
+)
+
+const (
+	eciesKeySize   = 32 // AES-256
+	eciesNonceSize = 12
+	eciesTagSize   = 16
+)
+
+// SharedSecret returns the ECDH shared secret between privKey and pub, that is
+// the x-coordinate of [d]Q on sizeFp bytes in big endian, where d is the secret
+// scalar of privKey and Q the point of pub (SEC 1, Version 2.0, Section 3.3.1).
+//
+// It returns an error if Q is not on the curve or is the point at infinity.
+//
+// The shared secret is not uniformly distributed, it should be fed into a key
+// derivation function, as in Encrypt.
+func (privKey *PrivateKey) SharedSecret(pub *PublicKey) ([]byte, error) {
+	if pub.A.IsInfinity() || !pub.A.IsOnCurve() {
+		return nil, errInvalidPoint
+	}
+	var scalar big.Int
+	scalar.SetBytes(privKey.scalar[:])
+
+	var p secp256k1.G1Affine
+	p.ScalarMultiplication(&pub.A, &scalar)
+
+	res := p.X.Bytes()
+	return res[:], nil
+}
+
+// Encrypt encrypts msg to pub with ECIES.
+//
+// An ephemeral key pair (r, R) is drawn from rand. The AES-256-GCM key and nonce are
+// derived with HKDF-SHA256 from the ECDH shared secret between r and pub, using
+// R||pub||sharedInfo as HKDF info. The output is R||AES-GCM(msg), where R is encoded
+// as PublicKey.Bytes and the authentication tag is appended to the ciphertext.
+//
+// sharedInfo is optional, and must be provided again to Decrypt.
+//
+// See EncryptDevp2p for the scheme used by Ethereum's devp2p.
+func Encrypt(rand io.Reader, pub *PublicKey, msg, sharedInfo []byte) ([]byte, error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralBin := ephemeral.PublicKey.Bytes()
+	aead, nonce, err := eciesAEAD(ephemeral, pub, ephemeralBin, pub.Bytes(), sharedInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, sizePublicKey, sizePublicKey+len(msg)+eciesTagSize)
+	copy(res, ephemeralBin)
+	return aead.Seal(res, nonce, msg, nil), nil
+}
+
+// Decrypt decrypts a ciphertext returned by Encrypt, see Encrypt.
+func (privKey *PrivateKey) Decrypt(ciphertext, sharedInfo []byte) ([]byte, error) {
+	if len(ciphertext) < sizePublicKey+eciesTagSize {
+		return nil, errShortCiphertext
+	}
+	var ephemeral PublicKey
+	if _, err := ephemeral.SetBytes(ciphertext[:sizePublicKey]); err != nil {
+		return nil, err
+	}
+	aead, nonce, err := eciesAEAD(privKey, &ephemeral, ciphertext[:sizePublicKey], privKey.PublicKey.Bytes(), sharedInfo)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := aead.Open(nil, nonce, ciphertext[sizePublicKey:], nil)
+	if err != nil {
+		return nil, errDecryptionFailed
+	}
+	return msg, nil
+}
+
+// eciesAEAD returns the AES-GCM instance and the nonce derived from the shared
+// secret between privKey and pub. The encodings of the ephemeral and recipient
+// public keys are bound to the derived key.
+func eciesAEAD(privKey *PrivateKey, pub *PublicKey, ephemeralBin, recipientBin, sharedInfo []byte) (cipher.AEAD, []byte, error) {
+	secret, err := privKey.SharedSecret(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := make([]byte, 0, 2*sizePublicKey+len(sharedInfo))
+	info = append(info, ephemeralBin...)
+	info = append(info, recipientBin...)
+	info = append(info, sharedInfo...)
+
+	var okm [eciesKeySize + eciesNonceSize]byte
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), okm[:]); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(okm[:eciesKeySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, okm[eciesKeySize:], nil
+}
+
+const (
+	devp2pKeySize = 16 // AES-128
+	devp2pTagSize = sha256.Size
+	// devp2pOverhead is the size of 0x04||R, the IV and the tag
+	devp2pOverhead = 1 + sizePublicKey + aes.BlockSize + devp2pTagSize
+)
+
+// EncryptDevp2p encrypts msg to pub with the ECIES scheme of Ethereum's devp2p
+// (RLPx handshake), which is the one of go-ethereum's crypto/ecies package with
+// the AES-128/SHA-256 parameters:
+//   - an ephemeral key pair (r, R) is drawn from rand,
+//   - ke||km' = ConcatKDF-SHA256(z, s1) (NIST SP 800-56A) on 32 bytes, where z is the
+//     ECDH shared secret between r and pub, and km = SHA-256(km'),
+//   - c = AES-128-CTR(ke, iv, msg) with a random iv,
+//   - d = HMAC-SHA256(km, iv||c||s2).
+//
+// The output is 0x04||R||iv||c||d, where R is encoded as PublicKey.Bytes.
+//
+// The shared information s1 and s2 are optional, and must be provided again to
+// DecryptDevp2p. devp2p uses an empty s1 and the size prefix of the message as s2.
+func EncryptDevp2p(rand io.Reader, pub *PublicKey, msg, s1, s2 []byte) ([]byte, error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	ke, km, err := devp2pKeys(ephemeral, pub, s1)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 1+sizePublicKey+aes.BlockSize+len(msg), len(msg)+devp2pOverhead)
+	res[0] = 0x04
+	copy(res[1:], ephemeral.PublicKey.Bytes())
+	iv := res[1+sizePublicKey : 1+sizePublicKey+aes.BlockSize]
+	if _, err := io.ReadFull(rand, iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCTR(block, iv).XORKeyStream(res[1+sizePublicKey+aes.BlockSize:], msg)
+
+	return append(res, devp2pTag(km, res[1+sizePublicKey:], s2)...), nil
+}
+
+// DecryptDevp2p decrypts a ciphertext returned by EncryptDevp2p, see EncryptDevp2p.
+func (privKey *PrivateKey) DecryptDevp2p(ciphertext, s1, s2 []byte) ([]byte, error) {
+	if len(ciphertext) < devp2pOverhead {
+		return nil, errShortCiphertext
+	}
+	// only the uncompressed encoding of the ephemeral key is used by devp2p
+	if ciphertext[0] != 0x04 {
+		return nil, errInvalidPoint
+	}
+	var ephemeral PublicKey
+	if _, err := ephemeral.SetBytes(ciphertext[1 : 1+sizePublicKey]); err != nil {
+		return nil, err
+	}
+	ke, km, err := devp2pKeys(privKey, &ephemeral, s1)
+	if err != nil {
+		return nil, err
+	}
+
+	em := ciphertext[1+sizePublicKey : len(ciphertext)-devp2pTagSize]
+	if !hmac.Equal(devp2pTag(km, em, s2), ciphertext[len(ciphertext)-devp2pTagSize:]) {
+		return nil, errDecryptionFailed
+	}
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, len(em)-aes.BlockSize)
+	cipher.NewCTR(block, em[:aes.BlockSize]).XORKeyStream(msg, em[aes.BlockSize:])
+	return msg, nil
+}
+
+// devp2pKeys returns the encryption and MAC keys derived from the shared secret
+// between privKey and pub.
+func devp2pKeys(privKey *PrivateKey, pub *PublicKey, s1 []byte) (ke, km []byte, err error) {
+	z, err := privKey.SharedSecret(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := concatKDF(sha256.New(), z, s1, 2*devp2pKeySize)
+	hkm := sha256.Sum256(k[devp2pKeySize:])
+	return k[:devp2pKeySize], hkm[:], nil
+}
+
+// devp2pTag returns HMAC-SHA256(km, em||s2)
+func devp2pTag(km, em, s2 []byte) []byte {
+	mac := hmac.New(sha256.New, km)
+	mac.Write(em)
+	mac.Write(s2)
+	return mac.Sum(nil)
+}
+
+// concatKDF is the concatenation key derivation function of NIST SP 800-56A,
+// Section 5.8.1, returning H(1||z||s1) || H(2||z||s1) || .. truncated to size bytes.
+func concatKDF(h hash.Hash, z, s1 []byte, size int) []byte {
+	var counter [4]byte
+	res := make([]byte, 0, size+h.Size())
+	for i := uint32(1); len(res) < size; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Reset()
+		h.Write(counter[:])
+		h.Write(z)
+		h.Write(s1)
+		res = h.Sum(res)
+	}
+	return res[:size]
+}
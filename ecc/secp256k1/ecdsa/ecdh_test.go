@@ -0,0 +1,259 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+func TestSharedSecret(t *testing.T) {
+	t.Parallel()
+
+	alice, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s1, err := alice.SharedSecret(&bob.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := bob.SharedSecret(&alice.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s1, s2) {
+		t.Fatal("shared secrets differ")
+	}
+	if len(s1) != sizeFp {
+		t.Fatal("wrong size of the shared secret")
+	}
+
+	// the point at infinity is rejected
+	if _, err = alice.SharedSecret(&PublicKey{}); err != errInvalidPoint {
+		t.Fatal("expected errInvalidPoint, got", err)
+	}
+
+	// points not on the curve are rejected
+	invalid := bob.PublicKey
+	invalid.A.Y.Double(&invalid.A.Y)
+	if _, err = alice.SharedSecret(&invalid); err != errInvalidPoint {
+		t.Fatal("expected errInvalidPoint, got", err)
+	}
+}
+
+func TestECIES(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("testing ECIES")
+	sharedInfo := []byte("context")
+
+	ciphertext, err := Encrypt(rand.Reader, &privKey.PublicKey, msg, sharedInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := privKey.Decrypt(ciphertext, sharedInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, decrypted) {
+		t.Fatal("wrong decrypted message")
+	}
+
+	// wrong shared info
+	if _, err = privKey.Decrypt(ciphertext, nil); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	// wrong recipient
+	other, _ := GenerateKey(rand.Reader)
+	if _, err = other.Decrypt(ciphertext, sharedInfo); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	// tampered ciphertext
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err = privKey.Decrypt(ciphertext, sharedInfo); err != errDecryptionFailed {
+		t.Fatal("expected errDecryptionFailed, got", err)
+	}
+
+	if _, err = privKey.Decrypt(ciphertext[:sizePublicKey], sharedInfo); err != errShortCiphertext {
+		t.Fatal("expected errShortCiphertext, got", err)
+	}
+}
+
+func TestConcatKDF(t *testing.T) {
+	// test vectors of go-ethereum's crypto/ecies
+	expected, _ := hex.DecodeString("858b192fa2ed4395e2bf88dd8d5770d67dc284ee539f12da8bceaa45d06ebae0700f1ab918a5f0413b8140f9940d6955f3467fd6672cce1024c5b1effccc0f61")
+	for _, size := range []int{6, 32, 48, 64} {
+		k := concatKDF(sha256.New(), []byte("input"), nil, size)
+		if !bytes.Equal(k, expected[:size]) {
+			t.Fatal("wrong derived key", size)
+		}
+	}
+}
+
+func TestECIESDevp2p(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, s2 := []byte("s1"), []byte{0x01, 0x23}
+
+	for _, msg := range [][]byte{nil, []byte("testing devp2p ECIES"), make([]byte, 307)} {
+		ciphertext, err := EncryptDevp2p(rand.Reader, &privKey.PublicKey, msg, s1, s2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ciphertext) != len(msg)+devp2pOverhead || ciphertext[0] != 0x04 {
+			t.Fatal("wrong ciphertext format")
+		}
+		decrypted, err := privKey.DecryptDevp2p(ciphertext, s1, s2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(msg, decrypted) {
+			t.Fatal("wrong decrypted message")
+		}
+
+		// wrong shared information
+		if _, err = privKey.DecryptDevp2p(ciphertext, nil, s2); err != errDecryptionFailed {
+			t.Fatal("expected errDecryptionFailed, got", err)
+		}
+		if _, err = privKey.DecryptDevp2p(ciphertext, s1, nil); err != errDecryptionFailed {
+			t.Fatal("expected errDecryptionFailed, got", err)
+		}
+
+		// compressed ephemeral key
+		ciphertext[0] = 0x02
+		if _, err = privKey.DecryptDevp2p(ciphertext, s1, s2); err != errInvalidPoint {
+			t.Fatal("expected errInvalidPoint, got", err)
+		}
+	}
+
+	if _, err = privKey.DecryptDevp2p(make([]byte, devp2pOverhead-1), s1, s2); err != errShortCiphertext {
+		t.Fatal("expected errShortCiphertext, got", err)
+	}
+}
+
+func TestECIESDevp2pEIP8(t *testing.T) {
+	t.Parallel()
+
+	// handshake messages of EIP-8, encrypted by go-ethereum's crypto/ecies, see
+	// eip8HandshakeAuthTests and eip8HandshakeRespTests in go-ethereum's p2p/rlpx
+	newKey := func(d string) *PrivateKey {
+		s, _ := new(big.Int).SetString(d, 16)
+		var privKey PrivateKey
+		s.FillBytes(privKey.scalar[:])
+		_, g := secp256k1.Generators()
+		privKey.PublicKey.A.ScalarMultiplication(&g, s)
+		return &privKey
+	}
+	keyA := newKey("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+	keyB := newKey("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ephB := newKey("e238eb8e04fee6511ab04c6dd3c89ce097b11f25d584863ac2b6d5b35b1847e4")
+	authSignature := "299ca6acfd35e3d72d8ba3d1e2b60b5561d5af5218eb5bc182045769eb4226910a301acae3b369fffc4a4899d6b02531e89fd4fe36a2cf0d93607ba470b50f7800"
+	nonceA := "7e968bba13b6c50e2c4cd7f241cc0d64d1ac25c7f5952df231ac6a2bda8ee5d6"
+	nonceB := "559aead08264d5795d3909718cdd05abd49572e84fe55590eef31a88a08fdffd"
+
+	for _, c := range []struct {
+		name       string
+		recipient  *PrivateKey
+		ciphertext string
+		plaintext  string // rlp encoding, followed by a random padding
+	}{
+		{
+			name:      "Auth2",
+			recipient: keyB,
+			ciphertext: `
+				01b304ab7578555167be8154d5cc456f567d5ba302662433674222360f08d5f1534499d3678b513b
+				0fca474f3a514b18e75683032eb63fccb16c156dc6eb2c0b1593f0d84ac74f6e475f1b8d56116b84
+				9634a8c458705bf83a626ea0384d4d7341aae591fae42ce6bd5c850bfe0b999a694a49bbbaf3ef6c
+				da61110601d3b4c02ab6c30437257a6e0117792631a4b47c1d52fc0f8f89caadeb7d02770bf999cc
+				147d2df3b62e1ffb2c9d8c125a3984865356266bca11ce7d3a688663a51d82defaa8aad69da39ab6
+				d5470e81ec5f2a7a47fb865ff7cca21516f9299a07b1bc63ba56c7a1a892112841ca44b6e0034dee
+				70c9adabc15d76a54f443593fafdc3b27af8059703f88928e199cb122362a4b35f62386da7caad09
+				c001edaeb5f8a06d2b26fb6cb93c52a9fca51853b68193916982358fe1e5369e249875bb8d0d0ec3
+				6f917bc5e1eafd5896d46bd61ff23f1a863a8a8dcd54c7b109b771c8e61ec9c8908c733c0263440e
+				2aa067241aaa433f0bb053c7b31a838504b148f570c0ad62837129e547678c5190341e4f1693956c
+				3bf7678318e2d5b5340c9e488eefea198576344afbdf66db5f51204a6961a63ce072c8926c`,
+			// [signature, public key of A, nonce of A, version 4]
+			plaintext: "f8a7" + "b841" + authSignature + "b840" + hex.EncodeToString(keyA.PublicKey.Bytes()) + "a0" + nonceA + "04",
+		},
+		{
+			name:      "Ack2",
+			recipient: keyA,
+			ciphertext: `
+				01ea0451958701280a56482929d3b0757da8f7fbe5286784beead59d95089c217c9b917788989470
+				b0e330cc6e4fb383c0340ed85fab836ec9fb8a49672712aeabbdfd1e837c1ff4cace34311cd7f4de
+				05d59279e3524ab26ef753a0095637ac88f2b499b9914b5f64e143eae548a1066e14cd2f4bd7f814
+				c4652f11b254f8a2d0191e2f5546fae6055694aed14d906df79ad3b407d94692694e259191cde171
+				ad542fc588fa2b7333313d82a9f887332f1dfc36cea03f831cb9a23fea05b33deb999e85489e645f
+				6aab1872475d488d7bd6c7c120caf28dbfc5d6833888155ed69d34dbdc39c1f299be1057810f34fb
+				e754d021bfca14dc989753d61c413d261934e1a9c67ee060a25eefb54e81a4d14baff922180c395d
+				3f998d70f46f6b58306f969627ae364497e73fc27f6d17ae45a413d322cb8814276be6ddd13b885b
+				201b943213656cde498fa0e9ddc8e0b8f8a53824fbd82254f3e2c17e8eaea009c38b4aa0a3f306e8
+				797db43c25d68e86f262e564086f59a2fc60511c42abfb3057c247a8a8fe4fb3ccbadde17514b7ac
+				8000cdb6a912778426260c47f38919a91f25f4b5ffb455d6aaaf150f7e5529c100ce62d6d92826a7
+				1778d809bdf60232ae21ce8a437eca8223f45ac37f6487452ce626f549b3b5fdee26afd2072e4bc7
+				5833c2464c805246155289f4`,
+			// [ephemeral public key of B, nonce of B, version 4]
+			plaintext: "f864" + "b840" + hex.EncodeToString(ephB.PublicKey.Bytes()) + "a0" + nonceB + "04",
+		},
+	} {
+		packet, err := hex.DecodeString(strings.Join(strings.Fields(c.ciphertext), ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the size prefix of the packet is the shared information s2
+		decrypted, err := c.recipient.DecryptDevp2p(packet[2:], nil, packet[:2])
+		if err != nil {
+			t.Fatal(c.name, err)
+		}
+		if expected, _ := hex.DecodeString(c.plaintext); !bytes.HasPrefix(decrypted, expected) {
+			t.Fatalf("%s: wrong plaintext %x", c.name, decrypted)
+		}
+	}
+}
+
+func BenchmarkSharedSecret(b *testing.B) {
+	alice, _ := GenerateKey(rand.Reader)
+	bob, _ := GenerateKey(rand.Reader)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		alice.SharedSecret(&bob.PublicKey)
+	}
+}
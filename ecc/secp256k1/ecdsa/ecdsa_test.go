@@ -17,10 +17,18 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 )
@@ -88,6 +96,343 @@ func TestRecoverPublicKey(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestDeterministicNonce(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 10
+	}
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[SECP256K1] RFC 6979 signatures should be deterministic and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			// the nonce depends on the HMAC hash function
+			sig3, err := privKey.SignWithOptions(msg, hFunc, WithDeterministicNonce(sha512.New))
+			if err != nil {
+				return false
+			}
+			flag1, _ := publicKey.Verify(sig1, msg, hFunc)
+			flag3, _ := publicKey.Verify(sig3, msg, hFunc)
+
+			return flag1 && flag3 && bytes.Equal(sig1, sig2) && !bytes.Equal(sig1, sig3)
+		},
+	))
+
+	properties.Property("[SECP256K1] RFC 6979 signatures should be deterministic and valid (pre-hashed)", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			sig1, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sig1, msg, nil)
+
+			return flag && bytes.Equal(sig1, sig2)
+		},
+	))
+
+	properties.Property("[SECP256K1] hedged signatures should be randomized and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignWithOptions(msg, hFunc, WithHedgedNonce(sha256.New, rand.Reader))
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignWithOptions(msg, hFunc, WithHedgedNonce(sha256.New, rand.Reader))
+			if err != nil {
+				return false
+			}
+			flag1, _ := publicKey.Verify(sig1, msg, hFunc)
+			flag2, _ := publicKey.Verify(sig2, msg, hFunc)
+
+			return flag1 && flag2 && !bytes.Equal(sig1, sig2)
+		},
+	))
+
+	properties.Property("[SECP256K1] low-S signatures should be normalized and valid", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sigBin, err := privKey.SignWithOptions(msg, hFunc, WithLowS())
+			if err != nil {
+				return false
+			}
+			var sig Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sigBin, msg, hFunc)
+
+			// the recovery information must match the normalized signature
+			v, r, s, err := privKey.SignForRecover(msg, nil, WithLowS(), WithDeterministicNonce(sha256.New))
+			if err != nil {
+				return false
+			}
+			var recovered PublicKey
+			if err := recovered.RecoverFrom(msg, v, r, s); err != nil {
+				return false
+			}
+			flag = flag && s.Cmp(halfOrder) <= 0 && recovered.Equal(&publicKey)
+
+			return flag && sig.IsLowS()
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestSignOptions(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("testing ECDSA")
+	if _, err := privKey.SignWithOptions(msg, nil, WithDeterministicNonce(nil)); err == nil {
+		t.Fatal("nil hash function should be rejected")
+	}
+	if _, err := privKey.SignWithOptions(msg, nil, WithHedgedNonce(sha256.New, nil)); err == nil {
+		t.Fatal("nil entropy source should be rejected")
+	}
+
+	// out of range signatures are rejected
+	var sig Signature
+	order.FillBytes(sig.R[:])
+	sig.S[sizeFr-1] = 1
+	if flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, nil); flag {
+		t.Fatal("r = order should be rejected")
+	}
+	sig = Signature{}
+	sig.R[sizeFr-1] = 1
+	if flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, nil); flag {
+		t.Fatal("s = 0 should be rejected")
+	}
+}
+
+func TestBatchRecover(t *testing.T) {
+	t.Parallel()
+
+	const n = 8
+	msgs := make([][]byte, n)
+	vs := make([]uint, n)
+	rs := make([]*big.Int, n)
+	ss := make([]*big.Int, n)
+	keys := make([]*PrivateKey, n)
+	for i := 0; i < n; i++ {
+		var err error
+		keys[i], err = GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs[i] = []byte{byte(i)}
+		vs[i], rs[i], ss[i], err = keys[i].SignForRecover(msgs[i], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recovered, err := BatchRecover(msgs, vs, rs, ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range recovered {
+		if !recovered[i].Equal(&keys[i].PublicKey) {
+			t.Fatal("wrong recovered public key", i)
+		}
+	}
+
+	// an invalid signature makes the whole batch fail
+	ss[n/2] = new(big.Int)
+	if _, err = BatchRecover(msgs, vs, rs, ss); err == nil {
+		t.Fatal("s = 0 should be rejected")
+	}
+	if _, err = BatchRecover(msgs[1:], vs, rs, ss); err == nil {
+		t.Fatal("inconsistent lengths should be rejected")
+	}
+}
+
+func TestRFC6979(t *testing.T) {
+	t.Parallel()
+
+	// test vectors for RFC 6979 on secp256k1 with SHA-256, used by the Bitcoin libraries
+	for _, c := range []struct {
+		d, msg, k, r, s string
+	}{
+		{
+			d:   "1",
+			msg: "Satoshi Nakamoto",
+			k:   "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+			r:   "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+			s:   "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			d:   "1",
+			msg: "All those moments will be lost in time, like tears in rain. Time to die...",
+			k:   "38aa22d72376b4dbc472e06c3ba403ee0a394da63fc58d88686c611aba98d6b3",
+			r:   "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b",
+			s:   "547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+		},
+		{
+			d:   "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			msg: "Satoshi Nakamoto",
+			k:   "33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90",
+			r:   "fd567d121db66e382991534ada77a6bd3106f0a1098c231e47993447cd6af2d0",
+		},
+	} {
+		d, _ := new(big.Int).SetString(c.d, 16)
+		var privKey PrivateKey
+		d.FillBytes(privKey.scalar[:])
+		_, g := secp256k1.Generators()
+		privKey.PublicKey.A.ScalarMultiplication(&g, d)
+
+		digest := sha256.Sum256([]byte(c.msg))
+		k, err := newRFC6979Nonces(sha256.New, privKey.scalar[:], digest[:], nil).next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(k.Bytes()) != c.k {
+			t.Fatalf("%q: wrong nonce %x", c.msg, k)
+		}
+
+		sigBin, err := privKey.SignWithOptions([]byte(c.msg), sha256.New(), WithDeterministicNonce(sha256.New), WithLowS())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig Signature
+		if _, err := sig.SetBytes(sigBin); err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(sig.R[:]) != c.r {
+			t.Fatalf("%q: wrong r %x", c.msg, sig.R)
+		}
+		if c.s != "" && hex.EncodeToString(sig.S[:]) != c.s {
+			t.Fatalf("%q: wrong s %x", c.msg, sig.S)
+		}
+		if flag, _ := privKey.PublicKey.Verify(sigBin, []byte(c.msg), sha256.New()); !flag {
+			t.Fatalf("%q: signature should be valid", c.msg)
+		}
+	}
+}
+
+// wycheproofVectors are the ECDSA verification vectors of Project Wycheproof,
+// https://github.com/C2SP/wycheproof, vendored in testdata from the testvectors directory
+// of github.com/google/wycheproof at commit 2196000605e4.
+var wycheproofVectors = []struct {
+	file string
+	der  bool
+}{
+	{file: "ecdsa_secp256k1_sha256_test.json", der: true},
+	{file: "ecdsa_secp256k1_sha256_p1363_test.json", der: false},
+}
+
+type wycheproofTestFile struct {
+	TestGroups []struct {
+		// the key is named key in the older versions of the vectors
+		Key struct {
+			Uncompressed string `json:"uncompressed"`
+		} `json:"key"`
+		PublicKey struct {
+			Uncompressed string `json:"uncompressed"`
+		} `json:"publicKey"`
+		Sha   string `json:"sha"`
+		Tests []struct {
+			TcID    int    `json:"tcId"`
+			Comment string `json:"comment"`
+			Msg     string `json:"msg"`
+			Sig     string `json:"sig"`
+			Result  string `json:"result"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+func TestWycheproof(t *testing.T) {
+	for _, vectors := range wycheproofVectors {
+		vectors := vectors
+		t.Run(vectors.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", vectors.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var testFile wycheproofTestFile
+			if err := json.Unmarshal(data, &testFile); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, group := range testFile.TestGroups {
+				if group.Sha != "SHA-256" {
+					t.Fatalf("unexpected hash function %s", group.Sha)
+				}
+				key := group.PublicKey.Uncompressed
+				if key == "" {
+					key = group.Key.Uncompressed
+				}
+				keyBin, err := hex.DecodeString(key)
+				if err != nil || len(keyBin) != 1+sizePublicKey || keyBin[0] != 0x04 {
+					t.Fatalf("invalid public key %s", key)
+				}
+				var publicKey PublicKey
+				if _, err := publicKey.SetBytes(keyBin[1:]); err != nil {
+					t.Fatal(err)
+				}
+
+				for _, test := range group.Tests {
+					if test.Result == "acceptable" {
+						continue
+					}
+					msg, err := hex.DecodeString(test.Msg)
+					if err != nil {
+						t.Fatal(err)
+					}
+					sigBin, err := hex.DecodeString(test.Sig)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					var flag bool
+					var sig Signature
+					if vectors.der {
+						n, err := sig.SetBytesDER(sigBin)
+						if err == nil && n == len(sigBin) {
+							flag, _ = publicKey.Verify(sig.Bytes(), msg, sha256.New())
+						}
+					} else if len(sigBin) == sizeSignature {
+						flag, _ = publicKey.Verify(sigBin, msg, sha256.New())
+					}
+
+					if flag != (test.Result == "valid") {
+						t.Errorf("tcId %d (%s): expected %s, got %v", test.TcID, test.Comment, test.Result, flag)
+					}
+				}
+			}
+		})
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -130,3 +475,27 @@ func BenchmarkRecoverPublicKey(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkBatchRecover(b *testing.B) {
+	const n = 256
+	msgs := make([][]byte, n)
+	vs := make([]uint, n)
+	rs := make([]*big.Int, n)
+	ss := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		sk, err := GenerateKey(rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		msgs[i] = []byte{byte(i)}
+		if vs[i], rs[i], ss[i], err = sk.SignForRecover(msgs[i], nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := BatchRecover(msgs, vs, rs, ss); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -18,13 +18,20 @@ package ecdsa
 
 import (
 	"crypto/subtle"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
 )
 
+var errInvalidDER = errors.New("invalid DER encoding of the signature")
+
 // Bytes returns the binary representation of the public key
 // follows https://tools.ietf.org/html/rfc8032#section-3.1
 // and returns a compressed representation of the point (x,y)
@@ -60,7 +67,24 @@ func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
 // information v and decompose signature {r,s}. If recovery succeeded, the
 // methods sets the current public key to the recovered value. Otherwise returns
 // error and leaves current public key unchanged.
+//
+// v is the recovery information returned by SignForRecover or SetBytesRecoverable,
+// the recovery id of Ethereum, see RecoverP.
 func (pk *PublicKey) RecoverFrom(msg []byte, v uint, r, s *big.Int) error {
+	var Q secp256k1.G1Jac
+	if err := recoverJac(&Q, msg, v, r, s); err != nil {
+		return err
+	}
+	pk.A.FromJacobian(&Q)
+	return nil
+}
+
+// recoverJac sets Q to the public key recovered from the message msg, the
+// recovery information v and the signature {r,s}, in Jacobian coordinates.
+func recoverJac(Q *secp256k1.G1Jac, msg []byte, v uint, r, s *big.Int) error {
+	if s.Sign() <= 0 || s.Cmp(fr.Modulus()) >= 0 {
+		return errors.New("s is not in [1, order-1]")
+	}
 	P, err := RecoverP(v, r)
 	if err != nil {
 		return err
@@ -72,12 +96,50 @@ func (pk *PublicKey) RecoverFrom(msg []byte, v uint, r, s *big.Int) error {
 	u1.Mod(u1, fr.Modulus())
 	u2 := new(big.Int).Mul(s, rinv)
 	u2.Mod(u2, fr.Modulus())
-	var Q secp256k1.G1Jac
 	Q.JointScalarMultiplicationBase(P, u1, u2)
-	pk.A.FromJacobian(&Q)
+	if Q.Z.IsZero() {
+		return errors.New("recovered public key is the point at infinity")
+	}
 	return nil
 }
 
+// BatchRecover recovers the public keys from the messages msgs[i], the
+// recovery information v[i] and the signatures {r[i],s[i]}, as in
+// PublicKey.RecoverFrom. The recoveries are run in parallel and the
+// conversions to affine coordinates share a single inversion.
+//
+// As in RecoverFrom, v[i] is the recovery information returned by SignForRecover
+// or SetBytesRecoverable.
+//
+// If any recovery fails, it returns an error wrapping the index of the first
+// failing signature.
+func BatchRecover(msgs [][]byte, v []uint, r, s []*big.Int) ([]PublicKey, error) {
+	n := len(msgs)
+	if len(v) != n || len(r) != n || len(s) != n {
+		return nil, errors.New("inconsistent number of messages, recovery ids and signatures")
+	}
+
+	points := make([]secp256k1.G1Jac, n)
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			errs[i] = recoverJac(&points[i], msgs[i], v[i], r[i], s[i])
+		}
+	})
+	for i := range errs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("signature %d: %w", i, errs[i])
+		}
+	}
+
+	affine := secp256k1.BatchJacobianToAffineG1(points)
+	res := make([]PublicKey, n)
+	for i := range affine {
+		res[i].A = affine[i]
+	}
+	return res, nil
+}
+
 // Bytes returns the binary representation of pk,
 // as byte array publicKey||scalar
 // where publicKey is as publicKey.Bytes(), and
@@ -132,3 +194,93 @@ func (sig *Signature) SetBytes(buf []byte) (int, error) {
 	n += sizeFr
 	return n, nil
 }
+
+// BytesDER returns the ASN.1 DER encoding of sig, as in SEC 1, Version 2.0, Appendix C.8:
+//
+//	ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+func (sig *Signature) BytesDER() []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig.R[:]))
+		b.AddASN1BigInt(new(big.Int).SetBytes(sig.S[:]))
+	})
+	// the builder can't fail on non-negative integers
+	return b.BytesOrPanic()
+}
+
+// SetBytesDER sets sig from its ASN.1 DER encoding, see BytesDER.
+// Non-canonical encodings (BER lengths, non-minimal or negative integers) are rejected,
+// as well as integers which don't fit in sizeFr bytes.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytesDER(buf []byte) (int, error) {
+	input := cryptobyte.String(buf)
+	var inner cryptobyte.String
+	r, s := new(big.Int), new(big.Int)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return 0, errInvalidDER
+	}
+	if r.Sign() < 0 || s.Sign() < 0 || r.BitLen() > 8*sizeFr || s.BitLen() > 8*sizeFr {
+		return 0, errInvalidDER
+	}
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+	return len(buf) - len(input), nil
+}
+
+// IsLowS returns true if s ≤ order/2. Both (r, s) and (r, order-s) are valid
+// signatures of a message; protocols requiring non-malleable signatures only
+// accept the one with the low s.
+func (sig *Signature) IsLowS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS sets s to order-s if s > order/2, see IsLowS.
+func (sig *Signature) NormalizeS() {
+	s := new(big.Int).SetBytes(sig.S[:])
+	if s.Cmp(halfOrder) > 0 && s.Cmp(order) < 0 {
+		s.Sub(order, s)
+		s.FillBytes(sig.S[:])
+	}
+}
+
+// sizeSignatureRecoverable is the size of the r||s||v encoding
+const sizeSignatureRecoverable = sizeSignature + 1
+
+// BytesRecoverable returns the 65 bytes encoding r||s||v of the signature and the
+// recovery information returned by SignForRecover, as used by Ethereum.
+func BytesRecoverable(v uint, r, s *big.Int) ([]byte, error) {
+	if v > 3 {
+		return nil, errors.New("invalid recovery information")
+	}
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(order) >= 0 || s.Cmp(order) >= 0 {
+		return nil, errors.New("r or s is out of range")
+	}
+	var res [sizeSignatureRecoverable]byte
+	r.FillBytes(res[:sizeFr])
+	s.FillBytes(res[sizeFr:sizeSignature])
+	res[sizeSignature] = byte(v)
+	return res[:], nil
+}
+
+// SetBytesRecoverable decodes the r||s||v encoding of a signature, see BytesRecoverable.
+// v is accepted either in [0, 3] or, following the legacy Ethereum convention, in [27, 30].
+// It returns the recovery information as expected by RecoverFrom.
+func SetBytesRecoverable(buf []byte) (v uint, r, s *big.Int, err error) {
+	if len(buf) < sizeSignatureRecoverable {
+		return 0, nil, nil, io.ErrShortBuffer
+	}
+	id := uint(buf[sizeSignature])
+	if id >= 27 {
+		id -= 27
+	}
+	if id > 3 {
+		return 0, nil, nil, errors.New("invalid recovery information")
+	}
+	r = new(big.Int).SetBytes(buf[:sizeFr])
+	s = new(big.Int).SetBytes(buf[sizeFr:sizeSignature])
+	return id, r, s, nil
+}
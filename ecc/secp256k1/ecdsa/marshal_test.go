@@ -17,12 +17,17 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
+	"golang.org/x/crypto/sha3"
 )
 
 const (
@@ -62,3 +67,198 @@ func TestSerialization(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+func TestSerializationSignature(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[SECP256K1] ECDSA DER serialization: SetBytesDER(BytesDER()) should stay the same", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			sigBin, _ := privKey.Sign([]byte("testing ECDSA"), sha256.New())
+			var sig, end Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+
+			der := sig.BytesDER()
+			n, err := end.SetBytesDER(der)
+			if err != nil || n != len(der) {
+				return false
+			}
+
+			// trailing data is not read
+			n, err = end.SetBytesDER(append(der, 0x00))
+			if err != nil || n != len(der) {
+				return false
+			}
+
+			return bytes.Equal(end.Bytes(), sigBin)
+		},
+	))
+
+	properties.Property("[SECP256K1] ECDSA low-S normalization should give a valid signature", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			msg := []byte("testing ECDSA")
+			sigBin, _ := privKey.Sign(msg, sha256.New())
+			var sig Signature
+			if _, err := sig.SetBytes(sigBin); err != nil {
+				return false
+			}
+			wasLow := sig.IsLowS()
+			sig.NormalizeS()
+			if !sig.IsLowS() || wasLow != bytes.Equal(sig.Bytes(), sigBin) {
+				return false
+			}
+			flag, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, sha256.New())
+			return flag
+		},
+	))
+
+	properties.Property("[SECP256K1] ECDSA r||s||v serialization should allow public key recovery", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			msg := []byte("testing ECDSA")
+			v, r, s, err := privKey.SignForRecover(msg, nil)
+			if err != nil {
+				return false
+			}
+			buf, err := BytesRecoverable(v, r, s)
+			if err != nil || len(buf) != sizeSignatureRecoverable {
+				return false
+			}
+
+			// legacy Ethereum recovery id
+			legacy := append([]byte{}, buf...)
+			legacy[sizeSignature] += 27
+
+			for _, b := range [][]byte{buf, legacy} {
+				v2, r2, s2, err := SetBytesRecoverable(b)
+				if err != nil || v2 != v || r2.Cmp(r) != 0 || s2.Cmp(s) != 0 {
+					return false
+				}
+				var recovered PublicKey
+				if err := recovered.RecoverFrom(msg, v2, r2, s2); err != nil || !recovered.Equal(&privKey.PublicKey) {
+					return false
+				}
+			}
+			return true
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestBytesRecoverableEthereum(t *testing.T) {
+	t.Parallel()
+
+	// signatures of go-ethereum: the ValidKey vector of the ecrecover precompile
+	// (core/vm/testdata/precompiles/ecRecover.json) with the expected address, and
+	// testsig of crypto/signature_test.go with the expected public key
+	var msgs [][]byte
+	var vs []uint
+	var rs, ss []*big.Int
+	var expected []PublicKey
+	for _, c := range []struct {
+		hash, sig, address, publicKey string
+	}{
+		{
+			hash:    "18c547e4f7b0f325ad1e56f57e26c745b09a3e503d86e00e5255ff7f715d3d1c",
+			sig:     "73b1693892219d736caba55bdb67216e485557ea6b6af75f37096c9aa6a5a75feeb940b1d03b21e36b0e47e79769f095fe2ab855bd91e3a38756b7d75a9c45491c",
+			address: "a94f5374fce5edbc8e2a8697c15331677e6ebf0b",
+		},
+		{
+			hash:      "ce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008",
+			sig:       "90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc9301",
+			publicKey: "e32df42865e97135acfb65f3bae71bdc86f4d49150ad6a440b6f15878109880a0a2b2667f7e725ceea70c673093bf67663e0312623c8e091b13cf2c0f11ef652",
+		},
+	} {
+		hash, _ := hex.DecodeString(c.hash)
+		sigBin, _ := hex.DecodeString(c.sig)
+		v, r, s, err := SetBytesRecoverable(sigBin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs, vs, rs, ss = append(msgs, hash), append(vs, v), append(rs, r), append(ss, s)
+		var publicKey PublicKey
+		if err = publicKey.RecoverFrom(hash, v, r, s); err != nil {
+			t.Fatal(err)
+		}
+		if c.publicKey != "" && hex.EncodeToString(publicKey.Bytes()) != c.publicKey {
+			t.Fatalf("wrong public key %x", publicKey.Bytes())
+		}
+		if c.address != "" {
+			h := sha3.NewLegacyKeccak256()
+			h.Write(publicKey.Bytes())
+			if address := h.Sum(nil)[12:]; hex.EncodeToString(address) != c.address {
+				t.Fatalf("wrong address %x", address)
+			}
+		}
+
+		// the encoding gives back the recovery id of Ethereum
+		buf, err := BytesRecoverable(v, r, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := sigBin[sizeSignature] % 27; !bytes.Equal(buf[:sizeSignature], sigBin[:sizeSignature]) || buf[sizeSignature] != id {
+			t.Fatalf("wrong encoding %x", buf)
+		}
+		expected = append(expected, publicKey)
+	}
+
+	// the decoded recovery information is the one expected by BatchRecover
+	recovered, err := BatchRecover(msgs, vs, rs, ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range recovered {
+		if !recovered[i].Equal(&expected[i]) {
+			t.Fatal("wrong recovered public key", i)
+		}
+	}
+}
+
+func TestSetBytesDERInvalid(t *testing.T) {
+	t.Parallel()
+
+	// r doesn't fit in sizeFr bytes
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 8*sizeFr)
+	tooLargeBin := append([]byte{0x02, byte(len(tooLarge.Bytes()))}, tooLarge.Bytes()...)
+	tooLargeDER := append([]byte{0x30, byte(len(tooLargeBin) + 3)}, tooLargeBin...)
+	tooLargeDER = append(tooLargeDER, 0x02, 0x01, 0x01)
+
+	for _, c := range []struct {
+		name, der string
+		valid     bool
+	}{
+		{"valid", "3006020101020101", true},
+		{"long form length", "308106020101020101", false},
+		{"indefinite length", "3080020101020101", false},
+		{"non-minimal integer", "300702020001020101", false},
+		{"negative integer", "30060201ff020101", false},
+		{"trailing data in sequence", "30080201010201010500", false},
+		{"missing s", "3003020101", false},
+		{"wrong tag", "3106020101020101", false},
+		{"truncated", "30060201010201", false},
+		{"empty", "", false},
+		{"too large", hex.EncodeToString(tooLargeDER), false},
+	} {
+		der, err := hex.DecodeString(c.der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig Signature
+		_, err = sig.SetBytesDER(der)
+		if (err == nil) != c.valid {
+			t.Fatalf("%s: expected valid=%v, got error %v", c.name, c.valid, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var (
+	errCurveMismatch     = errors.New("the key is not defined over secp256k1")
+	errInvalidPublicKey  = errors.New("the public key is not a valid point of the curve")
+	errInvalidPrivateKey = errors.New("the private key is not in [1, order-1] or does not match the public key")
+)
+
+// stdCurve implements elliptic.Curve for secp256k1, so that the keys can be
+// used with crypto/ecdsa. The point at infinity is encoded as (0, 0).
+type stdCurve struct {
+	params *elliptic.CurveParams
+}
+
+var (
+	stdCurveOnce     sync.Once
+	stdCurveInstance stdCurve
+)
+
+// Curve returns an implementation of elliptic.Curve for secp256k1.
+//
+// The arithmetic is not constant time; it is meant for interoperability
+// with the standard library and shouldn't be used to handle secret scalars
+// on hot paths, for which PrivateKey is more efficient.
+func Curve() elliptic.Curve {
+	stdCurveOnce.Do(func() {
+		_, g := secp256k1.Generators()
+		_, b := secp256k1.CurveCoefficients()
+		stdCurveInstance.params = &elliptic.CurveParams{
+			P:       fp.Modulus(),
+			N:       fr.Modulus(),
+			B:       b.BigInt(new(big.Int)),
+			Gx:      g.X.BigInt(new(big.Int)),
+			Gy:      g.Y.BigInt(new(big.Int)),
+			BitSize: fp.Bits,
+			Name:    "secp256k1",
+		}
+	})
+	return &stdCurveInstance
+}
+
+// Params returns the parameters of the curve. Note that the generic
+// arithmetic of elliptic.CurveParams assumes a = -3 and must not be used.
+func (c *stdCurve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// toAffine returns the point (x, y), which is not checked to be on the curve.
+func toAffine(x, y *big.Int) (p secp256k1.G1Affine) {
+	p.X.SetBigInt(x)
+	p.Y.SetBigInt(y)
+	return
+}
+
+// fromAffine returns the coordinates of p, (0, 0) if p is the point at infinity.
+func fromAffine(p *secp256k1.G1Affine) (x, y *big.Int) {
+	return p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int))
+}
+
+func fromJacobian(p *secp256k1.G1Jac) (x, y *big.Int) {
+	var a secp256k1.G1Affine
+	a.FromJacobian(p)
+	return fromAffine(&a)
+}
+
+// IsOnCurve reports whether (x, y) is a point of the curve, other than the
+// point at infinity.
+func (c *stdCurve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || y.Sign() < 0 || x.Cmp(c.params.P) >= 0 || y.Cmp(c.params.P) >= 0 {
+		return false
+	}
+	p := toAffine(x, y)
+	return !p.IsInfinity() && p.IsOnCurve()
+}
+
+// Add returns the sum of (x1, y1) and (x2, y2).
+func (c *stdCurve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	var p secp256k1.G1Jac
+	a1, a2 := toAffine(x1, y1), toAffine(x2, y2)
+	p.FromAffine(&a1).AddMixed(&a2)
+	return fromJacobian(&p)
+}
+
+// Double returns 2⋅(x1, y1).
+func (c *stdCurve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	var p secp256k1.G1Jac
+	a := toAffine(x1, y1)
+	p.FromAffine(&a).DoubleAssign()
+	return fromJacobian(&p)
+}
+
+// ScalarMult returns k⋅(x1, y1) where k is a big endian integer.
+func (c *stdCurve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	var p secp256k1.G1Affine
+	a := toAffine(x1, y1)
+	p.ScalarMultiplication(&a, new(big.Int).SetBytes(k))
+	return fromAffine(&p)
+}
+
+// ScalarBaseMult returns k⋅G, where G is the base point of the group and k is
+// a big endian integer.
+func (c *stdCurve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	var p secp256k1.G1Affine
+	p.ScalarMultiplicationBase(new(big.Int).SetBytes(k))
+	return fromAffine(&p)
+}
+
+// isStdCurve returns true if c has the parameters of secp256k1.
+func isStdCurve(c elliptic.Curve) bool {
+	if c == nil {
+		return false
+	}
+	if c == Curve() {
+		return true
+	}
+	params, ours := c.Params(), Curve().Params()
+	return params != nil &&
+		params.P.Cmp(ours.P) == 0 &&
+		params.N.Cmp(ours.N) == 0 &&
+		params.B.Cmp(ours.B) == 0 &&
+		params.Gx.Cmp(ours.Gx) == 0 &&
+		params.Gy.Cmp(ours.Gy) == 0
+}
+
+// ToECDSA returns the public key as a crypto/ecdsa public key over Curve().
+func (pk *PublicKey) ToECDSA() *ecdsa.PublicKey {
+	x, y := fromAffine(&pk.A)
+	return &ecdsa.PublicKey{Curve: Curve(), X: x, Y: y}
+}
+
+// FromECDSA sets pk from a crypto/ecdsa public key. It returns an error if the
+// key is not defined over secp256k1 or is not a valid point of the curve.
+func (pk *PublicKey) FromECDSA(pub *ecdsa.PublicKey) error {
+	if pub == nil || !isStdCurve(pub.Curve) {
+		return errCurveMismatch
+	}
+	if pub.X == nil || pub.Y == nil || !Curve().IsOnCurve(pub.X, pub.Y) {
+		return errInvalidPublicKey
+	}
+	pk.A = toAffine(pub.X, pub.Y)
+	return nil
+}
+
+// ToECDSA returns the private key as a crypto/ecdsa private key over Curve().
+func (privKey *PrivateKey) ToECDSA() *ecdsa.PrivateKey {
+	return &ecdsa.PrivateKey{
+		PublicKey: *privKey.PublicKey.ToECDSA(),
+		D:         new(big.Int).SetBytes(privKey.scalar[:]),
+	}
+}
+
+// FromECDSA sets privKey from a crypto/ecdsa private key. It returns an error
+// if the key is not defined over secp256k1, if the scalar is not in
+// [1, order-1] or if the public key doesn't match the scalar.
+func (privKey *PrivateKey) FromECDSA(priv *ecdsa.PrivateKey) error {
+	if priv == nil {
+		return errCurveMismatch
+	}
+	var pub PublicKey
+	if err := pub.FromECDSA(&priv.PublicKey); err != nil {
+		return err
+	}
+	if priv.D == nil || priv.D.Sign() <= 0 || priv.D.Cmp(order) >= 0 {
+		return errInvalidPrivateKey
+	}
+	var expected secp256k1.G1Affine
+	expected.ScalarMultiplicationBase(priv.D)
+	if !expected.Equal(&pub.A) {
+		return errInvalidPrivateKey
+	}
+	privKey.PublicKey = pub
+	priv.D.FillBytes(privKey.scalar[:])
+	return nil
+}
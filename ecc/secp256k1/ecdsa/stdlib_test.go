@@ -0,0 +1,169 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+func TestStdCurve(t *testing.T) {
+	t.Parallel()
+
+	c := Curve()
+	params := c.Params()
+	if !c.IsOnCurve(params.Gx, params.Gy) {
+		t.Fatal("generator not on curve")
+	}
+	if c.IsOnCurve(params.Gx, new(big.Int).Add(params.Gy, big.NewInt(1))) {
+		t.Fatal("invalid point accepted")
+	}
+	if c.IsOnCurve(new(big.Int), new(big.Int)) {
+		t.Fatal("point at infinity accepted")
+	}
+
+	// 3⋅G = 2⋅G + G
+	x2, y2 := c.Double(params.Gx, params.Gy)
+	x3, y3 := c.Add(x2, y2, params.Gx, params.Gy)
+	ex, ey := c.ScalarBaseMult([]byte{3})
+	if x3.Cmp(ex) != 0 || y3.Cmp(ey) != 0 {
+		t.Fatal("2⋅G + G != 3⋅G")
+	}
+	ex, ey = c.ScalarMult(params.Gx, params.Gy, []byte{3})
+	if x3.Cmp(ex) != 0 || y3.Cmp(ey) != 0 {
+		t.Fatal("ScalarMult and ScalarBaseMult disagree")
+	}
+
+	// N⋅G = 0
+	ex, ey = c.ScalarBaseMult(params.N.Bytes())
+	if ex.Sign() != 0 || ey.Sign() != 0 {
+		t.Fatal("N⋅G is not the point at infinity")
+	}
+}
+
+func TestStdlibInterop(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("testing ECDSA interoperability")
+	digest := sha256.Sum256(msg)
+
+	// signed by crypto/ecdsa, verified by this package
+	stdKey, err := ecdsa.GenerateKey(Curve(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, stdKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig Signature
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+
+	var privKey PrivateKey
+	if err = privKey.FromECDSA(stdKey); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := privKey.PublicKey.Verify(sig.Bytes(), digest[:], nil); err != nil || !ok {
+		t.Fatal("signature of crypto/ecdsa rejected")
+	}
+
+	// signed by this package, verified by crypto/ecdsa
+	sk, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigBin, err := sk.Sign(msg, sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = sig.SetBytes(sigBin); err != nil {
+		t.Fatal(err)
+	}
+	r.SetBytes(sig.R[:])
+	s.SetBytes(sig.S[:])
+	if !ecdsa.Verify(sk.PublicKey.ToECDSA(), digest[:], r, s) {
+		t.Fatal("signature rejected by crypto/ecdsa")
+	}
+
+	// round trip
+	var decoded PrivateKey
+	if err = decoded.FromECDSA(sk.ToECDSA()); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.scalar != sk.scalar || !decoded.PublicKey.Equal(&sk.PublicKey) {
+		t.Fatal("round trip through crypto/ecdsa failed")
+	}
+}
+
+func TestFromECDSAInvalid(t *testing.T) {
+	t.Parallel()
+
+	sk, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pk PublicKey
+	var privKey PrivateKey
+
+	// not on the curve
+	pub := sk.PublicKey.ToECDSA()
+	pub.Y.Add(pub.Y, big.NewInt(1))
+	if err = pk.FromECDSA(pub); err != errInvalidPublicKey {
+		t.Fatal("expected errInvalidPublicKey, got", err)
+	}
+
+	// another curve
+	pub = sk.PublicKey.ToECDSA()
+	pub.Curve = nil
+	if err = pk.FromECDSA(pub); err != errCurveMismatch {
+		t.Fatal("expected errCurveMismatch, got", err)
+	}
+
+	// scalar not matching the public key
+	priv := sk.ToECDSA()
+	priv.D.Add(priv.D, big.NewInt(1))
+	if err = privKey.FromECDSA(priv); err != errInvalidPrivateKey {
+		t.Fatal("expected errInvalidPrivateKey, got", err)
+	}
+
+	// scalar out of range
+	var g secp256k1.G1Affine
+	g.ScalarMultiplicationBase(big.NewInt(1))
+	priv = (&PrivateKey{PublicKey: PublicKey{A: g}}).ToECDSA()
+	priv.D.Add(fr.Modulus(), big.NewInt(1))
+	if err = privKey.FromECDSA(priv); err != errInvalidPrivateKey {
+		t.Fatal("expected errInvalidPrivateKey, got", err)
+	}
+}
+
+func BenchmarkVerifyStdlib(b *testing.B) {
+	stdKey, _ := ecdsa.GenerateKey(Curve(), rand.Reader)
+	digest := sha256.Sum256([]byte("benchmarking ECDSA verify()"))
+	r, s, _ := ecdsa.Sign(rand.Reader, stdKey, digest[:])
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ecdsa.Verify(&stdKey.PublicKey, digest[:], r, s)
+	}
+}
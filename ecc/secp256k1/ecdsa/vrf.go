@@ -0,0 +1,265 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	// vrfSuite is the suite_string of ECVRF-SECP256K1-SHA256-TAI. RFC 9381 only registers
+	// suites on P-256 and edwards25519, 0xFE is the value used by other secp256k1 implementations.
+	vrfSuite = 0xfe
+
+	// sizeVRFChallenge is cLen, the size of the challenge
+	sizeVRFChallenge = 16
+
+	// sizeVRFProof is the size of an encoded proof, Γ (compressed) || c || s
+	sizeVRFProof = sizeCompressedPoint + sizeVRFChallenge + sizeFr
+
+	// VRFOutputSize is the size of the VRF outputs
+	VRFOutputSize = sha256.Size
+)
+
+// domain separators of the hashes (RFC 9381 Section 5.4)
+const (
+	vrfDomainEncodeToCurve = 0x01
+	vrfDomainChallenge     = 0x02
+	vrfDomainProofToHash   = 0x03
+	vrfDomainBack          = 0x00
+)
+
+var (
+	errInvalidVRFKey       = errors.New("the public key is not a valid VRF key")
+	errInvalidVRFProof     = errors.New("invalid VRF proof encoding")
+	errVRFEncodeToCurve    = errors.New("no valid point found by try-and-increment")
+	errVRFInconsistentSize = errors.New("inconsistent number of public keys, inputs and proofs")
+)
+
+// VRFProof is a proof π = (Γ, c, s) of the verifiable random function ECVRF (RFC 9381),
+// in the ECVRF-SECP256K1-SHA256-TAI suite: the ECVRF-P256-SHA256-TAI suite on secp256k1.
+type VRFProof struct {
+	Gamma secp256k1.G1Affine
+	C     [sizeVRFChallenge]byte
+	S     [sizeFr]byte
+}
+
+// ProveVRF returns the proof that VRFProof.Output is the VRF output of privKey on alpha
+// (RFC 9381 Section 5.1):
+//
+//	H = encode_to_curve_try_and_increment(Y, alpha), Γ = x⋅H
+//	k = RFC 6979 nonce of SHA-256(H) with HMAC-SHA256
+//	c = challenge(Y, H, Γ, k⋅G, k⋅H), s = k + c⋅x mod order
+func (privKey *PrivateKey) ProveVRF(alpha []byte) (*VRFProof, error) {
+	Y := &privKey.PublicKey.A
+	H, err := vrfEncodeToCurve(Y, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	x := new(big.Int).SetBytes(privKey.scalar[:])
+	var res VRFProof
+	res.Gamma.ScalarMultiplication(&H, x)
+
+	// nonce generation of RFC 9381 Section 5.4.2.1
+	hString := compressPoint(&H)
+	digest := sha256.Sum256(hString[:])
+	k, err := newRFC6979Nonces(sha256.New, privKey.scalar[:], digest[:], nil).next()
+	if err != nil {
+		return nil, err
+	}
+
+	var U, V secp256k1.G1Affine
+	U.ScalarMultiplicationBase(k)
+	V.ScalarMultiplication(&H, k)
+	res.C = vrfChallenge(Y, &H, &res.Gamma, &U, &V)
+
+	s := new(big.Int).SetBytes(res.C[:])
+	s.Mul(s, x).
+		Add(s, k).
+		Mod(s, order)
+	s.FillBytes(res.S[:])
+
+	return &res, nil
+}
+
+// VerifyVRF reports whether proof is a valid proof of the VRF output of publicKey on
+// alpha (RFC 9381 Section 5.3):
+//
+//	U = s⋅G - c⋅Y, V = s⋅H - c⋅Γ, c ?= challenge(Y, H, Γ, U, V)
+//
+// It returns an error if publicKey is not on the curve or is the point at infinity.
+func (publicKey *PublicKey) VerifyVRF(alpha []byte, proof *VRFProof) (bool, error) {
+	Y := &publicKey.A
+	if Y.IsInfinity() || !Y.IsOnCurve() {
+		return false, errInvalidVRFKey
+	}
+	if proof.Gamma.IsInfinity() || !proof.Gamma.IsOnCurve() {
+		return false, nil
+	}
+	c := new(big.Int).SetBytes(proof.C[:])
+	s := new(big.Int).SetBytes(proof.S[:])
+	if s.Cmp(order) >= 0 {
+		return false, nil
+	}
+
+	H, err := vrfEncodeToCurve(Y, alpha)
+	if err != nil {
+		return false, err
+	}
+
+	// U = s⋅G - c⋅Y
+	negC := new(big.Int).Neg(c)
+	negC.Mod(negC, order)
+	var UJac secp256k1.G1Jac
+	UJac.JointScalarMultiplicationBase(Y, s, negC)
+
+	// V = s⋅H - c⋅Γ
+	var VJac, cGamma secp256k1.G1Jac
+	VJac.ScalarMultiplicationAffine(&H, s)
+	cGamma.ScalarMultiplicationAffine(&proof.Gamma, c)
+	VJac.SubAssign(&cGamma)
+
+	var U, V secp256k1.G1Affine
+	U.FromJacobian(&UJac)
+	V.FromJacobian(&VJac)
+
+	return vrfChallenge(Y, &H, &proof.Gamma, &U, &V) == proof.C, nil
+}
+
+// BatchVerifyVRF reports whether all the proofs[i] are valid proofs of the VRF outputs of
+// publicKeys[i] on alphas[i], as in PublicKey.VerifyVRF. The proofs are verified in parallel.
+//
+// If a verification fails with an error, it returns an error wrapping the index of the
+// first failing proof.
+func BatchVerifyVRF(publicKeys []PublicKey, alphas [][]byte, proofs []VRFProof) (bool, error) {
+	n := len(proofs)
+	if len(publicKeys) != n || len(alphas) != n {
+		return false, errVRFInconsistentSize
+	}
+
+	valid := make([]bool, n)
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			valid[i], errs[i] = publicKeys[i].VerifyVRF(alphas[i], &proofs[i])
+		}
+	})
+	for i := range errs {
+		if errs[i] != nil {
+			return false, fmt.Errorf("proof %d: %w", i, errs[i])
+		}
+	}
+	for i := range valid {
+		if !valid[i] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Output returns the VRF output beta of the proof (RFC 9381 Section 5.2), the
+// cofactor being 1:
+//
+//	beta = SHA-256(suite_string || 0x03 || Γ || 0x00)
+//
+// The output is only meaningful once the proof has been verified.
+func (proof *VRFProof) Output() [VRFOutputSize]byte {
+	gammaString := compressPoint(&proof.Gamma)
+	h := sha256.New()
+	h.Write([]byte{vrfSuite, vrfDomainProofToHash})
+	h.Write(gammaString[:])
+	h.Write([]byte{vrfDomainBack})
+	var res [VRFOutputSize]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+// Bytes returns the binary representation of the proof, as in RFC 9381:
+// Γ||c||s where Γ is compressed as in SEC 1, and c and s are in big endian.
+func (proof *VRFProof) Bytes() []byte {
+	var res [sizeVRFProof]byte
+	gammaBin := compressPoint(&proof.Gamma)
+	copy(res[:sizeCompressedPoint], gammaBin[:])
+	copy(res[sizeCompressedPoint:sizeCompressedPoint+sizeVRFChallenge], proof.C[:])
+	copy(res[sizeCompressedPoint+sizeVRFChallenge:], proof.S[:])
+	return res[:]
+}
+
+// SetBytes sets proof from its binary representation in buf, see Bytes.
+// It returns the number of bytes read from buf.
+func (proof *VRFProof) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizeVRFProof {
+		return 0, io.ErrShortBuffer
+	}
+	var gammaBin [sizeCompressedPoint]byte
+	copy(gammaBin[:], buf[:sizeCompressedPoint])
+	if err := decompressPoint(&proof.Gamma, &gammaBin); err != nil {
+		return 0, errInvalidVRFProof
+	}
+	copy(proof.C[:], buf[sizeCompressedPoint:sizeCompressedPoint+sizeVRFChallenge])
+	copy(proof.S[:], buf[sizeCompressedPoint+sizeVRFChallenge:sizeVRFProof])
+	return sizeVRFProof, nil
+}
+
+// vrfEncodeToCurve returns the point H of the input alpha of the public key Y with the
+// try-and-increment method (RFC 9381 Section 5.4.1.1):
+//
+//	H = string_to_point(0x02 || SHA-256(suite_string || 0x01 || Y || alpha || ctr || 0x00))
+//
+// for the first counter ctr giving a valid point.
+func vrfEncodeToCurve(Y *secp256k1.G1Affine, alpha []byte) (secp256k1.G1Affine, error) {
+	yString := compressPoint(Y)
+	h := sha256.New()
+	var candidate [sizeCompressedPoint]byte
+	candidate[0] = 0x02
+	var H secp256k1.G1Affine
+	for ctr := 0; ctr < 256; ctr++ {
+		h.Reset()
+		h.Write([]byte{vrfSuite, vrfDomainEncodeToCurve})
+		h.Write(yString[:])
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), vrfDomainBack})
+		copy(candidate[1:], h.Sum(nil))
+		if decompressPoint(&H, &candidate) == nil {
+			return H, nil
+		}
+	}
+	return H, errVRFEncodeToCurve
+}
+
+// vrfChallenge returns c = SHA-256(suite_string || 0x02 || points || 0x00)[:cLen]
+// (RFC 9381 Section 5.4.3)
+func vrfChallenge(points ...*secp256k1.G1Affine) [sizeVRFChallenge]byte {
+	h := sha256.New()
+	h.Write([]byte{vrfSuite, vrfDomainChallenge})
+	for _, p := range points {
+		pString := compressPoint(p)
+		h.Write(pString[:])
+	}
+	h.Write([]byte{vrfDomainBack})
+	var c [sizeVRFChallenge]byte
+	copy(c[:], h.Sum(nil))
+	return c
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"testing"
+)
+
+func TestVRF(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := []byte("sample")
+
+	proof, err := privKey.ProveVRF(alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := privKey.PublicKey.VerifyVRF(alpha, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Fatal("the proof should be valid")
+	}
+
+	// the proofs are deterministic
+	proof2, err := privKey.ProveVRF(alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(proof.Bytes(), proof2.Bytes()) {
+		t.Fatal("the proofs should be deterministic")
+	}
+
+	// another input or another key
+	if valid, _ = privKey.PublicKey.VerifyVRF([]byte("other"), proof); valid {
+		t.Fatal("the proof should not be valid for another input")
+	}
+	if valid, _ = other.PublicKey.VerifyVRF(alpha, proof); valid {
+		t.Fatal("the proof should not be valid for another key")
+	}
+	otherProof, err := privKey.ProveVRF([]byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Output() == otherProof.Output() {
+		t.Fatal("the outputs of different inputs should differ")
+	}
+
+	// tampered proofs
+	tampered := *proof
+	tampered.C[0] ^= 1
+	if valid, _ = privKey.PublicKey.VerifyVRF(alpha, &tampered); valid {
+		t.Fatal("the proof should not be valid with another challenge")
+	}
+	tampered = *proof
+	tampered.S[sizeFr-1] ^= 1
+	if valid, _ = privKey.PublicKey.VerifyVRF(alpha, &tampered); valid {
+		t.Fatal("the proof should not be valid with another s")
+	}
+	tampered = *proof
+	tampered.Gamma.Add(&tampered.Gamma, &privKey.PublicKey.A)
+	if valid, _ = privKey.PublicKey.VerifyVRF(alpha, &tampered); valid {
+		t.Fatal("the proof should not be valid with another Γ")
+	}
+
+	// invalid keys
+	var infinity PublicKey
+	if _, err = infinity.VerifyVRF(alpha, proof); err != errInvalidVRFKey {
+		t.Fatal("expected errInvalidVRFKey, got", err)
+	}
+}
+
+func TestVRFSerialization(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := privKey.ProveVRF([]byte("sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var proof2 VRFProof
+	n, err := proof2.SetBytes(proof.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != sizeVRFProof || proof2 != *proof {
+		t.Fatal("Error serialize(deserialize(.)) of the proof")
+	}
+
+	invalid := proof.Bytes()
+	invalid[0] = 0x04
+	if _, err = proof2.SetBytes(invalid); err != errInvalidVRFProof {
+		t.Fatal("expected errInvalidVRFProof, got", err)
+	}
+}
+
+func TestBatchVerifyVRF(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	publicKeys := make([]PublicKey, n)
+	alphas := make([][]byte, n)
+	proofs := make([]VRFProof, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(crand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		alphas[i] = []byte{byte(i)}
+		proof, err := privKey.ProveVRF(alphas[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = *proof
+	}
+
+	valid, err := BatchVerifyVRF(publicKeys, alphas, proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Fatal("the proofs should be valid")
+	}
+
+	alphas[2], alphas[3] = alphas[3], alphas[2]
+	if valid, _ = BatchVerifyVRF(publicKeys, alphas, proofs); valid {
+		t.Fatal("the proofs of swapped inputs should not be valid")
+	}
+
+	if _, err = BatchVerifyVRF(publicKeys[1:], alphas, proofs); err != errVRFInconsistentSize {
+		t.Fatal("expected errVRFInconsistentSize, got", err)
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkProveVRF(b *testing.B) {
+	privKey, _ := GenerateKey(crand.Reader)
+	alpha := []byte("benchmark")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.ProveVRF(alpha)
+	}
+}
+
+func BenchmarkVerifyVRF(b *testing.B) {
+	privKey, _ := GenerateKey(crand.Reader)
+	alpha := []byte("benchmark")
+	proof, _ := privKey.ProveVRF(alpha)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.VerifyVRF(alpha, proof)
+	}
+}
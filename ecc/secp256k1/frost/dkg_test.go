@@ -0,0 +1,284 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// network is an in-process simulated network, which routes the serialized messages of
+// the participants of the DKG
+type network struct {
+	// broadcast holds the round 1 messages
+	broadcast [][]byte
+	// inboxes[j-1] holds the round 2 messages sent to the participant j
+	inboxes [][][]byte
+	// tamper, if set, modifies the decoded round 2 messages on delivery
+	tamper func(*DKGShare)
+}
+
+func newNetwork(n int) *network {
+	return &network{inboxes: make([][][]byte, n)}
+}
+
+func (net *network) send(share *DKGShare) {
+	net.inboxes[share.To-1] = append(net.inboxes[share.To-1], share.Bytes())
+}
+
+func (net *network) receiveCommitments() ([]DKGCommitment, error) {
+	res := make([]DKGCommitment, len(net.broadcast))
+	for i := range net.broadcast {
+		if _, err := res[i].SetBytes(net.broadcast[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (net *network) receiveShares(id uint32) ([]DKGShare, error) {
+	inbox := net.inboxes[id-1]
+	res := make([]DKGShare, len(inbox))
+	for i := range inbox {
+		if _, err := res[i].SetBytes(inbox[i]); err != nil {
+			return nil, err
+		}
+		if net.tamper != nil {
+			net.tamper(&res[i])
+		}
+	}
+	return res, nil
+}
+
+// runDKG runs the DKG between n participants on the network, and returns their key shares
+func runDKG(net *network, threshold, n int) ([]KeyShare, error) {
+	participants := make([]*DKGParticipant, n)
+	for i := range participants {
+		var err error
+		if participants[i], err = NewDKGParticipant(uint32(i+1), threshold, n, crand.Reader); err != nil {
+			return nil, err
+		}
+		net.broadcast = append(net.broadcast, participants[i].Commitment().Bytes())
+	}
+
+	for _, p := range participants {
+		commitments, err := net.receiveCommitments()
+		if err != nil {
+			return nil, err
+		}
+		shares, err := p.Shares(commitments)
+		if err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			net.send(&shares[i])
+		}
+	}
+
+	res := make([]KeyShare, n)
+	for i, p := range participants {
+		shares, err := net.receiveShares(p.ID())
+		if err != nil {
+			return nil, err
+		}
+		keyShare, err := p.Finalize(shares)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = *keyShare
+	}
+	return res, nil
+}
+
+// checkKeyShares checks that the key shares are consistent shares of their group key
+func checkKeyShares(t *testing.T, keyShares []KeyShare, threshold int) {
+	t.Helper()
+	groupKey := keyShares[0].GroupKey.Bytes()
+	for i := range keyShares {
+		if !bytes.Equal(keyShares[i].GroupKey.Bytes(), groupKey) {
+			t.Fatal("the participants should agree on the group key")
+		}
+		if !keyShares[i].IsValid() {
+			t.Fatal("the key share should match its public key share")
+		}
+	}
+
+	// any threshold shares interpolate the secret key at 0
+	for _, first := range []int{0, len(keyShares) - threshold} {
+		subset := keyShares[first : first+threshold]
+		ids := make([]uint32, threshold)
+		for i := range subset {
+			ids[i] = subset[i].ID
+		}
+		var secret, term big.Int
+		for i := range subset {
+			term.Mul(&subset[i].Secret, lagrangeCoefficient(subset[i].ID, ids))
+			secret.Add(&secret, &term)
+		}
+		secret.Mod(&secret, order)
+		pub := scalarBaseMult(&secret)
+		if !pub.Equal(&keyShares[0].PublicKey) {
+			t.Fatal("the key shares should interpolate the secret key")
+		}
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	t.Parallel()
+
+	secret, err := randomScalar(crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const threshold, n = 3, 5
+	keyShares, err := SplitKey(crand.Reader, secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkKeyShares(t, keyShares, threshold)
+	pub := scalarBaseMult(secret)
+	if !pub.Equal(&keyShares[0].PublicKey) {
+		t.Fatal("the group key should be the public key of the secret")
+	}
+
+	for _, params := range [][2]int{{0, 3}, {4, 3}} {
+		if _, err = SplitKey(crand.Reader, secret, params[0], params[1]); err != ErrInvalidParameters {
+			t.Fatal("expected ErrInvalidParameters, got", err)
+		}
+	}
+}
+
+func TestDKG(t *testing.T) {
+	t.Parallel()
+
+	for _, params := range [][2]int{{1, 1}, {2, 3}, {3, 5}} {
+		threshold, n := params[0], params[1]
+		keyShares, err := runDKG(newNetwork(n), threshold, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKeyShares(t, keyShares, threshold)
+	}
+}
+
+func TestDKGCulprits(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 4
+
+	// the participant 2 sends an invalid share to the participant 3
+	net := newNetwork(n)
+	net.tamper = func(share *DKGShare) {
+		if share.From == 2 && share.To == 3 {
+			share.Share.Add(&share.Share, big.NewInt(1))
+		}
+	}
+	_, err := runDKG(net, threshold, n)
+	if !errors.Is(err, ErrInvalidShare) || !strings.Contains(err.Error(), "participant 2") {
+		t.Fatal("expected ErrInvalidShare from the participant 2, got", err)
+	}
+
+	// the participant 4 sends an invalid proof of knowledge
+	participants := make([]*DKGParticipant, n)
+	commitments := make([]DKGCommitment, n)
+	for i := range participants {
+		if participants[i], err = NewDKGParticipant(uint32(i+1), threshold, n, crand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		commitments[i] = *participants[i].Commitment()
+	}
+	commitments[3].ProofZ.Add(&commitments[3].ProofZ, big.NewInt(1))
+	_, err = participants[0].Shares(commitments)
+	if !errors.Is(err, ErrInvalidProof) || !strings.Contains(err.Error(), "participant 4") {
+		t.Fatal("expected ErrInvalidProof from the participant 4, got", err)
+	}
+
+	// a missing commitment
+	_, err = participants[0].Shares(commitments[:n-1])
+	if !errors.Is(err, ErrMissingMessage) {
+		t.Fatal("expected ErrMissingMessage, got", err)
+	}
+}
+
+func TestDKGSerialization(t *testing.T) {
+	t.Parallel()
+
+	const threshold, n = 2, 3
+	keyShares, err := SplitKey(crand.Reader, big.NewInt(42), threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keyShare KeyShare
+	buf := keyShares[1].Bytes()
+	read, err := keyShare.SetBytes(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != len(buf) || !bytes.Equal(keyShare.Bytes(), buf) || !keyShare.IsValid() {
+		t.Fatal("Error serialize(deserialize(.)) of the key share")
+	}
+	if _, err = keyShare.SetBytes(buf[:len(buf)-1]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+
+	participant, err := NewDKGParticipant(1, threshold, n, crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commitment DKGCommitment
+	buf = participant.Commitment().Bytes()
+	if read, err = commitment.SetBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if read != len(buf) || !bytes.Equal(commitment.Bytes(), buf) {
+		t.Fatal("Error serialize(deserialize(.)) of the DKG commitment")
+	}
+	if _, err = commitment.SetBytes(buf[:len(buf)-1]); err == nil {
+		t.Fatal("a short buffer should be rejected")
+	}
+
+	share := DKGShare{From: 1, To: 2}
+	share.Share.Set(&keyShares[0].Secret)
+	var share2 DKGShare
+	if _, err = share2.SetBytes(share.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if share2.From != share.From || share2.To != share.To || share2.Share.Cmp(&share.Share) != 0 {
+		t.Fatal("Error serialize(deserialize(.)) of the DKG share")
+	}
+	share.Share.Set(order)
+	if _, err = share2.SetBytes(share.Bytes()); err == nil {
+		t.Fatal("a non reduced share should be rejected")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkDKG(b *testing.B) {
+	const threshold, n = 3, 5
+	for i := 0; i < b.N; i++ {
+		if _, err := runDKG(newNetwork(n), threshold, n); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
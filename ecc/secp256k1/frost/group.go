@@ -0,0 +1,178 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package frost
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// contextString is the prefix of the domain separation tags of the hashes
+const contextString = "FROST-secp256k1-SHA256-v1"
+
+// point is an element of the group of the keys
+type point = secp256k1.G1Affine
+
+const (
+	// sizeScalar is the size of an encoded scalar
+	sizeScalar = fr.Bytes
+
+	// sizePoint is the size of a SEC 1 compressed point
+	sizePoint = 1 + fp.Bytes
+)
+
+var (
+	errInvalidPoint  = errors.New("invalid point encoding")
+	errInvalidScalar = errors.New("invalid scalar encoding")
+)
+
+// order is the order of the group
+var order = fr.Modulus()
+
+// identity returns the neutral element of the group
+func identity() point {
+	var res point
+	return res
+}
+
+// isIdentity reports whether p is the neutral element of the group
+func isIdentity(p *point) bool {
+	return p.IsInfinity()
+}
+
+// scalarBaseMult returns s⋅G, where G is the base point
+func scalarBaseMult(s *big.Int) point {
+	var res point
+	res.ScalarMultiplicationBase(s)
+	return res
+}
+
+// scalarMult returns s⋅p
+func scalarMult(p *point, s *big.Int) point {
+	var res point
+	res.ScalarMultiplication(p, s)
+	return res
+}
+
+// addPoints returns p + q
+func addPoints(p, q *point) point {
+	var res point
+	res.Add(p, q)
+	return res
+}
+
+// encodePoint returns the SEC 1 compressed encoding of p, 0x02 || x if y is even,
+// 0x03 || x otherwise
+func encodePoint(p *point) []byte {
+	res := make([]byte, sizePoint)
+	res[0] = 0x02
+	if p.Y.Bits()[0]&1 == 1 {
+		res[0] = 0x03
+	}
+	x := p.X.Bytes()
+	copy(res[1:], x[:])
+	return res
+}
+
+// decodePoint returns the point of SEC 1 compressed encoding buf, see encodePoint.
+// The point at infinity has no such encoding.
+func decodePoint(buf []byte) (point, error) {
+	var p point
+	if len(buf) != sizePoint || (buf[0] != 0x02 && buf[0] != 0x03) {
+		return p, errInvalidPoint
+	}
+	if err := p.X.SetBytesCanonical(buf[1:]); err != nil {
+		return p, errInvalidPoint
+	}
+	// y² = x³ + b
+	_, b := secp256k1.CurveCoefficients()
+	var y2 fp.Element
+	y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &b)
+	if p.Y.Sqrt(&y2) == nil {
+		return p, errInvalidPoint
+	}
+	if byte(p.Y.Bits()[0]&1) != buf[0]&1 {
+		p.Y.Neg(&p.Y)
+	}
+	return p, nil
+}
+
+// encodeScalar returns the big endian encoding of s ∈ [0, order-1] on sizeScalar bytes
+func encodeScalar(s *big.Int) []byte {
+	res := make([]byte, sizeScalar)
+	s.FillBytes(res)
+	return res
+}
+
+// decodeScalar returns the scalar of big endian encoding buf, which must be smaller than the order
+func decodeScalar(buf []byte) (*big.Int, error) {
+	if len(buf) != sizeScalar {
+		return nil, errInvalidScalar
+	}
+	s := new(big.Int).SetBytes(buf)
+	if s.Cmp(order) >= 0 {
+		return nil, errInvalidScalar
+	}
+	return s, nil
+}
+
+// randomScalar returns a uniformly random scalar in [1, order-1] read from rand
+func randomScalar(rand io.Reader) (*big.Int, error) {
+	// 128 extra bits make the bias of the reduction negligible
+	buf := make([]byte, (order.BitLen()+7)/8+16)
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return nil, err
+		}
+		s := new(big.Int).SetBytes(buf)
+		s.Mod(s, order)
+		if s.Sign() != 0 {
+			return s, nil
+		}
+	}
+}
+
+// hashToScalar returns hash_to_field(data, contextString || dst) as in RFC 9380, with
+// expand_message_xmd and SHA-256
+func hashToScalar(dst string, data ...[]byte) *big.Int {
+	var msg []byte
+	for i := range data {
+		msg = append(msg, data[i]...)
+	}
+	e, err := fr.Hash(msg, []byte(contextString+dst), 1)
+	if err != nil {
+		// the DST is shorter than 255 bytes
+		panic(err)
+	}
+	return e[0].BigInt(new(big.Int))
+}
+
+// hashBytes returns SHA-256(contextString || dst || data)
+func hashBytes(dst string, data ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(contextString + dst))
+	for i := range data {
+		h.Write(data[i])
+	}
+	return h.Sum(nil)
+}
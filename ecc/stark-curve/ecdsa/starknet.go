@@ -0,0 +1,231 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve"
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+// nbBitsStarknet is the bound on the size of the message hashes and of r and
+// s⁻¹ in Starknet signatures (N_ELEMENT_BITS_ECDSA in cairo-lang)
+const nbBitsStarknet = 251
+
+var (
+	errEmptySeed      = errors.New("the seed must not be empty")
+	errNotSignable    = errors.New("the message hash must be smaller than 2^251")
+	errInvalidAddress = errors.New("the Ethereum address must be 20 bytes long")
+
+	// boundStarknet = 2^251
+	boundStarknet = new(big.Int).Lsh(big.NewInt(1), nbBitsStarknet)
+)
+
+// GrindKey derives a scalar in [0, order-1] from seed as grind_key in cairo-lang: the
+// first sha256(seed || i), i = 0, 1, .., smaller than the largest multiple of the order
+// below 2^256, reduced modulo the order. i is encoded in big endian on the smallest
+// number of bytes, 0 being encoded as 0x00.
+//
+// cairo-lang encodes the seed on the smallest number of bytes while starknet.js pads it
+// to 32 bytes: they agree when the first byte of a 32-byte seed is not zero.
+func GrindKey(seed []byte) *big.Int {
+	// largest multiple of the order smaller than 2^256
+	limit := new(big.Int).Lsh(big.NewInt(1), 256)
+	limit.Sub(limit, new(big.Int).Mod(limit, order))
+
+	key := new(big.Int)
+	for i := new(big.Int); ; i.Add(i, one) {
+		index := i.Bytes()
+		if len(index) == 0 {
+			index = []byte{0}
+		}
+		h := sha256.New()
+		h.Write(seed)
+		h.Write(index)
+		key.SetBytes(h.Sum(nil))
+		if key.Cmp(limit) < 0 {
+			return key.Mod(key, order)
+		}
+	}
+}
+
+// KeyFromSeed returns the private key whose scalar is GrindKey(seed). This is how
+// the Starknet wallets derive their keys, the seed being typically a BIP-32
+// private key, see EIP2645Path.
+func KeyFromSeed(seed []byte) (*PrivateKey, error) {
+	if len(seed) == 0 {
+		return nil, errEmptySeed
+	}
+	k := GrindKey(seed)
+	if k.Sign() == 0 {
+		// happens with probability 1/order
+		return nil, errors.New("the seed is mapped to the null scalar")
+	}
+	return newPrivateKey(k), nil
+}
+
+// newPrivateKey returns the private key of scalar k ∈ [1, order-1]
+func newPrivateKey(k *big.Int) *PrivateKey {
+	_, g := starkcurve.Generators()
+
+	privateKey := new(PrivateKey)
+	k.FillBytes(privateKey.scalar[:sizeFr])
+	privateKey.PublicKey.A.ScalarMultiplication(&g, k)
+	return privateKey
+}
+
+// EIP2645Path returns the BIP-32 derivation path
+//
+//	m/2645'/layer'/application'/eth_address_1'/eth_address_2'/index
+//
+// of EIP-2645, where layer and application are the 31 least significant bits of
+// sha256(layer) and sha256(application), and eth_address_1, eth_address_2 the bits
+// 0-30 and 31-61 of the 20-byte Ethereum address. The hardened indices have their
+// most significant bit set. The Starknet key at this path is KeyFromSeed of the
+// 32-byte secp256k1 private key derived at the path.
+//
+// https://eips.ethereum.org/EIPS/eip-2645
+func EIP2645Path(layer, application string, ethAddress []byte, index uint32) ([]uint32, error) {
+	if len(ethAddress) != 20 {
+		return nil, errInvalidAddress
+	}
+	const (
+		hardened = 1 << 31
+		mask     = 1<<31 - 1
+	)
+	low31 := func(b []byte) uint32 {
+		return uint32(new(big.Int).SetBytes(b).Uint64() & mask)
+	}
+	layerHash := sha256.Sum256([]byte(layer))
+	applicationHash := sha256.Sum256([]byte(application))
+	addr := new(big.Int).SetBytes(ethAddress)
+
+	return []uint32{
+		2645 | hardened,
+		low31(layerHash[24:]) | hardened,
+		low31(applicationHash[24:]) | hardened,
+		uint32(new(big.Int).And(addr, big.NewInt(mask)).Uint64()) | hardened,
+		uint32(new(big.Int).Rsh(addr, 31).Uint64()&mask) | hardened,
+		index,
+	}, nil
+}
+
+// SignStarknet signs the Starknet message hash msgHash ∈ [0, 2^251-1], as sign in
+// cairo-lang:
+//
+//	k = RFC 6979 nonce with sha256, the additional data k' being the retry counter
+//	r = x_{k ⋅ g1Gen}, with r ∈ [1, 2^251-1]
+//	w = k ⋅ (m + r ⋅ sk)⁻¹ (mod order), with w ∈ [1, 2^251-1]
+//	s = w⁻¹ (mod order)
+//
+// Unlike ECDSA, r is not reduced modulo the order. The signatures are deterministic.
+// When a nonce does not satisfy the bounds, which happens with negligible probability,
+// cairo-lang derives a new nonce with the counter 1, 2, .. as additional data while
+// starknet.js resumes the HMAC_DRBG: the signatures then differ.
+//
+// https://github.com/starkware-libs/cairo-lang/blob/v0.12.0/src/starkware/crypto/signature/signature.py
+func (privKey *PrivateKey) SignStarknet(msgHash *big.Int) (r, s *big.Int, err error) {
+	if msgHash.Sign() < 0 || msgHash.Cmp(boundStarknet) >= 0 {
+		return nil, nil, errNotSignable
+	}
+
+	// cairo-lang pads the hash with a nibble to 256 bits before RFC 6979, which bits2int
+	// removes: h1 = msgHash. We feed msgHash ⋅ 2⁴ so that HashToInt gives the same h1.
+	digest := make([]byte, sizeFr)
+	new(big.Int).Lsh(msgHash, 4).FillBytes(digest)
+
+	scalar := new(big.Int).SetBytes(privKey.scalar[:sizeFr])
+	r, s = new(big.Int), new(big.Int)
+	var (
+		extra []byte
+		seed  = new(big.Int)
+	)
+	for {
+		k, err := newRFC6979Nonces(sha256.New, privKey.scalar[:], digest, extra).next()
+		if err != nil {
+			return nil, nil, err
+		}
+		// the next nonces are derived with the seed 1, 2, ..
+		seed.Add(seed, one)
+		extra = seed.Bytes()
+
+		var P starkcurve.G1Affine
+		P.ScalarMultiplicationBase(k)
+		P.X.BigInt(r)
+		if r.Sign() == 0 || r.Cmp(boundStarknet) >= 0 {
+			continue
+		}
+
+		// w = k / (m + r ⋅ sk)
+		s.Mul(r, scalar).
+			Add(s, msgHash).
+			Mod(s, order)
+		if s.Sign() == 0 {
+			continue
+		}
+		w := new(big.Int).ModInverse(s, order)
+		w.Mul(w, k).Mod(w, order)
+		if w.Sign() == 0 || w.Cmp(boundStarknet) >= 0 {
+			continue
+		}
+
+		s.ModInverse(w, order)
+		return r, s, nil
+	}
+}
+
+// VerifyStarknet validates the Starknet signature (r, s) of msgHash, as verify in
+// cairo-lang:
+//
+//	r ∈ [1, 2^251-1], s ∈ [1, order-1], w = s⁻¹ ∈ [1, 2^251-1], msgHash ∈ [0, 2^251-1]
+//	r ?= x_{w ⋅ (m ⋅ g1Gen + r ⋅ publicKey)}
+//
+// A Starknet public key is the x coordinate of publicKey.A, with which both
+// publicKey.A and -publicKey.A must be tried.
+func (publicKey *PublicKey) VerifyStarknet(msgHash, r, s *big.Int) bool {
+	if r.Sign() <= 0 || r.Cmp(boundStarknet) >= 0 ||
+		s.Sign() <= 0 || s.Cmp(order) >= 0 ||
+		msgHash.Sign() < 0 || msgHash.Cmp(boundStarknet) >= 0 {
+		return false
+	}
+	w := new(big.Int).ModInverse(s, order)
+	if w.Cmp(boundStarknet) >= 0 {
+		return false
+	}
+
+	u1 := new(big.Int).Mul(msgHash, w)
+	u1.Mod(u1, order)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, order)
+	var U starkcurve.G1Jac
+	U.JointScalarMultiplicationBase(&publicKey.A, u1, u2)
+	if U.Z.IsZero() {
+		return false
+	}
+
+	var x fp.Element
+	x.Square(&U.Z).
+		Inverse(&x).
+		Mul(&x, &U.X)
+
+	var rFp fp.Element
+	rFp.SetBigInt(r)
+	return x.Equal(&rFp)
+}
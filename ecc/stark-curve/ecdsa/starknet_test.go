@@ -0,0 +1,197 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func hexToInt(t testing.TB, s string) *big.Int {
+	t.Helper()
+	res, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		t.Fatalf("invalid integer %s", s)
+	}
+	return res
+}
+
+func TestGrindKey(t *testing.T) {
+	t.Parallel()
+
+	// test vector of starknet.js
+	seed, err := hex.DecodeString("86F3E7293141F20A8BAFF320E8EE4ACCB9D4A4BF2B4D295E8CEE784DB46E0519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hexToInt(t, "0x5c8c8683596c732541a59e03007b2d30dbbbb873556fe65b5fb63c16688f941")
+	if got := GrindKey(seed); got.Cmp(want) != 0 {
+		t.Fatalf("GrindKey: got %x, want %x", got, want)
+	}
+
+	privKey, err := KeyFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetBytes(privKey.scalar[:]).Cmp(want) != 0 {
+		t.Fatal("KeyFromSeed: the scalar should be GrindKey(seed)")
+	}
+	if _, err := KeyFromSeed(nil); err == nil {
+		t.Fatal("KeyFromSeed: an empty seed should be rejected")
+	}
+}
+
+func TestEIP2645Path(t *testing.T) {
+	t.Parallel()
+
+	ethAddress, err := hex.DecodeString("a4864d977b944315389d1765ffa7e66f74ee8cd7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := EIP2645Path("starkex", "starkdeployement", ethAddress, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const h = 1 << 31
+	want := []uint32{2645 + h, 579218131 + h, 891216374 + h, 1961790679 + h, 2135936222 + h, 3}
+	if len(path) != len(want) {
+		t.Fatalf("EIP2645Path: got %d indices, want %d", len(path), len(want))
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("EIP2645Path: index %d is %d, want %d", i, path[i], want[i])
+		}
+	}
+
+	if _, err := EIP2645Path("starkex", "starkdeployement", ethAddress[1:], 0); err == nil {
+		t.Fatal("EIP2645Path: a truncated address should be rejected")
+	}
+}
+
+func TestSignStarknet(t *testing.T) {
+	t.Parallel()
+
+	// test vectors of cairo-lang
+	privKey := newPrivateKey(hexToInt(t, "0x3c1e9550e66958296d11b60f8e8e7a7ad990d07fa65d5f7652c4a6c87d4e3cc"))
+	publicKey := privKey.PublicKey
+	if x := publicKey.A.X.BigInt(new(big.Int)); x.Cmp(hexToInt(t, "0x77a3b314db07c45076d11f62b6f9e748a39790441823307743cf00d6597ea43")) != 0 {
+		t.Fatalf("unexpected public key %x", x)
+	}
+
+	tests := []struct {
+		msgHash, r, s string
+	}{
+		{
+			"0x397e76d1667c4454bfb83514e120583af836f8e32a516765497823eabe16a3f",
+			"0x173fd03d8b008ee7432977ac27d1e9d1a1f6c98b1a2f05fa84a21c84c44e882",
+			"0x4b6d75385aed025aa222f28a0adc6d58db78ff17e51c3f59e259b131cd5a1cc",
+		},
+		{
+			"0x0",
+			"0x5501cb3b19658d23128baf8bcfa2e45c12525b44eec10a3f485dbb40a82065",
+			"0x2d67d973b258c5923ef085c7d9b9416ec146170865773aad9bffa2236500227",
+		},
+		{
+			"0x2a",
+			"0x5439d0da4146cb5a617493061bc0dde05f21ed4cb37cbb2aee367c69cf99c62",
+			"0xb724d023860208353f539f9c668ec2078debd669259d89966cd1ae2e3c0ad1",
+		},
+		{
+			"0x7ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"0x21ccb5451291847152be189d8c988539c543c2e18a4407974fc4bc931e1af92",
+			"0x27eb27ea532b516952108de21eb242dd0910bd2f41ee0ce0facfa44b5a3f964",
+		},
+	}
+	for _, tt := range tests {
+		msgHash := hexToInt(t, tt.msgHash)
+		r, s, err := privKey.SignStarknet(msgHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(hexToInt(t, tt.r)) != 0 || s.Cmp(hexToInt(t, tt.s)) != 0 {
+			t.Fatalf("SignStarknet(%s): got (%x, %x), want (%s, %s)", tt.msgHash, r, s, tt.r, tt.s)
+		}
+		if !publicKey.VerifyStarknet(msgHash, r, s) {
+			t.Fatalf("VerifyStarknet(%s): the signature should be valid", tt.msgHash)
+		}
+	}
+
+	// the message hash must be smaller than 2^251
+	if _, _, err := privKey.SignStarknet(new(big.Int).Set(boundStarknet)); err == nil {
+		t.Fatal("SignStarknet: a message hash ≥ 2^251 should be rejected")
+	}
+}
+
+func TestVerifyStarknet(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 10
+	}
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[STARK-CURVE] Starknet signatures should be deterministic and only valid for the signed hash", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msgHash, _ := rand.Int(rand.Reader, boundStarknet)
+			r, s, err := privKey.SignStarknet(msgHash)
+			if err != nil {
+				return false
+			}
+			r2, s2, err := privKey.SignStarknet(msgHash)
+			if err != nil {
+				return false
+			}
+			other := new(big.Int).Add(msgHash, one)
+			other.Mod(other, boundStarknet)
+
+			return r.Cmp(r2) == 0 && s.Cmp(s2) == 0 &&
+				publicKey.VerifyStarknet(msgHash, r, s) &&
+				!publicKey.VerifyStarknet(other, r, s) &&
+				!publicKey.VerifyStarknet(msgHash, s, r) &&
+				!publicKey.VerifyStarknet(msgHash, new(big.Int).Add(r, boundStarknet), s)
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func BenchmarkSignStarknet(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader)
+	msgHash := new(big.Int).SetBytes([]byte("testing Starknet signatures"))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.SignStarknet(msgHash)
+	}
+}
+
+func BenchmarkVerifyStarknet(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader)
+	msgHash := new(big.Int).SetBytes([]byte("testing Starknet signatures"))
+	r, s, _ := privKey.SignStarknet(msgHash)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.VerifyStarknet(msgHash, r, s)
+	}
+}
@@ -1,7 +1,9 @@
 package pedersenhash
 
 import (
-	"math/big"
+	"errors"
+	"hash"
+	"sync"
 
 	starkcurve "github.com/consensys/gnark-crypto/ecc/stark-curve"
 	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
@@ -57,10 +59,11 @@ func PedersenArray(elems ...*fp.Element) *fp.Element {
 // [Pedersen hash]: https://docs.starknet.io/documentation/develop/Hashing/hash-functions/#pedersen_hash
 // [reference implementation]: https://github.com/starkware-libs/cairo-lang/blob/de741b92657f245a50caab99cfaef093152fd8be/src/starkware/crypto/signature/fast_pedersen_hash.py
 func Pedersen(a *fp.Element, b *fp.Element) *fp.Element {
+	tablesOnce.Do(initTables)
 
 	result := new(starkcurve.G1Jac).Set(&shiftPoint)
-	result.AddAssign(processElement(a, &p0, &p1))
-	result.AddAssign(processElement(b, &p2, &p3))
+	processElement(result, a, p0Table, p1Table)
+	processElement(result, b, p2Table, p3Table)
 
 	// recover the affine x coordinate
 	var x fp.Element
@@ -71,17 +74,128 @@ func Pedersen(a *fp.Element, b *fp.Element) *fp.Element {
 	return &x
 }
 
-func processElement(a *fp.Element, p1 *starkcurve.G1Jac, p2 *starkcurve.G1Jac) *starkcurve.G1Jac {
-	var bigInt big.Int
-	var aBytes [32]byte
-	a.BigInt(&bigInt).FillBytes(aBytes[:])
+// processElement adds [low]pLow + [high]pHigh to res, where low are the 248 least significant bits
+// of a and high its top nibble (bits 248-251).
+func processElement(res *starkcurve.G1Jac, a *fp.Element, pLow, pHigh pointTable) {
+	aBytes := a.Bytes()
 
-	highPart := bigInt.SetUint64(uint64(aBytes[0])) // The top nibble (bits 249-252)
-	lowPart := aBytes[1:]                           // Zero-out the top nibble (bits 249-252)
+	pLow.addMultiple(res, aBytes[1:])
+	if aBytes[0] != 0 {
+		res.AddMixed(&pHigh[0][aBytes[0]-1])
+	}
+}
+
+// pointTable holds the multiples of a point by all the nibbles at each position:
+// table[i][d-1] = [d⋅16ⁱ]P, for 1 ≤ d ≤ 15.
+type pointTable [][15]starkcurve.G1Affine
+
+var (
+	tablesOnce sync.Once
+
+	// the low parts have 248 bits, and the high parts 4 bits
+	p0Table, p1Table, p2Table, p3Table pointTable
+)
+
+func initTables() {
+	p0Table = newPointTable(&p0, 62)
+	p1Table = newPointTable(&p1, 1)
+	p2Table = newPointTable(&p2, 62)
+	p3Table = newPointTable(&p3, 1)
+}
+
+func newPointTable(p *starkcurve.G1Jac, nbNibbles int) pointTable {
+	points := make([]starkcurve.G1Jac, 15*nbNibbles)
+	var base starkcurve.G1Jac
+	base.Set(p)
+	for i := 0; i < nbNibbles; i++ {
+		row := points[15*i : 15*(i+1)]
+		row[0].Set(&base)
+		for d := 1; d < 15; d++ {
+			row[d].Set(&row[d-1]).AddAssign(&base)
+		}
+		// [16⋅16ⁱ]P
+		base.Set(&row[14]).AddAssign(&row[0])
+	}
+
+	affine := starkcurve.BatchJacobianToAffineG1(points)
+	table := make(pointTable, nbNibbles)
+	for i := range table {
+		copy(table[i][:], affine[15*i:15*(i+1)])
+	}
+	return table
+}
+
+// addMultiple adds [s]P to res, where s is the big endian integer encoded by b.
+// len(b) must be half the number of nibbles of the table.
+func (table pointTable) addMultiple(res *starkcurve.G1Jac, b []byte) {
+	for i := range b {
+		n := 2 * (len(b) - 1 - i)
+		if lo := b[i] & 0xf; lo != 0 {
+			res.AddMixed(&table[n][lo-1])
+		}
+		if hi := b[i] >> 4; hi != 0 {
+			res.AddMixed(&table[n+1][hi-1])
+		}
+	}
+}
 
-	m := new(starkcurve.G1Jac).ScalarMultiplication(p2, highPart)
+// digest implements hash.Hash with PedersenArray
+type digest struct {
+	data []*fp.Element
+}
+
+// New returns a hash.Hash computing the Pedersen array hash (see PedersenArray) of the written field elements.
+func New() hash.Hash {
+	return &digest{}
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size fp.Bytes represents a big endian fp.Element. If len(p) is not a multiple
+// of fp.Bytes or any of the blocks represents an integer larger than fp.Modulus, Write returns an error.
+func (d *digest) Write(p []byte) (int, error) {
+	elems, err := readElements(p)
+	if err != nil {
+		return 0, err
+	}
+	d.data = append(d.data, elems...)
+	return len(p), nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := PedersenArray(d.data...).Bytes()
+	return append(b, h[:]...)
+}
 
-	var n starkcurve.G1Jac
-	n.ScalarMultiplication(p1, bigInt.SetBytes(lowPart))
-	return m.AddAssign(&n)
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return fp.Bytes
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return fp.Bytes
+}
+
+// readElements reads the big endian field elements encoded by p
+func readElements(p []byte) ([]*fp.Element, error) {
+	if len(p)%fp.Bytes != 0 {
+		return nil, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*fp.Bytes")
+	}
+	elems := make([]*fp.Element, 0, len(p)/fp.Bytes)
+	for start := 0; start < len(p); start += fp.Bytes {
+		elem, err := fp.BigEndian.Element((*[fp.Bytes]byte)(p[start : start+fp.Bytes]))
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, &elem)
+	}
+	return elems, nil
 }
@@ -2,8 +2,10 @@ package pedersenhash
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	starkcurve "github.com/consensys/gnark-crypto/ecc/stark-curve"
 	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
 )
 
@@ -114,6 +116,79 @@ func TestPedersenArray(t *testing.T) {
 	}
 }
 
+// pedersenReference computes the Pedersen hash with generic scalar multiplications
+func pedersenReference(a, b *fp.Element) *fp.Element {
+	process := func(e *fp.Element, pLow, pHigh *starkcurve.G1Jac) *starkcurve.G1Jac {
+		var bigInt big.Int
+		var eBytes [32]byte
+		e.BigInt(&bigInt).FillBytes(eBytes[:])
+
+		var high, low starkcurve.G1Jac
+		high.ScalarMultiplication(pHigh, bigInt.SetUint64(uint64(eBytes[0])))
+		low.ScalarMultiplication(pLow, bigInt.SetBytes(eBytes[1:]))
+		return high.AddAssign(&low)
+	}
+
+	var res starkcurve.G1Jac
+	res.Set(&shiftPoint)
+	res.AddAssign(process(a, &p0, &p1))
+	res.AddAssign(process(b, &p2, &p3))
+	var resAffine starkcurve.G1Affine
+	resAffine.FromJacobian(&res)
+	return &resAffine.X
+}
+
+func TestPedersenTables(t *testing.T) {
+	var zero, minusOne fp.Element
+	minusOne.SetOne().Neg(&minusOne)
+	inputs := []*fp.Element{&zero, &minusOne}
+	for i := 0; i < 10; i++ {
+		e, err := new(fp.Element).SetRandom()
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, e)
+	}
+	for _, a := range inputs {
+		for _, b := range inputs {
+			if got, want := Pedersen(a, b), pedersenReference(a, b); !got.Equal(want) {
+				t.Fatalf("Pedersen(%s, %s) = %s, want %s", a.Text(16), b.Text(16), got.Text(16), want.Text(16))
+			}
+		}
+	}
+}
+
+func TestPedersenHasher(t *testing.T) {
+	var data []*fp.Element
+	h := New()
+	for i := 0; i < 5; i++ {
+		e, err := new(fp.Element).SetRandom()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, e)
+		b := e.Bytes()
+		if _, err := h.Write(b[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := PedersenArray(data...).Bytes()
+	if got := h.Sum(nil); string(got) != string(want[:]) {
+		t.Fatalf("New().Sum() = %x, want %x", got, want)
+	}
+
+	h.Reset()
+	want = PedersenArray().Bytes()
+	if got := h.Sum(nil); string(got) != string(want[:]) {
+		t.Fatalf("New().Sum() = %x, want %x", got, want)
+	}
+
+	// non canonical inputs are rejected
+	if _, err := h.Write(fp.Modulus().FillBytes(make([]byte, fp.Bytes))); err == nil {
+		t.Fatal("expected an error for a non canonical field element")
+	}
+}
+
 var feltBench *fp.Element
 
 // go test -bench=. -run=^# -cpu=1,2,4,8,16
@@ -0,0 +1,183 @@
+package poseidonhash
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"strconv"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+const (
+	nbFullRounds    = 8
+	nbPartialRounds = 83
+	nbRounds        = nbFullRounds + nbPartialRounds
+
+	// StateSize is the size of the state of the Hades permutation (rate 2, capacity 1)
+	StateSize = 3
+)
+
+var (
+	roundConstants [nbRounds][StateSize]fp.Element
+	once           sync.Once
+)
+
+// initConstants computes the round constants of the [reference implementation]: the i-th constant
+// is sha256("Hades" || i) mod p.
+//
+// [reference implementation]: https://github.com/starkware-libs/cairo-lang/blob/v0.12.0/src/starkware/cairo/common/poseidon_utils.py
+func initConstants() {
+	for i := 0; i < nbRounds; i++ {
+		for j := 0; j < StateSize; j++ {
+			h := sha256.Sum256([]byte("Hades" + strconv.Itoa(StateSize*i+j)))
+			roundConstants[i][j].SetBytes(h[:])
+		}
+	}
+}
+
+// HadesPermutation applies the Hades permutation of [Starknet's Poseidon] to state.
+//
+// [Starknet's Poseidon]: https://docs.starknet.io/documentation/architecture_and_concepts/Cryptography/hash-functions/#poseidon_hash
+func HadesPermutation(state *[StateSize]fp.Element) {
+	once.Do(initConstants)
+
+	for i := 0; i < nbRounds; i++ {
+		// add round constants
+		for j := 0; j < StateSize; j++ {
+			state[j].Add(&state[j], &roundConstants[i][j])
+		}
+
+		// S-box x ↦ x³, on the whole state in the full rounds and on the last element in the partial rounds
+		if i < nbFullRounds/2 || i >= nbFullRounds/2+nbPartialRounds {
+			for j := 0; j < StateSize; j++ {
+				cube(&state[j])
+			}
+		} else {
+			cube(&state[StateSize-1])
+		}
+
+		mixLayer(state)
+	}
+}
+
+func cube(x *fp.Element) {
+	var x2 fp.Element
+	x2.Square(x)
+	x.Mul(x, &x2)
+}
+
+// mixLayer multiplies state by the MDS matrix
+//
+//	⎛3  1  1⎞
+//	⎜1 -1  1⎟
+//	⎝1  1 -2⎠
+func mixLayer(state *[StateSize]fp.Element) {
+	var t, tmp fp.Element
+	t.Add(&state[0], &state[1]).Add(&t, &state[2])
+
+	// t + 2s₀
+	state[0].Double(&state[0]).Add(&state[0], &t)
+	// t - 2s₁
+	state[1].Double(&state[1]).Sub(&t, &state[1])
+	// t - 3s₂
+	tmp.Double(&state[2]).Add(&tmp, &state[2])
+	state[2].Sub(&t, &tmp)
+}
+
+// Poseidon returns the Poseidon hash of a and b (poseidon_hash in cairo-lang).
+func Poseidon(a, b *fp.Element) *fp.Element {
+	var state [StateSize]fp.Element
+	state[0].Set(a)
+	state[1].Set(b)
+	state[2].SetUint64(2)
+	HadesPermutation(&state)
+	return &state[0]
+}
+
+// PoseidonSingle returns the Poseidon hash of a (poseidon_hash_single in cairo-lang).
+func PoseidonSingle(a *fp.Element) *fp.Element {
+	var state [StateSize]fp.Element
+	state[0].Set(a)
+	state[2].SetOne()
+	HadesPermutation(&state)
+	return &state[0]
+}
+
+// PoseidonMany returns the Poseidon hash of elems (poseidon_hash_many in cairo-lang): the elements,
+// padded with 1 and then with 0 to an even length, are absorbed two at a time.
+func PoseidonMany(elems ...*fp.Element) *fp.Element {
+	var state [StateSize]fp.Element
+	var one fp.Element
+	one.SetOne()
+
+	for i := 0; i+1 < len(elems); i += 2 {
+		state[0].Add(&state[0], elems[i])
+		state[1].Add(&state[1], elems[i+1])
+		HadesPermutation(&state)
+	}
+
+	// padding
+	if len(elems)%2 == 1 {
+		state[0].Add(&state[0], elems[len(elems)-1])
+		state[1].Add(&state[1], &one)
+	} else {
+		state[0].Add(&state[0], &one)
+	}
+	HadesPermutation(&state)
+
+	return &state[0]
+}
+
+// digest implements hash.Hash with PoseidonMany
+type digest struct {
+	data []*fp.Element
+}
+
+// New returns a hash.Hash computing the Poseidon hash (see PoseidonMany) of the written field elements.
+func New() hash.Hash {
+	return &digest{}
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size fp.Bytes represents a big endian fp.Element. If len(p) is not a multiple
+// of fp.Bytes or any of the blocks represents an integer larger than fp.Modulus, Write returns an error.
+func (d *digest) Write(p []byte) (int, error) {
+	if len(p)%fp.Bytes != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*fp.Bytes")
+	}
+	elems := make([]*fp.Element, 0, len(p)/fp.Bytes)
+	for start := 0; start < len(p); start += fp.Bytes {
+		elem, err := fp.BigEndian.Element((*[fp.Bytes]byte)(p[start : start+fp.Bytes]))
+		if err != nil {
+			return 0, err
+		}
+		elems = append(elems, &elem)
+	}
+	d.data = append(d.data, elems...)
+	return len(p), nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := PoseidonMany(d.data...).Bytes()
+	return append(b, h[:]...)
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return fp.Bytes
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return fp.Bytes
+}
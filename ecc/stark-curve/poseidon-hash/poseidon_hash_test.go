@@ -0,0 +1,137 @@
+package poseidonhash
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+func newElement(t testing.TB, s string) *fp.Element {
+	e, err := new(fp.Element).SetString(s)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err)
+	}
+	return e
+}
+
+func TestHadesPermutation(t *testing.T) {
+	var state [StateSize]fp.Element
+	HadesPermutation(&state)
+
+	want := [StateSize]string{
+		"0x79e8d1e78258000a28fc9d49e233bc6852357968577b1e386550ed6a9086133",
+		"0x3840d003d0f3f96dbb796ff6aa6a63be5b5404b91ccaabca256154cbb6fb984",
+		"0x1eb39da3f7d3b04142d0ac83d9da00c9325a61fb2ef326e50b70eaa8a3c7cc7",
+	}
+	for i := range want {
+		if !state[i].Equal(newElement(t, want[i])) {
+			t.Errorf("HadesPermutation(0, 0, 0)[%d] = %s, want %s", i, state[i].Text(16), want[i])
+		}
+	}
+}
+
+func TestPoseidon(t *testing.T) {
+	// test vectors of cairo-lang's poseidon_hash
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{
+			"0x1",
+			"0x2",
+			"0x5d44a3decb2b2e0cc71071f7b802f45dd792d064f0fc7316c46514f70f9891a",
+		},
+		{
+			"0xb662f9017fa7956fd70e26129b1833e10ad000fd37b4d9f4e0ce6884b7bbe",
+			"0x1fe356bf76102cdae1bfbdc173602ead228b12904c00dad9cf16e035468bea",
+			"0x75540825a6ecc5dc7d7c2f5f868164182742227f1367d66c43ee51ec7937a81",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("TestHash %d", i), func(t *testing.T) {
+			ans := Poseidon(newElement(t, tt.a), newElement(t, tt.b))
+			if want := newElement(t, tt.want); !ans.Equal(want) {
+				t.Errorf("TestHash got %s, want %s", ans.Text(16), want.Text(16))
+			}
+		})
+	}
+}
+
+func TestPoseidonSingle(t *testing.T) {
+	ans := PoseidonSingle(newElement(t, "0x9dad5d6f502ccbcb6d34ede04f0337df3b98936aaf782f4cc07d147e3a4fd6"))
+	want := newElement(t, "0x11222854783f17f1c580ff64671bc3868de034c236f956216e8ed4ab7533455")
+	if !ans.Equal(want) {
+		t.Errorf("PoseidonSingle got %s, want %s", ans.Text(16), want.Text(16))
+	}
+}
+
+func TestPoseidonMany(t *testing.T) {
+	tests := []struct {
+		input []string
+		want  string
+	}{
+		{
+			input: nil,
+			want:  "0x2272be0f580fd156823304800919530eaa97430e972d7213ee13f4fbf7a5dbc",
+		},
+		{
+			input: []string{"0x1"},
+			want:  "0x579e8877c7755365d5ec1ec7d3a94a457eff5d1f40482bbe9729c064cdead2",
+		},
+		{
+			input: []string{"0x1", "0x2"},
+			want:  "0x371cb6995ea5e7effcd2e174de264b5b407027a75a231a70c2c8d196107f0e7",
+		},
+		{
+			input: []string{"0x1", "0x2", "0x3"},
+			want:  "0x2f0d8840bcf3bc629598d8a6cc80cb7c0d9e52d93dab244bbf9cd0dca0ad082",
+		},
+	}
+	for _, test := range tests {
+		var data []*fp.Element
+		for _, item := range test.input {
+			data = append(data, newElement(t, item))
+		}
+		want := newElement(t, test.want)
+		if got := PoseidonMany(data...); !got.Equal(want) {
+			t.Errorf("PoseidonMany(%x) = %x, want %x", data, got, want)
+		}
+
+		// hash.Hash
+		h := New()
+		for _, e := range data {
+			b := e.Bytes()
+			if _, err := h.Write(b[:]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		wantBytes := want.Bytes()
+		if got := h.Sum(nil); string(got) != string(wantBytes[:]) {
+			t.Errorf("New().Sum(%x) = %x, want %x", data, got, wantBytes)
+		}
+	}
+
+	// non canonical inputs are rejected
+	h := New()
+	b := fp.Modulus().FillBytes(make([]byte, fp.Bytes))
+	if _, err := h.Write(b); err == nil {
+		t.Error("expected an error for a non canonical field element")
+	}
+	if _, err := h.Write(b[1:]); err == nil {
+		t.Error("expected an error for a truncated field element")
+	}
+}
+
+var feltBench *fp.Element
+
+func BenchmarkPoseidon(b *testing.B) {
+	e0 := newElement(b, "0x3d937c035c878245caf64531a5756109c53068da139362728feb561405371cb")
+	e1 := newElement(b, "0x208a0a10250e382e1e4bbe2880906c2791bf6275695e02fbbc6aeff9cd8b31a")
+
+	var f *fp.Element
+	for n := 0; n < b.N; n++ {
+		f = Poseidon(e0, e1)
+	}
+	feltBench = f
+}
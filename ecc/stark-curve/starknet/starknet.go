@@ -0,0 +1,105 @@
+// Package starknet computes the hashes signed by Starknet accounts: selectors, contract
+// addresses, transaction hashes and SNIP-12 typed data messages, with the Pedersen hash
+// of pedersen-hash.
+//
+// The keys and the signatures of the messages are in ecc/stark-curve/ecdsa (KeyFromSeed,
+// PrivateKey.SignStarknet).
+package starknet
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+	pedersenhash "github.com/consensys/gnark-crypto/ecc/stark-curve/pedersen-hash"
+	"golang.org/x/crypto/sha3"
+)
+
+// maxShortStringLength is the maximum length of a Cairo short string
+const maxShortStringLength = 31
+
+var (
+	errShortStringLength = errors.New("a short string must have at most 31 characters")
+	errShortStringASCII  = errors.New("a short string must only contain ASCII characters")
+)
+
+var (
+	// mask250 = 2^250 - 1
+	mask250 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 250), big.NewInt(1))
+
+	// addressBound is the upper bound of the contract addresses, 2^251 - 256
+	addressBound = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 251), big.NewInt(256))
+)
+
+// EncodeShortString returns the felt encoding of the Cairo short string s: its ASCII
+// characters read as a big endian integer.
+func EncodeShortString(s string) (*fp.Element, error) {
+	if len(s) > maxShortStringLength {
+		return nil, errShortStringLength
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return nil, errShortStringASCII
+		}
+	}
+	// 31 bytes are smaller than the modulus
+	return new(fp.Element).SetBigInt(new(big.Int).SetBytes([]byte(s))), nil
+}
+
+// mustShortString returns EncodeShortString(s), for the constants of the package
+func mustShortString(s string) *fp.Element {
+	res, err := EncodeShortString(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// ChainID returns the felt encoding of a Starknet chain id, such as "SN_MAIN" or "SN_SEPOLIA".
+func ChainID(name string) (*fp.Element, error) {
+	return EncodeShortString(name)
+}
+
+// Keccak returns starknet_keccak(data): the 250 least significant bits of the Keccak-256 hash of data.
+func Keccak(data []byte) *fp.Element {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	d := new(big.Int).SetBytes(h.Sum(nil))
+	d.And(d, mask250)
+	return new(fp.Element).SetBigInt(d)
+}
+
+// Selector returns the entry point selector of the function name, starknet_keccak(name).
+func Selector(name string) *fp.Element {
+	return Keccak([]byte(name))
+}
+
+// ContractAddress returns the address of the contract of class classHash deployed by deployer
+// with the salt and the constructor calldata (calculate_contract_address_from_hash in cairo-lang):
+//
+//	h("STARKNET_CONTRACT_ADDRESS", deployer, salt, classHash, h(calldata)) mod 2^251 - 256
+//
+// where h is pedersenhash.PedersenArray. The deployer is 0 for the accounts deployed
+// with a DEPLOY_ACCOUNT transaction.
+func ContractAddress(salt, classHash *fp.Element, constructorCalldata []fp.Element, deployer *fp.Element) *fp.Element {
+	d := pedersenhash.PedersenArray(
+		mustShortString("STARKNET_CONTRACT_ADDRESS"),
+		deployer,
+		salt,
+		classHash,
+		hashOnElements(constructorCalldata),
+	)
+	var res big.Int
+	d.BigInt(&res)
+	res.Mod(&res, addressBound)
+	return d.SetBigInt(&res)
+}
+
+// hashOnElements returns pedersenhash.PedersenArray(elems...), compute_hash_on_elements in cairo-lang
+func hashOnElements(elems []fp.Element) *fp.Element {
+	ptrs := make([]*fp.Element, len(elems))
+	for i := range elems {
+		ptrs[i] = &elems[i]
+	}
+	return pedersenhash.PedersenArray(ptrs...)
+}
@@ -0,0 +1,157 @@
+package starknet
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+func newElement(t testing.TB, s string) *fp.Element {
+	t.Helper()
+	e, err := new(fp.Element).SetString(s)
+	if err != nil {
+		t.Fatalf("expected no error but got %s", err)
+	}
+	return e
+}
+
+func newElements(t testing.TB, s ...string) []fp.Element {
+	t.Helper()
+	res := make([]fp.Element, len(s))
+	for i := range s {
+		res[i] = *newElement(t, s[i])
+	}
+	return res
+}
+
+func TestEncodeShortString(t *testing.T) {
+	e, err := EncodeShortString("SN_MAIN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := newElement(t, "0x534e5f4d41494e"); !e.Equal(want) {
+		t.Errorf("EncodeShortString got %s, want %s", e.Text(16), want.Text(16))
+	}
+	if e, err := ChainID("SN_SEPOLIA"); err != nil || !e.Equal(newElement(t, "0x534e5f5345504f4c4941")) {
+		t.Error("unexpected chain id for SN_SEPOLIA")
+	}
+
+	if _, err := EncodeShortString("a string which is longer than 31 characters"); err == nil {
+		t.Error("expected an error for a string of more than 31 characters")
+	}
+	if _, err := EncodeShortString("é"); err == nil {
+		t.Error("expected an error for a non ASCII string")
+	}
+}
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"transfer", "0x83afd3f4caedc6eebf44246fe54e38c95e3179a5ec9ea81740eca5b482d12e"},
+		{"__execute__", "0x15d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad"},
+	}
+	for _, tt := range tests {
+		if got, want := Selector(tt.name), newElement(t, tt.want); !got.Equal(want) {
+			t.Errorf("Selector(%s) got %s, want %s", tt.name, got.Text(16), want.Text(16))
+		}
+	}
+}
+
+func TestExecuteCalldata(t *testing.T) {
+	token := newElement(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	calldata := ExecuteCalldata([]Call{{
+		To:       *token,
+		Selector: *Selector("transfer"),
+		Calldata: []fp.Element{*newElement(t, "0x1234"), *newElement(t, "0x64"), {}},
+	}})
+	want := []*fp.Element{newElement(t, "1"), token, Selector("transfer"), newElement(t, "3"), newElement(t, "0x1234"), newElement(t, "0x64"), new(fp.Element)}
+	if len(calldata) != len(want) {
+		t.Fatal("unexpected length of the __execute__ calldata")
+	}
+	for i := range want {
+		if !calldata[i].Equal(want[i]) {
+			t.Fatalf("unexpected __execute__ calldata at %d", i)
+		}
+	}
+}
+
+// The transactions of TestTransactionHash are Starknet transactions from the test data of the
+// feeder gateway client of juno (clients/feeder/testdata of github.com/NethermindEth/juno v0.11.0).
+func TestTransactionHash(t *testing.T) {
+	mainnet, err := ChainID("SN_MAIN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the chain id of the integration network
+	goerli, err := ChainID("SN_GOERLI")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mainnet, block 16730
+	invoke := InvokeTransactionV1{
+		SenderAddress: *newElement(t, "0x1fc039de7d864580b57a575e8e6b7114f4d2a954d7d29f876b2eb3dd09394a0"),
+		Calldata: newElements(t,
+			"0x1",
+			"0x727a63f78ee3f1bd18f78009067411ab369c31dece1ae22e16f567906409905",
+			"0x22de356837ac200bca613c78bd1fcc962a97770c06625f0c8b3edeb6ae4aa59",
+			"0x0",
+			"0xb",
+			"0xb",
+			"0xa",
+			"0x6db793d93ce48bc75a5ab02e6a82aad67f01ce52b7b903090725dbc4000eaa2",
+			"0x6141eac4031dfb422080ed567fe008fb337b9be2561f479a377aa1de1d1b676",
+			"0x27eb1a21fa7593dd12e988c9dd32917a0dea7d77db7e89a809464c09cf951c0",
+			"0x400a29400a34d8f69425e1f4335e6a6c24ce1111db3954e4befe4f90ca18eb7",
+			"0x599e56821170a12cdcf88fb8714057ce364a8728f738853da61d5b3af08a390",
+			"0x46ad66f467df625f3b2dd9d3272e61713e8f74b68adac6718f7497d742cfb17",
+			"0x4f348b585e6c1919d524a4bfe6f97230ecb61736fe57534ec42b628f7020849",
+			"0x19ae40a095ffe79b0c9fc03df2de0d2ab20f59a2692ed98a8c1062dbf691572",
+			"0xe120336994adef6c6e47694f87278686511d4622997d4a6f216bd6e9fa9acc",
+			"0x56e6637a4958d062db8c8198e315772819f64d915e5c7a8d58a99fa90ff0742",
+		),
+		MaxFee: *newElement(t, "0x17f0de82f4be6"),
+		Nonce:  *newElement(t, "0x42"),
+	}
+	want := newElement(t, "0x2897e3cec3e24e4d341df26b8cf1ab84ea1c01a051021836b36c6639145b497")
+	if got := invoke.Hash(mainnet); !got.Equal(want) {
+		t.Errorf("InvokeTransactionV1.Hash got %s, want %s", got.Text(16), want.Text(16))
+	}
+
+	// integration, block 320525
+	declare := DeclareTransactionV2{
+		SenderAddress:     *newElement(t, "0x3bb81d22ecd0e0a6f3138bdc5c072ff5726c5add02bcfd5b81cd657a6ae10a8"),
+		ClassHash:         *newElement(t, "0x7cb013a4139335cefce52adc2ac342c0110811353e7992baefbe547200223c7"),
+		CompiledClassHash: *newElement(t, "0x67f7deab53a3ba70500bdafe66fb3038bbbaadb36a6dd1a7a5fc5b094e9d724"),
+		MaxFee:            *newElement(t, "0x50c8f30c048"),
+		Nonce:             *newElement(t, "0x11"),
+	}
+	want = newElement(t, "0x44b971f7eface29b185f86dd7b3b70acb1e48e0ad459e3a41e06fc42937aaa4")
+	if got := declare.Hash(goerli); !got.Equal(want) {
+		t.Errorf("DeclareTransactionV2.Hash got %s, want %s", got.Text(16), want.Text(16))
+	}
+
+	// mainnet, block 11437
+	deploy := DeployAccountTransactionV1{
+		ClassHash:           *newElement(t, "0x25ec026985a3bf9d0cc1fe17326b245dfdc3ff89b8fde106542a3ea56c5a918"),
+		ContractAddressSalt: *newElement(t, "0x25b9dbdab19b190a556aa42cdfbc07ad6ffe415031e42a8caffd4a2438d5cc3"),
+		ConstructorCalldata: newElements(t,
+			"0x33434ad846cdd5f23eb73ff09fe6fddd568284a0fb7d1be20ee482f044dabe2",
+			"0x79dc0da7c54b95f10aa182ad0a46400db63156920adb65eca2654c0945a463",
+			"0x2",
+			"0x25b9dbdab19b190a556aa42cdfbc07ad6ffe415031e42a8caffd4a2438d5cc3",
+			"0x0",
+		),
+		MaxFee: *newElement(t, "0x59f5f9f474b0"),
+		Nonce:  *newElement(t, "0x0"),
+	}
+	want = newElement(t, "0x104714313388bd0ab569ac247fed6cf0b7a2c737105c00d64c23e24bd8dea40")
+	if got := deploy.Address(); !got.Equal(want) {
+		t.Errorf("DeployAccountTransactionV1.Address got %s, want %s", got.Text(16), want.Text(16))
+	}
+	want = newElement(t, "0x32b272b6d0d584305a460197aa849b5c7a9a85903b66e9d3e1afa2427ef093e")
+	if got := deploy.Hash(mainnet); !got.Equal(want) {
+		t.Errorf("DeployAccountTransactionV1.Hash got %s, want %s", got.Text(16), want.Text(16))
+	}
+}
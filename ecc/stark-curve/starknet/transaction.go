@@ -0,0 +1,104 @@
+package starknet
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+// Call is a call of a contract function, from the multicall of an account.
+type Call struct {
+	To       fp.Element
+	Selector fp.Element
+	Calldata []fp.Element
+}
+
+// ExecuteCalldata returns the calldata of the __execute__ function of a Cairo 1 account
+// making the calls:
+//
+//	len(calls), to_0, selector_0, len(calldata_0), calldata_0.., to_1, ..
+func ExecuteCalldata(calls []Call) []fp.Element {
+	res := make([]fp.Element, 1, 1+4*len(calls))
+	res[0].SetUint64(uint64(len(calls)))
+	for i := range calls {
+		var n fp.Element
+		n.SetUint64(uint64(len(calls[i].Calldata)))
+		res = append(res, calls[i].To, calls[i].Selector, n)
+		res = append(res, calls[i].Calldata...)
+	}
+	return res
+}
+
+// transactionHash returns the hash of a transaction before v3
+// (calculate_transaction_hash_common in cairo-lang):
+//
+//	h(prefix, version, address, entryPointSelector, h(calldata), maxFee, chainID, additionalData..)
+//
+// where h is pedersenhash.PedersenArray.
+func transactionHash(prefix string, version uint64, address, entryPointSelector *fp.Element, calldata []fp.Element, maxFee, chainID *fp.Element, additionalData ...fp.Element) *fp.Element {
+	var v fp.Element
+	v.SetUint64(version)
+	data := make([]fp.Element, 0, 7+len(additionalData))
+	data = append(data,
+		*mustShortString(prefix),
+		v,
+		*address,
+		*entryPointSelector,
+		*hashOnElements(calldata),
+		*maxFee,
+		*chainID,
+	)
+	data = append(data, additionalData...)
+	return hashOnElements(data)
+}
+
+// InvokeTransactionV1 is an INVOKE transaction of version 1, calling the __execute__
+// function of the account SenderAddress with Calldata (see ExecuteCalldata).
+type InvokeTransactionV1 struct {
+	SenderAddress fp.Element
+	Calldata      []fp.Element
+	MaxFee        fp.Element
+	Nonce         fp.Element
+}
+
+// Hash returns the hash of the transaction on the chain chainID, signed by the account.
+func (tx *InvokeTransactionV1) Hash(chainID *fp.Element) *fp.Element {
+	return transactionHash("invoke", 1, &tx.SenderAddress, new(fp.Element), tx.Calldata, &tx.MaxFee, chainID, tx.Nonce)
+}
+
+// DeclareTransactionV2 is a DECLARE transaction of version 2, declaring the Cairo 1 class
+// ClassHash with its compiled (CASM) class hash.
+type DeclareTransactionV2 struct {
+	SenderAddress     fp.Element
+	ClassHash         fp.Element
+	CompiledClassHash fp.Element
+	MaxFee            fp.Element
+	Nonce             fp.Element
+}
+
+// Hash returns the hash of the transaction on the chain chainID, signed by the account.
+func (tx *DeclareTransactionV2) Hash(chainID *fp.Element) *fp.Element {
+	return transactionHash("declare", 2, &tx.SenderAddress, new(fp.Element), []fp.Element{tx.ClassHash}, &tx.MaxFee, chainID, tx.Nonce, tx.CompiledClassHash)
+}
+
+// DeployAccountTransactionV1 is a DEPLOY_ACCOUNT transaction of version 1, deploying
+// the account of class ClassHash at ContractAddress(ContractAddressSalt, ClassHash,
+// ConstructorCalldata, 0).
+type DeployAccountTransactionV1 struct {
+	ClassHash           fp.Element
+	ContractAddressSalt fp.Element
+	ConstructorCalldata []fp.Element
+	MaxFee              fp.Element
+	Nonce               fp.Element
+}
+
+// Address returns the address of the deployed account.
+func (tx *DeployAccountTransactionV1) Address() *fp.Element {
+	return ContractAddress(&tx.ContractAddressSalt, &tx.ClassHash, tx.ConstructorCalldata, new(fp.Element))
+}
+
+// Hash returns the hash of the transaction on the chain chainID, signed by the deployed account.
+func (tx *DeployAccountTransactionV1) Hash(chainID *fp.Element) *fp.Element {
+	calldata := make([]fp.Element, 0, 2+len(tx.ConstructorCalldata))
+	calldata = append(calldata, tx.ClassHash, tx.ContractAddressSalt)
+	calldata = append(calldata, tx.ConstructorCalldata...)
+	return transactionHash("deploy_account", 1, tx.Address(), new(fp.Element), calldata, &tx.MaxFee, chainID, tx.Nonce)
+}
@@ -0,0 +1,238 @@
+package starknet
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+// domainType is the type of the domain of the typed data (revision 0)
+const domainType = "StarkNetDomain"
+
+// TypedDataField is a member of a struct type of TypedData.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is a SNIP-12 typed data message, in revision 0 (the "StarkNetDomain"
+// revision, hashed with Pedersen), as signed by the Starknet wallets.
+//
+// The values of the domain and the message are as decoded by encoding/json: a map[string]interface{}
+// for the structs, a []interface{} for the arrays, and a number, a bool or a string for
+// the felts. A string is read as a hexadecimal integer if it starts with 0x, as a decimal
+// integer if it only has digits, and as a short string otherwise.
+//
+// The supported types are felt, bool, string (a short string), selector (a function
+// name or its selector), the struct types and the arrays of those (type*).
+//
+// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-12.md
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// EncodeType returns the encoding of the struct type typeName, followed by the
+// encodings of its dependencies in alphabetical order:
+//
+//	Mail(from:Person,to:Person,contents:felt)Person(name:felt,wallet:felt)
+func (td *TypedData) EncodeType(typeName string) (string, error) {
+	if _, ok := td.Types[typeName]; !ok {
+		return "", fmt.Errorf("unknown type %s", typeName)
+	}
+	deps := make(map[string]bool)
+	td.dependencies(typeName, deps)
+	delete(deps, typeName)
+
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var sb strings.Builder
+	for _, t := range append([]string{typeName}, sorted...) {
+		sb.WriteString(t)
+		sb.WriteByte('(')
+		for i, field := range td.Types[t] {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(field.Name)
+			sb.WriteByte(':')
+			sb.WriteString(field.Type)
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String(), nil
+}
+
+// dependencies adds to deps the struct types reachable from typeName
+func (td *TypedData) dependencies(typeName string, deps map[string]bool) {
+	typeName = strings.TrimSuffix(typeName, "*")
+	fields, ok := td.Types[typeName]
+	if !ok || deps[typeName] {
+		return
+	}
+	deps[typeName] = true
+	for _, field := range fields {
+		td.dependencies(field.Type, deps)
+	}
+}
+
+// TypeHash returns starknet_keccak(EncodeType(typeName)).
+func (td *TypedData) TypeHash(typeName string) (*fp.Element, error) {
+	enc, err := td.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return Keccak([]byte(enc)), nil
+}
+
+// StructHash returns the hash of the value data of the struct type typeName:
+//
+//	h(TypeHash(typeName), encode(data[field_0]), encode(data[field_1]), ..)
+//
+// where h is pedersenhash.PedersenArray, a struct member is encoded by its StructHash,
+// an array by h of the encodings of its elements, and a selector by starknet_keccak
+// of the function name.
+func (td *TypedData) StructHash(typeName string, data map[string]interface{}) (*fp.Element, error) {
+	typeHash, err := td.TypeHash(typeName)
+	if err != nil {
+		return nil, err
+	}
+	fields := td.Types[typeName]
+	elems := make([]fp.Element, 1, 1+len(fields))
+	elems[0] = *typeHash
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for %s.%s", typeName, field.Name)
+		}
+		e, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Name, err)
+		}
+		elems = append(elems, *e)
+	}
+	return hashOnElements(elems), nil
+}
+
+// encodeValue returns the encoding of value, of type typeName
+func (td *TypedData) encodeValue(typeName string, value interface{}) (*fp.Element, error) {
+	if _, ok := td.Types[typeName]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the value of type %s must be a struct", typeName)
+		}
+		return td.StructHash(typeName, data)
+	}
+
+	if strings.HasSuffix(typeName, "*") {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the value of type %s must be an array", typeName)
+		}
+		elems := make([]fp.Element, len(values))
+		for i := range values {
+			e, err := td.encodeValue(strings.TrimSuffix(typeName, "*"), values[i])
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = *e
+		}
+		return hashOnElements(elems), nil
+	}
+
+	switch typeName {
+	case "felt", "bool", "string", "shortstring", "ContractAddress", "ClassHash", "timestamp", "u128":
+		return toFelt(value)
+	case "selector":
+		if s, ok := value.(string); ok && !strings.HasPrefix(s, "0x") {
+			return Selector(s), nil
+		}
+		return toFelt(value)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typeName)
+	}
+}
+
+// MessageHash returns the hash of the typed data signed by the account address:
+//
+//	h("StarkNet Message", StructHash("StarkNetDomain", Domain), account, StructHash(PrimaryType, Message))
+//
+// where h is pedersenhash.PedersenArray.
+func (td *TypedData) MessageHash(account *fp.Element) (*fp.Element, error) {
+	domainHash, err := td.StructHash(domainType, td.Domain)
+	if err != nil {
+		return nil, err
+	}
+	messageHash, err := td.StructHash(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+	return hashOnElements([]fp.Element{
+		*mustShortString("StarkNet Message"),
+		*domainHash,
+		*account,
+		*messageHash,
+	}), nil
+}
+
+// toFelt converts a scalar value of the typed data to a felt
+func toFelt(value interface{}) (*fp.Element, error) {
+	var res fp.Element
+	switch v := value.(type) {
+	case bool:
+		if v {
+			res.SetOne()
+		}
+		return &res, nil
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v > 1<<53 {
+			return nil, fmt.Errorf("%v is not a felt", v)
+		}
+		return res.SetUint64(uint64(v)), nil
+	case json.Number:
+		return stringToFelt(v.String())
+	case string:
+		return stringToFelt(v)
+	default:
+		return nil, fmt.Errorf("unsupported value %v of type %T", value, value)
+	}
+}
+
+// stringToFelt reads s as a hexadecimal integer if it starts with 0x, as a decimal integer
+// if it only has digits, and as a short string otherwise
+func stringToFelt(s string) (*fp.Element, error) {
+	isDecimal := s != ""
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			isDecimal = false
+			break
+		}
+	}
+	if !isDecimal && !strings.HasPrefix(s, "0x") {
+		return EncodeShortString(s)
+	}
+
+	base, digits := 10, s
+	if !isDecimal {
+		base, digits = 16, s[2:]
+	}
+	v, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", s)
+	}
+	if v.Cmp(fp.Modulus()) >= 0 {
+		return nil, fmt.Errorf("the integer %s is larger than the modulus", s)
+	}
+	return new(fp.Element).SetBigInt(v), nil
+}
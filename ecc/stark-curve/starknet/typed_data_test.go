@@ -0,0 +1,135 @@
+package starknet
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// typed data example of starknet.js
+const mailTypedData = `{
+	"types": {
+		"StarkNetDomain": [
+			{ "name": "name", "type": "felt" },
+			{ "name": "version", "type": "felt" },
+			{ "name": "chainId", "type": "felt" }
+		],
+		"Person": [
+			{ "name": "name", "type": "felt" },
+			{ "name": "wallet", "type": "felt" }
+		],
+		"Mail": [
+			{ "name": "from", "type": "Person" },
+			{ "name": "to", "type": "Person" },
+			{ "name": "contents", "type": "felt" }
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "StarkNet Mail",
+		"version": "1",
+		"chainId": 1
+	},
+	"message": {
+		"from": {
+			"name": "Cow",
+			"wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+		},
+		"to": {
+			"name": "Bob",
+			"wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"
+		},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedData(t *testing.T) {
+	var td TypedData
+	if err := json.Unmarshal([]byte(mailTypedData), &td); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := td.EncodeType("Mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Mail(from:Person,to:Person,contents:felt)Person(name:felt,wallet:felt)"; enc != want {
+		t.Errorf("EncodeType got %s, want %s", enc, want)
+	}
+
+	typeHash, err := td.TypeHash("StarkNetDomain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := newElement(t, "0x1bfc207425a47a5dfa1a50a4f5241203f50624ca5fdf5e18755765416b8e288"); !typeHash.Equal(want) {
+		t.Errorf("TypeHash got %s, want %s", typeHash.Text(16), want.Text(16))
+	}
+
+	h, err := td.MessageHash(newElement(t, "0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := newElement(t, "0x6fcff244f63e38b9d88b9e3378d44757710d1b244282b435cb472053c8d78d0"); !h.Equal(want) {
+		t.Errorf("MessageHash got %s, want %s", h.Text(16), want.Text(16))
+	}
+}
+
+func TestTypedDataArrays(t *testing.T) {
+	td := TypedData{
+		Types: map[string][]TypedDataField{
+			"StarkNetDomain": {{Name: "name", Type: "felt"}},
+			"Call":           {{Name: "to", Type: "felt"}, {Name: "selector", Type: "selector"}, {Name: "calldata", Type: "felt*"}},
+			"Session":        {{Name: "calls", Type: "Call*"}, {Name: "active", Type: "bool"}},
+		},
+		PrimaryType: "Session",
+		Domain:      map[string]interface{}{"name": "dapp"},
+		Message: map[string]interface{}{
+			"calls": []interface{}{
+				map[string]interface{}{"to": "0x42", "selector": "transfer", "calldata": []interface{}{"1", "0x2", float64(3)}},
+			},
+			"active": true,
+		},
+	}
+
+	enc, err := td.EncodeType("Session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Session(calls:Call*,active:bool)Call(to:felt,selector:selector,calldata:felt*)"; enc != want {
+		t.Errorf("EncodeType got %s, want %s", enc, want)
+	}
+
+	// selectors can be given by name or value, integers in decimal or hexadecimal
+	call, err := td.StructHash("Call", td.Message["calls"].([]interface{})[0].(map[string]interface{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := td.StructHash("Call", map[string]interface{}{
+		"to":       "66",
+		"selector": "0x" + Selector("transfer").Text(16),
+		"calldata": []interface{}{json.Number("1"), "2", "0x3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !call.Equal(same) {
+		t.Error("equal values should have the same hash")
+	}
+
+	if _, err := td.MessageHash(newElement(t, "0x1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid messages
+	delete(td.Message, "active")
+	if _, err := td.MessageHash(newElement(t, "0x1")); err == nil {
+		t.Error("expected an error for a missing value")
+	}
+	td.Message["active"] = -1.0
+	if _, err := td.MessageHash(newElement(t, "0x1")); err == nil {
+		t.Error("expected an error for a negative number")
+	}
+	td.Types["Session"][1].Type = "merkletree"
+	if _, err := td.MessageHash(newElement(t, "0x1")); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+}
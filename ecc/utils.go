@@ -46,6 +46,36 @@ func NafDecomposition(a *big.Int, result []int8) int {
 	return length
 }
 
+// WnafDecomposition gets the width-w naf decomposition of a non-negative big number,
+// with w in [2, 8]: the digits are zero or odd, in ]-2ʷ⁻¹, 2ʷ⁻¹[, and any w consecutive
+// digits contain at most one non-zero digit. result must be of size a.BitLen()+1.
+func WnafDecomposition(a *big.Int, w uint, result []int8) int {
+
+	var k, d big.Int
+	k.Set(a)
+
+	mask := big.Word(1)<<w - 1
+	half := int64(1) << (w - 1)
+
+	length := 0
+	for k.Sign() != 0 {
+		if k.Bit(0) == 1 {
+			digit := int64(k.Bits()[0] & mask)
+			if digit >= half {
+				digit -= 1 << w
+			}
+			result[length] = int8(digit)
+			d.SetInt64(digit)
+			k.Sub(&k, &d)
+		} else {
+			result[length] = 0
+		}
+		k.Rsh(&k, 1)
+		length++
+	}
+	return length
+}
+
 //-------------------------------------------------------
 // GLV utils
 
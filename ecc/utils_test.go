@@ -21,6 +21,40 @@ func TestNafDecomposition(t *testing.T) {
 	}
 }
 
+func TestWnafDecomposition(t *testing.T) {
+	t.Parallel()
+
+	var result [300]int8
+	for w := uint(2); w <= 8; w++ {
+		for _, e := range []string{"0", "1", "13", "255", "256", "115792089237316195423570985008687907852837564279074904382605163141518161494336"} {
+			var exp, acc, d big.Int
+			exp.SetString(e, 10)
+			n := WnafDecomposition(&exp, w, result[:])
+			if n > exp.BitLen()+1 {
+				t.Fatal("wnaf decomposition is too long")
+			}
+
+			lastNonZero := n + int(w)
+			for i := n - 1; i >= 0; i-- {
+				digit := result[i]
+				if digit != 0 {
+					if digit%2 == 0 || int(digit) >= 1<<(w-1) || int(digit) <= -(1<<(w-1)) {
+						t.Fatalf("invalid digit %d for w=%d", digit, w)
+					}
+					if lastNonZero-i < int(w) {
+						t.Fatalf("non-zero digits too close for w=%d", w)
+					}
+					lastNonZero = i
+				}
+				acc.Lsh(&acc, 1).Add(&acc, d.SetInt64(int64(digit)))
+			}
+			if acc.Cmp(&exp) != 0 {
+				t.Fatalf("wrong decomposition of %s for w=%d", e, w)
+			}
+		}
+	}
+}
+
 func TestSplitting(t *testing.T) {
 	t.Parallel()
 
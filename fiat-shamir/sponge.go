@@ -0,0 +1,297 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// wideReductionBytes is the number of extra bytes squeezed when sampling an integer
+// modulo some bound with a byte oriented hash function, so that the statistical distance
+// to the uniform distribution is at most 2⁻¹²⁸.
+const wideReductionBytes = 16
+
+var (
+	errInvalidModulus = errors.New("modulus must be strictly positive")
+	errInvalidBound   = errors.New("bound must be strictly positive")
+)
+
+// fieldHasher is implemented by the algebraic hash functions, which only absorb field
+// elements and provide their own way to absorb arbitrary strings (like MiMC).
+type fieldHasher interface {
+	WriteString(rawBytes []byte)
+}
+
+// Marshaler is implemented by the types that can be absorbed by a Sponge,
+// typically field elements (fr.Element, fp.Element) and curve points (G1Affine, G2Affine).
+type Marshaler interface {
+	Marshal() []byte
+}
+
+// Sponge is a Fiat-Shamir transcript following the duplex sponge paradigm.
+//
+// Unlike Transcript, challenges don't need to be declared up-front: the prover and
+// the verifier absorb labelled values and squeeze labelled challenges in the same order.
+// Every squeeze depends on all the values absorbed and all the challenges squeezed before it.
+//
+// The underlying hash function can be a byte oriented hash (SHA-256, Keccak, ...) or an
+// algebraic hash such as MiMC. In the latter case, labels are mapped to the field through
+// the hash's WriteString method and absorbed values must be encodings of field elements.
+type Sponge struct {
+	newHash func() hash.Hash
+	h       hash.Hash
+
+	state   []byte   // chaining value, digest of everything absorbed and squeezed so far
+	pending []absorb // values absorbed since the last squeeze
+}
+
+type absorb struct {
+	label string
+	data  []byte
+}
+
+// NewSponge returns a new Sponge transcript.
+// newHash is called once per transcript (and once per fork or clone) to instantiate the hash function.
+// domainSeparator identifies the protocol and is absorbed first.
+func NewSponge(newHash func() hash.Hash, domainSeparator string) *Sponge {
+	s := &Sponge{
+		newHash: newHash,
+		h:       newHash(),
+	}
+	s.h.Reset()
+	s.writeString("fiat-shamir.sponge")
+	s.writeString(domainSeparator)
+	s.state = s.h.Sum(nil)
+	return s
+}
+
+// Absorb appends the values to the transcript, under the given label.
+// The values are copied, so they can be modified by the caller afterward.
+func (s *Sponge) Absorb(label string, values ...[]byte) {
+	for _, v := range values {
+		vCopy := make([]byte, len(v))
+		copy(vCopy, v)
+		s.pending = append(s.pending, absorb{label: label, data: vCopy})
+	}
+	if len(values) == 0 {
+		s.pending = append(s.pending, absorb{label: label})
+	}
+}
+
+// AbsorbMarshalers appends the binary encoding of the values to the transcript, under the given label.
+func (s *Sponge) AbsorbMarshalers(label string, values ...Marshaler) {
+	for _, v := range values {
+		s.pending = append(s.pending, absorb{label: label, data: v.Marshal()})
+	}
+	if len(values) == 0 {
+		s.pending = append(s.pending, absorb{label: label})
+	}
+}
+
+// Squeeze returns nbBytes pseudo-random bytes, bound to the label and to the current
+// state of the transcript.
+func (s *Sponge) Squeeze(label string, nbBytes int) ([]byte, error) {
+	if err := s.duplex(label); err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 0, nbBytes)
+	var counter [8]byte
+	for i := uint64(0); len(res) < nbBytes; i++ {
+		s.h.Reset()
+		if _, err := s.h.Write(s.state); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(counter[:], i)
+		s.writeString(string(counter[:]))
+		res = s.h.Sum(res)
+	}
+	s.h.Reset()
+
+	return res[:nbBytes], nil
+}
+
+// SqueezeBigInt returns an integer in [0, modulus). See SqueezeBigInts for its
+// distance to the uniform distribution.
+func (s *Sponge) SqueezeBigInt(label string, modulus *big.Int) (*big.Int, error) {
+	res, err := s.SqueezeBigInts(label, modulus, 1)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// SqueezeBigInts returns n integers in [0, modulus), sampled from a single squeeze.
+//
+// With a byte oriented hash function (SHA-256, Keccak, ...), each integer is reduced from
+// 128 more bits than the modulus, and its statistical distance to the uniform distribution
+// is at most 2⁻¹²⁸.
+//
+// With an algebraic hash function over a field 𝔽q (like MiMC), the squeezed bytes are
+// encodings of elements of 𝔽q and are not uniform. Each integer is then reduced from whole
+// elements, the least significant of which is uniform in [0, q), and its statistical
+// distance to the uniform distribution is at most (q mod modulus)/q: it is uniform if
+// modulus = q, for instance for challenges in the field of the hash, and the distance is
+// at most modulus/q if modulus < q, but it is not close to uniform otherwise.
+func (s *Sponge) SqueezeBigInts(label string, modulus *big.Int, n int) ([]*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, errInvalidModulus
+	}
+	nbBytes := (modulus.BitLen()+7)/8 + wideReductionBytes
+	if _, ok := s.h.(fieldHasher); ok {
+		size := s.h.Size()
+		nbBytes = ((modulus.BitLen()+7)/8 + size - 1) / size * size
+	}
+	b, err := s.Squeeze(label, n*nbBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*big.Int, n)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(b[i*nbBytes : (i+1)*nbBytes])
+		res[i].Mod(res[i], modulus)
+	}
+	return res, nil
+}
+
+// SqueezeUint64 returns an integer in [0, bound). It is typically used to sample query
+// positions. See SqueezeBigInts for its distance to the uniform distribution.
+func (s *Sponge) SqueezeUint64(label string, bound uint64) (uint64, error) {
+	if bound == 0 {
+		return 0, errInvalidBound
+	}
+	r, err := s.SqueezeBigInt(label, new(big.Int).SetUint64(bound))
+	if err != nil {
+		return 0, err
+	}
+	return r.Uint64(), nil
+}
+
+// SqueezeElements returns n field elements, sampled as SqueezeBigInts (see its distance to
+// the uniform distribution). E is the field element type, for instance fr.Element:
+//
+//	alpha, err := fiatshamir.SqueezeElements[fr.Element](sponge, "alpha", fr.Modulus(), 1)
+func SqueezeElements[E any, PE interface {
+	*E
+	SetBigInt(*big.Int) *E
+}](s *Sponge, label string, modulus *big.Int, n int) ([]E, error) {
+	ints, err := s.SqueezeBigInts(label, modulus, n)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]E, n)
+	for i := range res {
+		PE(&res[i]).SetBigInt(ints[i])
+	}
+	return res, nil
+}
+
+// SqueezeElement returns a field element, sampled as SqueezeElements.
+func SqueezeElement[E any, PE interface {
+	*E
+	SetBigInt(*big.Int) *E
+}](s *Sponge, label string, modulus *big.Int) (E, error) {
+	res, err := SqueezeElements[E, PE](s, label, modulus, 1)
+	if err != nil {
+		var zero E
+		return zero, err
+	}
+	return res[0], nil
+}
+
+// Fork returns a new transcript, bound to the current state of s and to the label.
+// It is meant to be handed to a sub-protocol; s can be used independently afterward.
+// The values absorbed in s and not squeezed yet are bound to the fork.
+func (s *Sponge) Fork(label string) (*Sponge, error) {
+	if err := s.duplex("fork." + label); err != nil {
+		return nil, err
+	}
+	f := s.Clone()
+	f.Absorb("fork", []byte(label))
+	return f, nil
+}
+
+// Clone returns an independent copy of the transcript in its current state.
+func (s *Sponge) Clone() *Sponge {
+	res := &Sponge{
+		newHash: s.newHash,
+		h:       s.newHash(),
+		state:   make([]byte, len(s.state)),
+		pending: make([]absorb, len(s.pending)),
+	}
+	copy(res.state, s.state)
+	copy(res.pending, s.pending)
+	return res
+}
+
+// duplex updates the state of the sponge with the pending values and the label.
+// state ← H(state || label || pending...), where every pending value is absorbed as
+// label || len(data) || data, so that distinct sequences of values give distinct inputs.
+func (s *Sponge) duplex(label string) error {
+	s.h.Reset()
+	defer s.h.Reset()
+
+	if _, err := s.h.Write(s.state); err != nil {
+		return err
+	}
+	s.writeString(label)
+	for _, a := range s.pending {
+		s.writeString(a.label)
+		if err := s.writeLength(len(a.data)); err != nil {
+			return err
+		}
+		if len(a.data) == 0 {
+			continue
+		}
+		if _, err := s.h.Write(a.data); err != nil {
+			return err
+		}
+	}
+	s.state = s.h.Sum(s.state[:0])
+	s.pending = s.pending[:0]
+	return nil
+}
+
+// writeLength writes the length of an absorbed value in the hash, on 8 bytes in big endian,
+// or on a block in big endian if the hash function only accepts field elements.
+func (s *Sponge) writeLength(n int) error {
+	if _, ok := s.h.(fieldHasher); ok {
+		prefix := make([]byte, s.h.BlockSize())
+		binary.BigEndian.PutUint64(prefix[len(prefix)-8:], uint64(n))
+		_, err := s.h.Write(prefix)
+		return err
+	}
+	var prefix [8]byte
+	binary.BigEndian.PutUint64(prefix[:], uint64(n))
+	_, err := s.h.Write(prefix[:])
+	return err
+}
+
+// writeString writes a length prefixed label in the hash. If the hash function
+// provides its own way to absorb arbitrary strings (like MiMC, which only accepts
+// field elements), it is used instead.
+func (s *Sponge) writeString(str string) {
+	if hashToField, ok := s.h.(fieldHasher); ok {
+		hashToField.WriteString([]byte(str))
+		return
+	}
+	var prefix [8]byte
+	binary.BigEndian.PutUint64(prefix[:], uint64(len(str)))
+	_, _ = s.h.Write(prefix[:])
+	_, _ = s.h.Write([]byte(str))
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+func TestSpongeDeterministic(t *testing.T) {
+	t.Parallel()
+
+	run := func() []byte {
+		s := NewSponge(sha256.New, "test")
+		s.Absorb("a", []byte("v1"), []byte("v2"))
+		c1, err := s.Squeeze("alpha", 40)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Absorb("b", []byte("v3"))
+		c2, err := s.Squeeze("beta", 40)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(c1, c2...)
+	}
+
+	if !bytes.Equal(run(), run()) {
+		t.Fatal("prover and verifier transcripts disagree")
+	}
+}
+
+func TestSpongeBinding(t *testing.T) {
+	t.Parallel()
+
+	challenge := func(domain, label string, value []byte) []byte {
+		s := NewSponge(sha256.New, domain)
+		s.Absorb(label, value)
+		c, err := s.Squeeze("alpha", 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	ref := challenge("test", "a", []byte("v"))
+	if bytes.Equal(ref, challenge("other", "a", []byte("v"))) {
+		t.Fatal("challenge should depend on the domain separator")
+	}
+	if bytes.Equal(ref, challenge("test", "b", []byte("v"))) {
+		t.Fatal("challenge should depend on the label of absorbed values")
+	}
+	if bytes.Equal(ref, challenge("test", "a", []byte("w"))) {
+		t.Fatal("challenge should depend on the absorbed values")
+	}
+
+	// the values are length prefixed: one value can't mimic the encoding of several ones
+	var prefix [8]byte
+	prefix[7] = 1
+	forged := append(append([]byte("x"), prefix[:]...), 'a', 'y')
+	s1 := NewSponge(sha256.New, "test")
+	s1.Absorb("a", forged)
+	s2 := NewSponge(sha256.New, "test")
+	s2.Absorb("a", []byte("x"), []byte("y"))
+	c1, _ := s1.Squeeze("alpha", 32)
+	c2, _ := s2.Squeeze("alpha", 32)
+	if bytes.Equal(c1, c2) {
+		t.Fatal("challenge should depend on the boundaries of the absorbed values")
+	}
+
+	// successive squeezes with the same label must differ
+	s := NewSponge(sha256.New, "test")
+	c1, _ = s.Squeeze("alpha", 32)
+	c2, _ = s.Squeeze("alpha", 32)
+	if bytes.Equal(c1, c2) {
+		t.Fatal("successive challenges should differ")
+	}
+}
+
+func TestSpongeForkClone(t *testing.T) {
+	t.Parallel()
+
+	s := NewSponge(sha256.New, "test")
+	s.Absorb("a", []byte("v"))
+
+	c := s.Clone()
+	c1, _ := s.Squeeze("alpha", 32)
+	c2, _ := c.Squeeze("alpha", 32)
+	if !bytes.Equal(c1, c2) {
+		t.Fatal("a clone should behave as the original transcript")
+	}
+
+	f1, err := s.Fork("sub1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f2, err := c.Fork("sub2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d1, _ := f1.Squeeze("beta", 32)
+	d2, _ := f2.Squeeze("beta", 32)
+	d3, _ := s.Squeeze("beta", 32)
+	if bytes.Equal(d1, d2) || bytes.Equal(d1, d3) {
+		t.Fatal("forks should be independent from each other and from their parent")
+	}
+}
+
+func TestSpongeTypedValues(t *testing.T) {
+	t.Parallel()
+
+	for _, newHash := range []struct {
+		name string
+		s    *Sponge
+	}{
+		{"sha256", NewSponge(sha256.New, "test")},
+		{"mimc", NewSponge(mimc.NewMiMC, "test")},
+	} {
+		s := newHash.s
+
+		var x fr.Element
+		x.SetRandom()
+		s.AbsorbMarshalers("x", &x)
+
+		challenges, err := SqueezeElements[fr.Element](s, "alpha", fr.Modulus(), 3)
+		if err != nil {
+			t.Fatal(newHash.name, err)
+		}
+		if challenges[0].Equal(&challenges[1]) || challenges[1].Equal(&challenges[2]) {
+			t.Fatal(newHash.name, "squeezed elements should differ")
+		}
+
+		for i := 0; i < 100; i++ {
+			r, err := s.SqueezeUint64("query", 10)
+			if err != nil {
+				t.Fatal(newHash.name, err)
+			}
+			if r >= 10 {
+				t.Fatal(newHash.name, "squeezed integer out of range")
+			}
+		}
+	}
+
+	// curve points can be absorbed in a byte oriented sponge
+	_, _, g1, _ := bn254.Generators()
+	s := NewSponge(sha256.New, "test")
+	s.AbsorbMarshalers("g1", &g1)
+	if _, err := s.SqueezeBigInt("alpha", fr.Modulus()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SqueezeUint64("alpha", 0); err == nil {
+		t.Fatal("squeezing in an empty range should fail")
+	}
+}
+
+func TestSpongeAlgebraicSqueeze(t *testing.T) {
+	t.Parallel()
+
+	// with an algebraic hash, the elements of its field are sampled from whole squeezed
+	// elements, without reduction
+	s := NewSponge(mimc.NewMiMC, "test")
+	c := s.Clone()
+	b, err := c.Squeeze("alpha", 2*fr.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenges, err := SqueezeElements[fr.Element](s, "alpha", fr.Modulus(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range challenges {
+		var expected fr.Element
+		if err := expected.SetBytesCanonical(b[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			t.Fatal(err)
+		}
+		if !challenges[i].Equal(&expected) {
+			t.Fatal("the challenges should be the squeezed elements")
+		}
+	}
+}
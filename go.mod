@@ -11,6 +11,7 @@ require (
 	github.com/stretchr/testify v1.8.0
 	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa
 	golang.org/x/sys v0.2.0
+	golang.org/x/text v0.3.8
 )
 
 require (
@@ -0,0 +1,21 @@
+package copyconstraint
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// copy constraint argument
+	conf.Package = "copyconstraint"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "copyconstraint.go"), Templates: []string{"copyconstraint.go.tmpl"}},
+		{File: filepath.Join(baseDir, "copyconstraint_test.go"), Templates: []string{"copyconstraint.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./copyconstraint/template/", entries...)
+
+}
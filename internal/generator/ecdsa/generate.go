@@ -15,10 +15,33 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "ecdsa.go"), Templates: []string{"ecdsa.go.tmpl"}},
+		{File: filepath.Join(baseDir, "nonce.go"), Templates: []string{"nonce.go.tmpl"}},
 		{File: filepath.Join(baseDir, "ecdsa_test.go"), Templates: []string{"ecdsa.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
 	}
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.STARK_CURVE) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ecdh.go"), Templates: []string{"ecdh.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ecdh_test.go"), Templates: []string{"ecdh.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.SECP256K1) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "stdlib.go"), Templates: []string{"stdlib.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "stdlib_test.go"), Templates: []string{"stdlib.test.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "bip32.go"), Templates: []string{"bip32.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "bip32_test.go"), Templates: []string{"bip32.test.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "vrf.go"), Templates: []string{"vrf.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "vrf_test.go"), Templates: []string{"vrf.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.STARK_CURVE) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "starknet.go"), Templates: []string{"starknet.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "starknet_test.go"), Templates: []string{"starknet.test.go.tmpl"}},
+		)
+	}
 	return bgen.Generate(conf, conf.Package, "./ecdsa/template", entries...)
 
 }
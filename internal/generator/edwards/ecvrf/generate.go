@@ -0,0 +1,24 @@
+package ecvrf
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// Generate generates the ecvrf package of the twisted Edwards curve. Unlike the eddsa
+// generator, it keeps conf.Package, the package of the curve, for the imports.
+func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	const packageName = "ecvrf"
+	baseDir = filepath.Join(baseDir, packageName)
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ecvrf.go"), Templates: []string{"ecvrf.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ecvrf_test.go"), Templates: []string{"ecvrf.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+	}
+	return bgen.Generate(conf, packageName, "./edwards/ecvrf/template", entries...)
+
+}
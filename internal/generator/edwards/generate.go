@@ -13,6 +13,8 @@ func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.Batc
 		{File: filepath.Join(baseDir, "point_test.go"), Templates: []string{"tests/point.go.tmpl"}},
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "curve.go"), Templates: []string{"curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve.go"), Templates: []string{"hash_to_curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve_test.go"), Templates: []string{"tests/hash_to_curve.go.tmpl"}},
 	}
 
 	return bgen.Generate(conf, conf.Package, "./edwards/template", entries...)
@@ -0,0 +1,26 @@
+package ringsig
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// Generate generates the ringsig package of the twisted Edwards curve. As the ecvrf generator,
+// it keeps conf.Package, the package of the curve, for the imports.
+func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	const packageName = "ringsig"
+	baseDir = filepath.Join(baseDir, packageName)
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ringsig.go"), Templates: []string{"ringsig.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lsag.go"), Templates: []string{"lsag.go.tmpl"}},
+		{File: filepath.Join(baseDir, "clsag.go"), Templates: []string{"clsag.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ringsig_test.go"), Templates: []string{"ringsig.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, packageName, "./edwards/ringsig/template", entries...)
+
+}
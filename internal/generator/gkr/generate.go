@@ -17,11 +17,15 @@ type Config struct {
 func Generate(config Config, baseDir string, bgen *bavard.BatchGenerator) error {
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "gkr.go"), Templates: []string{"gkr.go.tmpl"}},
+		{File: filepath.Join(baseDir, "gates.go"), Templates: []string{"gates.go.tmpl"}},
+		{File: filepath.Join(baseDir, "layered.go"), Templates: []string{"layered.go.tmpl"}},
 	}
 
 	if config.GenerateTests {
 		entries = append(entries,
-			bavard.Entry{File: filepath.Join(baseDir, "gkr_test.go"), Templates: []string{"gkr.test.go.tmpl", "gkr.test.vectors.go.tmpl"}})
+			bavard.Entry{File: filepath.Join(baseDir, "gkr_test.go"), Templates: []string{"gkr.test.go.tmpl", "gkr.test.vectors.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "gates_test.go"), Templates: []string{"gates.test.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "layered_test.go"), Templates: []string{"layered.test.go.tmpl"}})
 	}
 
 	return bgen.Generate(config, "gkr", "./gkr/template/", entries...)
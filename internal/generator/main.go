@@ -11,23 +11,29 @@ import (
 	"github.com/consensys/gnark-crypto/field/generator"
 	field "github.com/consensys/gnark-crypto/field/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/copyconstraint"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
+	"github.com/consensys/gnark-crypto/internal/generator/edwards/ecvrf"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards/eddsa"
+	"github.com/consensys/gnark-crypto/internal/generator/edwards/ringsig"
 	"github.com/consensys/gnark-crypto/internal/generator/fft"
 	fri "github.com/consensys/gnark-crypto/internal/generator/fri/template"
 	"github.com/consensys/gnark-crypto/internal/generator/gkr"
 	"github.com/consensys/gnark-crypto/internal/generator/iop"
 	"github.com/consensys/gnark-crypto/internal/generator/kzg"
+	"github.com/consensys/gnark-crypto/internal/generator/mlkzg"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
 	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
 	"github.com/consensys/gnark-crypto/internal/generator/plookup"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
+	"github.com/consensys/gnark-crypto/internal/generator/secretsharing"
 	"github.com/consensys/gnark-crypto/internal/generator/sumcheck"
 	"github.com/consensys/gnark-crypto/internal/generator/test_vector_utils"
+	"github.com/consensys/gnark-crypto/internal/generator/threshold"
 	"github.com/consensys/gnark-crypto/internal/generator/tower"
 )
 
@@ -74,6 +80,8 @@ func main() {
 			assertNoError(ecc.Generate(conf, curveDir, bgen))
 
 			if conf.Equal(config.SECP256K1) {
+				// generate frost
+				assertNoError(threshold.GenerateFROST(conf, curveDir, bgen))
 				return
 			}
 
@@ -92,15 +100,24 @@ func main() {
 			// generate kzg on fr
 			assertNoError(kzg.Generate(conf, filepath.Join(curveDir, "fr", "kzg"), bgen))
 
+			// generate multilinear kzg on fr
+			assertNoError(mlkzg.Generate(conf, filepath.Join(curveDir, "fr", "mlkzg"), bgen))
+
 			// generate pedersen on fr
 			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
 
+			// generate secret sharing on fr
+			assertNoError(secretsharing.Generate(conf, filepath.Join(curveDir, "fr", "secretsharing"), bgen))
+
 			// generate plookup on fr
 			assertNoError(plookup.Generate(conf, filepath.Join(curveDir, "fr", "plookup"), bgen))
 
 			// generate permutation on fr
 			assertNoError(permutation.Generate(conf, filepath.Join(curveDir, "fr", "permutation"), bgen))
 
+			// generate copy constraint argument on fr
+			assertNoError(copyconstraint.Generate(conf, filepath.Join(curveDir, "fr", "copyconstraint"), bgen))
+
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
@@ -151,6 +168,11 @@ func main() {
 			// generate iop functions
 			assertNoError(iop.Generate(conf, filepath.Join(curveDir, "fr", "iop"), bgen))
 
+			// generate threshold BLS signatures
+			if conf.Equal(config.BN254) || conf.Equal(config.BLS12_381) {
+				assertNoError(threshold.GenerateThresholdBLS(conf, curveDir, bgen))
+			}
+
 		}(conf)
 
 	}
@@ -167,6 +189,15 @@ func main() {
 
 			// generate eddsa on companion curves
 			assertNoError(eddsa.Generate(conf, curveDir, bgen))
+
+			// generate ecvrf on companion curves
+			assertNoError(ecvrf.Generate(conf, curveDir, bgen))
+
+			// generate frost on companion curves
+			assertNoError(threshold.GenerateEdwardsFROST(conf, curveDir, bgen))
+
+			// generate linkable ring signatures on companion curves
+			assertNoError(ringsig.Generate(conf, curveDir, bgen))
 		}(conf)
 
 	}
@@ -13,7 +13,10 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	conf.Package = "pedersen"
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "pedersen.go"), Templates: []string{"pedersen.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hiding.go"), Templates: []string{"hiding.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "pedersen_test.go"), Templates: []string{"pedersen.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hiding_test.go"), Templates: []string{"hiding.test.go.tmpl"}},
 	}
 	return bgen.Generate(conf, conf.Package, "./pedersen/template/", entries...)
 
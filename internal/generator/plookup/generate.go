@@ -15,7 +15,13 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "vector.go"), Templates: []string{"vector.go.tmpl"}},
 		{File: filepath.Join(baseDir, "table.go"), Templates: []string{"table.go.tmpl"}},
+		{File: filepath.Join(baseDir, "logup.go"), Templates: []string{"logup.go.tmpl"}},
+		{File: filepath.Join(baseDir, "cq.go"), Templates: []string{"cq.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "plookup_test.go"), Templates: []string{"plookup.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "logup_test.go"), Templates: []string{"logup.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "cq_test.go"), Templates: []string{"cq.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
 	}
 	return bgen.Generate(conf, conf.Package, "./plookup/template/", entries...)
 
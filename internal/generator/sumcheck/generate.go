@@ -10,6 +10,8 @@ func Generate(conf config.FieldDependency, baseDir string, bgen *bavard.BatchGen
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "sumcheck.go"), Templates: []string{"sumcheck.go.tmpl"}},
 		{File: filepath.Join(baseDir, "sumcheck_test.go"), Templates: []string{"sumcheck.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "claims.go"), Templates: []string{"claims.go.tmpl"}},
+		{File: filepath.Join(baseDir, "claims_test.go"), Templates: []string{"claims.test.go.tmpl"}},
 	}
 	return bgen.Generate(conf, "sumcheck", "./sumcheck/template/", entries...)
 }
@@ -0,0 +1,95 @@
+package threshold
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// groups of the schemes, which determine the encoding of the points and the hash to scalars
+const (
+	groupSEC1    = "sec1"    // secp256k1, with SEC 1 compressed points
+	groupPairing = "pairing" // G1 of a pairing friendly curve
+	groupEdwards = "edwards" // twisted Edwards curve
+)
+
+// Config is the template data of the threshold signature packages
+type Config struct {
+	// Name is the name of the curve, the directory under ecc
+	Name string
+	// EnumID is the identifier of the curve in ecc.ID
+	EnumID string
+	// CurvePackage is the package of the points, CurvePath its path under ecc
+	CurvePackage, CurvePath string
+	// Group is one of groupSEC1, groupPairing or groupEdwards
+	Group string
+	// Package is frost or tbls
+	Package string
+	// ContextString is the domain separation prefix of the hashes
+	ContextString string
+	// Suite is the hash to curve suite of the BLS signatures (tbls only)
+	Suite string
+}
+
+// GenerateFROST generates the FROST threshold Schnorr signatures on secp256k1
+func GenerateFROST(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	return generate(Config{
+		Name:          conf.Name,
+		EnumID:        conf.EnumID,
+		CurvePackage:  conf.CurvePackage,
+		CurvePath:     conf.Name,
+		Group:         groupSEC1,
+		Package:       "frost",
+		ContextString: "FROST-secp256k1-SHA256-v1",
+	}, baseDir, bgen)
+}
+
+// GenerateEdwardsFROST generates the FROST threshold Schnorr signatures on a twisted Edwards curve
+func GenerateEdwardsFROST(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	return generate(Config{
+		Name:          conf.Name,
+		EnumID:        conf.EnumID,
+		CurvePackage:  conf.Package,
+		CurvePath:     conf.Name + "/" + conf.Package,
+		Group:         groupEdwards,
+		Package:       "frost",
+		ContextString: "FROST-" + conf.Name + "-" + conf.Package + "-SHA512-v1",
+	}, baseDir, bgen)
+}
+
+// GenerateThresholdBLS generates the threshold BLS signatures on a pairing friendly curve,
+// with the public keys in G1 and the signatures in G2
+func GenerateThresholdBLS(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	c := Config{
+		Name:          conf.Name,
+		EnumID:        conf.EnumID,
+		CurvePackage:  conf.CurvePackage,
+		CurvePath:     conf.Name,
+		Group:         groupPairing,
+		Package:       "tbls",
+		ContextString: "TBLS-" + conf.Name + "-v1",
+	}
+	switch {
+	case conf.Equal(config.BLS12_381):
+		c.Suite = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+	case conf.Equal(config.BN254):
+		c.Suite = "BLS_SIG_BN254G2_XMD:SHA-256_SVDW_RO_NUL_"
+	}
+	return generate(c, baseDir, bgen)
+}
+
+func generate(conf Config, baseDir string, bgen *bavard.BatchGenerator) error {
+	baseDir = filepath.Join(baseDir, conf.Package)
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "group.go"), Templates: []string{"group.go.tmpl"}},
+		{File: filepath.Join(baseDir, "dkg.go"), Templates: []string{"dkg.go.tmpl"}},
+		{File: filepath.Join(baseDir, "dkg_test.go"), Templates: []string{"dkg.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, conf.Package+".go"), Templates: []string{conf.Package + ".go.tmpl"}},
+		{File: filepath.Join(baseDir, conf.Package+"_test.go"), Templates: []string{conf.Package + ".test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./threshold/template", entries...)
+}